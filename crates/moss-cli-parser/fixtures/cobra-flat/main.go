@@ -0,0 +1,61 @@
+// Example cobra CLI with a root command and nothing else, as flat tools
+// such as formatters and linters are: help has no "Available Commands:"
+// section and its usage line no [command], and cobra adds neither a help
+// nor a completion command, so "help" is just an argument.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "example [flags] [file...]",
+	Short: "Reformat source files",
+	Long: `Example reformats the files given, or standard input if there are none,
+and prints the result. Directories are walked recursively.`,
+	Example: `  # Rewrite every file under src in place
+  example -w src
+
+  # Check formatting in CI
+  example -l --exclude 'vendor/*' .`,
+	Version: "1.0.0",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Flags().Visit(func(f *pflag.Flag) {
+			fmt.Printf("%s=%s\n", f.Name, f.Value)
+		})
+		fmt.Println("Formatting", args)
+	},
+}
+
+func init() {
+	f := rootCmd.Flags()
+	f.BoolP("write", "w", false, "Write the result to each file instead of stdout")
+	f.BoolP("list", "l", false, "List files whose formatting differs")
+	f.BoolP("diff", "d", false, "Print a diff instead of the result")
+	f.IntP("indent", "i", 4, "Spaces per indentation level")
+	f.Bool("tabs", false, "Indent with tabs instead of spaces")
+	f.Int("line-width", 100, "Wrap lines longer than this")
+	f.StringArrayP("exclude", "e", nil, "Skip files matching this glob (repeatable)")
+	f.String("config", "", "Read settings from this file")
+	rootCmd.MarkFlagFilename("config", "toml")
+	f.String("stdin-filename", "", "Name to report standard input as")
+	f.String("color", "auto", "Colorize diffs: auto, always or never")
+	f.Lookup("color").NoOptDefVal = "always"
+	f.IntP("jobs", "j", 0, "Files to format in parallel, 0 for one per CPU")
+	f.Duration("timeout", 0, "Give up on a file after this long")
+	f.BoolP("quiet", "q", false, "Print nothing but errors")
+	f.Bool("debug", false, "Dump the syntax tree")
+	f.MarkHidden("debug")
+	f.Bool("fast", false, "Skip the equivalence check")
+	f.MarkDeprecated("fast", "the check is always fast now")
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,178 @@
+// Example cobra CLI whose flag defaults depend on the platform it runs on:
+// paths under the user's home, config and cache directories and the
+// temporary directory, and values chosen by GOOS. The same source prints
+// different help on Linux, macOS and Windows, and on one OS different help
+// for different users.
+//
+// Usage:
+//
+//	example [-as <goos>] [flags] [project]
+//	example [-as <goos>] -gen-defaults <dir>
+//
+// The second form writes the defaults this build computed, and where each
+// came from, to <dir>/example.defaults.json, as ground truth for its help.
+// With -as, either form computes the defaults the build for goos would from
+// the same environment, so that one host can capture every platform's help.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// platformDefault is a flag default computed at startup.
+type platformDefault struct {
+	Flag string `json:"flag"`
+	// Default is the default as pflag prints it.
+	Default string `json:"default"`
+	// Source is what the default was computed from, such as os.TempDir.
+	Source string `json:"source"`
+}
+
+// platformDefaults are the defaults of the flags in init, in order.
+var platformDefaults []platformDefault
+
+var rootCmd = &cobra.Command{
+	Use:   "example [flags] [project]",
+	Short: "Manage local project workspaces",
+	Long: `Example checks out projects into a workspace and keeps their downloads
+in a shared cache. Every directory it uses has a default for the platform
+it runs on.`,
+	Version: "1.0.0",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Flags().Visit(func(f *pflag.Flag) {
+			fmt.Printf("%s=%s\n", f.Name, f.Value)
+		})
+		fmt.Println("Opening", args)
+	},
+}
+
+// pathFlag defines a string flag defaulting to path, which came from
+// source.
+func pathFlag(name, path, source, usage string) {
+	rootCmd.Flags().String(name, path, usage)
+	platformDefaults = append(platformDefaults, platformDefault{name, rootCmd.Flags().Lookup(name).DefValue, source})
+}
+
+// dirs are the directories a build computes its defaults from, and how it
+// joins paths.
+type dirs struct {
+	goos                      string
+	home, config, cache, temp string
+	join                      func(elem ...string) string
+}
+
+// hostDirs returns the dirs of this build, from the os package.
+func hostDirs() dirs {
+	home, _ := os.UserHomeDir()
+	config, _ := os.UserConfigDir()
+	cache, _ := os.UserCacheDir()
+	return dirs{runtime.GOOS, home, config, cache, os.TempDir(), filepath.Join}
+}
+
+// dirsAs returns the dirs the build for goos would compute from this
+// environment, by the rules os.UserHomeDir, os.UserConfigDir,
+// os.UserCacheDir and os.TempDir follow there.
+func dirsAs(goos string) (dirs, error) {
+	home := os.Getenv("HOME")
+	switch goos {
+	case "windows":
+		temp := "C:\\Windows"
+		for _, v := range []string{"TMP", "TEMP", "USERPROFILE"} {
+			if dir := os.Getenv(v); dir != "" {
+				temp = dir
+				break
+			}
+		}
+		join := func(elem ...string) string {
+			return strings.ReplaceAll(path.Join(elem...), "/", `\`)
+		}
+		return dirs{goos, os.Getenv("USERPROFILE"), os.Getenv("APPDATA"), os.Getenv("LOCALAPPDATA"), strings.TrimSuffix(temp, `\`), join}, nil
+	case "darwin":
+		return dirs{goos, home, home + "/Library/Application Support", home + "/Library/Caches", unixTemp(), path.Join}, nil
+	case "linux":
+		config, cache := os.Getenv("XDG_CONFIG_HOME"), os.Getenv("XDG_CACHE_HOME")
+		if config == "" {
+			config = home + "/.config"
+		}
+		if cache == "" {
+			cache = home + "/.cache"
+		}
+		return dirs{goos, home, config, cache, unixTemp(), path.Join}, nil
+	}
+	return dirs{}, fmt.Errorf("-as %s: not one of linux, darwin and windows", goos)
+}
+
+// unixTemp returns os.TempDir as Unix builds compute it.
+func unixTemp() string {
+	if dir := os.Getenv("TMPDIR"); dir != "" {
+		return dir
+	}
+	return "/tmp"
+}
+
+// defineFlags defines the flags of rootCmd, with defaults computed from d.
+func defineFlags(d dirs) {
+	pathFlag("config", d.join(d.config, "example", "config.yaml"), "os.UserConfigDir", "Read settings from this file")
+	pathFlag("cache-dir", d.join(d.cache, "example"), "os.UserCacheDir", "Keep downloaded artifacts here")
+	pathFlag("workspace", d.join(d.home, "example"), "os.UserHomeDir", "Check projects out under this directory")
+	pathFlag("tmp-dir", d.temp, "os.TempDir", "Scratch space for builds")
+	pathFlag("socket", d.join(d.temp, "example.sock"), "os.TempDir", "Talk to the daemon over this socket")
+
+	shell, system := "/bin/sh", "/usr/local/lib/example/plugins"
+	if d.goos == "windows" {
+		shell, system = "cmd.exe", `C:\ProgramData\example\plugins`
+	}
+	pathFlag("shell", shell, "runtime.GOOS", "Run hooks with this shell")
+	rootCmd.Flags().StringSlice("plugin-path", []string{d.join(d.home, ".example", "plugins"), system}, "Search these directories for plugins")
+	platformDefaults = append(platformDefaults, platformDefault{"plugin-path", rootCmd.Flags().Lookup("plugin-path").DefValue, "os.UserHomeDir, runtime.GOOS"})
+	rootCmd.Flags().BoolP("verbose", "v", false, "Print each directory as it is used")
+}
+
+// genDefaults writes the computed defaults, with the GOOS they were
+// computed for, to dir/example.defaults.json.
+func genDefaults(goos, dir string) error {
+	data, err := json.MarshalIndent(struct {
+		GOOS     string            `json:"goos"`
+		Defaults []platformDefault `json:"defaults"`
+	}{goos, platformDefaults}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "example.defaults.json"), append(data, '\n'), 0o644)
+}
+
+func main() {
+	args, d := os.Args[1:], hostDirs()
+	if len(args) >= 2 && args[0] == "-as" {
+		var err error
+		if d, err = dirsAs(args[1]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		args = args[2:]
+	}
+	defineFlags(d)
+	if len(args) == 2 && args[0] == "-gen-defaults" {
+		if err := genDefaults(d.goos, args[1]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	rootCmd.SetArgs(args)
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
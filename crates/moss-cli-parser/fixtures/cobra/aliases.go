@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// aliasHelp is one command's --help asked for by a path naming it, or an
+// ancestor of it, by an alias, as genAliases records it.
+type aliasHelp struct {
+	// Invoked is the path as typed, such as "example b", and Command the
+	// canonical path it resolves to, such as "example build".
+	Invoked string `json:"invoked"`
+	Command string `json:"command"`
+	// Help is the file the help was written to, relative to the directory
+	// of example.aliases.json.
+	Help string `json:"help"`
+	// Usage is the first line of the help's Usage: section, and Canonical
+	// is set when it names Command rather than the path as invoked. Help
+	// topics print no usage, so neither is set for them.
+	Usage     string `json:"usage,omitempty"`
+	Canonical bool   `json:"canonical"`
+	// Identical is set when the help printed the same as Command's own.
+	Identical bool `json:"identical"`
+}
+
+// genAliases writes the help of every command genGolden covers whose path
+// has aliases, asked for once by each way of spelling the path with them,
+// as example-<words typed>.help, and maps each spelling to the command it
+// resolved to in example.aliases.json. cobra prints a command's path by
+// its name however it was reached, so the usage names the command by its
+// canonical path and the help is that of the canonical path.
+func genAliases(root *cobra.Command, dir string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	index := []aliasHelp{}
+	walkDocumented(root, func(cmd *cobra.Command) {
+		if err != nil || cmd == root {
+			return
+		}
+		canonical := strings.Fields(cmd.CommandPath())[1:]
+		want, cerr := exec.Command(self, append(canonical, "--help")...).Output()
+		if cerr != nil {
+			err = fmt.Errorf("%s --help: %w", cmd.CommandPath(), cerr)
+			return
+		}
+		for _, words := range aliasPaths(cmd)[1:] {
+			out, rerr := exec.Command(self, append(words, "--help")...).Output()
+			if rerr != nil {
+				err = fmt.Errorf("%s %s --help: %w", root.Name(), strings.Join(words, " "), rerr)
+				return
+			}
+			name := root.Name() + "-" + strings.Join(words, "-") + ".help"
+			if err = os.WriteFile(filepath.Join(dir, name), out, 0o644); err != nil {
+				return
+			}
+			usage := usageLine(string(out))
+			index = append(index, aliasHelp{
+				Invoked:   root.Name() + " " + strings.Join(words, " "),
+				Command:   cmd.CommandPath(),
+				Help:      name,
+				Usage:     usage,
+				Canonical: usage == cmd.CommandPath() || strings.HasPrefix(usage, cmd.CommandPath()+" "),
+				Identical: string(out) == string(want),
+			})
+		}
+	})
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "example.aliases.json"), append(data, '\n'), 0o644)
+}
+
+// aliasPaths returns every way of spelling cmd's path below the root, one
+// word of each command on it by its name or one of its aliases, the
+// canonical spelling first.
+func aliasPaths(cmd *cobra.Command) [][]string {
+	if !cmd.HasParent() {
+		return [][]string{nil}
+	}
+	var paths [][]string
+	for _, parent := range aliasPaths(cmd.Parent()) {
+		for _, word := range append([]string{cmd.Name()}, cmd.Aliases...) {
+			paths = append(paths, append(append([]string{}, parent...), word))
+		}
+	}
+	return paths
+}
+
+// usageLine returns the first line of help's Usage: section, unindented.
+func usageLine(help string) string {
+	_, rest, _ := strings.Cut(help, "Usage:\n")
+	line, _, _ := strings.Cut(rest, "\n")
+	return strings.TrimSpace(line)
+}
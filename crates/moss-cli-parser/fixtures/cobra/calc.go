@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/cobra"
+)
+
+// calc takes numbers as positionals. pflag reads any argument starting with
+// a dash as a flag, so a negative number is an unknown shorthand unless it
+// follows --, or is the value of a flag that takes one.
+var calcCmd = &cobra.Command{
+	Use:   "calc [flags] <a> <b>",
+	Short: "Combine two numbers",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		printFlags(cmd)
+		var x [2]float64
+		for i, arg := range args {
+			v, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				return fmt.Errorf("%q is not a number", arg)
+			}
+			x[i] = v
+		}
+		op, _ := cmd.Flags().GetString("op")
+		scale, _ := cmd.Flags().GetFloat64("scale")
+		var result float64
+		switch op {
+		case "add":
+			result = x[0] + x[1]
+		case "sub":
+			result = x[0] - x[1]
+		case "mul":
+			result = x[0] * x[1]
+		default:
+			return fmt.Errorf("unknown operation %q", op)
+		}
+		fmt.Println(result * scale)
+		return nil
+	},
+}
+
+func init() {
+	calcCmd.Flags().StringP("op", "o", "add", "Operation, one of: add|sub|mul")
+	calcCmd.Flags().Float64("scale", 1, "Multiply the result by this")
+	rootCmd.AddCommand(calcCmd)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// A four-level command tree: example cluster node pool create.
+
+var clusterCmd = &cobra.Command{
+	Use:         "cluster",
+	Aliases:     []string{"clusters", "cl"},
+	Short:       "Manage clusters",
+	Annotations: map[string]string{"stability": "beta", "since": "0.5.0", "category": "management"},
+	Long: `Manage clusters and the resources inside them.
+
+Cluster commands talk to the control plane selected by --context. Most
+subcommands are organised by resource: nodes, then the pools those nodes
+belong to.`,
+}
+
+var nodeCmd = &cobra.Command{
+	Use:   "node",
+	Short: "Manage cluster nodes",
+}
+
+var nodeListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List nodes in the cluster",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Listing nodes...")
+	},
+}
+
+var poolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Manage node pools",
+}
+
+var poolCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a node pool",
+	Long: `Create a node pool with the given name.
+
+The pool is created in the zone given by --zone and starts with --size nodes
+of the requested machine type.`,
+	Example: `  # Create a three-node pool in the default zone
+  example cluster node pool create workers
+
+  # Create a larger pool of high-memory machines
+  example cluster node pool create batch --size 10 --machine-type highmem`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Creating pool", args[0])
+	},
+}
+
+var poolDeleteCmd = &cobra.Command{
+	Use:   "delete <name> [name...]",
+	Short: "Delete up to three node pools",
+	Args:  cobra.RangeArgs(1, 3),
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		var names []string
+		for _, pool := range pools {
+			if !slices.Contains(args, strings.Split(pool, "\t")[0]) {
+				names = append(names, pool)
+			}
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Deleting pools", args)
+	},
+}
+
+// pools stands in for a control-plane lookup in dynamic completions.
+var pools = []string{
+	"batch\tSpot instances for batch jobs",
+	"gpu\tAccelerated nodes",
+	"workers\tGeneral purpose pool",
+}
+
+func init() {
+	clusterCmd.PersistentFlags().String("context", "", "Cluster context to use")
+	nodeCmd.PersistentFlags().StringP("selector", "l", "", "Label selector for nodes")
+	nodeListCmd.Flags().BoolP("wide", "w", false, "Show additional columns")
+	output := newEnum("format", "table", "json", "yaml", "table")
+	nodeListCmd.Flags().VarP(output, "output", "o", output.usage("Output format"))
+	nodeListCmd.Flags().SetAnnotation("output", "since", []string{"0.6.0"})
+	poolCmd.PersistentFlags().String("zone", "us-east-1a", "Availability zone")
+	poolCmd.RegisterFlagCompletionFunc("zone", cobra.FixedCompletions(
+		[]string{"us-east-1a", "us-east-1b", "eu-west-1a"}, cobra.ShellCompDirectiveNoFileComp))
+	poolCreateCmd.Flags().Int("size", 3, "Number of nodes in the pool")
+	poolCreateCmd.Flags().String("machine-type", "standard", "Machine type for pool nodes")
+	poolDeleteCmd.Flags().Bool("force", false, "Delete even if nodes are busy")
+
+	poolCmd.AddCommand(poolCreateCmd)
+	poolCmd.AddCommand(poolDeleteCmd)
+	nodeCmd.AddCommand(nodeListCmd)
+	nodeCmd.AddCommand(poolCmd)
+	clusterCmd.AddCommand(nodeCmd)
+	rootCmd.AddCommand(clusterCmd)
+}
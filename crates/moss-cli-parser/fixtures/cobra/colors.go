@@ -0,0 +1,76 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+)
+
+var (
+	headerColor = color.New(color.FgYellow, color.Bold)
+	nameColor   = color.New(color.FgCyan)
+
+	// flagNameRE matches the names at the start of a FlagUsages line.
+	flagNameRE = regexp.MustCompile(`(?m)^(\s+)(?:(-[^-\s])(, ))?(--[^\s=]+)`)
+)
+
+// usageHeaders are the section headers of cobra's default usage template.
+var usageHeaders = []string{
+	"Usage:", "Aliases:", "Examples:", "Available Commands:",
+	"Additional Commands:", "Flags:", "Global Flags:", "Additional help topics:",
+}
+
+// colorize makes help print section headers in bold yellow and command and
+// flag names in cyan, the way fatih/color based CLIs do, when useColor
+// allows it.
+func colorize(root *cobra.Command) {
+	color.NoColor = !useColor()
+	cobra.AddTemplateFunc("header", func(s string) string {
+		return headerColor.Sprint(s)
+	})
+	cobra.AddTemplateFunc("commandName", func(name string, padding int) string {
+		return nameColor.Sprint(name) + strings.Repeat(" ", max(padding-len(name), 0))
+	})
+	cobra.AddTemplateFunc("flagNames", func(usages string) string {
+		return flagNameRE.ReplaceAllStringFunc(usages, func(s string) string {
+			m := flagNameRE.FindStringSubmatch(s)
+			out := m[1]
+			if m[2] != "" {
+				out += nameColor.Sprint(m[2]) + m[3]
+			}
+			return out + nameColor.Sprint(m[4])
+		})
+	})
+
+	t := "\n" + root.UsageTemplate()
+	for _, h := range usageHeaders {
+		t = strings.ReplaceAll(t, "\n"+h, `
+{{header "`+h+`"}}`)
+	}
+	t = strings.ReplaceAll(t, "\n{{.Title}}", "\n{{header .Title}}")
+	t = strings.ReplaceAll(t, "{{rpad .Name .NamePadding }}", "{{commandName .Name .NamePadding}}")
+	t = strings.ReplaceAll(t, "{{rpad .CommandPath .CommandPathPadding}}", "{{commandName .CommandPath .CommandPathPadding}}")
+	t = strings.ReplaceAll(t, ".FlagUsages |", ".FlagUsages | flagNames |")
+	root.SetUsageTemplate(strings.TrimPrefix(t, "\n"))
+}
+
+// useColor decides whether to emit escapes the way most CLIs do: NO_COLOR
+// (https://no-color.org) turns color off whatever else is set, then
+// CLICOLOR_FORCE other than 0 turns it on, then CLICOLOR=0 turns it off, and
+// otherwise color is used only when stdout is a terminal.
+func useColor() bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	if os.Getenv("CLICOLOR") == "0" {
+		return false
+	}
+	return isatty.IsTerminal(os.Stdout.Fd()) || isatty.IsCygwinTerminal(os.Stdout.Fd())
+}
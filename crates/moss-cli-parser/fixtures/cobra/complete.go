@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// completeCase is one command line genComplete asks cobra's hidden
+// __complete command to complete, and what it answered.
+type completeCase struct {
+	Command string `json:"command"`
+	// Cursor says where the word being completed, always the last of
+	// Args, is:
+	//
+	//	arg          an empty word where an argument or subcommand goes
+	//	prefix       part of the first subcommand's name
+	//	dash         a lone "-", where flags go
+	//	double-dash  a lone "--", where long flags go
+	//	terminator   an empty word after a "--" ending the flags
+	//	flag-prefix  part of the first flag's long name
+	//	flag-value   an empty word after Flag
+	//	flag-equals  the empty value of --Flag=
+	//	flag-dash    a "-" after Flag, its value if it takes one and a
+	//	             flag otherwise
+	Cursor string `json:"cursor"`
+	Flag   string `json:"flag,omitempty"`
+	// Args are the words after __complete.
+	Args   []string `json:"args"`
+	Stdout string   `json:"stdout"`
+	Stderr string   `json:"stderr,omitempty"`
+	Exit   int      `json:"exit"`
+	// Directive is the number ending Stdout, and Directives the names of
+	// its bits cobra's closing note on stderr gives.
+	Directive  int         `json:"directive"`
+	Directives []string    `json:"directives"`
+	Candidates []candidate `json:"candidates"`
+}
+
+type candidate struct {
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// directiveNote starts the line __complete ends its stderr with.
+const directiveNote = "Completion ended with directive: "
+
+// genComplete runs __complete, as shells do on every tab, for each command
+// genGolden covers at each cursor position of completeCase, each flag it
+// declares getting its own flag- cases, and writes what it answered to
+// example.complete.json. The answers tell what help does not: which flags
+// take a value, and what the values and arguments complete to.
+func genComplete(root *cobra.Command, dir string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cases := []completeCase{}
+	walkDocumented(root, func(cmd *cobra.Command) {
+		if err != nil || cmd.IsAdditionalHelpTopicCommand() {
+			return
+		}
+		path := strings.Fields(cmd.CommandPath())[1:]
+		add := func(cursor, flag string, words ...string) {
+			if err != nil {
+				return
+			}
+			c := completeCase{Command: cmd.CommandPath(), Cursor: cursor, Flag: flag, Args: append(append([]string{}, path...), words...)}
+			err = c.run(self)
+			cases = append(cases, c)
+		}
+		add("arg", "", "")
+		for _, sub := range cmd.Commands() {
+			if sub.IsAvailableCommand() {
+				add("prefix", "", sub.Name()[:(len(sub.Name())+1)/2])
+				break
+			}
+		}
+		add("dash", "", "-")
+		add("double-dash", "", "--")
+		add("terminator", "", "--", "")
+		var flags []*pflag.Flag
+		cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+			if !f.Hidden && f.Deprecated == "" && f.Name != "help" {
+				flags = append(flags, f)
+			}
+		})
+		for i, f := range flags {
+			if i == 0 {
+				add("flag-prefix", "--"+f.Name, "--"+f.Name[:(len(f.Name)+1)/2])
+			}
+			add("flag-value", "--"+f.Name, "--"+f.Name, "")
+			add("flag-equals", "--"+f.Name, "--"+f.Name+"=")
+			add("flag-dash", "--"+f.Name, "--"+f.Name, "-")
+		}
+	})
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(cases, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "example.complete.json"), append(data, '\n'), 0o644)
+}
+
+// run runs self __complete with c's Args and records its answer in c.
+func (c *completeCase) run(self string) error {
+	cmd := exec.Command(self, append([]string{"__complete"}, c.Args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	err := cmd.Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		c.Exit = exit.ExitCode()
+	} else if err != nil {
+		return err
+	}
+	c.Stdout, c.Stderr = stdout.String(), stderr.String()
+	lines := strings.Split(strings.TrimSuffix(c.Stdout, "\n"), "\n")
+	last := lines[len(lines)-1]
+	if c.Directive, err = strconv.Atoi(strings.TrimPrefix(last, ":")); err != nil || !strings.HasPrefix(last, ":") {
+		return fmt.Errorf("__complete %s: no directive in %q", strings.Join(c.Args, " "), c.Stdout)
+	}
+	c.Candidates = []candidate{}
+	for _, line := range lines[:len(lines)-1] {
+		if line == "" {
+			continue
+		}
+		value, desc, _ := strings.Cut(line, "\t")
+		c.Candidates = append(c.Candidates, candidate{value, desc})
+	}
+	c.Directives = []string{}
+	for _, line := range strings.Split(c.Stderr, "\n") {
+		if names, ok := strings.CutPrefix(line, directiveNote); ok {
+			c.Directives = strings.Split(names, ", ")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,1901 @@
+# bash completion for example                              -*- shell-script -*-
+
+__example_debug()
+{
+    if [[ -n ${BASH_COMP_DEBUG_FILE:-} ]]; then
+        echo "$*" >> "${BASH_COMP_DEBUG_FILE}"
+    fi
+}
+
+# Homebrew on Macs have version 1.3 of bash-completion which doesn't include
+# _init_completion. This is a very minimal version of that function.
+__example_init_completion()
+{
+    COMPREPLY=()
+    _get_comp_words_by_ref "$@" cur prev words cword
+}
+
+__example_index_of_word()
+{
+    local w word=$1
+    shift
+    index=0
+    for w in "$@"; do
+        [[ $w = "$word" ]] && return
+        index=$((index+1))
+    done
+    index=-1
+}
+
+__example_contains_word()
+{
+    local w word=$1; shift
+    for w in "$@"; do
+        [[ $w = "$word" ]] && return
+    done
+    return 1
+}
+
+__example_handle_go_custom_completion()
+{
+    __example_debug "${FUNCNAME[0]}: cur is ${cur}, words[*] is ${words[*]}, #words[@] is ${#words[@]}"
+
+    local shellCompDirectiveError=1
+    local shellCompDirectiveNoSpace=2
+    local shellCompDirectiveNoFileComp=4
+    local shellCompDirectiveFilterFileExt=8
+    local shellCompDirectiveFilterDirs=16
+
+    local out requestComp lastParam lastChar comp directive args
+
+    # Prepare the command to request completions for the program.
+    # Calling ${words[0]} instead of directly example allows handling aliases
+    args=("${words[@]:1}")
+    # Disable ActiveHelp which is not supported for bash completion v1
+    requestComp="EXAMPLE_ACTIVE_HELP=0 ${words[0]} __completeNoDesc ${args[*]}"
+
+    lastParam=${words[$((${#words[@]}-1))]}
+    lastChar=${lastParam:$((${#lastParam}-1)):1}
+    __example_debug "${FUNCNAME[0]}: lastParam ${lastParam}, lastChar ${lastChar}"
+
+    if [ -z "${cur}" ] && [ "${lastChar}" != "=" ]; then
+        # If the last parameter is complete (there is a space following it)
+        # We add an extra empty parameter so we can indicate this to the go method.
+        __example_debug "${FUNCNAME[0]}: Adding extra empty parameter"
+        requestComp="${requestComp} \"\""
+    fi
+
+    __example_debug "${FUNCNAME[0]}: calling ${requestComp}"
+    # Use eval to handle any environment variables and such
+    out=$(eval "${requestComp}" 2>/dev/null)
+
+    # Extract the directive integer at the very end of the output following a colon (:)
+    directive=${out##*:}
+    # Remove the directive
+    out=${out%:*}
+    if [ "${directive}" = "${out}" ]; then
+        # There is not directive specified
+        directive=0
+    fi
+    __example_debug "${FUNCNAME[0]}: the completion directive is: ${directive}"
+    __example_debug "${FUNCNAME[0]}: the completions are: ${out}"
+
+    if [ $((directive & shellCompDirectiveError)) -ne 0 ]; then
+        # Error code.  No completion.
+        __example_debug "${FUNCNAME[0]}: received error from custom completion go code"
+        return
+    else
+        if [ $((directive & shellCompDirectiveNoSpace)) -ne 0 ]; then
+            if [[ $(type -t compopt) = "builtin" ]]; then
+                __example_debug "${FUNCNAME[0]}: activating no space"
+                compopt -o nospace
+            fi
+        fi
+        if [ $((directive & shellCompDirectiveNoFileComp)) -ne 0 ]; then
+            if [[ $(type -t compopt) = "builtin" ]]; then
+                __example_debug "${FUNCNAME[0]}: activating no file completion"
+                compopt +o default
+            fi
+        fi
+    fi
+
+    if [ $((directive & shellCompDirectiveFilterFileExt)) -ne 0 ]; then
+        # File extension filtering
+        local fullFilter filter filteringCmd
+        # Do not use quotes around the $out variable or else newline
+        # characters will be kept.
+        for filter in ${out}; do
+            fullFilter+="$filter|"
+        done
+
+        filteringCmd="_filedir $fullFilter"
+        __example_debug "File filtering command: $filteringCmd"
+        $filteringCmd
+    elif [ $((directive & shellCompDirectiveFilterDirs)) -ne 0 ]; then
+        # File completion for directories only
+        local subdir
+        # Use printf to strip any trailing newline
+        subdir=$(printf "%s" "${out}")
+        if [ -n "$subdir" ]; then
+            __example_debug "Listing directories in $subdir"
+            __example_handle_subdirs_in_dir_flag "$subdir"
+        else
+            __example_debug "Listing directories in ."
+            _filedir -d
+        fi
+    else
+        while IFS='' read -r comp; do
+            COMPREPLY+=("$comp")
+        done < <(compgen -W "${out}" -- "$cur")
+    fi
+}
+
+__example_handle_reply()
+{
+    __example_debug "${FUNCNAME[0]}"
+    local comp
+    case $cur in
+        -*)
+            if [[ $(type -t compopt) = "builtin" ]]; then
+                compopt -o nospace
+            fi
+            local allflags
+            if [ ${#must_have_one_flag[@]} -ne 0 ]; then
+                allflags=("${must_have_one_flag[@]}")
+            else
+                allflags=("${flags[*]} ${two_word_flags[*]}")
+            fi
+            while IFS='' read -r comp; do
+                COMPREPLY+=("$comp")
+            done < <(compgen -W "${allflags[*]}" -- "$cur")
+            if [[ $(type -t compopt) = "builtin" ]]; then
+                [[ "${COMPREPLY[0]}" == *= ]] || compopt +o nospace
+            fi
+
+            # complete after --flag=abc
+            if [[ $cur == *=* ]]; then
+                if [[ $(type -t compopt) = "builtin" ]]; then
+                    compopt +o nospace
+                fi
+
+                local index flag
+                flag="${cur%=*}"
+                __example_index_of_word "${flag}" "${flags_with_completion[@]}"
+                COMPREPLY=()
+                if [[ ${index} -ge 0 ]]; then
+                    PREFIX=""
+                    cur="${cur#*=}"
+                    ${flags_completion[${index}]}
+                    if [ -n "${ZSH_VERSION:-}" ]; then
+                        # zsh completion needs --flag= prefix
+                        eval "COMPREPLY=( \"\${COMPREPLY[@]/#/${flag}=}\" )"
+                    fi
+                fi
+            fi
+
+            if [[ -z "${flag_parsing_disabled}" ]]; then
+                # If flag parsing is enabled, we have completed the flags and can return.
+                # If flag parsing is disabled, we may not know all (or any) of the flags, so we fallthrough
+                # to possibly call handle_go_custom_completion.
+                return 0;
+            fi
+            ;;
+    esac
+
+    # check if we are handling a flag with special work handling
+    local index
+    __example_index_of_word "${prev}" "${flags_with_completion[@]}"
+    if [[ ${index} -ge 0 ]]; then
+        ${flags_completion[${index}]}
+        return
+    fi
+
+    # we are parsing a flag and don't have a special handler, no completion
+    if [[ ${cur} != "${words[cword]}" ]]; then
+        return
+    fi
+
+    local completions
+    completions=("${commands[@]}")
+    if [[ ${#must_have_one_noun[@]} -ne 0 ]]; then
+        completions+=("${must_have_one_noun[@]}")
+    elif [[ -n "${has_completion_function}" ]]; then
+        # if a go completion function is provided, defer to that function
+        __example_handle_go_custom_completion
+    fi
+    if [[ ${#must_have_one_flag[@]} -ne 0 ]]; then
+        completions+=("${must_have_one_flag[@]}")
+    fi
+    while IFS='' read -r comp; do
+        COMPREPLY+=("$comp")
+    done < <(compgen -W "${completions[*]}" -- "$cur")
+
+    if [[ ${#COMPREPLY[@]} -eq 0 && ${#noun_aliases[@]} -gt 0 && ${#must_have_one_noun[@]} -ne 0 ]]; then
+        while IFS='' read -r comp; do
+            COMPREPLY+=("$comp")
+        done < <(compgen -W "${noun_aliases[*]}" -- "$cur")
+    fi
+
+    if [[ ${#COMPREPLY[@]} -eq 0 ]]; then
+        if declare -F __example_custom_func >/dev/null; then
+            # try command name qualified custom func
+            __example_custom_func
+        else
+            # otherwise fall back to unqualified for compatibility
+            declare -F __custom_func >/dev/null && __custom_func
+        fi
+    fi
+
+    # available in bash-completion >= 2, not always present on macOS
+    if declare -F __ltrim_colon_completions >/dev/null; then
+        __ltrim_colon_completions "$cur"
+    fi
+
+    # If there is only 1 completion and it is a flag with an = it will be completed
+    # but we don't want a space after the =
+    if [[ "${#COMPREPLY[@]}" -eq "1" ]] && [[ $(type -t compopt) = "builtin" ]] && [[ "${COMPREPLY[0]}" == --*= ]]; then
+       compopt -o nospace
+    fi
+}
+
+# The arguments should be in the form "ext1|ext2|extn"
+__example_handle_filename_extension_flag()
+{
+    local ext="$1"
+    _filedir "@(${ext})"
+}
+
+__example_handle_subdirs_in_dir_flag()
+{
+    local dir="$1"
+    pushd "${dir}" >/dev/null 2>&1 && _filedir -d && popd >/dev/null 2>&1 || return
+}
+
+__example_handle_flag()
+{
+    __example_debug "${FUNCNAME[0]}: c is $c words[c] is ${words[c]}"
+
+    # if a command required a flag, and we found it, unset must_have_one_flag()
+    local flagname=${words[c]}
+    local flagvalue=""
+    # if the word contained an =
+    if [[ ${words[c]} == *"="* ]]; then
+        flagvalue=${flagname#*=} # take in as flagvalue after the =
+        flagname=${flagname%=*} # strip everything after the =
+        flagname="${flagname}=" # but put the = back
+    fi
+    __example_debug "${FUNCNAME[0]}: looking for ${flagname}"
+    if __example_contains_word "${flagname}" "${must_have_one_flag[@]}"; then
+        must_have_one_flag=()
+    fi
+
+    # if you set a flag which only applies to this command, don't show subcommands
+    if __example_contains_word "${flagname}" "${local_nonpersistent_flags[@]}"; then
+      commands=()
+    fi
+
+    # keep flag value with flagname as flaghash
+    # flaghash variable is an associative array which is only supported in bash > 3.
+    if [[ -z "${BASH_VERSION:-}" || "${BASH_VERSINFO[0]:-}" -gt 3 ]]; then
+        if [ -n "${flagvalue}" ] ; then
+            flaghash[${flagname}]=${flagvalue}
+        elif [ -n "${words[ $((c+1)) ]}" ] ; then
+            flaghash[${flagname}]=${words[ $((c+1)) ]}
+        else
+            flaghash[${flagname}]="true" # pad "true" for bool flag
+        fi
+    fi
+
+    # skip the argument to a two word flag
+    if [[ ${words[c]} != *"="* ]] && __example_contains_word "${words[c]}" "${two_word_flags[@]}"; then
+        __example_debug "${FUNCNAME[0]}: found a flag ${words[c]}, skip the next argument"
+        c=$((c+1))
+        # if we are looking for a flags value, don't show commands
+        if [[ $c -eq $cword ]]; then
+            commands=()
+        fi
+    fi
+
+    c=$((c+1))
+
+}
+
+__example_handle_noun()
+{
+    __example_debug "${FUNCNAME[0]}: c is $c words[c] is ${words[c]}"
+
+    if __example_contains_word "${words[c]}" "${must_have_one_noun[@]}"; then
+        must_have_one_noun=()
+    elif __example_contains_word "${words[c]}" "${noun_aliases[@]}"; then
+        must_have_one_noun=()
+    fi
+
+    nouns+=("${words[c]}")
+    c=$((c+1))
+}
+
+__example_handle_command()
+{
+    __example_debug "${FUNCNAME[0]}: c is $c words[c] is ${words[c]}"
+
+    local next_command
+    if [[ -n ${last_command} ]]; then
+        next_command="_${last_command}_${words[c]//:/__}"
+    else
+        if [[ $c -eq 0 ]]; then
+            next_command="_example_root_command"
+        else
+            next_command="_${words[c]//:/__}"
+        fi
+    fi
+    c=$((c+1))
+    __example_debug "${FUNCNAME[0]}: looking for ${next_command}"
+    declare -F "$next_command" >/dev/null && $next_command
+}
+
+__example_handle_word()
+{
+    if [[ $c -ge $cword ]]; then
+        __example_handle_reply
+        return
+    fi
+    __example_debug "${FUNCNAME[0]}: c is $c words[c] is ${words[c]}"
+    if [[ "${words[c]}" == -* ]]; then
+        __example_handle_flag
+    elif __example_contains_word "${words[c]}" "${commands[@]}"; then
+        __example_handle_command
+    elif [[ $c -eq 0 ]]; then
+        __example_handle_command
+    elif __example_contains_word "${words[c]}" "${command_aliases[@]}"; then
+        # aliashash variable is an associative array which is only supported in bash > 3.
+        if [[ -z "${BASH_VERSION:-}" || "${BASH_VERSINFO[0]:-}" -gt 3 ]]; then
+            words[c]=${aliashash[${words[c]}]}
+            __example_handle_command
+        else
+            __example_handle_noun
+        fi
+    else
+        __example_handle_noun
+    fi
+    __example_handle_word
+}
+
+_example_build()
+{
+    last_command="example_build"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--cache=")
+    two_word_flags+=("--cache")
+    local_nonpersistent_flags+=("--cache")
+    local_nonpersistent_flags+=("--cache=")
+    flags+=("--env-file=")
+    two_word_flags+=("--env-file")
+    flags_with_completion+=("--env-file")
+    flags_completion+=("__example_handle_filename_extension_flag env")
+    local_nonpersistent_flags+=("--env-file")
+    local_nonpersistent_flags+=("--env-file=")
+    flags+=("--jobs=")
+    two_word_flags+=("--jobs")
+    two_word_flags+=("-j")
+    local_nonpersistent_flags+=("--jobs")
+    local_nonpersistent_flags+=("--jobs=")
+    local_nonpersistent_flags+=("-j")
+    flags+=("--release")
+    flags+=("-r")
+    local_nonpersistent_flags+=("--release")
+    local_nonpersistent_flags+=("-r")
+    flags+=("--target=")
+    two_word_flags+=("--target")
+    flags_with_completion+=("--target")
+    flags_completion+=("_filedir -d")
+    two_word_flags+=("-t")
+    flags_with_completion+=("-t")
+    flags_completion+=("_filedir -d")
+    local_nonpersistent_flags+=("--target")
+    local_nonpersistent_flags+=("--target=")
+    local_nonpersistent_flags+=("-t")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_calc()
+{
+    last_command="example_calc"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--op=")
+    two_word_flags+=("--op")
+    two_word_flags+=("-o")
+    local_nonpersistent_flags+=("--op")
+    local_nonpersistent_flags+=("--op=")
+    local_nonpersistent_flags+=("-o")
+    flags+=("--scale=")
+    two_word_flags+=("--scale")
+    local_nonpersistent_flags+=("--scale")
+    local_nonpersistent_flags+=("--scale=")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_clean()
+{
+    last_command="example_clean"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--force")
+    flags+=("-f")
+    local_nonpersistent_flags+=("--force")
+    local_nonpersistent_flags+=("-f")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_cluster_node_list()
+{
+    last_command="example_cluster_node_list"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--output=")
+    two_word_flags+=("--output")
+    two_word_flags+=("-o")
+    local_nonpersistent_flags+=("--output")
+    local_nonpersistent_flags+=("--output=")
+    local_nonpersistent_flags+=("-o")
+    flags+=("--wide")
+    flags+=("-w")
+    local_nonpersistent_flags+=("--wide")
+    local_nonpersistent_flags+=("-w")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--context=")
+    two_word_flags+=("--context")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--selector=")
+    two_word_flags+=("--selector")
+    two_word_flags+=("-l")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_cluster_node_pool_create()
+{
+    last_command="example_cluster_node_pool_create"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--machine-type=")
+    two_word_flags+=("--machine-type")
+    local_nonpersistent_flags+=("--machine-type")
+    local_nonpersistent_flags+=("--machine-type=")
+    flags+=("--size=")
+    two_word_flags+=("--size")
+    local_nonpersistent_flags+=("--size")
+    local_nonpersistent_flags+=("--size=")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--context=")
+    two_word_flags+=("--context")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--selector=")
+    two_word_flags+=("--selector")
+    two_word_flags+=("-l")
+    flags+=("--verbose")
+    flags+=("-v")
+    flags+=("--zone=")
+    two_word_flags+=("--zone")
+    flags_with_completion+=("--zone")
+    flags_completion+=("__example_handle_go_custom_completion")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_cluster_node_pool_delete()
+{
+    last_command="example_cluster_node_pool_delete"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--force")
+    local_nonpersistent_flags+=("--force")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--context=")
+    two_word_flags+=("--context")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--selector=")
+    two_word_flags+=("--selector")
+    two_word_flags+=("-l")
+    flags+=("--verbose")
+    flags+=("-v")
+    flags+=("--zone=")
+    two_word_flags+=("--zone")
+    flags_with_completion+=("--zone")
+    flags_completion+=("__example_handle_go_custom_completion")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    has_completion_function=1
+    noun_aliases=()
+}
+
+_example_cluster_node_pool()
+{
+    last_command="example_cluster_node_pool"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("create")
+    commands+=("delete")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--zone=")
+    two_word_flags+=("--zone")
+    flags_with_completion+=("--zone")
+    flags_completion+=("__example_handle_go_custom_completion")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--context=")
+    two_word_flags+=("--context")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--selector=")
+    two_word_flags+=("--selector")
+    two_word_flags+=("-l")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_cluster_node()
+{
+    last_command="example_cluster_node"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("list")
+    if [[ -z "${BASH_VERSION:-}" || "${BASH_VERSINFO[0]:-}" -gt 3 ]]; then
+        command_aliases+=("ls")
+        aliashash["ls"]="list"
+    fi
+    commands+=("pool")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--selector=")
+    two_word_flags+=("--selector")
+    two_word_flags+=("-l")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--context=")
+    two_word_flags+=("--context")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_cluster()
+{
+    last_command="example_cluster"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("node")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--context=")
+    two_word_flags+=("--context")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_config_check()
+{
+    last_command="example_config_check"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_config_get()
+{
+    last_command="example_config_get"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    has_completion_function=1
+    noun_aliases=()
+}
+
+_example_config_import()
+{
+    last_command="example_config_import"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_config_path()
+{
+    last_command="example_config_path"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_config_set()
+{
+    last_command="example_config_set"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_config()
+{
+    last_command="example_config"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("check")
+    commands+=("get")
+    commands+=("import")
+    commands+=("path")
+    commands+=("set")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_convert()
+{
+    last_command="example_convert"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--include=")
+    two_word_flags+=("--include")
+    local_nonpersistent_flags+=("--include")
+    local_nonpersistent_flags+=("--include=")
+    flags+=("--indent=")
+    two_word_flags+=("--indent")
+    two_word_flags+=("-i")
+    local_nonpersistent_flags+=("--indent")
+    local_nonpersistent_flags+=("--indent=")
+    local_nonpersistent_flags+=("-i")
+    flags+=("--log=")
+    two_word_flags+=("--log")
+    flags_with_completion+=("--log")
+    flags_completion+=("_filedir")
+    local_nonpersistent_flags+=("--log")
+    local_nonpersistent_flags+=("--log=")
+    flags+=("--overwrite")
+    local_nonpersistent_flags+=("--overwrite")
+    flags+=("--schema=")
+    two_word_flags+=("--schema")
+    flags_with_completion+=("--schema")
+    flags_completion+=("__example_handle_filename_extension_flag json|yaml")
+    local_nonpersistent_flags+=("--schema")
+    local_nonpersistent_flags+=("--schema=")
+    flags+=("--strict")
+    local_nonpersistent_flags+=("--strict")
+    flags+=("--to=")
+    two_word_flags+=("--to")
+    local_nonpersistent_flags+=("--to")
+    local_nonpersistent_flags+=("--to=")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_deploy_rollback()
+{
+    last_command="example_deploy_rollback"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--steps=")
+    two_word_flags+=("--steps")
+    local_nonpersistent_flags+=("--steps")
+    local_nonpersistent_flags+=("--steps=")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--env=")
+    two_word_flags+=("--env")
+    two_word_flags+=("-e")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_deploy()
+{
+    last_command="example_deploy"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("rollback")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--env=")
+    two_word_flags+=("--env")
+    two_word_flags+=("-e")
+    flags+=("--extremely-long-configuration-override-path=")
+    two_word_flags+=("--extremely-long-configuration-override-path")
+    local_nonpersistent_flags+=("--extremely-long-configuration-override-path")
+    local_nonpersistent_flags+=("--extremely-long-configuration-override-path=")
+    flags+=("--image=")
+    two_word_flags+=("--image")
+    local_nonpersistent_flags+=("--image")
+    local_nonpersistent_flags+=("--image=")
+    flags+=("--yes")
+    flags+=("-y")
+    local_nonpersistent_flags+=("--yes")
+    local_nonpersistent_flags+=("-y")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_flag+=("--env=")
+    must_have_one_flag+=("-e")
+    must_have_one_flag+=("--image=")
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_exec()
+{
+    last_command="example_exec"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--dry-run")
+    local_nonpersistent_flags+=("--dry-run")
+    flags+=("--env=")
+    two_word_flags+=("--env")
+    two_word_flags+=("-e")
+    local_nonpersistent_flags+=("--env")
+    local_nonpersistent_flags+=("--env=")
+    local_nonpersistent_flags+=("-e")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_greet_café()
+{
+    last_command="example_greet_café"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--emoji=")
+    two_word_flags+=("--emoji")
+    two_word_flags+=("-e")
+    flags+=("--name=")
+    two_word_flags+=("--name")
+    flags+=("--naïve")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+    flags+=("--名前=")
+    two_word_flags+=("--名前")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_greet_grüße()
+{
+    last_command="example_greet_grüße"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--emoji=")
+    two_word_flags+=("--emoji")
+    two_word_flags+=("-e")
+    flags+=("--name=")
+    two_word_flags+=("--name")
+    flags+=("--naïve")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+    flags+=("--名前=")
+    two_word_flags+=("--名前")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_greet_こんにちは()
+{
+    last_command="example_greet_こんにちは"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--emoji=")
+    two_word_flags+=("--emoji")
+    two_word_flags+=("-e")
+    flags+=("--name=")
+    two_word_flags+=("--name")
+    flags+=("--naïve")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+    flags+=("--名前=")
+    two_word_flags+=("--名前")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_greet()
+{
+    last_command="example_greet"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("café")
+    commands+=("grüße")
+    commands+=("こんにちは")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--emoji=")
+    two_word_flags+=("--emoji")
+    two_word_flags+=("-e")
+    flags+=("--name=")
+    two_word_flags+=("--name")
+    flags+=("--naïve")
+    flags+=("--名前=")
+    two_word_flags+=("--名前")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_init()
+{
+    last_command="example_init"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--env=")
+    two_word_flags+=("--env")
+    local_nonpersistent_flags+=("--env")
+    local_nonpersistent_flags+=("--env=")
+    flags+=("--exclude=")
+    two_word_flags+=("--exclude")
+    local_nonpersistent_flags+=("--exclude")
+    local_nonpersistent_flags+=("--exclude=")
+    flags+=("--force")
+    local_nonpersistent_flags+=("--force")
+    flags+=("--git")
+    local_nonpersistent_flags+=("--git")
+    flags+=("--grace=")
+    two_word_flags+=("--grace")
+    local_nonpersistent_flags+=("--grace")
+    local_nonpersistent_flags+=("--grace=")
+    flags+=("--ignore=")
+    two_word_flags+=("--ignore")
+    local_nonpersistent_flags+=("--ignore")
+    local_nonpersistent_flags+=("--ignore=")
+    flags+=("--jitter=")
+    two_word_flags+=("--jitter")
+    local_nonpersistent_flags+=("--jitter")
+    local_nonpersistent_flags+=("--jitter=")
+    flags+=("--languages=")
+    two_word_flags+=("--languages")
+    local_nonpersistent_flags+=("--languages")
+    local_nonpersistent_flags+=("--languages=")
+    flags+=("--meta=")
+    two_word_flags+=("--meta")
+    local_nonpersistent_flags+=("--meta")
+    local_nonpersistent_flags+=("--meta=")
+    flags+=("--name=")
+    two_word_flags+=("--name")
+    local_nonpersistent_flags+=("--name")
+    local_nonpersistent_flags+=("--name=")
+    flags+=("--ports=")
+    two_word_flags+=("--ports")
+    local_nonpersistent_flags+=("--ports")
+    local_nonpersistent_flags+=("--ports=")
+    flags+=("--retries=")
+    two_word_flags+=("--retries")
+    local_nonpersistent_flags+=("--retries")
+    local_nonpersistent_flags+=("--retries=")
+    flags+=("--separator=")
+    two_word_flags+=("--separator")
+    local_nonpersistent_flags+=("--separator")
+    local_nonpersistent_flags+=("--separator=")
+    flags+=("--template=")
+    two_word_flags+=("--template")
+    local_nonpersistent_flags+=("--template")
+    local_nonpersistent_flags+=("--template=")
+    flags+=("--threshold=")
+    two_word_flags+=("--threshold")
+    local_nonpersistent_flags+=("--threshold")
+    local_nonpersistent_flags+=("--threshold=")
+    flags+=("--wait=")
+    two_word_flags+=("--wait")
+    local_nonpersistent_flags+=("--wait")
+    local_nonpersistent_flags+=("--wait=")
+    flags+=("--workers=")
+    two_word_flags+=("--workers")
+    local_nonpersistent_flags+=("--workers")
+    local_nonpersistent_flags+=("--workers=")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_login()
+{
+    last_command="example_login"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--password=")
+    two_word_flags+=("--password")
+    local_nonpersistent_flags+=("--password")
+    local_nonpersistent_flags+=("--password=")
+    flags+=("--password-stdin")
+    local_nonpersistent_flags+=("--password-stdin")
+    flags+=("--token=")
+    two_word_flags+=("--token")
+    local_nonpersistent_flags+=("--token")
+    local_nonpersistent_flags+=("--token=")
+    flags+=("--username=")
+    two_word_flags+=("--username")
+    two_word_flags+=("-u")
+    local_nonpersistent_flags+=("--username")
+    local_nonpersistent_flags+=("--username=")
+    local_nonpersistent_flags+=("-u")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_mirror()
+{
+    last_command="example_mirror"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--ca-file=")
+    two_word_flags+=("--ca-file")
+    local_nonpersistent_flags+=("--ca-file")
+    local_nonpersistent_flags+=("--ca-file=")
+    flags+=("--dest=")
+    two_word_flags+=("--dest")
+    local_nonpersistent_flags+=("--dest")
+    local_nonpersistent_flags+=("--dest=")
+    flags+=("--exclude=")
+    two_word_flags+=("--exclude")
+    local_nonpersistent_flags+=("--exclude")
+    local_nonpersistent_flags+=("--exclude=")
+    flags+=("--include=")
+    two_word_flags+=("--include")
+    local_nonpersistent_flags+=("--include")
+    local_nonpersistent_flags+=("--include=")
+    flags+=("--keep-partial")
+    local_nonpersistent_flags+=("--keep-partial")
+    flags+=("--retries=")
+    two_word_flags+=("--retries")
+    local_nonpersistent_flags+=("--retries")
+    local_nonpersistent_flags+=("--retries=")
+    flags+=("--upstream-url=")
+    two_word_flags+=("--upstream-url")
+    local_nonpersistent_flags+=("--upstream-url")
+    local_nonpersistent_flags+=("--upstream-url=")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_proxy()
+{
+    last_command="example_proxy"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--workdir=")
+    two_word_flags+=("--workdir")
+    two_word_flags+=("-w")
+    local_nonpersistent_flags+=("--workdir")
+    local_nonpersistent_flags+=("--workdir=")
+    local_nonpersistent_flags+=("-w")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_query_save()
+{
+    last_command="example_query_save"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--since=")
+    two_word_flags+=("--since")
+    local_nonpersistent_flags+=("--since")
+    local_nonpersistent_flags+=("--since=")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_query()
+{
+    last_command="example_query"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("save")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--file=")
+    two_word_flags+=("--file")
+    local_nonpersistent_flags+=("--file")
+    local_nonpersistent_flags+=("--file=")
+    flags+=("--format=")
+    two_word_flags+=("--format")
+    local_nonpersistent_flags+=("--format")
+    local_nonpersistent_flags+=("--format=")
+    flags+=("--quiet")
+    flags+=("-q")
+    local_nonpersistent_flags+=("--quiet")
+    local_nonpersistent_flags+=("-q")
+    flags+=("--status=")
+    two_word_flags+=("--status")
+    local_nonpersistent_flags+=("--status")
+    local_nonpersistent_flags+=("--status=")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_run()
+{
+    last_command="example_run"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--color")
+    local_nonpersistent_flags+=("--color")
+    flags+=("--profile")
+    local_nonpersistent_flags+=("--profile")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_search()
+{
+    last_command="example_search"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--context=")
+    two_word_flags+=("--context")
+    two_word_flags+=("-C")
+    local_nonpersistent_flags+=("--context")
+    local_nonpersistent_flags+=("--context=")
+    local_nonpersistent_flags+=("-C")
+    flags+=("--glob=")
+    two_word_flags+=("--glob")
+    two_word_flags+=("-g")
+    local_nonpersistent_flags+=("--glob")
+    local_nonpersistent_flags+=("--glob=")
+    local_nonpersistent_flags+=("-g")
+    flags+=("--hidden")
+    local_nonpersistent_flags+=("--hidden")
+    flags+=("--ignore-case")
+    flags+=("-i")
+    local_nonpersistent_flags+=("--ignore-case")
+    local_nonpersistent_flags+=("-i")
+    flags+=("--line-number")
+    flags+=("-n")
+    local_nonpersistent_flags+=("--line-number")
+    local_nonpersistent_flags+=("-n")
+    flags+=("--max-count=")
+    two_word_flags+=("--max-count")
+    local_nonpersistent_flags+=("--max-count")
+    local_nonpersistent_flags+=("--max-count=")
+    flags+=("--word-regexp")
+    flags+=("-w")
+    local_nonpersistent_flags+=("--word-regexp")
+    local_nonpersistent_flags+=("-w")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_serve()
+{
+    last_command="example_serve"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--allow=")
+    two_word_flags+=("--allow")
+    local_nonpersistent_flags+=("--allow")
+    local_nonpersistent_flags+=("--allow=")
+    flags+=("--bind=")
+    two_word_flags+=("--bind")
+    local_nonpersistent_flags+=("--bind")
+    local_nonpersistent_flags+=("--bind=")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    local_nonpersistent_flags+=("--config")
+    local_nonpersistent_flags+=("--config=")
+    flags+=("--header=")
+    two_word_flags+=("--header")
+    local_nonpersistent_flags+=("--header")
+    local_nonpersistent_flags+=("--header=")
+    flags+=("--key=")
+    two_word_flags+=("--key")
+    local_nonpersistent_flags+=("--key")
+    local_nonpersistent_flags+=("--key=")
+    flags+=("--labels=")
+    two_word_flags+=("--labels")
+    local_nonpersistent_flags+=("--labels")
+    local_nonpersistent_flags+=("--labels=")
+    flags+=("--log-level=")
+    two_word_flags+=("--log-level")
+    local_nonpersistent_flags+=("--log-level")
+    local_nonpersistent_flags+=("--log-level=")
+    flags+=("--max-body=")
+    two_word_flags+=("--max-body")
+    local_nonpersistent_flags+=("--max-body")
+    local_nonpersistent_flags+=("--max-body=")
+    flags+=("--ports=")
+    two_word_flags+=("--ports")
+    local_nonpersistent_flags+=("--ports")
+    local_nonpersistent_flags+=("--ports=")
+    flags+=("--quiet")
+    flags+=("-q")
+    local_nonpersistent_flags+=("--quiet")
+    local_nonpersistent_flags+=("-q")
+    flags+=("--ratio=")
+    two_word_flags+=("--ratio")
+    local_nonpersistent_flags+=("--ratio")
+    local_nonpersistent_flags+=("--ratio=")
+    flags+=("--tags=")
+    two_word_flags+=("--tags")
+    local_nonpersistent_flags+=("--tags")
+    local_nonpersistent_flags+=("--tags=")
+    flags+=("--timeout=")
+    two_word_flags+=("--timeout")
+    local_nonpersistent_flags+=("--timeout")
+    local_nonpersistent_flags+=("--timeout=")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_status()
+{
+    last_command="example_status"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--verbose")
+    flags+=("-v")
+    local_nonpersistent_flags+=("--verbose")
+    local_nonpersistent_flags+=("-v")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    must_have_one_noun+=("api")
+    must_have_one_noun+=("cache")
+    must_have_one_noun+=("db")
+    must_have_one_noun+=("worker")
+    noun_aliases=()
+}
+
+_example_sync()
+{
+    last_command="example_sync"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--jobs=")
+    two_word_flags+=("--jobs")
+    two_word_flags+=("-j")
+    local_nonpersistent_flags+=("--jobs")
+    local_nonpersistent_flags+=("--jobs=")
+    local_nonpersistent_flags+=("-j")
+    flags+=("--quiet")
+    local_nonpersistent_flags+=("--quiet")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_version()
+{
+    last_command="example_version"
+
+    command_aliases=()
+
+    commands=()
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+_example_root_command()
+{
+    last_command="example"
+
+    command_aliases=()
+
+    commands=()
+    commands+=("build")
+    if [[ -z "${BASH_VERSION:-}" || "${BASH_VERSINFO[0]:-}" -gt 3 ]]; then
+        command_aliases+=("b")
+        aliashash["b"]="build"
+        command_aliases+=("make")
+        aliashash["make"]="build"
+    fi
+    commands+=("calc")
+    commands+=("clean")
+    if [[ -z "${BASH_VERSION:-}" || "${BASH_VERSINFO[0]:-}" -gt 3 ]]; then
+        command_aliases+=("rm")
+        aliashash["rm"]="clean"
+    fi
+    commands+=("cluster")
+    if [[ -z "${BASH_VERSION:-}" || "${BASH_VERSINFO[0]:-}" -gt 3 ]]; then
+        command_aliases+=("cl")
+        aliashash["cl"]="cluster"
+        command_aliases+=("clusters")
+        aliashash["clusters"]="cluster"
+    fi
+    commands+=("config")
+    commands+=("convert")
+    commands+=("deploy")
+    commands+=("exec")
+    commands+=("greet")
+    commands+=("init")
+    commands+=("login")
+    commands+=("mirror")
+    commands+=("proxy")
+    commands+=("query")
+    commands+=("run")
+    if [[ -z "${BASH_VERSION:-}" || "${BASH_VERSINFO[0]:-}" -gt 3 ]]; then
+        command_aliases+=("r")
+        aliashash["r"]="run"
+    fi
+    commands+=("search")
+    commands+=("serve")
+    commands+=("status")
+    commands+=("sync")
+    commands+=("version")
+
+    flags=()
+    two_word_flags=()
+    local_nonpersistent_flags=()
+    flags_with_completion=()
+    flags_completion=()
+
+    flags+=("--chdir=")
+    two_word_flags+=("--chdir")
+    two_word_flags+=("-C")
+    local_nonpersistent_flags+=("--chdir")
+    local_nonpersistent_flags+=("--chdir=")
+    local_nonpersistent_flags+=("-C")
+    flags+=("--config=")
+    two_word_flags+=("--config")
+    two_word_flags+=("-c")
+    flags+=("--port=")
+    two_word_flags+=("--port")
+    two_word_flags+=("-p")
+    flags+=("--verbose")
+    flags+=("-v")
+
+    must_have_one_flag=()
+    must_have_one_noun=()
+    noun_aliases=()
+}
+
+__start_example()
+{
+    local cur prev words cword split
+    declare -A flaghash 2>/dev/null || :
+    declare -A aliashash 2>/dev/null || :
+    if declare -F _init_completion >/dev/null 2>&1; then
+        _init_completion -s || return
+    else
+        __example_init_completion -n "=" || return
+    fi
+
+    local c=0
+    local flag_parsing_disabled=
+    local flags=()
+    local two_word_flags=()
+    local local_nonpersistent_flags=()
+    local flags_with_completion=()
+    local flags_completion=()
+    local commands=("example")
+    local command_aliases=()
+    local must_have_one_flag=()
+    local must_have_one_noun=()
+    local has_completion_function=""
+    local last_command=""
+    local nouns=()
+    local noun_aliases=()
+
+    __example_handle_word
+}
+
+if [[ $(type -t compopt) = "builtin" ]]; then
+    complete -o default -F __start_example example
+else
+    complete -o default -o nospace -F __start_example example
+fi
+
+# ex: ts=4 sw=4 et filetype=sh
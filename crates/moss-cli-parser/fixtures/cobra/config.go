@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Read and write project settings",
+}
+
+var configGetCmd = &cobra.Command{
+	Use:                   "get <key>",
+	Short:                 "Print a setting",
+	Args:                  cobra.ExactArgs(1),
+	DisableFlagsInUseLine: true,
+	ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) != 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return settings, cobra.ShellCompDirectiveNoFileComp
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(args[0], "is unset")
+	},
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key>=<value>...",
+	Short: "Change one or more settings",
+	Args:  keyValueArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		for _, arg := range args {
+			fmt.Println("Set", arg)
+		}
+	},
+}
+
+// configPathCmd hides its help flag, leaving it no flags of its own: its help
+// has no Flags section, only the inherited Global Flags.
+var configPathCmd = &cobra.Command{
+	Use:   "path",
+	Short: "Print the path of the settings file",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(".example.toml")
+	},
+}
+
+// configCheckCmd fails at run time, with errors wrapped as they travel up.
+// cobra prints usage after any error RunE returns, so its runtime errors
+// look like usage errors but for the message.
+var configCheckCmd = &cobra.Command{
+	Use:   "check [file]",
+	Short: "Check a settings file",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		file := settingsFile(args)
+		if _, err := readSettings(file); err != nil {
+			return err
+		}
+		fmt.Println(file, "is valid")
+		return nil
+	},
+}
+
+// configImportCmd silences usage once its arguments are checked, as CLIs
+// do to tell runtime errors from usage errors, and exits 3 for settings it
+// does not know.
+var configImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Import settings from a file",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		values, err := readSettings(args[0])
+		if err != nil {
+			return fmt.Errorf("importing settings: %w", err)
+		}
+		for _, kv := range values {
+			key, _, _ := strings.Cut(kv, "=")
+			if !knownSetting(key) {
+				return &exitError{3, fmt.Errorf("importing settings: unknown setting %q", key)}
+			}
+			fmt.Println("Set", kv)
+		}
+		return nil
+	},
+}
+
+// errMalformed is wrapped by readSettings for lines it cannot read.
+var errMalformed = errors.New("expected KEY = VALUE")
+
+// settingsFile is the settings file named by args, else the one --config
+// names, else .example.toml.
+func settingsFile(args []string) string {
+	switch {
+	case len(args) > 0:
+		return args[0]
+	case config != "":
+		return config
+	}
+	return ".example.toml"
+}
+
+// readSettings reads the KEY = VALUE lines of file as KEY=VALUE.
+func readSettings(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, fmt.Errorf("opening config: %w", err)
+	}
+	defer f.Close()
+	var values []string
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		key, value, ok := strings.Cut(sc.Text(), "=")
+		if !ok {
+			return nil, fmt.Errorf("parsing config: %s:%d: %w", file, n, errMalformed)
+		}
+		values = append(values, strings.TrimSpace(key)+"="+strings.Trim(strings.TrimSpace(value), `"`))
+	}
+	return values, sc.Err()
+}
+
+// knownSetting reports whether key is one of settings.
+func knownSetting(key string) bool {
+	for _, s := range settings {
+		if name, _, _ := strings.Cut(s, "\t"); name == key {
+			return true
+		}
+	}
+	return false
+}
+
+// settings are the keys config get completes, with descriptions.
+var settings = []string{
+	"build.target\tDefault build target directory",
+	"run.port\tPort used by example run",
+	"user.name\tName recorded in project metadata",
+}
+
+// keyValueArgs is a custom positional validator: at least one argument, each
+// of the form KEY=VALUE.
+func keyValueArgs(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%q requires at least one KEY=VALUE argument", cmd.CommandPath())
+	}
+	for _, arg := range args {
+		if key, _, ok := strings.Cut(arg, "="); !ok || key == "" {
+			return fmt.Errorf("argument %q is not of the form KEY=VALUE", arg)
+		}
+	}
+	return nil
+}
+
+func init() {
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configPathCmd.Flags().BoolP("help", "h", false, "help for path")
+	configPathCmd.Flags().MarkHidden("help")
+	configCmd.AddCommand(configPathCmd)
+	configCmd.AddCommand(configCheckCmd)
+	configCmd.AddCommand(configImportCmd)
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// convert spells out where flags go in its Use line, so cobra does not append
+// its own [flags].
+var convertCmd = &cobra.Command{
+	Use:   "convert [flags] <input> [output...]",
+	Short: "Convert a file between formats",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
+		fmt.Println("Converting", args[0], "to", args[1:])
+	},
+}
+
+func init() {
+	to := newEnum("format", "json", "json", "yaml", "toml")
+	convertCmd.Flags().Var(to, "to", to.usage("Target format"))
+	convertCmd.Flags().Bool("overwrite", false, "Replace existing output files")
+	// Backticks in a usage string name the flag's value in help, in place of
+	// its type. Only the first quoted word counts, and bools honour it too.
+	convertCmd.Flags().String("log", "", "Write a log of the conversion to `FILE`")
+	convertCmd.MarkFlagFilename("log")
+	convertCmd.Flags().IntP("indent", "i", 2, "Indent nested values by `N` spaces")
+	convertCmd.Flags().String("schema", "", "Validate against `SCHEMA`, then against `BASE` if one is given")
+	convertCmd.MarkFlagFilename("schema", "json", "yaml")
+	convertCmd.Flags().Bool("strict", false, "Fail on `unknown` keys instead of dropping them")
+	convertCmd.Flags().StringSlice("include", nil, "Convert only the keys in `KEY,...`")
+
+	rootCmd.AddCommand(convertCmd)
+}
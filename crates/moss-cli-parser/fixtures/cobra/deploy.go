@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// deploy carries required flags: --env is a persistent required flag that
+// rollback inherits, --image is required locally.
+
+var deployCmd = &cobra.Command{
+	Use:         "deploy",
+	Short:       "Deploy the project",
+	Annotations: map[string]string{"stability": "alpha", "since": "0.9.0", "category": "management"},
+	Run: func(cmd *cobra.Command, args []string) {
+		env, _ := cmd.Flags().GetString("env")
+		image, _ := cmd.Flags().GetString("image")
+		fmt.Println("Deploying", image, "to", env)
+	},
+}
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back the last deployment",
+	Run: func(cmd *cobra.Command, args []string) {
+		env, _ := cmd.Flags().GetString("env")
+		fmt.Println("Rolling back", env)
+	},
+}
+
+func init() {
+	deployCmd.PersistentFlags().StringP("env", "e", "", "Target environment (required)")
+	deployCmd.MarkPersistentFlagRequired("env")
+	deployCmd.Flags().String("image", "", "Image to deploy")
+	deployCmd.MarkFlagRequired("image")
+	deployCmd.Flags().BoolP("yes", "y", false, "Skip confirmation")
+	deployCmd.Flags().String("extremely-long-configuration-override-path", "", "Path to a file whose settings override the environment's deployment configuration")
+	rollbackCmd.Flags().Int("steps", 1, "Number of releases to roll back")
+
+	deployCmd.AddCommand(rollbackCmd)
+	rootCmd.AddCommand(deployCmd)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Deterministic mode makes everything the fixture writes depend only on its
+// source and arguments: flags and commands are sorted, the binary calls
+// itself example whatever its file is named, build metadata is zeroed, and
+// generated docs are dated SOURCE_DATE_EPOCH, or the Unix epoch, instead of
+// today. Build with -tags deterministic, pass --deterministic before any
+// other argument, or set EXAMPLE_DETERMINISTIC=1 to turn it on; the last is
+// how genGolden passes it to the processes it starts.
+func isDeterministic() bool {
+	return deterministicBuild || os.Getenv("EXAMPLE_DETERMINISTIC") == "1"
+}
+
+// takeDeterministicFlag removes a leading --deterministic from os.Args,
+// turning deterministic mode on for this process and those it starts.
+func takeDeterministicFlag() {
+	if len(os.Args) > 1 && os.Args[1] == "--deterministic" {
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+		os.Setenv("EXAMPLE_DETERMINISTIC", "1")
+	}
+}
+
+func makeDeterministic(root *cobra.Command) {
+	os.Args[0] = root.Name()
+	commit = "0000000"
+	buildDate = time.Unix(0, 0).UTC().Format(time.RFC3339)
+	cobra.EnableCommandSorting = true
+	walk(root, func(cmd *cobra.Command) {
+		cmd.Flags().SortFlags = true
+		cmd.PersistentFlags().SortFlags = true
+	})
+}
+
+// docDate is the date deterministic docs carry.
+func docDate() time.Time {
+	if epoch, err := strconv.ParseInt(os.Getenv("SOURCE_DATE_EPOCH"), 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC()
+	}
+	return time.Unix(0, 0).UTC()
+}
+
+// autoGenRE matches the footer cobra/doc dates with today's date in
+// Markdown and ReST docs.
+var autoGenRE = regexp.MustCompile(`(Auto generated by spf13/cobra on )\d{1,2}-[A-Z][a-z]{2}-\d{4}`)
+
+// pinDocDates redates the footer of every doc in dir to docDate, in the
+// format cobra/doc writes it.
+func pinDocDates(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"))
+	if err != nil {
+		return err
+	}
+	date := []byte("${1}" + docDate().Format("2-Jan-2006"))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, autoGenRE.ReplaceAll(data, date), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,5 @@
+//go:build deterministic
+
+package main
+
+const deterministicBuild = true
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// Flags can be bound to environment variables through viper, the usual
+// companion to cobra. Bound flags advertise the variable in their usage, and
+// a variable that is set fills in any flag not given on the command line.
+
+var environ = viper.New()
+
+// envBinding is a flag bound to an environment variable by bindEnv.
+type envBinding struct {
+	fs   *pflag.FlagSet
+	name string
+}
+
+// envFlags are the flags bound by bindEnv, keyed by variable name.
+var envFlags = map[string]envBinding{}
+
+// bindEnv binds the flag name in fs to the environment variable env.
+func bindEnv(fs *pflag.FlagSet, name, env string) {
+	f := fs.Lookup(name)
+	f.Usage += fmt.Sprintf(" (env: %s)", env)
+	environ.BindPFlag(env, f)
+	environ.BindEnv(env, env)
+	envFlags[env] = envBinding{fs, name}
+}
+
+// loadEnv copies environment values into flags left unset.
+func loadEnv() {
+	for env, b := range envFlags {
+		if !b.fs.Lookup(b.name).Changed && environ.IsSet(env) {
+			if err := b.fs.Set(b.name, environ.GetString(env)); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: invalid %s: %v\n", env, err)
+				os.Exit(1)
+			}
+		}
+	}
+}
+
+func init() {
+	cobra.OnInitialize(loadEnv)
+}
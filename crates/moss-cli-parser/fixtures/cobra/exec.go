@@ -0,0 +1,27 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// exec runs another command, POSIX style: its flags end at the first
+// positional, so everything from the command name on reaches the command as
+// given, flags, --help and -- included, with no -- needed.
+var execCmd = &cobra.Command{
+	Use:   "exec [flags] <command> [args...]",
+	Short: "Run a command in the project environment",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
+		fmt.Println("Executing", args[0], "with", args[1:])
+	},
+}
+
+func init() {
+	execCmd.Flags().StringArrayP("env", "e", nil, "Set an environment variable, as KEY=VALUE")
+	execCmd.Flags().Bool("dry-run", false, "Print the command instead of running it")
+	execCmd.Flags().SetInterspersed(false)
+	rootCmd.AddCommand(execCmd)
+}
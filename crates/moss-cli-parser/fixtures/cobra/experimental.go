@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Experimental features change the command tree itself rather than how it
+// is shown: a watch command, a --incremental flag on build and a new default
+// for build --jobs. They are switched on by EXAMPLE_EXPERIMENTAL=1, or by
+// experimental = true in the settings file EXAMPLE_CONFIG names, else
+// .example.toml, so the same binary has two trees depending on where it
+// runs.
+
+// experimentalJobs is the build --jobs default under experimental features.
+const experimentalJobs = "4"
+
+var watchCmd = &cobra.Command{
+	Use:         "watch [flags] [path...]",
+	Short:       "Rebuild whenever a source file changes",
+	Annotations: map[string]string{"stability": "experimental", "since": "0.9.0", "category": "build"},
+	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
+		fmt.Println("Watching", args)
+	},
+}
+
+func init() {
+	watchCmd.Flags().Duration("interval", 500*time.Millisecond, "How often to poll for changes")
+	watchCmd.Flags().StringSlice("ignore", []string{".git"}, "Paths never to watch")
+}
+
+// experimental reports whether experimental features are switched on.
+func experimental() bool {
+	if v, ok := os.LookupEnv("EXAMPLE_EXPERIMENTAL"); ok {
+		return v == "1"
+	}
+	file := os.Getenv("EXAMPLE_CONFIG")
+	if file == "" {
+		file = ".example.toml"
+	}
+	values, _ := readSettings(file)
+	for _, v := range values {
+		if v == "experimental=true" {
+			return true
+		}
+	}
+	return false
+}
+
+// loadExperimental adds the experimental features to root when they are
+// switched on.
+func loadExperimental(root *cobra.Command) {
+	if !experimental() {
+		return
+	}
+	root.AddCommand(watchCmd)
+	buildCmd.Flags().Bool("incremental", false, "Rebuild only the packages that changed")
+	buildCmd.Flags().SetAnnotation("incremental", "stability", []string{"experimental"})
+	jobs := buildCmd.Flags().Lookup("jobs")
+	jobs.DefValue = experimentalJobs
+	jobs.Value.Set(experimentalJobs)
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"cobratree"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+	"github.com/spf13/pflag"
+)
+
+// generators write fixture files derived from the command tree instead of
+// running it. They are selected by a leading -gen-<name> <dir> argument,
+// e.g. ./example -gen-man man/, which cobra never sees.
+var generators = map[string]func(root *cobra.Command, dir string) error{
+	"aliases":     genAliases,
+	"annotations": genAnnotations,
+	"complete":    genComplete,
+	"bash-v1":     genBashV1,
+	"forms":       genForms,
+	"golden":      genGolden,
+	"help-json":   genHelpJSON,
+	"man":         genMan,
+	"markdown":    genMarkdown,
+	"rest":        genReST,
+	"tree":        genTree,
+	"yaml":        doc.GenYamlTree,
+}
+
+// runGenerator runs the generator named by args, reporting whether args
+// selected one at all.
+func runGenerator(root *cobra.Command, args []string) (bool, error) {
+	if len(args) == 0 || !strings.HasPrefix(args[0], "-gen-") {
+		return false, nil
+	}
+	name := strings.TrimPrefix(args[0], "-gen-")
+	gen, ok := generators[name]
+	if !ok {
+		return true, fmt.Errorf("unknown generator %q", name)
+	}
+	if len(args) != 2 {
+		return true, fmt.Errorf("usage: %s -gen-%s <dir>", root.Name(), name)
+	}
+	if err := os.MkdirAll(args[1], 0o755); err != nil {
+		return true, err
+	}
+	return true, gen(root, args[1])
+}
+
+// genBashV1 writes the bash completion script cobra generated before V2,
+// which lists every command and flag itself where V2 asks the program, as
+// <name>-v1.bash.
+func genBashV1(root *cobra.Command, dir string) error {
+	return root.GenBashCompletionFile(filepath.Join(dir, root.Name()+"-v1.bash"))
+}
+
+// genMan writes one roff page per command. The date comes from
+// SOURCE_DATE_EPOCH when set, and is the Unix epoch otherwise in
+// deterministic mode.
+func genMan(root *cobra.Command, dir string) error {
+	header := &doc.GenManHeader{Section: "1"}
+	if isDeterministic() {
+		date := docDate()
+		header.Date = &date
+	}
+	return doc.GenManTree(root, header, dir)
+}
+
+// genMarkdown writes one Markdown page per command. cobra/doc dates them
+// today, whatever SOURCE_DATE_EPOCH says, so deterministic mode redates
+// them afterwards.
+func genMarkdown(root *cobra.Command, dir string) error {
+	if err := doc.GenMarkdownTree(root, dir); err != nil || !isDeterministic() {
+		return err
+	}
+	return pinDocDates(dir)
+}
+
+// genReST writes one reStructuredText page per command, dated as
+// genMarkdown dates its pages.
+func genReST(root *cobra.Command, dir string) error {
+	if err := doc.GenReSTTree(root, dir); err != nil || !isDeterministic() {
+		return err
+	}
+	return pinDocDates(dir)
+}
+
+// commandAnnotations is the JSON written by genAnnotations.
+type commandAnnotations struct {
+	Command     string                         `json:"command"`
+	Annotations map[string]string              `json:"annotations,omitempty"`
+	Flags       map[string]map[string][]string `json:"flags,omitempty"`
+}
+
+// genAnnotations writes the Annotations of each command and of its local
+// flags, including those cobra sets itself, as example-<path>.annotations.json.
+// Commands with neither are skipped.
+func genAnnotations(root *cobra.Command, dir string) error {
+	var err error
+	walk(root, func(cmd *cobra.Command) {
+		if err != nil {
+			return
+		}
+		out := commandAnnotations{Command: cmd.CommandPath(), Annotations: cmd.Annotations}
+		cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+			if len(f.Annotations) == 0 {
+				return
+			}
+			if out.Flags == nil {
+				out.Flags = map[string]map[string][]string{}
+			}
+			out.Flags[f.Name] = f.Annotations
+		})
+		if out.Annotations == nil && out.Flags == nil {
+			return
+		}
+		data, jerr := json.MarshalIndent(out, "", "  ")
+		if jerr != nil {
+			err = jerr
+			return
+		}
+		name := strings.ReplaceAll(cmd.CommandPath(), " ", "-") + ".annotations.json"
+		err = os.WriteFile(filepath.Join(dir, name), append(data, '\n'), 0o644)
+	})
+	return err
+}
+
+// genGolden writes the --help output of every command in the tree, help
+// topics and cobra's own help and completion commands included, as
+// example-<path>.help. Help is captured by running this binary again, so
+// the files match what a user sees byte for byte. Hidden commands are
+// skipped, as they are from help.
+func genGolden(root *cobra.Command, dir string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	walkDocumented(root, func(cmd *cobra.Command) {
+		if err != nil {
+			return
+		}
+		path := strings.Fields(cmd.CommandPath())
+		out, rerr := exec.Command(self, append(path[1:], "--help")...).Output()
+		if rerr != nil {
+			err = fmt.Errorf("%s --help: %w", cmd.CommandPath(), rerr)
+			return
+		}
+		name := strings.Join(path, "-") + ".help"
+		err = os.WriteFile(filepath.Join(dir, name), out, 0o644)
+	})
+	return err
+}
+
+// walkDocumented calls fn for each command help lists, cobra's own help
+// and completion commands included: all but the hidden and deprecated.
+func walkDocumented(root *cobra.Command, fn func(*cobra.Command)) {
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+	walk(root, func(cmd *cobra.Command) {
+		if cmd.Hidden {
+			return
+		}
+		if cmd != root && cmd.Name() != "help" && !cmd.IsAvailableCommand() && !cmd.IsAdditionalHelpTopicCommand() {
+			return
+		}
+		fn(cmd)
+	})
+}
+
+// helpForms are the ways of asking a command for help, each with the
+// directory genForms writes what it printed to and the arguments it runs
+// for a command's path.
+var helpForms = []struct {
+	name, dir string
+	args      func(path []string) []string
+}{
+	{"--help", "long", func(path []string) []string { return append(path, "--help") }},
+	{"-h", "short", func(path []string) []string { return append(path, "-h") }},
+	{"help", "command", func(path []string) []string { return append([]string{"help"}, path...) }},
+}
+
+// commandForms is one command's entry in the example.forms.json genForms
+// writes: the file each form of asking for its help printed, by form, and
+// relative to the directory of example.forms.json.
+type commandForms struct {
+	Command string            `json:"command"`
+	Forms   map[string]string `json:"forms"`
+	// Identical is set when every form printed the same.
+	Identical bool `json:"identical"`
+}
+
+// genForms writes the help of every command genGolden covers once per
+// form in helpForms, as <form dir>/example-<path>.help, and links the
+// files of each command in example.forms.json. Every form prints to stdout and
+// exits 0, or genForms fails.
+func genForms(root *cobra.Command, dir string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	var index []commandForms
+	walkDocumented(root, func(cmd *cobra.Command) {
+		if err != nil {
+			return
+		}
+		path := strings.Fields(cmd.CommandPath())
+		entry := commandForms{Command: cmd.CommandPath(), Forms: map[string]string{}, Identical: true}
+		var first []byte
+		for i, form := range helpForms {
+			args := form.args(path[1:])
+			out, rerr := exec.Command(self, args...).Output()
+			if rerr != nil {
+				err = fmt.Errorf("%s %s: %w", path[0], strings.Join(args, " "), rerr)
+				return
+			}
+			name := form.dir + "/" + strings.Join(path, "-") + ".help"
+			if err = os.MkdirAll(filepath.Join(dir, form.dir), 0o755); err != nil {
+				return
+			}
+			if err = os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), out, 0o644); err != nil {
+				return
+			}
+			entry.Forms[form.name] = name
+			if i == 0 {
+				first = out
+			} else if string(out) != string(first) {
+				entry.Identical = false
+			}
+		}
+		index = append(index, entry)
+	})
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, "example.forms.json"), append(data, '\n'), 0o644)
+}
+
+// genTree writes the whole command tree, with cobra's default help and
+// completion commands and help and version flags added as Execute would, to
+// example.tree.json. It is the machine-readable ground truth for the help
+// fixtures.
+func genTree(root *cobra.Command, dir string) error {
+	return cobratree.Write(root, filepath.Join(dir, "example.tree.json"))
+}
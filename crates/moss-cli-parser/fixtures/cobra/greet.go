@@ -0,0 +1,55 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// greet puts emoji, CJK and combining marks into names, descriptions and
+// defaults. cobra and pflag size their columns in bytes, so multi-byte and
+// double-width text knocks the alignment out of true.
+
+var greetCmd = &cobra.Command{
+	Use:   "greet",
+	Short: "Say hello 👋 in several languages",
+	Long: "Say hello 👋 in several languages.\n\n" +
+		"Greetings are printed in the native script: 日本語, 中文, 한국어, Ελληνικά and\n" +
+		"Deutsch all work, as do decomposed accents like cafe\u0301 and nai\u0308ve.",
+}
+
+var greetGermanCmd = &cobra.Command{
+	Use:   "grüße",
+	Short: "Grüße auf Deutsch 🇩🇪",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Guten Tag!")
+	},
+}
+
+var greetJapaneseCmd = &cobra.Command{
+	Use:   "こんにちは",
+	Short: "日本語で挨拶する 🎌",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("こんにちは、世界！")
+	},
+}
+
+var greetFrenchCmd = &cobra.Command{
+	Use:   "café",
+	Short: "Salut depuis le café ☕",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Bonjour !")
+	},
+}
+
+func init() {
+	greetCmd.PersistentFlags().String("name", "世界", "Who to greet 🌏")
+	greetCmd.PersistentFlags().String("名前", "", "挨拶する相手の名前")
+	greetCmd.PersistentFlags().Bool("naïve", false, "Skip locale detection")
+	greetCmd.PersistentFlags().StringP("emoji", "e", "🎉", "Emoji to append to the greeting")
+
+	greetCmd.AddCommand(greetGermanCmd)
+	greetCmd.AddCommand(greetJapaneseCmd)
+	greetCmd.AddCommand(greetFrenchCmd)
+	rootCmd.AddCommand(greetCmd)
+}
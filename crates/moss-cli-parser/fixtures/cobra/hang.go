@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// Some CLIs take their time printing help, or never finish: they wait for
+// input, call out to something that hangs, or draw slowly over a network.
+// EXAMPLE_HANG=<mode>[@<path>] makes the help of the command at path, its
+// words below the root such as "cluster node", or of every command without
+// one, misbehave in one of these ways, so a driver's timeout, what it keeps
+// of partial output and how it kills can be tried on a binary that does:
+//
+//   - stdin reads its input to the end before printing help;
+//   - hold prints nothing and blocks;
+//   - sleep prints help up to its first blank line and blocks;
+//   - stubborn sleeps, ignoring interrupt, terminate and hangup, so only a
+//     kill ends it;
+//   - slow prints help a line at a time, EXAMPLE_HANG_DELAY apart, 100ms
+//     by default;
+//   - orphan prints help and exits, leaving a process behind that holds
+//     stdout open and blocks.
+//
+// Only help is affected; the commands run as ever.
+
+// defaultHangDelay is how long slow waits between lines.
+const defaultHangDelay = 100 * time.Millisecond
+
+// loadHang makes help misbehave as EXAMPLE_HANG says.
+func loadHang(root *cobra.Command) {
+	setting := os.Getenv("EXAMPLE_HANG")
+	if setting == "" {
+		return
+	}
+	mode, path, scoped := strings.Cut(setting, "@")
+	switch mode {
+	case "stdin", "hold", "sleep", "stubborn", "slow", "orphan":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown EXAMPLE_HANG mode %q\n", mode)
+		os.Exit(2)
+	}
+	delay := defaultHangDelay
+	if v := os.Getenv("EXAMPLE_HANG_DELAY"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "invalid EXAMPLE_HANG_DELAY %q: %v\n", v, err)
+			os.Exit(2)
+		}
+		delay = d
+	}
+	help := root.HelpFunc()
+	root.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		if scoped && strings.Join(strings.Fields(cmd.CommandPath())[1:], " ") != path {
+			help(cmd, args)
+			return
+		}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		help(cmd, args)
+		cmd.SetOut(nil)
+		hang(cmd.OutOrStdout(), mode, buf.String(), delay)
+	})
+}
+
+// hang prints text, the help, to w as mode misbehaves.
+func hang(w io.Writer, mode, text string, delay time.Duration) {
+	switch mode {
+	case "stdin":
+		io.Copy(io.Discard, os.Stdin)
+		io.WriteString(w, text)
+	case "hold":
+		block()
+	case "stubborn":
+		signal.Ignore(os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+		fallthrough
+	case "sleep":
+		head, _, _ := strings.Cut(text, "\n\n")
+		io.WriteString(w, head+"\n\n")
+		block()
+	case "slow":
+		for _, line := range strings.SplitAfter(text, "\n") {
+			io.WriteString(w, line)
+			time.Sleep(delay)
+		}
+	case "orphan":
+		self, err := os.Executable()
+		if err == nil {
+			child := exec.Command(self, os.Args[1:]...)
+			child.Env = append(os.Environ(), "EXAMPLE_HANG=hold")
+			child.Stdout = os.Stdout
+			err = child.Start()
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		io.WriteString(w, text)
+	}
+}
+
+// block never returns. An empty select would have the runtime exit, seeing
+// nothing left to wake it.
+func block() {
+	for {
+		time.Sleep(time.Hour)
+	}
+}
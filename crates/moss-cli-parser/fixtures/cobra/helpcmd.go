@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// renameHelp replaces the built-in help command with an equivalent one
+// called explain, so help <cmd> no longer works but explain <cmd> does.
+// cobra's usage template lists the help command only if it is named help,
+// so explain works without appearing under "Available Commands:".
+func renameHelp(root *cobra.Command) {
+	root.SetHelpCommand(&cobra.Command{
+		Use:   "explain [command]",
+		Short: "Explain any command",
+		Run: func(c *cobra.Command, args []string) {
+			cmd, _, err := c.Root().Find(args)
+			if cmd == nil || err != nil {
+				c.Printf("Unknown help topic %#q\n", args)
+				cobra.CheckErr(c.Root().Usage())
+				return
+			}
+			cmd.InitDefaultHelpFlag()
+			cmd.InitDefaultVersionFlag()
+			cobra.CheckErr(cmd.Help())
+		},
+	})
+}
+
+// replaceHelp swaps the help command for one that prints a terse summary
+// instead of the command's full help, and fails on unknown commands.
+func replaceHelp(root *cobra.Command) {
+	root.SetHelpCommand(&cobra.Command{
+		Use:   "help [command]",
+		Short: "Summarize a command",
+		RunE: func(c *cobra.Command, args []string) error {
+			cmd, rest, err := c.Root().Find(args)
+			if err != nil || len(rest) > 0 {
+				return fmt.Errorf("no help for %q", strings.Join(args, " "))
+			}
+			fmt.Fprintf(c.OutOrStdout(), "%s: %s\n", cmd.CommandPath(), cmd.Short)
+			var names []string
+			for _, sub := range cmd.Commands() {
+				if sub.IsAvailableCommand() {
+					names = append(names, sub.Name())
+				}
+			}
+			if len(names) > 0 {
+				fmt.Fprintf(c.OutOrStdout(), "subcommands: %s\n", strings.Join(names, ", "))
+			}
+			return nil
+		},
+	})
+}
+
+// removeHelp disables the help and completion commands, leaving --help as
+// the only way to ask for help.
+func removeHelp(root *cobra.Command) {
+	root.SetHelpCommand(&cobra.Command{Hidden: true})
+	root.CompletionOptions.DisableDefaultCmd = true
+}
+
+// removeCompletion disables the completion command alone.
+func removeCompletion(root *cobra.Command) {
+	root.CompletionOptions.DisableDefaultCmd = true
+}
+
+// hideCompletion keeps the completion command but leaves it out of
+// "Available Commands:", where cobra lists it by default.
+func hideCompletion(root *cobra.Command) {
+	root.CompletionOptions.HiddenDefaultCmd = true
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// The help-json variant gives every command a --help-json flag, which asks
+// for help as --help does but has it printed as JSON: the structure cobra's
+// help template lays out, each field as the program declared it rather than
+// as the template prints it. No CLI prints it yet; it prototypes the
+// structured help a parser could read in place of text.
+
+// helpJSONFormat names the JSON's format and its version.
+const helpJSONFormat = "cli-help/v1"
+
+type helpJSON struct {
+	Format string `json:"format"`
+	Path   string `json:"path"`
+	// Usage are the lines of the Usage: section.
+	Usage    []string      `json:"usage"`
+	Aliases  []string      `json:"aliases,omitempty"`
+	Short    string        `json:"short,omitempty"`
+	Long     string        `json:"long,omitempty"`
+	Examples string        `json:"examples,omitempty"`
+	Commands []helpJSONCmd `json:"commands,omitempty"`
+	Topics   []helpJSONCmd `json:"help_topics,omitempty"`
+	// Flags are those help lists under Flags: and InheritedFlags those under
+	// Global Flags:.
+	Flags          []helpJSONFlag `json:"flags,omitempty"`
+	InheritedFlags []helpJSONFlag `json:"inherited_flags,omitempty"`
+}
+
+type helpJSONCmd struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+	Short   string   `json:"short,omitempty"`
+	// Group is the title of the group the command is listed in, empty for
+	// commands in none.
+	Group string `json:"group,omitempty"`
+}
+
+// helpJSONFlag is a flag by the names help lists it by: a shorthand-only
+// flag without its long name, and a flag whose shorthand is deprecated
+// without the shorthand.
+type helpJSONFlag struct {
+	Name      string `json:"name,omitempty"`
+	Shorthand string `json:"shorthand,omitempty"`
+	// Type is the pflag type, Value the placeholder help prints for it, and
+	// Usage the description without the backquotes naming a placeholder.
+	Type         string `json:"type"`
+	Value        string `json:"value,omitempty"`
+	Usage        string `json:"usage"`
+	Default      string `json:"default"`
+	NoOptDefault string `json:"no_opt_default,omitempty"`
+	Deprecated   string `json:"deprecated,omitempty"`
+}
+
+// helpJSONFlagValue is the value of one command's --help-json. Setting it
+// sets the command's help flag too, so cobra goes on to print help.
+type helpJSONFlagValue struct {
+	cmd *cobra.Command
+	on  bool
+}
+
+func (v *helpJSONFlagValue) String() string { return strconv.FormatBool(v.on) }
+func (v *helpJSONFlagValue) Type() string   { return "bool" }
+
+func (v *helpJSONFlagValue) Set(s string) error {
+	on, err := strconv.ParseBool(s)
+	if err != nil {
+		return err
+	}
+	v.on = on
+	if on {
+		return v.cmd.Flags().Set("help", "true")
+	}
+	return nil
+}
+
+// helpJSONFlagName is the flag the help-json variant adds.
+const helpJSONFlagName = "help-json"
+
+// addHelpJSON adds a hidden --help-json to every command, cobra's help and
+// completion commands included, and prints help as JSON when it is set.
+func addHelpJSON(root *cobra.Command) {
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+	walk(root, func(cmd *cobra.Command) {
+		f := cmd.Flags().VarPF(&helpJSONFlagValue{cmd: cmd}, helpJSONFlagName, "", "Print help as JSON")
+		f.NoOptDefVal = "true"
+		f.Hidden = true
+	})
+	help := root.HelpFunc()
+	root.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		if f := cmd.Flags().Lookup(helpJSONFlagName); f == nil || !f.Changed {
+			help(cmd, args)
+			return
+		}
+		data, err := json.MarshalIndent(describeHelp(cmd), "", "  ")
+		if err != nil {
+			cmd.PrintErrln(err)
+			return
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n", data)
+	})
+}
+
+// describeHelp returns cmd's help as --help-json prints it.
+func describeHelp(cmd *cobra.Command) helpJSON {
+	out := helpJSON{
+		Format:   helpJSONFormat,
+		Path:     cmd.CommandPath(),
+		Usage:    []string{},
+		Aliases:  cmd.Aliases,
+		Short:    cmd.Short,
+		Long:     cmd.Long,
+		Examples: cmd.Example,
+	}
+	if !cmd.Runnable() && !cmd.HasSubCommands() {
+		// Help shows only the text of a help topic.
+		return out
+	}
+	if cmd.Runnable() {
+		out.Usage = append(out.Usage, cmd.UseLine())
+	}
+	if cmd.HasAvailableSubCommands() {
+		out.Usage = append(out.Usage, cmd.CommandPath()+" [command]")
+	}
+	titles := map[string]string{}
+	for _, g := range cmd.Groups() {
+		titles[g.ID] = strings.TrimSuffix(g.Title, ":")
+	}
+	for _, sub := range cmd.Commands() {
+		c := helpJSONCmd{Name: sub.Name(), Aliases: sub.Aliases, Short: sub.Short, Group: titles[sub.GroupID]}
+		switch {
+		case sub.IsAvailableCommand() || sub.Name() == "help":
+			out.Commands = append(out.Commands, c)
+		case sub.IsAdditionalHelpTopicCommand():
+			out.Topics = append(out.Topics, c)
+		}
+	}
+	out.Flags = describeHelpFlags(cmd.LocalFlags())
+	out.InheritedFlags = describeHelpFlags(cmd.InheritedFlags())
+	return out
+}
+
+// describeHelpFlags returns the flags of fs help lists.
+func describeHelpFlags(fs *pflag.FlagSet) []helpJSONFlag {
+	var out []helpJSONFlag
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		value, usage := pflag.UnquoteUsage(f)
+		name, shorthand := f.Name, f.Shorthand
+		if _, ok := f.Annotations[shorthandOnly]; ok {
+			name = ""
+		}
+		if f.ShorthandDeprecated != "" {
+			shorthand = ""
+		}
+		out = append(out, helpJSONFlag{
+			Name:         name,
+			Shorthand:    shorthand,
+			Type:         f.Value.Type(),
+			Value:        value,
+			Usage:        usage,
+			Default:      f.DefValue,
+			NoOptDefault: f.NoOptDefVal,
+			Deprecated:   f.Deprecated,
+		})
+	})
+	return out
+}
+
+// genHelpJSON writes what --help-json prints, with the help-json variant
+// on, for every command genGolden covers, as example-<path>.json, the JSON
+// each command's golden help was printed from.
+func genHelpJSON(root *cobra.Command, dir string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	walkDocumented(root, func(cmd *cobra.Command) {
+		if err != nil {
+			return
+		}
+		path := strings.Fields(cmd.CommandPath())
+		run := exec.Command(self, append(path[1:], "--"+helpJSONFlagName)...)
+		run.Env = append(os.Environ(), "EXAMPLE_VARIANT=help-json")
+		out, rerr := run.Output()
+		if rerr != nil {
+			err = fmt.Errorf("%s --%s: %w", cmd.CommandPath(), helpJSONFlagName, rerr)
+			return
+		}
+		name := strings.Join(path, "-") + ".json"
+		err = os.WriteFile(filepath.Join(dir, name), out, 0o644)
+	})
+	return err
+}
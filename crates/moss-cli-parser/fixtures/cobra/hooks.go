@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// hooks installs run hooks that print trace lines to stdout: persistent ones
+// on the root and on cluster, and local ones on build. cobra runs only the
+// nearest persistent hook unless EnableTraverseRunHooks is set, which the
+// traverse-hooks variant does.
+func hooks(root *cobra.Command) {
+	root.PersistentPreRun = trace(root, "persistent pre-run")
+	root.PersistentPostRun = trace(root, "persistent post-run")
+	clusterCmd.PersistentPreRun = trace(clusterCmd, "persistent pre-run")
+	clusterCmd.PersistentPostRun = trace(clusterCmd, "persistent post-run")
+	buildCmd.PreRun = trace(buildCmd, "pre-run")
+	buildCmd.PostRun = trace(buildCmd, "post-run")
+}
+
+// traverseHooks is hooks with every ancestor's persistent hooks run too.
+func traverseHooks(root *cobra.Command) {
+	hooks(root)
+	cobra.EnableTraverseRunHooks = true
+}
+
+// trace returns a hook reporting which command owns it and which is running.
+func trace(owner *cobra.Command, stage string) func(*cobra.Command, []string) {
+	return func(cmd *cobra.Command, args []string) {
+		fmt.Fprintf(cmd.OutOrStdout(), "trace: %s %s (running %s)\n", owner.CommandPath(), stage, cmd.CommandPath())
+	}
+}
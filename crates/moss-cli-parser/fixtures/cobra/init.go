@@ -0,0 +1,42 @@
+package main
+
+import (
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// init's flags pair zero and non-zero defaults of each type, covering every
+// "(default ...)" rendering pflag has as well as the cases where it omits one.
+
+var initCmd = &cobra.Command{
+	Use:   "init [dir]",
+	Short: "Create a new project",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
+	},
+}
+
+func init() {
+	f := initCmd.Flags()
+	f.String("name", "", "Project name (defaults to the directory name)")
+	f.String("template", "basic", "Template to start from")
+	f.String("separator", ",", "Separator for generated lists")
+	f.Int("retries", 0, "Retries for template downloads")
+	f.Int("workers", 4, "Parallel template workers")
+	f.Bool("force", false, "Overwrite existing files")
+	f.Bool("git", true, "Initialise a git repository")
+	f.Float64("threshold", 0.75, "Similarity threshold for merges")
+	f.Float64("jitter", 0, "Random delay factor")
+	f.Duration("wait", 0, "Wait before starting")
+	f.Duration("grace", 90*time.Second, "Grace period for slow hooks")
+	f.StringSlice("exclude", nil, "Paths to leave out")
+	f.StringSlice("ignore", []string{}, "Patterns to add to .gitignore")
+	f.StringSlice("languages", []string{"go", "rust"}, "Languages to scaffold")
+	f.IntSlice("ports", []int{80, 443}, "Ports to expose")
+	f.StringToString("meta", map[string]string{"owner": "core"}, "Metadata as key=value pairs")
+	f.StringToString("env", nil, "Environment as key=value pairs")
+
+	rootCmd.AddCommand(initCmd)
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// translation is the text cobra prints itself, in one language.
+type translation struct {
+	// headers maps the English strings of cobra's usage template to their
+	// translations.
+	headers []string
+	// helpFor prefixes the help flag's usage; cobra's is "help for".
+	helpFor string
+	// helpCmd and completionCmd are the Shorts of the default help and
+	// completion commands.
+	helpCmd, completionCmd string
+}
+
+// translations are keyed by the language part of a POSIX locale name, e.g.
+// de for de_DE.UTF-8. French puts a space before colons, as its typography
+// wants.
+var translations = map[string]translation{
+	"de": {
+		headers: []string{
+			"Usage:", "Verwendung:",
+			"Aliases:", "Aliase:",
+			"Examples:", "Beispiele:",
+			"Available Commands:", "Verfügbare Befehle:",
+			"Additional Commands:", "Weitere Befehle:",
+			"Global Flags:", "Globale Optionen:",
+			"Flags:", "Optionen:",
+			"Additional help topics:", "Weitere Hilfethemen:",
+			`Use "{{.CommandPath}} [command] --help" for more information about a command.`,
+			`Verwenden Sie "{{.CommandPath}} [Befehl] --help" für weitere Informationen zu einem Befehl.`,
+			"[command]", "[Befehl]",
+		},
+		helpFor:       "Hilfe für",
+		helpCmd:       "Hilfe zu einem beliebigen Befehl",
+		completionCmd: "Autovervollständigungsskript für die angegebene Shell erzeugen",
+	},
+	"es": {
+		headers: []string{
+			"Usage:", "Uso:",
+			"Aliases:", "Alias:",
+			"Examples:", "Ejemplos:",
+			"Available Commands:", "Comandos disponibles:",
+			"Additional Commands:", "Comandos adicionales:",
+			"Global Flags:", "Opciones globales:",
+			"Flags:", "Opciones:",
+			"Additional help topics:", "Temas de ayuda adicionales:",
+			`Use "{{.CommandPath}} [command] --help" for more information about a command.`,
+			`Use "{{.CommandPath}} [comando] --help" para más información sobre un comando.`,
+			"[command]", "[comando]",
+		},
+		helpFor:       "ayuda para",
+		helpCmd:       "Ayuda sobre cualquier comando",
+		completionCmd: "Generar el script de autocompletado para el shell indicado",
+	},
+	"fr": {
+		headers: []string{
+			"Usage:", "Utilisation :",
+			"Aliases:", "Alias :",
+			"Examples:", "Exemples :",
+			"Available Commands:", "Commandes disponibles :",
+			"Additional Commands:", "Commandes supplémentaires :",
+			"Global Flags:", "Options globales :",
+			"Flags:", "Options :",
+			"Additional help topics:", "Rubriques d'aide supplémentaires :",
+			`Use "{{.CommandPath}} [command] --help" for more information about a command.`,
+			`Utilisez "{{.CommandPath}} [commande] --help" pour plus d'informations sur une commande.`,
+			"[command]", "[commande]",
+		},
+		helpFor:       "aide pour",
+		helpCmd:       "Aide sur n'importe quelle commande",
+		completionCmd: "Générer le script d'autocomplétion pour le shell indiqué",
+	},
+}
+
+// locale returns the language of the message locale, looked up as POSIX
+// does: LC_ALL, then LC_MESSAGES, then LANG.
+func locale() string {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(name); value != "" {
+			lang, _, _ := strings.Cut(value, ".")
+			lang, _, _ = strings.Cut(lang, "_")
+			return lang
+		}
+	}
+	return ""
+}
+
+// localize translates cobra's usage template, help flags and default
+// commands into the language of the locale, as a CLI shipping translated
+// templates would. Locales without a translation, C and POSIX among them,
+// keep cobra's English.
+func localize(root *cobra.Command) {
+	tr, ok := translations[locale()]
+	if !ok {
+		return
+	}
+	root.SetUsageTemplate(strings.NewReplacer(tr.headers...).Replace(root.UsageTemplate()))
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+	walk(root, func(cmd *cobra.Command) {
+		switch cmd.Name() {
+		case "help":
+			cmd.Short = tr.helpCmd
+		case "completion":
+			cmd.Short = tr.completionCmd
+		}
+		if cmd.Flags().Lookup("help") == nil {
+			cmd.Flags().BoolP("help", "h", false, tr.helpFor+" "+cmd.Name())
+		}
+	})
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// login exercises cobra's flag groups: a credential must be given in exactly
+// one way, and a password is only meaningful together with a username.
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in to the registry",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Logged in")
+	},
+}
+
+func init() {
+	loginCmd.Flags().StringP("username", "u", "", "Registry username")
+	loginCmd.Flags().String("password", "", "Registry password")
+	loginCmd.Flags().Bool("password-stdin", false, "Read the password from stdin")
+	loginCmd.Flags().String("token", "", "Access token")
+	loginCmd.MarkFlagsRequiredTogether("username", "password")
+	loginCmd.MarkFlagsMutuallyExclusive("password", "password-stdin", "token")
+	loginCmd.MarkFlagsOneRequired("password", "password-stdin", "token")
+
+	rootCmd.AddCommand(loginCmd)
+}
@@ -2,10 +2,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
 )
 
 var (
@@ -15,50 +19,193 @@ var (
 )
 
 var rootCmd = &cobra.Command{
-	Use:     "example",
-	Short:   "An example CLI tool for testing",
+	Use:   "example",
+	Short: "An example CLI tool for testing",
+	Example: `  # Build and run in one go
+  example build && example run`,
 	Version: "1.0.0",
 }
 
 var buildCmd = &cobra.Command{
-	Use:   "build",
-	Short: "Build the project",
+	Use:         "build",
+	Aliases:     []string{"b", "make"},
+	Short:       "Build the project",
+	Annotations: map[string]string{"stability": "stable", "since": "0.1.0", "category": "build"},
+	Long: `Build compiles every package in the project and writes the artifacts
+to the target directory.
+
+By default a debug build is produced. Pass --release to enable optimizations;
+release builds take longer to produce but run considerably faster.`,
+	Example: `  # Build in debug mode
+  example build
+
+  # Build in release mode into ./dist
+  example build --release --target ./dist`,
 	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
 		fmt.Println("Building...")
+		if term.IsTerminal(int(os.Stdout.Fd())) {
+			showProgress("Compiling", 4)
+		}
 	},
 }
 
 var runCmd = &cobra.Command{
-	Use:   "run [args...]",
-	Short: "Run the project",
+	Use:         "run [flags] -- [args...]",
+	Aliases:     []string{"r"},
+	SuggestFor:  []string{"start", "exec"},
+	Short:       "Run the project",
+	Annotations: map[string]string{"stability": "stable", "since": "0.1.0", "category": "build"},
+	Long: `Run builds the project if needed and then executes it, passing any
+remaining arguments through to the program. Arguments after -- are never
+parsed as flags, even if they start with a dash.`,
+	Example: `  example run
+  example run --port 9000 -- serve --debug`,
 	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
 		fmt.Println("Running with args:", args)
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			fmt.Println("Passed through after --:", args[dash:])
+		}
 	},
 }
 
 var cleanCmd = &cobra.Command{
-	Use:   "clean",
-	Short: "Clean build artifacts",
+	Use:         "clean",
+	Aliases:     []string{"rm"},
+	SuggestFor:  []string{"purge", "wipe"},
+	Short:       "Clean build artifacts",
+	Annotations: map[string]string{"stability": "stable", "since": "0.2.0", "category": "build"},
+	Args:        cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
 		fmt.Println("Cleaning...")
 	},
 }
 
+var compileCmd = &cobra.Command{
+	Use:         "compile",
+	Short:       "Compile the project",
+	Annotations: map[string]string{"stability": "deprecated", "since": "0.1.0", "category": "build"},
+	Deprecated:  `use "build" instead`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Compiling...")
+	},
+}
+
+var debugCmd = &cobra.Command{
+	Use:         "debug",
+	Short:       "Dump internal state",
+	Annotations: map[string]string{"stability": "internal"},
+	Hidden:      true,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Debugging...")
+	},
+}
+
 func init() {
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
 	rootCmd.PersistentFlags().StringVarP(&config, "config", "c", "", "Config file path")
 	rootCmd.PersistentFlags().IntVarP(&port, "port", "p", 8080, "Port number")
+	bindEnv(rootCmd.PersistentFlags(), "verbose", "EXAMPLE_VERBOSE")
+	bindEnv(rootCmd.PersistentFlags(), "config", "EXAMPLE_CONFIG")
+	bindEnv(rootCmd.PersistentFlags(), "port", "EXAMPLE_PORT")
+	rootCmd.PersistentFlags().Bool("trace", false, "Trace internal calls")
+	rootCmd.PersistentFlags().MarkHidden("trace")
+	rootCmd.Flags().StringP("chdir", "C", "", "Run as if started in this directory")
 
 	buildCmd.Flags().BoolP("release", "r", false, "Build in release mode")
 	buildCmd.Flags().StringP("target", "t", "", "Target directory")
+	buildCmd.MarkFlagDirname("target")
+	buildCmd.Flags().String("cache", "local", "Where to keep the build cache. A local cache lives in the target directory and is never shared between checkouts, while a remote cache is consulted before every compile step and populated afterwards, which makes clean builds on CI machines considerably faster at the cost of network traffic")
+	buildCmd.Flags().String("env-file", "", "Load build environment variables from a file.\nEach line has the form KEY=VALUE; blank lines and\nlines starting with # are ignored.\n\nVariables already set in the environment win.")
+	buildCmd.MarkFlagFilename("env-file", "env")
+	buildCmd.Flags().Bool("dry-run", false, "Print the build plan without running it")
+	buildCmd.Flags().MarkHidden("dry-run")
+	buildCmd.Flags().SetAnnotation("dry-run", "stability", []string{"experimental"})
+	buildCmd.Flags().String("out", "", "Output directory")
+	buildCmd.Flags().MarkDeprecated("out", "use --target instead")
+	buildCmd.Flags().IntP("jobs", "j", 0, "Number of parallel jobs")
+	buildCmd.Flags().SetAnnotation("jobs", "since", []string{"0.3.0"})
+	buildCmd.Flags().MarkShorthandDeprecated("jobs", "use --jobs instead")
+
+	cleanCmd.Flags().BoolP("force", "f", false, "Remove artifacts even while a build is running")
+	cleanCmd.Flags().MarkShorthandDeprecated("force", "use --force instead")
+
+	runCmd.Flags().String("color", "auto", "Colorize output: auto, always or never")
+	runCmd.Flags().Lookup("color").NoOptDefVal = "always"
+	runCmd.Flags().String("profile", "", "Write a CPU profile, to cpu.prof if no file is given")
+	runCmd.Flags().Lookup("profile").NoOptDefVal = "cpu.prof"
+	runCmd.Flags().SetAnnotation("profile", "stability", []string{"experimental"})
+	runCmd.Flags().SetAnnotation("profile", "since", []string{"0.7.0"})
 
 	rootCmd.AddCommand(buildCmd)
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(cleanCmd)
+	rootCmd.AddCommand(compileCmd)
+	rootCmd.AddCommand(debugCmd)
+}
+
+// printFlags echoes the flags set on the command line, so invocation
+// fixtures show how values were parsed. Local flags of ancestors can only be
+// set under TraverseChildren and are listed with the command they belong to.
+func printFlags(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed {
+			fmt.Printf("%s=%s\n", f.Name, f.Value)
+		}
+	})
+	for p := cmd.Parent(); p != nil; p = p.Parent() {
+		p.LocalNonPersistentFlags().VisitAll(func(f *pflag.Flag) {
+			if f.Changed {
+				fmt.Printf("%s=%s (%s)\n", f.Name, f.Value, p.CommandPath())
+			}
+		})
+	}
+}
+
+// showProgress draws a progress bar over steps steps on one line, redrawn
+// with \r as terminal progress bars are, then ends the line.
+func showProgress(label string, steps int) {
+	const width = 20
+	for i := 0; i <= steps; i++ {
+		done := width * i / steps
+		fmt.Printf("\r%s [%s%s] %3d%%", label, strings.Repeat("=", done), strings.Repeat(" ", width-done), 100*i/steps)
+	}
+	fmt.Println()
 }
 
 func main() {
+	takeDeterministicFlag()
+	loadExperimental(rootCmd)
+	applyVariants(rootCmd)
+	loadHang(rootCmd)
+	loadPlugins(rootCmd)
+	if isDeterministic() {
+		makeDeterministic(rootCmd)
+	}
+	if ok, err := runGenerator(rootCmd, os.Args[1:]); ok {
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
 	if err := rootCmd.Execute(); err != nil {
+		var exit *exitError
+		if errors.As(err, &exit) {
+			os.Exit(exit.code)
+		}
 		os.Exit(1)
 	}
 }
+
+// exitError is an error that exits with its own code rather than 1.
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string { return e.err.Error() }
+
+func (e *exitError) Unwrap() error { return e.err }
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// mirror's flag descriptions are fitted to pflag's wrapping at 80 columns,
+// where they start at column 30 and wrapped lines end by column 74: pflag
+// breaks a description at the last space within 45 columns, 50 less its 5
+// of slop, and lets the rest run on when it fits in 50. Captured there and
+// a column either side, they land words on the last column a wrapped line
+// reaches and one past it, break lines of their own where the next word
+// would just have fitted, keep hyphenated words whole across the edge, and
+// run URLs and paths too long to wrap past it, with the rest of their
+// description after them or on lines below.
+
+var mirrorCmd = &cobra.Command{
+	Use:   "mirror [flags] <repository>",
+	Short: "Mirror a package repository",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
+		fmt.Println("Mirroring", args)
+	},
+}
+
+func init() {
+	f := mirrorCmd.Flags()
+	// "(default" ends on column 74; at 79 columns it moves down.
+	f.String("dest", "./mirror", "Where the mirrored packages are written to, in a directory created when missing")
+	// "in" would end on column 75, so fits only at 81 columns.
+	f.StringSlice("include", nil, "Only mirror packages matching these globs, in any order")
+	// "after" would have ended on column 74.
+	f.StringSlice("exclude", nil, "Leave out packages matching the globs,\nafter --include has matched them")
+	// "run" ends on column 74, "re-" of "re-verified" would fit, and the
+	// last line runs past on pflag's slop.
+	f.Bool("keep-partial", false, "Keep partial downloads, so that the next run resumes them; files it resumes are re-verified against their checksums, and those that fail are re-downloaded from scratch")
+	f.Int("retries", 3, "Times to retry a failed download")
+	f.String("ca-file", "", "Bundle of certificate authorities to trust in place of the system's, such as /etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem\nRead from stdin when -")
+	f.String("upstream-url", "", "Upstream to mirror from, such as https://mirror.example.com/packages/stable/x86_64/repodata/primary.xml.gz or a mirror list")
+	rootCmd.AddCommand(mirrorCmd)
+}
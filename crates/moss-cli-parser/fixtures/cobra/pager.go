@@ -0,0 +1,55 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// paged sends help through a pager when stdout is a terminal, as git does.
+// A CLI like this hangs anything reading its help on a terminal until a key
+// is pressed, unless PAGER is set to cat or empty.
+func paged(root *cobra.Command) {
+	help := root.HelpFunc()
+	root.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		pager := pagerCommand()
+		if pager == "" {
+			help(cmd, args)
+			return
+		}
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		help(cmd, args)
+		cmd.SetOut(nil)
+		p := exec.Command("sh", "-c", pager)
+		p.Stdin = &buf
+		p.Stdout = os.Stdout
+		p.Stderr = os.Stderr
+		if _, ok := os.LookupEnv("LESS"); !ok {
+			p.Env = append(os.Environ(), "LESS=FRX")
+		}
+		if err := p.Run(); err != nil {
+			os.Stdout.Write(buf.Bytes())
+		}
+	})
+}
+
+// pagerCommand returns the pager to run, or "" for none: $PAGER, or less
+// when PAGER is unset, but none when PAGER is empty or cat or stdout is
+// not a terminal.
+func pagerCommand() string {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return ""
+	}
+	pager, ok := os.LookupEnv("PAGER")
+	if !ok {
+		return "less"
+	}
+	if pager == "cat" {
+		return ""
+	}
+	return pager
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Plugins are executables named example-<name> in the directory given by
+// EXAMPLE_PLUGINS, discovered at startup in the style of kubectl and krew.
+// Each becomes a command in a "Plugins:" section of root help, described by
+// a "# Short:" comment in the plugin, and is handed its arguments verbatim.
+
+// loadPlugins registers a command for each plugin found.
+func loadPlugins(root *cobra.Command) {
+	dir := os.Getenv("EXAMPLE_PLUGINS")
+	if dir == "" {
+		return
+	}
+	paths, _ := filepath.Glob(filepath.Join(dir, "example-*"))
+	sort.Strings(paths)
+	var cmds []*cobra.Command
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+			continue
+		}
+		cmds = append(cmds, pluginCommand(path))
+	}
+	if len(cmds) == 0 {
+		return
+	}
+	root.AddGroup(&cobra.Group{ID: "plugins", Title: "Plugins:"})
+	root.AddCommand(cmds...)
+}
+
+// pluginCommand returns a command that runs the plugin at path.
+func pluginCommand(path string) *cobra.Command {
+	name := strings.TrimPrefix(filepath.Base(path), "example-")
+	short := pluginShort(path)
+	if short == "" {
+		short = "Run the " + name + " plugin"
+	}
+	return &cobra.Command{
+		Use:                name,
+		Short:              short,
+		GroupID:            "plugins",
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			plugin := exec.Command(path, args...)
+			plugin.Stdin = os.Stdin
+			plugin.Stdout = cmd.OutOrStdout()
+			plugin.Stderr = cmd.ErrOrStderr()
+			err := plugin.Run()
+			var exit *exec.ExitError
+			if errors.As(err, &exit) {
+				os.Exit(exit.ExitCode())
+			}
+			return err
+		},
+	}
+}
+
+// pluginShort returns the "# Short:" comment near the top of a plugin.
+func pluginShort(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for i := 0; i < 5 && scanner.Scan(); i++ {
+		if short, ok := strings.CutPrefix(scanner.Text(), "# Short:"); ok {
+			return strings.TrimSpace(short)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,26 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// proxy wraps another tool and whitelists unknown flags. They are not an error,
+// but pflag drops them, along with a following value unless given as
+// --flag=value; only arguments after -- reach the tool intact.
+var proxyCmd = &cobra.Command{
+	Use:                "proxy [flags] <tool> [-- tool flags...]",
+	Short:              "Run a tool with the project environment",
+	Args:               cobra.MinimumNArgs(1),
+	FParseErrWhitelist: cobra.FParseErrWhitelist{UnknownFlags: true},
+	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
+		fmt.Println("Proxying to", args[0], "with", args[1:])
+	},
+}
+
+func init() {
+	proxyCmd.Flags().StringP("workdir", "w", ".", "Directory to run the tool in")
+	rootCmd.AddCommand(proxyCmd)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// query's examples are shell sessions rather than single command lines:
+// pipes, redirects, comments and heredocs whose bodies start in the first
+// column, where a line ending in a colon reads like a section header. Its
+// save subcommand's examples are Markdown, code fences and all, as CLIs
+// sharing text with their README print them.
+
+var queryCmd = &cobra.Command{
+	Use:   "query [flags] [filter]",
+	Short: "Query build and deployment history",
+	Example: `  # Count the failed jobs
+  example query --status failed | wc -l
+
+  # Save a report, errors included, and keep a copy of what was printed
+  example query --format json > report.json 2>&1
+  example query --format table 2>/dev/null | tee report.txt
+
+  # Read the filter from a heredoc
+  example query --file - <<'EOF'
+filter:
+  status: failed
+  since: 24h
+EOF
+
+  # Fail a CI step when anything is still running
+  example query --status running --quiet && echo "all done" || exit 1`,
+	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
+		fmt.Println("Querying", args)
+	},
+}
+
+var querySaveCmd = &cobra.Command{
+	Use:   "save <name> [filter]",
+	Short: "Save a filter for later queries",
+	Args:  cobra.MinimumNArgs(1),
+	Example: "Save the filter under a name:\n\n" +
+		"```sh\n" +
+		"example query save failed-today --status failed --since 24h\n" +
+		"```\n\n" +
+		"Then query by name:\n\n" +
+		"```console\n" +
+		"$ example query @failed-today # saved filters start with @\n" +
+		"```",
+	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
+		fmt.Println("Saving", args)
+	},
+}
+
+func init() {
+	queryCmd.Flags().String("status", "", "Only jobs with this status")
+	queryCmd.Flags().String("format", "table", "Output format: table, json or csv")
+	queryCmd.Flags().String("file", "", "Read the filter from a YAML file, - for stdin")
+	queryCmd.Flags().BoolP("quiet", "q", false, "Print nothing; exit 1 when no job matches")
+	querySaveCmd.Flags().String("since", "", "Only jobs newer than this")
+
+	queryCmd.AddCommand(querySaveCmd)
+	rootCmd.AddCommand(queryCmd)
+}
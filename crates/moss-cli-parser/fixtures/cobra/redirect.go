@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Some CLIs point cobra's output writers somewhere other than it defaults
+// to, so help and errors reach a different channel than a capture expects,
+// or pass through the program before they reach any.
+
+// stderrOutput sends everything cobra prints, help included, to stderr, as
+// CLIs that keep stdout for machine-readable output do.
+func stderrOutput(root *cobra.Command) {
+	root.SetOut(os.Stderr)
+}
+
+// stdoutErrors sends cobra's errors to stdout. The usage it prints after
+// them stays on stderr, where cobra sends output while no output writer is
+// set, so the two come apart.
+func stdoutErrors(root *cobra.Command) {
+	root.SetErr(os.Stdout)
+}
+
+// docsURL is where bufferedHelp points each command's documentation.
+const docsURL = "https://example.com/docs/"
+
+// bufferedHelp renders help into a buffer and rewrites it before printing:
+// the closing hint to run --help on a subcommand gives way to a link to the
+// command's documentation.
+func bufferedHelp(root *cobra.Command) {
+	help := root.HelpFunc()
+	root.SetHelpFunc(func(cmd *cobra.Command, args []string) {
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		help(cmd, args)
+		cmd.SetOut(nil)
+		text := strings.TrimRight(buf.String(), "\n")
+		if i := strings.LastIndex(text, "\n"); i >= 0 && strings.HasPrefix(text[i+1:], "Use \"") {
+			text = strings.TrimRight(text[:i], "\n")
+		}
+		link := docsURL + strings.ReplaceAll(cmd.CommandPath(), " ", "-")
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\n\nDocumentation: %s\n", text, link)
+	})
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// Some CLIs build a whole manual into one command's help. The hidden
+// reference command's Long lists referenceCodes diagnostics and its Example
+// looks up referenceExamples of them, so its help runs to tens of thousands
+// of lines and over a megabyte, past the output a discovery driver keeps by
+// default.
+const (
+	referenceCodes    = 20000
+	referenceExamples = 10000
+)
+
+// referenceSubjects and referenceProblems make up the diagnostics' texts.
+var (
+	referenceSubjects = []string{"import path", "build tag", "flag value", "cache entry", "target directory", "environment file", "plugin manifest"}
+	referenceProblems = []string{"is malformed", "is not allowed here", "could not be read", "conflicts with an earlier one", "is deprecated", "was never used"}
+)
+
+var referenceCmd = &cobra.Command{
+	Use:         "reference",
+	Short:       "Print the diagnostic reference",
+	Annotations: map[string]string{"stability": "internal"},
+	Hidden:      true,
+	Long:        referenceLong(),
+	Example:     referenceExample(),
+	Args:        cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println(cmd.Long)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(referenceCmd)
+}
+
+// referenceLong lists every diagnostic by code.
+func referenceLong() string {
+	var b strings.Builder
+	b.WriteString("Reference lists every diagnostic example can report, by code.\n")
+	for i := 1; i <= referenceCodes; i++ {
+		if i%1000 == 1 {
+			fmt.Fprintf(&b, "\nE%02dxxx:\n", i/1000)
+		}
+		fmt.Fprintf(&b, "  E%05d  The %s %s (severity %d)\n", i,
+			referenceSubjects[i%len(referenceSubjects)], referenceProblems[i%len(referenceProblems)], i%3)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// referenceExample looks up each of the first referenceExamples diagnostics.
+func referenceExample() string {
+	lines := make([]string, referenceExamples)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("  example reference | grep E%05d", i+1)
+	}
+	return strings.Join(lines, "\n")
+}
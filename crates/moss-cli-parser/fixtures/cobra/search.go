@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// search mixes flags with both forms, long-only flags and grep-style flags
+// documented by their shorthand alone. pflag always wants a long name, so the
+// latter are annotated and rendered through flagUsages.
+
+const shorthandOnly = "example_shorthand_only"
+
+var searchCmd = &cobra.Command{
+	Use:   "search <pattern> [path...]",
+	Short: "Search project files",
+	Args:  cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
+		fmt.Println("Searching for", args[0], "in", args[1:])
+	},
+}
+
+// flagUsages renders fs like FlagUsages, but lists shorthand-only flags by
+// their single-letter form, padded so the description column stays put.
+func flagUsages(fs *pflag.FlagSet) string {
+	out := fs.FlagUsages()
+	fs.VisitAll(func(f *pflag.Flag) {
+		if _, ok := f.Annotations[shorthandOnly]; !ok {
+			return
+		}
+		both := fmt.Sprintf("-%s, --%s", f.Shorthand, f.Name)
+		out = strings.Replace(out, both, fmt.Sprintf("%-*s", len(both), "-"+f.Shorthand), 1)
+	})
+	return out
+}
+
+func init() {
+	f := searchCmd.Flags()
+	f.BoolP("ignore-case", "i", false, "Match case-insensitively")
+	f.BoolP("line-number", "n", false, "Prefix matches with line numbers")
+	f.BoolP("word-regexp", "w", false, "Match whole words only")
+	for _, name := range []string{"ignore-case", "line-number", "word-regexp"} {
+		f.SetAnnotation(name, shorthandOnly, []string{"true"})
+	}
+	f.IntP("context", "C", 0, "Lines of context around each match")
+	f.StringP("glob", "g", "", "Only search files matching the glob")
+	f.Int("max-count", 0, "Stop after this many matches per file")
+	f.Bool("hidden", false, "Search hidden files and directories")
+
+	cobra.AddTemplateFunc("flagUsages", flagUsages)
+	searchCmd.SetUsageTemplate(strings.ReplaceAll(searchCmd.UsageTemplate(),
+		".LocalFlags.FlagUsages", "flagUsages .LocalFlags"))
+
+	rootCmd.AddCommand(searchCmd)
+}
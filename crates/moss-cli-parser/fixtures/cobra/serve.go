@@ -0,0 +1,45 @@
+package main
+
+import (
+	"net"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// serve registers one flag of every pflag value type so help renders each
+// type name.
+
+var maxBody = byteSize(1 << 20)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the project over HTTP",
+	Run: func(cmd *cobra.Command, args []string) {
+		printFlags(cmd)
+	},
+}
+
+func init() {
+	f := serveCmd.Flags()
+	f.Duration("timeout", 30*time.Second, "Request timeout")
+	f.IP("bind", net.IPv4(127, 0, 0, 1), "Address to listen on")
+	f.IPNet("allow", net.IPNet{IP: net.IPv4(10, 0, 0, 0), Mask: net.CIDRMask(8, 32)}, "Network allowed to connect")
+	f.StringSlice("tags", nil, "Tags to attach to the server")
+	f.StringArray("header", nil, "Extra response header (repeatable)")
+	f.StringToString("labels", nil, "Labels as key=value pairs")
+	f.IntSlice("ports", nil, "Additional ports to listen on")
+	f.CountP("quiet", "q", "Reduce log output (repeatable)")
+	f.BytesHex("key", nil, "Session key in hex")
+	f.Float64("ratio", 0.5, "Fraction of requests to sample")
+	f.SetAnnotation("ratio", "stability", []string{"beta"})
+	f.Var(&maxBody, "max-body", "Maximum request body size")
+	// Shadows the root's persistent --config, and drops its -c shorthand.
+	f.String("config", "serve.toml", "Server configuration file")
+	level := newEnum("level", "info", "debug", "info", "warn", "error")
+	f.Var(level, "log-level", level.usage("Minimum level to log"))
+	bindEnv(f, "timeout", "EXAMPLE_SERVE_TIMEOUT")
+	bindEnv(f, "log-level", "EXAMPLE_LOG_LEVEL")
+
+	rootCmd.AddCommand(serveCmd)
+}
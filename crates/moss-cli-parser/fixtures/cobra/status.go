@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// statusVerbosity counts -v given to status, which declares its own
+// --verbose in place of the global one: -v shows each component's version,
+// -vv where it runs too.
+var statusVerbosity int
+
+var statusCmd = &cobra.Command{
+	Use:       "status [component...]",
+	Short:     "Show the status of project components",
+	ValidArgs: []string{"api", "cache", "db", "worker"},
+	Args:      cobra.OnlyValidArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			args = cmd.ValidArgs
+		}
+		printFlags(cmd)
+		for _, component := range args {
+			switch {
+			case statusVerbosity >= 2:
+				fmt.Println(component, "ok", "v1.0.0", "on localhost")
+			case statusVerbosity == 1:
+				fmt.Println(component, "ok", "v1.0.0")
+			default:
+				fmt.Println(component, "ok")
+			}
+		}
+	},
+}
+
+func init() {
+	statusCmd.Flags().CountVarP(&statusVerbosity, "verbose", "v", "Show more detail; repeat for more")
+	rootCmd.AddCommand(statusCmd)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// sync logs and draws progress bars on stderr whether or not it is a
+// terminal, as tools that never check do, before it finds its remote
+// unknown and cobra prints the error and usage after them. A capture of its
+// stderr holds the bars as a terminal was sent them: redrawn in place with
+// carriage returns, the cursor moved up over the two of them and lines
+// erased to log between, the cursor hidden while they draw, and the log
+// levels colored.
+
+// syncRemotes are the remotes sync knows.
+var syncRemotes = []string{"origin", "backup"}
+
+var syncCmd = &cobra.Command{
+	Use:   "sync [flags] <remote>",
+	Short: "Sync the local cache with a remote",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		quiet, _ := cmd.Flags().GetBool("quiet")
+		w := io.Discard
+		if !quiet {
+			w = os.Stderr
+		}
+		syncProgress(w, args[0])
+		for _, remote := range syncRemotes {
+			if remote == args[0] {
+				printFlags(cmd)
+				fmt.Println("Synced with", remote)
+				return nil
+			}
+		}
+		syncLog(w, "ERROR", "31", "remote "+args[0]+" not found")
+		return fmt.Errorf("unknown remote %q (known: %s)", args[0], strings.Join(syncRemotes, ", "))
+	},
+}
+
+// syncLog writes a log line at level, colored as log libraries color a
+// level for a terminal. Its time is fixed, so captures stay the same.
+func syncLog(w io.Writer, level, color, msg string) {
+	fmt.Fprintf(w, "2024-01-02T15:04:05Z \x1b[%sm%-5s\x1b[0m %s\n", color, level, msg)
+}
+
+// syncBar is one progress bar line, drawn over an erased line.
+func syncBar(w io.Writer, label string, done, steps int) {
+	const width = 20
+	n := width * done / steps
+	fmt.Fprintf(w, "\x1b[2K%-10s [%s%s] %3d%%", label, strings.Repeat("#", n), strings.Repeat(".", width-n), 100*done/steps)
+}
+
+// syncProgress draws the fetch and verify bars sync shows, one above the
+// other, redrawing both at each step by moving the cursor back up to the
+// first, and logging a line halfway through above where they were.
+func syncProgress(w io.Writer, remote string) {
+	const steps = 4
+	syncLog(w, "INFO", "36", "resolving remote "+remote)
+	fmt.Fprint(w, "\x1b[?25l")
+	for i := 0; i <= steps; i++ {
+		if i > 0 {
+			fmt.Fprint(w, "\x1b[1A\r")
+		}
+		if i == steps/2 {
+			fmt.Fprint(w, "\x1b[2K")
+			syncLog(w, "WARN", "33", "index is stale, fetching in full")
+		}
+		syncBar(w, "fetching", i, steps)
+		fmt.Fprint(w, "\n")
+		syncBar(w, "verifying", i/2, steps)
+	}
+	fmt.Fprint(w, "\n\x1b[?25h")
+}
+
+func init() {
+	syncCmd.Flags().Bool("quiet", false, "Print no logs or progress")
+	syncCmd.Flags().IntP("jobs", "j", 4, "Number of parallel downloads")
+	rootCmd.AddCommand(syncCmd)
+}
@@ -0,0 +1,32 @@
+package main
+
+// customHelpTemplate reorders the stock cobra sections (commands before
+// flags, usage and examples last) and renames every header.
+const customHelpTemplate = `{{with (or .Long .Short)}}{{. | trimTrailingWhitespaces}}
+
+{{end}}{{if .HasAvailableSubCommands}}Commands:{{range .Commands}}{{if .IsAvailableCommand}}
+  {{rpad .Name .NamePadding }} {{.Short}}{{end}}{{end}}
+
+{{end}}{{if .HasAvailableLocalFlags}}Options:
+{{.LocalFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}{{if .HasAvailableInheritedFlags}}Global Options:
+{{.InheritedFlags.FlagUsages | trimTrailingWhitespaces}}
+
+{{end}}Synopsis:{{if .Runnable}}
+  {{.UseLine}}{{end}}{{if .HasAvailableSubCommands}}
+  {{.CommandPath}} <command> [options]{{end}}{{if .HasExample}}
+
+Examples:
+{{.Example}}{{end}}
+`
+
+// condensedUsageTemplate squeezes the usage block into a few terse lines with
+// lowercase headers and flags listed inline.
+const condensedUsageTemplate = `usage: {{.UseLine}}{{if .HasAvailableSubCommands}}
+       {{.CommandPath}} <command> [<args>]{{end}}{{if gt (len .Aliases) 0}}
+aliases: {{.NameAndAliases}}{{end}}{{if .HasAvailableSubCommands}}
+commands:{{range .Commands}}{{if .IsAvailableCommand}} {{.Name}}{{end}}{{end}}{{end}}{{if .HasAvailableFlags}}
+flags:
+{{.Flags.FlagUsagesWrapped 0 | trimTrailingWhitespaces}}{{end}}
+`
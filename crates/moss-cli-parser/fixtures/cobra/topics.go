@@ -0,0 +1,45 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// Help topics are commands with no Run and no subcommands. cobra lists them
+// under "Additional help topics:" and they can only be read via help.
+
+var environmentTopic = &cobra.Command{
+	Use:   "environment",
+	Short: "Environment variables read by example",
+	Long: `Environment variables read by example.
+
+Flags marked "(env: NAME)" in help fall back to the named variable when
+they are not given on the command line. In addition:
+
+  EXAMPLE_PLUGINS   Directory searched for example-<name> plugins.
+  EXAMPLE_VARIANT   Comma-separated tweaks to the command tree, for testing.`,
+}
+
+var exitCodesTopic = &cobra.Command{
+	Use:   "exit-codes",
+	Short: "Exit statuses and what they mean",
+	Long: `Exit statuses and what they mean.
+
+  0   The command succeeded.
+  1   The command failed, or its flags or arguments were invalid.
+  2   EXAMPLE_VARIANT named an unknown variant.
+
+Plugins exit with whatever status the plugin itself returns.`,
+}
+
+var contextsTopic = &cobra.Command{
+	Use:   "contexts",
+	Short: "How --context picks a cluster",
+	Long: `How --context picks a cluster.
+
+A context names a cluster and the credentials used to reach it. Without
+--context, cluster commands use the context marked current in the config
+file given by --config.`,
+}
+
+func init() {
+	rootCmd.AddCommand(environmentTopic, exitCodesTopic)
+	clusterCmd.AddCommand(contextsTopic)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// byteSize is a custom pflag.Value accepting sizes like "512K" or "10MB".
+type byteSize int64
+
+var sizeUnits = []struct {
+	suffix string
+	scale  int64
+}{
+	{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+	{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10},
+	{"B", 1},
+}
+
+func (s *byteSize) String() string {
+	for _, u := range sizeUnits[:3] {
+		if *s != 0 && int64(*s)%u.scale == 0 {
+			return strconv.FormatInt(int64(*s)/u.scale, 10) + u.suffix
+		}
+	}
+	return strconv.FormatInt(int64(*s), 10)
+}
+
+func (s *byteSize) Set(v string) error {
+	scale := int64(1)
+	upper := strings.ToUpper(v)
+	for _, u := range sizeUnits {
+		if strings.HasSuffix(upper, u.suffix) {
+			upper, scale = strings.TrimSuffix(upper, u.suffix), u.scale
+			break
+		}
+	}
+	n, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid size %q", v)
+	}
+	*s = byteSize(n * scale)
+	return nil
+}
+
+func (s *byteSize) Type() string {
+	return "size"
+}
+
+// enum is a pflag.Value restricted to a fixed set of choices.
+type enum struct {
+	value    string
+	choices  []string
+	typeName string
+}
+
+func newEnum(typeName, value string, choices ...string) *enum {
+	return &enum{value: value, choices: choices, typeName: typeName}
+}
+
+func (e *enum) String() string {
+	return e.value
+}
+
+func (e *enum) Set(v string) error {
+	for _, c := range e.choices {
+		if v == c {
+			e.value = v
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of: %s", strings.Join(e.choices, "|"))
+}
+
+func (e *enum) Type() string {
+	return e.typeName
+}
+
+// usage appends the allowed values to a flag description.
+func (e *enum) usage(desc string) string {
+	return fmt.Sprintf("%s, one of: %s", desc, strings.Join(e.choices, "|"))
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"golang.org/x/term"
+)
+
+// variants are named tweaks applied to the command tree before execution, so
+// one binary can produce several flavours of help output. Select them with
+// EXAMPLE_VARIANT, e.g. EXAMPLE_VARIANT=unhidden ./example --help.
+var variants = map[string]func(root *cobra.Command){
+	"unhidden":          unhide,
+	"grouped":           group,
+	"custom-help":       customHelp,
+	"usage-template":    condensedUsage,
+	"usage-func":        synopsisUsage,
+	"wrapped":           wrapFlags,
+	"build-info":        buildInfo,
+	"traverse":          traverse,
+	"prefix-matching":   prefixMatching,
+	"silence-usage":     silenceUsage,
+	"silence-errors":    silenceErrors,
+	"hooks":             hooks,
+	"traverse-hooks":    traverseHooks,
+	"flag-error":        flagError,
+	"help-renamed":      renameHelp,
+	"help-replaced":     replaceHelp,
+	"no-help":           removeHelp,
+	"no-completion":     removeCompletion,
+	"hidden-completion": hideCompletion,
+	"no-autogen-tag":    noAutoGenTag,
+	"colored":           colorize,
+	"windows":           windows,
+	"localized":         localize,
+	"paged":             paged,
+	"whitespace":        whitespace,
+	"stderr-output":     stderrOutput,
+	"stdout-errors":     stdoutErrors,
+	"buffered-help":     bufferedHelp,
+	"help-json":         addHelpJSON,
+}
+
+func applyVariants(root *cobra.Command) {
+	for _, name := range strings.Split(os.Getenv("EXAMPLE_VARIANT"), ",") {
+		if name == "" {
+			continue
+		}
+		apply, ok := variants[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown EXAMPLE_VARIANT %q\n", name)
+			os.Exit(2)
+		}
+		apply(root)
+	}
+}
+
+// walk calls fn on cmd and every command below it.
+func walk(cmd *cobra.Command, fn func(*cobra.Command)) {
+	fn(cmd)
+	for _, child := range cmd.Commands() {
+		walk(child, fn)
+	}
+}
+
+// unhide makes hidden commands and flags show up in help, cobra's
+// completion command included.
+func unhide(root *cobra.Command) {
+	root.CompletionOptions.HiddenDefaultCmd = false
+	walk(root, func(cmd *cobra.Command) {
+		cmd.Hidden = false
+		show := func(f *pflag.Flag) { f.Hidden = false }
+		cmd.Flags().VisitAll(show)
+		cmd.PersistentFlags().VisitAll(show)
+	})
+}
+
+// group sorts the root's commands into docker-style help sections.
+func group(root *cobra.Command) {
+	root.AddGroup(
+		&cobra.Group{ID: "build", Title: "Build Commands:"},
+		&cobra.Group{ID: "manage", Title: "Management Commands:"},
+	)
+	for _, cmd := range []*cobra.Command{buildCmd, cleanCmd, compileCmd, runCmd} {
+		cmd.GroupID = "build"
+	}
+	clusterCmd.GroupID = "manage"
+}
+
+// customHelp swaps in a help template with non-canonical section order.
+func customHelp(root *cobra.Command) {
+	root.SetHelpTemplate(customHelpTemplate)
+}
+
+// condensedUsage swaps in a terse usage template; help embeds it too.
+func condensedUsage(root *cobra.Command) {
+	root.SetUsageTemplate(condensedUsageTemplate)
+}
+
+// synopsisUsage installs a usage function in place of any template.
+func synopsisUsage(root *cobra.Command) {
+	root.SetUsageFunc(usageSynopsis)
+}
+
+// usageSynopsis replaces the usage block with a single getopt-style line,
+// built by hand rather than through a template.
+func usageSynopsis(cmd *cobra.Command) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "usage: %s", cmd.CommandPath())
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		name := "--" + f.Name
+		if f.Shorthand != "" && f.ShorthandDeprecated == "" {
+			name = "-" + f.Shorthand + "|" + name
+		}
+		if t := f.Value.Type(); t != "bool" {
+			name += " <" + t + ">"
+		}
+		fmt.Fprintf(&b, " [%s]", name)
+	})
+	if cmd.HasAvailableSubCommands() {
+		b.WriteString(" <command> [<args>]")
+	}
+	fmt.Fprintln(cmd.OutOrStderr(), b.String())
+	return nil
+}
+
+// wrapFlags wraps flag descriptions to the terminal width, as CLIs that
+// size their help to the terminal do.
+func wrapFlags(root *cobra.Command) {
+	root.SetUsageTemplate(strings.ReplaceAll(root.UsageTemplate(),
+		".FlagUsages ", fmt.Sprintf(".FlagUsagesWrapped %d ", terminalWidth())))
+}
+
+// terminalWidth is the width from COLUMNS, else that of the terminal on
+// stdout, else 80.
+func terminalWidth() int {
+	if n, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && n > 0 {
+		return n
+	}
+	if n, _, err := term.GetSize(int(os.Stdout.Fd())); err == nil && n > 0 {
+		return n
+	}
+	return 80
+}
+
+// buildInfo makes --version and the version command print build metadata.
+func buildInfo(root *cobra.Command) {
+	root.SetVersionTemplate(buildInfoTemplate())
+}
+
+// traverse parses each command's local flags on the way down to the
+// subcommand, so root-only flags such as -C may precede it.
+func traverse(root *cobra.Command) {
+	root.TraverseChildren = true
+}
+
+// prefixMatching lets a command be named by any prefix of its name or an
+// alias that no other command's starts with, so "example bui" runs build.
+// A prefix several commands share is an unknown command, suggested as each
+// of them.
+func prefixMatching(root *cobra.Command) {
+	cobra.EnablePrefixMatching = true
+}
+
+// noAutoGenTag leaves the "Auto generated by spf13/cobra" tag and date out
+// of every command's generated docs.
+func noAutoGenTag(root *cobra.Command) {
+	walk(root, func(cmd *cobra.Command) {
+		cmd.DisableAutoGenTag = true
+	})
+}
+
+// silenceUsage stops cobra printing usage after an error.
+func silenceUsage(root *cobra.Command) {
+	root.SilenceUsage = true
+}
+
+// silenceErrors stops cobra printing the "Error:" line itself.
+func silenceErrors(root *cobra.Command) {
+	root.SilenceErrors = true
+}
+
+// flagError reports flag errors in a terse style of its own, pointing at the
+// command's help instead of printing usage, as many real CLIs do. Other
+// errors keep cobra's usage but share the lowercase "error:" prefix.
+func flagError(root *cobra.Command) {
+	root.SetErrPrefix("error:")
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%w, see '%s --help'", err, cmd.CommandPath())
+	})
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// Build metadata, normally stamped with -ldflags "-X main.commit=...".
+var (
+	commit    = "0000000"
+	buildDate = "1970-01-01T00:00:00Z"
+)
+
+// versionCmd prints the same text as --version, through the root's version
+// template.
+var versionCmd = &cobra.Command{
+	Use:   "version",
+	Short: "Print version information",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		root := cmd.Root()
+		tmpl, err := template.New("version").Parse(root.VersionTemplate())
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(cmd.OutOrStdout(), root)
+	},
+}
+
+// buildInfoTemplate reports build metadata over several lines.
+func buildInfoTemplate() string {
+	return fmt.Sprintf(`{{.Name}} {{.Version}}
+  commit: %s
+  built:  %s
+`, commit, buildDate)
+}
+
+func init() {
+	rootCmd.AddCommand(versionCmd)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// whitespace indents the usage template with tabs instead of spaces, and
+// puts a tab, trailing spaces or a non-breaking space in some descriptions,
+// as help written by hand or pasted from elsewhere often has.
+func whitespace(root *cobra.Command) {
+	cobra.AddTemplateFunc("tabIndent", tabIndent)
+	tmpl := strings.ReplaceAll(root.UsageTemplate(), "\n  {{", "\n\t{{")
+	root.SetUsageTemplate(strings.ReplaceAll(tmpl, "FlagUsages |", "FlagUsages | tabIndent |"))
+
+	buildCmd.Short = "Build\tthe project"
+	buildCmd.Long = strings.Replace(buildCmd.Long, "\n", "  \n", 1)
+	cleanCmd.Short = "Clean build artifacts   "
+	runCmd.Short = "Run the\u00a0project"
+	buildCmd.Flags().Lookup("target").Usage = "Target\tdirectory"
+	buildCmd.Flags().Lookup("release").Usage = "Build in release mode  "
+	buildCmd.Flags().Lookup("jobs").Usage = "Number of parallel\u00a0jobs"
+}
+
+// tabIndent replaces the two spaces pflag indents each flag row with by a
+// tab.
+func tabIndent(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "  ") {
+			lines[i] = "\t" + line[2:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
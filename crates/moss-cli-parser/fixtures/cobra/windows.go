@@ -0,0 +1,52 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// windowsDefaults are the path defaults the example would compute on
+// Windows, keyed by command path and flag name.
+var windowsDefaults = []struct {
+	command, flag, value string
+}{
+	{"example", "config", `C:\Users\example\AppData\Roaming\example\config.yaml`},
+	{"example build", "target", `.\target`},
+	{"example proxy", "workdir", `C:\Projects\example`},
+}
+
+// windows makes help look as captured on Windows: path defaults use drive
+// letters and backslashes, and every line ends in CRLF.
+func windows(root *cobra.Command) {
+	walk(root, func(cmd *cobra.Command) {
+		for _, d := range windowsDefaults {
+			if cmd.CommandPath() != d.command {
+				continue
+			}
+			var f *pflag.Flag
+			if f = cmd.PersistentFlags().Lookup(d.flag); f == nil {
+				f = cmd.Flags().Lookup(d.flag)
+			}
+			f.Value.Set(d.value)
+			f.DefValue = d.value
+		}
+	})
+	root.SetOut(crlfWriter{os.Stdout})
+	root.SetErr(crlfWriter{os.Stderr})
+}
+
+// crlfWriter writes LF line endings as CRLF.
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,239 @@
+// Package cobratree exports a cobra command tree as JSON: the ground truth
+// the fixture programs built on cobra record next to their help fixtures,
+// as cobra/example.tree.json and the <binary>.tree.json of each workspace
+// binary, for parsers to be checked against.
+//
+// Each command records where its flags come from, as help tells them apart
+// under Flags: and Global Flags:. Its local flags are listed in full, the
+// persistent ones among them marked so, and a local flag hiding an
+// ancestor's persistent flag of the same name names the ancestor it
+// shadows. The persistent flags it inherits are listed by name only, each
+// with the nearest ancestor declaring it, where it is listed in full.
+package cobratree
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Shared is the annotation marking a command or flag as defined by a
+// library the program shares with others, as the workspace binaries share
+// theirs; the tree records it as Shared.
+const Shared = "cobratree_shared"
+
+// Command is the ground truth recorded for one command.
+type Command struct {
+	Name     string   `json:"name"`
+	Path     string   `json:"path"`
+	Use      string   `json:"use"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Short    string   `json:"short,omitempty"`
+	GroupID  string   `json:"group,omitempty"`
+	Runnable bool     `json:"runnable"`
+	// NonInterspersed is set for a command whose flags end at its first
+	// positional argument, by SetInterspersed(false).
+	NonInterspersed bool     `json:"non_interspersed,omitempty"`
+	Hidden          bool     `json:"hidden,omitempty"`
+	Shared          bool     `json:"shared,omitempty"`
+	Deprecated      string   `json:"deprecated,omitempty"`
+	HelpTopic       bool     `json:"help_topic,omitempty"`
+	ValidArgs       []string `json:"valid_args,omitempty"`
+	Args            *Args    `json:"args,omitempty"`
+	Flags           []Flag   `json:"flags,omitempty"`
+	// InheritedFlags are the persistent flags of ancestors the command
+	// takes, in the order help lists them under Global Flags:.
+	InheritedFlags []Inherited `json:"inherited_flags,omitempty"`
+	FlagGroups     []Group     `json:"flag_groups,omitempty"`
+	SubCommands    []Command   `json:"commands,omitempty"`
+}
+
+// Args describes a command's positional argument validator. Min and Max
+// are found by probing it; Max is omitted when any number is accepted.
+type Args struct {
+	Validator string `json:"validator"`
+	Min       *int   `json:"min,omitempty"`
+	Max       *int   `json:"max,omitempty"`
+}
+
+// Flag is one flag declared on a command. Inherited flags are recorded in
+// full only on the command that declares them, with Persistent set, and by
+// name on each command inheriting them.
+type Flag struct {
+	Name                string `json:"name"`
+	Shorthand           string `json:"shorthand,omitempty"`
+	Type                string `json:"type"`
+	Default             string `json:"default"`
+	NoOptDefault        string `json:"no_opt_default,omitempty"`
+	Usage               string `json:"usage"`
+	Persistent          bool   `json:"persistent,omitempty"`
+	Required            bool   `json:"required,omitempty"`
+	Hidden              bool   `json:"hidden,omitempty"`
+	Shared              bool   `json:"shared,omitempty"`
+	Deprecated          string `json:"deprecated,omitempty"`
+	ShorthandDeprecated string `json:"shorthand_deprecated,omitempty"`
+	// Shadows is the path of the nearest ancestor with a persistent flag of
+	// the same name, which this one hides from the command and below.
+	Shadows string `json:"shadows,omitempty"`
+}
+
+// Inherited names a persistent flag a command inherits, and the path of the
+// ancestor declaring it.
+type Inherited struct {
+	Name string `json:"name"`
+	From string `json:"from"`
+}
+
+// Group is a set of flags cobra validates together.
+type Group struct {
+	Kind  string   `json:"kind"`
+	Flags []string `json:"flags"`
+}
+
+// flagGroupKinds maps cobra's flag group annotations to Group kinds.
+var flagGroupKinds = []struct{ annotation, kind string }{
+	{"cobra_annotation_required_if_others_set", "required_together"},
+	{"cobra_annotation_one_required", "one_required"},
+	{"cobra_annotation_mutually_exclusive", "mutually_exclusive"},
+}
+
+// argsProbeLimit is how many positional arguments describeArgs tries a
+// validator with before deciding it has no maximum.
+const argsProbeLimit = 8
+
+// Write writes root's whole command tree, with cobra's default help and
+// completion commands and help and version flags added as Execute would,
+// to the file at path.
+func Write(root *cobra.Command, path string) error {
+	root.InitDefaultHelpCmd()
+	root.InitDefaultCompletionCmd()
+	root.InitDefaultVersionFlag()
+	var initHelp func(*cobra.Command)
+	initHelp = func(cmd *cobra.Command) {
+		cmd.InitDefaultHelpFlag()
+		for _, sub := range cmd.Commands() {
+			initHelp(sub)
+		}
+	}
+	initHelp(root)
+	data, err := json.MarshalIndent(Describe(root), "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// Describe returns the tree of cmd as it stands, without the defaults Write
+// adds first.
+func Describe(cmd *cobra.Command) Command {
+	out := Command{
+		Name:            cmd.Name(),
+		Path:            cmd.CommandPath(),
+		Use:             cmd.Use,
+		Aliases:         cmd.Aliases,
+		Short:           cmd.Short,
+		GroupID:         cmd.GroupID,
+		Runnable:        cmd.Runnable(),
+		NonInterspersed: !interspersed(cmd),
+		Hidden:          cmd.Hidden,
+		Shared:          cmd.Annotations[Shared] == "true",
+		Deprecated:      cmd.Deprecated,
+		HelpTopic:       cmd.IsAdditionalHelpTopicCommand(),
+		ValidArgs:       cmd.ValidArgs,
+		Args:            describeArgs(cmd),
+	}
+	groups := map[string]bool{}
+	cmd.LocalFlags().VisitAll(func(f *pflag.Flag) {
+		_, required := f.Annotations[cobra.BashCompOneRequiredFlag]
+		_, shared := f.Annotations[Shared]
+		out.Flags = append(out.Flags, Flag{
+			Name:                f.Name,
+			Shorthand:           f.Shorthand,
+			Type:                f.Value.Type(),
+			Default:             f.DefValue,
+			NoOptDefault:        f.NoOptDefVal,
+			Usage:               f.Usage,
+			Persistent:          cmd.PersistentFlags().Lookup(f.Name) != nil,
+			Required:            required,
+			Hidden:              f.Hidden,
+			Shared:              shared,
+			Deprecated:          f.Deprecated,
+			ShorthandDeprecated: f.ShorthandDeprecated,
+			Shadows:             declaring(cmd.Parent(), f.Name),
+		})
+		for _, k := range flagGroupKinds {
+			for _, group := range f.Annotations[k.annotation] {
+				if key := k.kind + " " + group; !groups[key] {
+					groups[key] = true
+					out.FlagGroups = append(out.FlagGroups, Group{k.kind, strings.Fields(group)})
+				}
+			}
+		}
+	})
+	cmd.InheritedFlags().VisitAll(func(f *pflag.Flag) {
+		out.InheritedFlags = append(out.InheritedFlags, Inherited{f.Name, declaring(cmd.Parent(), f.Name)})
+	})
+	for _, sub := range cmd.Commands() {
+		out.SubCommands = append(out.SubCommands, Describe(sub))
+	}
+	return out
+}
+
+// declaring returns the path of the nearest of cmd and its ancestors that
+// declares a persistent flag named name, or "" if none does.
+func declaring(cmd *cobra.Command, name string) string {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.PersistentFlags().Lookup(name) != nil {
+			return cmd.CommandPath()
+		}
+	}
+	return ""
+}
+
+// describeArgs names cmd's Args validator and probes it with up to
+// argsProbeLimit arguments, taken from ValidArgs when it has them, to find
+// how many it accepts. Validators that check the arguments' form rather
+// than their number may report no bounds.
+func describeArgs(cmd *cobra.Command) *Args {
+	if cmd.Args == nil {
+		return nil
+	}
+	name := runtime.FuncForPC(reflect.ValueOf(cmd.Args).Pointer()).Name()
+	name = strings.TrimSuffix(name, ".func1")
+	name = strings.TrimPrefix(name, "github.com/spf13/cobra.")
+	out := &Args{Validator: name}
+	minimum, maximum := -1, -1
+	for n := 0; n <= argsProbeLimit; n++ {
+		args := make([]string, n)
+		for i := range args {
+			args[i] = "arg"
+			if len(cmd.ValidArgs) > 0 {
+				args[i] = cmd.ValidArgs[i%len(cmd.ValidArgs)]
+			}
+		}
+		if cmd.Args(cmd, args) == nil {
+			if minimum < 0 {
+				minimum = n
+			}
+			maximum = n
+		}
+	}
+	if minimum >= 0 {
+		out.Min = &minimum
+		if maximum < argsProbeLimit {
+			out.Max = &maximum
+		}
+	}
+	return out
+}
+
+// interspersed reports whether cmd's flags may follow its positional
+// arguments, which pflag has no getter for.
+func interspersed(cmd *cobra.Command) bool {
+	return reflect.ValueOf(cmd.Flags()).Elem().FieldByName("interspersed").Bool()
+}
@@ -0,0 +1,276 @@
+// Package corpus embeds the CLI help fixtures, so test suites and other
+// tools can use them without knowing where the fixtures directory is.
+//
+// Each Entry is one captured output (a .help, .err, .out or .complete file)
+// with what is known about it: the sidecar channels the capture kept, the
+// command line and environment it was captured with, and the ground truth
+// for the command tree it came from, and the warnings a parse of it is
+// expected to report. FS gives access to every other fixture
+// (generated docs, completion scripts, manifests) by path, including
+// manifest.json, which records each fixture's hash and provenance.
+//
+// The fixtures are embedded from corpus.tar, which pack.go writes from
+// the fixtures directory; generate.sh repacks it after regenerating them.
+package corpus
+
+//go:generate go run pack.go
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"testing/fstest"
+)
+
+//go:embed corpus.tar
+var archive []byte
+
+// Entry is one captured fixture.
+type Entry struct {
+	// Path is the fixture's path relative to the fixtures directory, such
+	// as "cobra/example-build.help".
+	Path string
+	// Framework is the fixture directory the entry belongs to, such as
+	// "cobra" or "urfave-v2".
+	Framework string
+	// Help is the fixture's contents.
+	Help string
+	// Stdout and Stderr are the channels the fixture leaves out, when the
+	// capture kept them (see capture in generate.sh); otherwise empty.
+	Stdout, Stderr string
+	// Truth is the ground truth describing the command tree the fixture
+	// was captured from, when the fixture binary exports one, and TruthPath
+	// names the file it came from: cobra's example.tree.json, the
+	// <binary>.tree.json of each workspace binary, or the example.tree.json
+	// of a randomly generated CLI. Both are empty otherwise.
+	Truth     []byte
+	TruthPath string
+	// Invocation is how the fixture was captured, or nil if it was not
+	// captured from a single command line.
+	Invocation *Invocation
+	// Warnings are the warnings a lenient parse of the fixture must and may
+	// report, as the expected_warnings.json in its directory declares them,
+	// or nil if that declares none for it.
+	Warnings *ExpectedWarnings
+}
+
+// Invocation is a command line a fixture was captured from, as recorded
+// in exit-codes.jsonl.
+type Invocation struct {
+	Argv []string          `json:"argv"`
+	Env  map[string]string `json:"env"`
+	Exit int               `json:"exit"`
+}
+
+// ExpectedWarnings are the warnings a lenient parse of a fixture reports
+// when it parses as well as it is known to: each of Require, any of Allow,
+// and no others. A fixture declared with neither, as {}, parses with no
+// warnings at all.
+//
+// The expected_warnings.json in a fixture directory declares them for the
+// fixtures in it, by file name. It is written by hand, to pin down what
+// parsers tell of the negative and mutated corpora, and of known-imperfect
+// parses anywhere else, so regenerating fixtures keeps it.
+type ExpectedWarnings struct {
+	// Require are the warnings the parse must report, as for the problems
+	// a malformed fixture was made with.
+	Require []ExpectedWarning `json:"require,omitempty"`
+	// Allow are the warnings it may report, as for what the parser is
+	// known to read imperfectly.
+	Allow []ExpectedWarning `json:"allow,omitempty"`
+}
+
+// ExpectedWarning matches a warning: its line and column, 1-based, and
+// the start of its message. A zero Line or Column matches any.
+type ExpectedWarning struct {
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// Matches reports whether w matches the warning at line and column with
+// message.
+func (w ExpectedWarning) Matches(line, column int, message string) bool {
+	return (w.Line == 0 || w.Line == line) && (w.Column == 0 || w.Column == column) &&
+		strings.HasPrefix(message, w.Message)
+}
+
+// expectedWarningsFile is the name of the file declaring the expected
+// warnings of the fixtures in its directory.
+const expectedWarningsFile = "expected_warnings.json"
+
+// captureExts are the extensions of fixtures that hold captured output.
+var captureExts = map[string]bool{".help": true, ".err": true, ".out": true, ".complete": true}
+
+// truthFile is the name of the ground-truth file, which covers the fixtures
+// in its directory and below. A directory capturing several binaries holds
+// a tree per binary, <binary>.tree.json, which covers the fixtures named for
+// the binary before it.
+const truthFile = "example.tree.json"
+
+var (
+	load    sync.Once
+	files   fstest.MapFS
+	entries []Entry
+)
+
+// FS returns every embedded fixture, by its path relative to the fixtures
+// directory.
+func FS() fs.FS {
+	load.Do(unpack)
+	return files
+}
+
+// All returns every entry, sorted by path.
+func All() []Entry {
+	load.Do(unpack)
+	return append([]Entry(nil), entries...)
+}
+
+// ByFramework returns the entries of one fixture directory, such as
+// "cobra", sorted by path.
+func ByFramework(framework string) []Entry {
+	load.Do(unpack)
+	var out []Entry
+	for _, e := range entries {
+		if e.Framework == framework {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Lookup returns the entry for the fixture at path.
+func Lookup(path string) (Entry, bool) {
+	load.Do(unpack)
+	i := sort.Search(len(entries), func(i int) bool { return entries[i].Path >= path })
+	if i < len(entries) && entries[i].Path == path {
+		return entries[i], true
+	}
+	return Entry{}, false
+}
+
+// Frameworks returns the names of the fixture directories with entries,
+// sorted.
+func Frameworks() []string {
+	load.Do(unpack)
+	var out []string
+	for _, e := range entries {
+		if len(out) == 0 || out[len(out)-1] != e.Framework {
+			out = append(out, e.Framework)
+		}
+	}
+	return out
+}
+
+// unpack reads the archive into files and builds entries from it. The
+// archive is written by pack.go, so any error reading it is a bug.
+func unpack() {
+	files = fstest.MapFS{}
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			panic("corpus: " + err.Error())
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			panic("corpus: " + err.Error())
+		}
+		files[hdr.Name] = &fstest.MapFile{Data: data, Mode: 0o444}
+	}
+
+	invocations := map[string]*Invocation{}
+	warnings := map[string]*ExpectedWarnings{}
+	for name, f := range files {
+		switch path.Base(name) {
+		case "exit-codes.jsonl":
+			readInvocations(f.Data, invocations)
+		case expectedWarningsFile:
+			readExpectedWarnings(name, f.Data, warnings)
+		}
+	}
+	for name, f := range files {
+		if !captureExts[path.Ext(name)] {
+			continue
+		}
+		e := Entry{
+			Path:       name,
+			Framework:  name[:strings.Index(name, "/")],
+			Help:       string(f.Data),
+			Invocation: invocations[name],
+			Warnings:   warnings[name],
+		}
+		if f, ok := files[name+".stdout"]; ok {
+			e.Stdout = string(f.Data)
+		}
+		if f, ok := files[name+".stderr"]; ok {
+			e.Stderr = string(f.Data)
+		}
+		e.TruthPath, e.Truth = truthFor(name)
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+}
+
+// readInvocations adds the invocations recorded in an exit-codes.jsonl
+// manifest to m, by fixture path.
+func readInvocations(data []byte, m map[string]*Invocation) {
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		var line struct {
+			Fixture string `json:"fixture"`
+			Invocation
+		}
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			panic("corpus: exit-codes.jsonl: " + err.Error())
+		}
+		inv := line.Invocation
+		m[line.Fixture] = &inv
+	}
+}
+
+// readExpectedWarnings adds the warnings declared in the
+// expected_warnings.json at name to m, by fixture path.
+func readExpectedWarnings(name string, data []byte, m map[string]*ExpectedWarnings) {
+	var byFixture map[string]*ExpectedWarnings
+	if err := json.Unmarshal(data, &byFixture); err != nil {
+		panic("corpus: " + name + ": " + err.Error())
+	}
+	for fixture, w := range byFixture {
+		if w == nil {
+			w = &ExpectedWarnings{}
+		}
+		m[path.Join(path.Dir(name), fixture)] = w
+	}
+}
+
+// truthFor returns the nearest ground-truth file covering the fixture at
+// name: one in its directory or a parent of it within its framework.
+func truthFor(name string) (string, []byte) {
+	// binary is the name of the binary the fixture is named for, the
+	// first word of its file name.
+	binary := path.Base(name)
+	if i := strings.IndexAny(binary, "-._"); i >= 0 {
+		binary = binary[:i]
+	}
+	for dir := path.Dir(name); dir != "."; dir = path.Dir(dir) {
+		for _, t := range []string{binary + ".tree.json", truthFile} {
+			if f, ok := files[path.Join(dir, t)]; ok {
+				return path.Join(dir, t), f.Data
+			}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,3 @@
+module github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus
+
+go 1.21
@@ -0,0 +1,309 @@
+//go:build ignore
+
+// pack writes corpus.tar: every fixture under the fixtures directory,
+// leaving out the sources, binaries and scripts that produce them. go:embed
+// cannot reach files in other modules, which every fixture directory is, so
+// the corpus package embeds this archive instead. It is not compressed, so
+// that git stores each repacked archive as a small delta of the last rather
+// than as a blob of its own.
+//
+// It also writes ../manifest.json, packed as manifest.json, recording where
+// each fixture came from: its hash, the framework and version that printed
+// it, the command line and environment it was captured with, and the
+// generate.sh and Go toolchain that ran. When a regenerated fixture differs
+// from the committed one, comparing manifests shows what else differed.
+//
+// The archive depends only on the fixtures' paths and contents, so packing
+// an unchanged tree reproduces it byte for byte.
+package main
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// skipDirs hold tools, build output and dependencies rather than fixtures.
+var skipDirs = map[string]bool{
+	"corpus":       true,
+	"fixturegen":   true,
+	"node_modules": true,
+	"src":          true,
+	"target":       true,
+}
+
+// sourceExts are the extensions of fixture sources, not captured output.
+var sourceExts = map[string]bool{
+	".go": true, ".js": true, ".mod": true, ".py": true, ".rs": true, ".sum": true, ".toml": true,
+}
+
+// library is the argument parsing library behind a fixture directory.
+type library struct {
+	name string
+	// file declares the version used, relative to the fixture directory:
+	// go.mod, Cargo.toml or package.json. It is empty for standard
+	// libraries and unpinned packages.
+	file string
+}
+
+var libraries = map[string]library{
+	"argparse":       {"argparse", ""},
+	"clap":           {"clap", "Cargo.toml"},
+	"click":          {"click", ""},
+	"cobra":          {"github.com/spf13/cobra", "go.mod"},
+	"cobra-flat":     {"github.com/spf13/cobra", "go.mod"},
+	"cobra-platform": {"github.com/spf13/cobra", "go.mod"},
+	"commander":      {"commander", "package.json"},
+	"docker":         {"github.com/spf13/cobra", "go.mod"},
+	"docopt":         {"github.com/docopt/docopt-go", "go.mod"},
+	"external":       {"os", ""},
+	"ffcli":          {"github.com/peterbourgon/ff/v3", "go.mod"},
+	"flag":           {"flag", ""},
+	"gh":             {"github.com/spf13/cobra", "go.mod"},
+	"go-flags":       {"github.com/jessevdk/go-flags", "go.mod"},
+	"kingpin":        {"github.com/alecthomas/kingpin/v2", "go.mod"},
+	"kong":           {"github.com/alecthomas/kong", "go.mod"},
+	"kubectl":        {"github.com/spf13/cobra", "go.mod"},
+	"mitchellh-cli":  {"github.com/mitchellh/cli", "go.mod"},
+	"mixed":          {"github.com/spf13/cobra", "go.mod"},
+	"multicall":      {"flag", ""},
+	"spec":           {"github.com/spf13/cobra", "go.mod"},
+	"urfave-v2":      {"github.com/urfave/cli/v2", "go.mod"},
+	"urfave-v3":      {"github.com/urfave/cli/v3", "go.mod"},
+	"workspace":      {"github.com/spf13/cobra", "go.mod"},
+	"yargs":          {"yargs", "package.json"},
+}
+
+// versionDirRE matches the directories of fixtures captured with another
+// version of the library, such as cobra/versions/v1.9.1/.
+var versionDirRE = regexp.MustCompile(`^[^/]+/versions/(v[^/]+)/`)
+
+type manifest struct {
+	Generator generator `json:"generator"`
+	Go        string    `json:"go"`
+	GOOS      string    `json:"goos"`
+	GOARCH    string    `json:"goarch"`
+	Fixtures  []fixture `json:"fixtures"`
+}
+
+// generator identifies the generate.sh that produced the fixtures.
+type generator struct {
+	Script string `json:"script"`
+	SHA256 string `json:"sha256"`
+}
+
+type fixture struct {
+	Path      string `json:"path"`
+	SHA256    string `json:"sha256"`
+	Framework string `json:"framework"`
+	Library   string `json:"library"`
+	Version   string `json:"version,omitempty"`
+	// Argv, Env and Exit come from exit-codes.jsonl, for fixtures captured
+	// from a single command line.
+	Argv []string         `json:"argv,omitempty"`
+	Env  *json.RawMessage `json:"env,omitempty"`
+	Exit *int             `json:"exit,omitempty"`
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("pack: ")
+	root := ".."
+	var paths []string
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(root, p)
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if isFixture(rel, d) {
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	sort.Strings(paths)
+
+	m, err := describe(root, paths)
+	if err != nil {
+		log.Fatal(err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(filepath.Join(root, "manifest.json"), data, 0o644); err != nil {
+		log.Fatal(err)
+	}
+	paths = append(paths, "manifest.json")
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, p := range paths {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(p)))
+		if err != nil {
+			log.Fatal(err)
+		}
+		hdr := &tar.Header{Name: p, Mode: 0o644, Size: int64(len(data)), Format: tar.FormatPAX}
+		if err := tw.WriteHeader(hdr); err != nil {
+			log.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile("corpus.tar", buf.Bytes(), 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// describe builds the manifest of the fixtures at paths.
+func describe(root string, paths []string) (*manifest, error) {
+	script, err := os.ReadFile(filepath.Join(root, "generate.sh"))
+	if err != nil {
+		return nil, err
+	}
+	m := &manifest{
+		Generator: generator{"generate.sh", hash(script)},
+		Go:        runtime.Version(),
+		GOOS:      runtime.GOOS,
+		GOARCH:    runtime.GOARCH,
+	}
+	invocations := map[string]fixture{}
+	versions := map[string]string{}
+	for _, p := range paths {
+		if path.Base(p) == "exit-codes.jsonl" {
+			if err := readInvocations(filepath.Join(root, filepath.FromSlash(p)), invocations); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, p := range paths {
+		data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(p)))
+		if err != nil {
+			return nil, err
+		}
+		f := invocations[p]
+		f.Path, f.SHA256 = p, hash(data)
+		f.Framework = p[:strings.Index(p, "/")]
+		lib := libraries[f.Framework]
+		f.Library = lib.name
+		if v, ok := versions[f.Framework]; ok {
+			f.Version = v
+		} else {
+			f.Version = declaredVersion(filepath.Join(root, f.Framework), lib)
+			versions[f.Framework] = f.Version
+		}
+		if m := versionDirRE.FindStringSubmatch(p); m != nil {
+			f.Version = m[1]
+		}
+		m.Fixtures = append(m.Fixtures, f)
+	}
+	return m, nil
+}
+
+// readInvocations adds the command lines recorded in an exit-codes.jsonl
+// manifest to m, by fixture path.
+func readInvocations(file string, m map[string]fixture) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		var line struct {
+			Fixture string          `json:"fixture"`
+			Argv    []string        `json:"argv"`
+			Env     json.RawMessage `json:"env"`
+			Exit    int             `json:"exit"`
+		}
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		m[line.Fixture] = fixture{Argv: line.Argv, Env: &line.Env, Exit: &line.Exit}
+	}
+	return sc.Err()
+}
+
+var (
+	goRequireRE = regexp.MustCompile(`(?m)^(?:require)?\s+(\S+) (v\S+)`)
+	cargoDepRE  = regexp.MustCompile(`(?m)^(\S+) = (?:"([^"]+)"|\{ version = "([^"]+)")`)
+)
+
+// declaredVersion returns the version of lib that the fixture directory
+// dir declares, or "" if it declares none.
+func declaredVersion(dir string, lib library) string {
+	if lib.file == "" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(dir, lib.file))
+	if err != nil {
+		return ""
+	}
+	switch lib.file {
+	case "go.mod":
+		for _, m := range goRequireRE.FindAllStringSubmatch(string(data), -1) {
+			if m[1] == lib.name {
+				return m[2]
+			}
+		}
+	case "Cargo.toml":
+		for _, m := range cargoDepRE.FindAllStringSubmatch(string(data), -1) {
+			if m[1] == lib.name {
+				return m[2] + m[3]
+			}
+		}
+	case "package.json":
+		var pkg struct {
+			Dependencies map[string]string `json:"dependencies"`
+		}
+		if json.Unmarshal(data, &pkg) == nil {
+			return pkg.Dependencies[lib.name]
+		}
+	}
+	return ""
+}
+
+func hash(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// isFixture reports whether the file at rel, relative to the fixtures
+// directory, is a fixture: a regular, non-executable file inside a fixture
+// directory that is not source code or a package manifest.
+func isFixture(rel string, d fs.DirEntry) bool {
+	if !strings.Contains(rel, "/") || !d.Type().IsRegular() {
+		return false
+	}
+	if info, err := d.Info(); err != nil || info.Mode()&0o111 != 0 {
+		return false
+	}
+	name := path.Base(rel)
+	return !sourceExts[path.Ext(name)] && !strings.HasPrefix(name, "package") && !strings.HasPrefix(name, "Cargo.")
+}
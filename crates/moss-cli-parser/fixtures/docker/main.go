@@ -0,0 +1,194 @@
+// Example CLI replicating docker's help conventions, for testing help output
+// parsing: usage first, options in a wide column before the commands, a
+// "Management Commands:" section for commands with subcommands, and a
+// closing "Run 'example COMMAND --help'" hint.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var rootCmd = &cobra.Command{
+	Use:              "example [OPTIONS] COMMAND [ARG...]",
+	Short:            "A self-sufficient runtime for containers",
+	SilenceUsage:     true,
+	SilenceErrors:    true,
+	TraverseChildren: true,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if len(args) == 0 {
+			return nil
+		}
+		return fmt.Errorf("example: '%s' is not an example command.\nSee 'example --help'", args[0])
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+	Version: "1.0.0, build 0000000",
+}
+
+// flagErrorFunc reports flag errors docker's way, pointing at --help.
+func flagErrorFunc(cmd *cobra.Command, err error) error {
+	return fmt.Errorf("%w\nSee '%s --help'.", err, cmd.CommandPath())
+}
+
+// operation returns a command that only echoes its flags and arguments.
+func operation(use, short string) *cobra.Command {
+	return &cobra.Command{
+		Use:   use,
+		Short: short,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Flags().Visit(func(f *pflag.Flag) {
+				fmt.Printf("%s=%s\n", f.Name, f.Value)
+			})
+			fmt.Println(cmd.CommandPath(), args)
+		},
+	}
+}
+
+// management returns a command that only groups subcommands.
+func management(use, short string, subs ...*cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintf(os.Stderr, "\"%s\" requires a subcommand.\n", cmd.CommandPath())
+			cmd.Help()
+		},
+	}
+	cmd.AddCommand(subs...)
+	return cmd
+}
+
+// newRunCommand builds run, which docker offers both as "container run" and
+// as a top-level shortcut.
+func newRunCommand() *cobra.Command {
+	cmd := operation("run [OPTIONS] IMAGE [COMMAND] [ARG...]", "Create and run a new container from an image")
+	cmd.Args = cobra.MinimumNArgs(1)
+	cmd.Annotations = map[string]string{"aliases": "example container run, example run"}
+	cmd.Flags().SetInterspersed(false)
+	f := cmd.Flags()
+	f.Var(&listValue{}, "add-host", "Add a custom host-to-IP mapping (host:ip)")
+	f.VarP(&listValue{}, "attach", "a", "Attach to STDIN, STDOUT or STDERR")
+	f.String("cpus", "", "Number of CPUs")
+	f.BoolP("detach", "d", false, "Run container in background and print container ID")
+	f.VarP(&listValue{}, "env", "e", "Set environment variables")
+	f.Var(&listValue{}, "env-file", "Read in a file of environment variables")
+	f.String("entrypoint", "", "Overwrite the default ENTRYPOINT of the image")
+	f.StringP("hostname", "h", "", "Container host name")
+	f.BoolP("interactive", "i", false, "Keep STDIN open even if not attached")
+	f.StringP("memory", "m", "", "Memory limit")
+	f.String("name", "", "Assign a name to the container")
+	f.String("pull", "missing", `Pull image before running ("always", "missing", "never")`)
+	f.VarP(&listValue{}, "publish", "p", "Publish a container's port(s) to the host")
+	f.String("restart", "no", "Restart policy to apply when a container exits")
+	f.Bool("rm", false, "Automatically remove the container when it exits")
+	f.Duration("stop-timeout", 0, "Timeout to stop a container")
+	f.BoolP("tty", "t", false, "Allocate a pseudo-TTY")
+	f.VarP(&listValue{}, "volume", "v", "Bind mount a volume")
+	f.StringP("workdir", "w", "", "Working directory inside the container")
+	return cmd
+}
+
+// newPsCommand builds ps, docker's shortcut for "container ls".
+func newPsCommand(use string) *cobra.Command {
+	cmd := operation(use, "List containers")
+	cmd.Annotations = map[string]string{"aliases": "example container ls, example container list, example container ps, example ps"}
+	cmd.Args = cobra.NoArgs
+	f := cmd.Flags()
+	f.BoolP("all", "a", false, "Show all containers (default shows just running)")
+	f.StringP("filter", "f", "", "Filter output based on conditions provided")
+	f.String("format", "", "Format output using a custom template:\n'table':            Print output in table format with column headers (default)\n'table TEMPLATE':   Print output in table format using the given Go template\n'json':             Print in JSON format")
+	f.IntP("last", "n", -1, "Show n last created containers (includes all states)")
+	f.BoolP("quiet", "q", false, "Only display container IDs")
+	f.BoolP("size", "s", false, "Display total file sizes")
+	return cmd
+}
+
+func init() {
+	f := rootCmd.Flags()
+	f.String("config", "$HOME/.example", "Location of client config files")
+	f.StringP("context", "c", "", `Name of the context to use to connect to the daemon (overrides EXAMPLE_HOST env var and default context set with "example context use")`)
+	f.BoolP("debug", "D", false, "Enable debug mode")
+	f.VarP(&listValue{}, "host", "H", "Daemon socket to connect to")
+	f.StringP("log-level", "l", "info", `Set the logging level ("debug", "info", "warn", "error", "fatal")`)
+	f.Bool("tls", false, "Use TLS; implied by --tlsverify")
+	f.String("tlscacert", "$HOME/.example/ca.pem", "Trust certs signed only by this CA")
+	f.Bool("tlsverify", false, "Use TLS and verify the remote")
+	f.BoolP("version", "v", false, "Print version information and quit")
+
+	rootCmd.AddCommand(
+		management("builder", "Manage builds",
+			operation("prune", "Remove build cache"),
+			operation("ls", "List builder instances"),
+		),
+		management("container", "Manage containers",
+			operation("attach [OPTIONS] CONTAINER", "Attach local standard input, output, and error streams to a running container"),
+			operation("exec [OPTIONS] CONTAINER COMMAND [ARG...]", "Execute a command in a running container"),
+			newPsCommand("ls [OPTIONS]"),
+			newRunCommand(),
+			operation("stop [OPTIONS] CONTAINER [CONTAINER...]", "Stop one or more running containers"),
+		),
+		management("image", "Manage images",
+			operation("ls [OPTIONS] [REPOSITORY[:TAG]]", "List images"),
+			operation("pull [OPTIONS] NAME[:TAG|@DIGEST]", "Download an image from a registry"),
+			operation("push [OPTIONS] NAME[:TAG]", "Upload an image to a registry"),
+		),
+		management("network", "Manage networks",
+			operation("create [OPTIONS] NETWORK", "Create a network"),
+			operation("ls [OPTIONS]", "List networks"),
+		),
+		management("system", "Manage Example",
+			operation("df [OPTIONS]", "Show example disk usage"),
+			operation("prune [OPTIONS]", "Remove unused data"),
+		),
+		management("volume", "Manage volumes",
+			operation("create [OPTIONS] [VOLUME]", "Create a volume"),
+			operation("ls [OPTIONS]", "List volumes"),
+		),
+		operation("attach [OPTIONS] CONTAINER", "Attach local standard input, output, and error streams to a running container"),
+		operation("build [OPTIONS] PATH | URL | -", "Build an image from an Example file"),
+		operation("exec [OPTIONS] CONTAINER COMMAND [ARG...]", "Execute a command in a running container"),
+		operation("images [OPTIONS] [REPOSITORY[:TAG]]", "List images"),
+		operation("login [OPTIONS] [SERVER]", "Log in to a registry"),
+		operation("logout [SERVER]", "Log out from a registry"),
+		newPsCommand("ps [OPTIONS]"),
+		operation("pull [OPTIONS] NAME[:TAG|@DIGEST]", "Download an image from a registry"),
+		operation("push [OPTIONS] NAME[:TAG]", "Upload an image to a registry"),
+		newRunCommand(),
+		operation("stop [OPTIONS] CONTAINER [CONTAINER...]", "Stop one or more running containers"),
+		operation("version [OPTIONS]", "Show the Example version information"),
+	)
+
+	// docker gives every command --help without a shorthand, so that run can
+	// use -h for --hostname, and drops cobra's help and completion commands.
+	walk(rootCmd, func(cmd *cobra.Command) {
+		cmd.Flags().Bool("help", false, "Print usage")
+		cmd.DisableFlagsInUseLine = true
+	})
+	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true})
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+	rootCmd.SetUsageTemplate(usageTemplate)
+	rootCmd.SetHelpTemplate(helpTemplate)
+	rootCmd.SetFlagErrorFunc(flagErrorFunc)
+	rootCmd.SetVersionTemplate("Example version {{.Version}}\n")
+}
+
+// walk calls fn on cmd and every command below it.
+func walk(cmd *cobra.Command, fn func(*cobra.Command)) {
+	fn(cmd)
+	for _, child := range cmd.Commands() {
+		walk(child, fn)
+	}
+}
+
+func main() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(125)
+	}
+}
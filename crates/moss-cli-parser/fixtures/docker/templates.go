@@ -0,0 +1,94 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// docker replaces cobra's templates so that help opens with the usage line,
+// lists options before commands, and splits commands with subcommands off
+// as "Management Commands:".
+const usageTemplate = `Usage:
+{{- if not .HasSubCommands}}  {{.UseLine}}{{end}}
+{{- if .HasSubCommands}}  {{ .CommandPath}}{{- if .HasAvailableFlags}} [OPTIONS]{{end}} COMMAND{{end}}
+
+{{if ne .Long ""}}{{ .Long | trim }}{{ else }}{{ .Short | trim }}{{end}}
+{{- if hasAliases .}}
+
+Aliases:
+  {{aliases .}}
+
+{{- end}}
+{{- if .HasExample}}
+
+Examples:
+{{ .Example }}
+
+{{- end}}
+{{- if .HasAvailableFlags}}
+
+Options:
+{{ wrappedFlagUsages . | trimRightSpace}}
+
+{{- end}}
+{{- if managementSubCommands . }}
+
+Management Commands:
+
+{{- range managementSubCommands . }}
+  {{rpad .Name (add .NamePadding 1)}}{{.Short}}
+{{- end}}
+
+{{- end}}
+{{- if operationSubCommands .}}
+
+Commands:
+
+{{- range operationSubCommands . }}
+  {{rpad .Name .NamePadding }} {{.Short}}
+{{- end}}
+{{- end}}
+
+{{- if .HasSubCommands }}
+
+Run '{{.CommandPath}} COMMAND --help' for more information on a command.
+{{- end}}
+`
+
+// helpTemplate starts with a blank line, as docker's does.
+const helpTemplate = `
+{{if or .Runnable .HasSubCommands}}{{.UsageString}}{{end}}`
+
+// flagWidth is the width docker wraps flag usage to when not on a terminal.
+const flagWidth = 80
+
+func init() {
+	cobra.AddTemplateFunc("add", func(a, b int) int { return a + b })
+	cobra.AddTemplateFunc("hasAliases", func(c *cobra.Command) bool { return c.Annotations["aliases"] != "" })
+	cobra.AddTemplateFunc("aliases", func(c *cobra.Command) string { return c.Annotations["aliases"] })
+	cobra.AddTemplateFunc("wrappedFlagUsages", func(c *cobra.Command) string {
+		return c.Flags().FlagUsagesWrapped(flagWidth - 1)
+	})
+	cobra.AddTemplateFunc("managementSubCommands", func(c *cobra.Command) []*cobra.Command {
+		return subCommands(c, true)
+	})
+	cobra.AddTemplateFunc("operationSubCommands", func(c *cobra.Command) []*cobra.Command {
+		return subCommands(c, false)
+	})
+	cobra.AddTemplateFunc("trimRightSpace", func(s string) string {
+		return strings.TrimRightFunc(s, func(r rune) bool { return r == ' ' || r == '\n' })
+	})
+}
+
+// subCommands returns the available subcommands of c that do, or do not,
+// have subcommands of their own.
+func subCommands(c *cobra.Command, management bool) []*cobra.Command {
+	var cmds []*cobra.Command
+	for _, sub := range c.Commands() {
+		if sub.IsAvailableCommand() && sub.HasSubCommands() == management {
+			cmds = append(cmds, sub)
+		}
+	}
+	return cmds
+}
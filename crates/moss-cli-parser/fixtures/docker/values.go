@@ -0,0 +1,15 @@
+package main
+
+import "strings"
+
+// listValue is docker's repeatable "list" option.
+type listValue []string
+
+func (l *listValue) String() string { return strings.Join(*l, ",") }
+
+func (l *listValue) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+func (l *listValue) Type() string { return "list" }
@@ -0,0 +1,5 @@
+module example
+
+go 1.21
+
+require github.com/docopt/docopt-go v0.0.0-20180111231733-ee0de3bc6815
@@ -0,0 +1,47 @@
+// Example docopt CLI for testing help output parsing. The usage text is the
+// grammar: docopt parses the command line against it.
+package main
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/docopt/docopt-go"
+)
+
+const usage = `Example, an example CLI tool for testing.
+
+Usage:
+  example build [--release | --debug] [--target=<dir>] [<package>...]
+  example run [-v...] <program> [--] [<args>...]
+  example clean [--all]
+  example cluster (list | ls) [--output=<format>]
+  example cluster delete <name> [--force] --reason=<text>
+  example config (get <key> | set <key> <value>...)
+  example (-h | --help)
+  example --version
+
+Options:
+  -h --help            Show this screen.
+  --version            Show version.
+  --release            Build in release mode.
+  --debug              Build in debug mode, the default.
+  --target=<dir>       Directory to write artifacts to [default: target].
+  -v --verbose         Enable verbose output; repeat for more.
+  --all                Also remove downloaded dependencies.
+  -o --output=<format>  Output format: json, yaml or table [default: table].
+  -f --force           Do not ask for confirmation.
+  --reason=<text>      Why the cluster is being deleted.
+`
+
+func main() {
+	opts, err := docopt.ParseArgs(usage, os.Args[1:], "1.0.0")
+	if err != nil {
+		os.Exit(1)
+	}
+	// Print what docopt matched, which is the ground truth for the grammar.
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	enc.Encode(opts)
+}
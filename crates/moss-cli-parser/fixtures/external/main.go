@@ -0,0 +1,122 @@
+// Example git-style CLI for testing help output parsing. Commands it does not
+// know are run as example-<command> from PATH, the way git runs git-<command>,
+// so its help lists only a part of what it can do.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+)
+
+// builtin is a command implemented by example itself.
+type builtin struct {
+	name  string
+	brief string
+}
+
+var builtins = []builtin{
+	{"build", "Build the project"},
+	{"clean", "Remove build artifacts"},
+	{"run", "Run the project"},
+	{"status", "Show the status of project components"},
+}
+
+const usage = "usage: example [--version] [--help] <command> [<args>]"
+
+func main() {
+	args := os.Args[1:]
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" || args[0] == "help" && len(args) == 1 {
+		printHelp()
+		return
+	}
+	if args[0] == "--version" {
+		fmt.Println("example version 1.0.0")
+		return
+	}
+	if args[0] == "help" && args[1] == "-a" {
+		printAll()
+		return
+	}
+	if args[0] == "help" {
+		args = []string{args[1], "--help"}
+	}
+	for _, b := range builtins {
+		if b.name == args[0] {
+			runBuiltin(b, args[1:])
+			return
+		}
+	}
+	runExternal(args[0], args[1:])
+}
+
+func printHelp() {
+	fmt.Printf("%s\n\nThese are common example commands:\n\n", usage)
+	for _, b := range builtins {
+		fmt.Printf("   %-10s %s\n", b.name, b.brief)
+	}
+	fmt.Print(`
+'example help -a' lists available subcommands, including those found on
+your $PATH. See 'example help <command>' to read about a specific
+subcommand.
+`)
+}
+
+// printAll lists the built-in commands and then every example-<command>
+// found on PATH, as git help -a does.
+func printAll() {
+	fmt.Printf("%s\n\nBuilt-in commands\n", usage)
+	for _, b := range builtins {
+		fmt.Printf("   %-10s %s\n", b.name, b.brief)
+	}
+	seen := map[string]bool{}
+	var external []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		paths, _ := filepath.Glob(filepath.Join(dir, "example-*"))
+		for _, path := range paths {
+			name := strings.TrimPrefix(filepath.Base(path), "example-")
+			if info, err := os.Stat(path); err == nil && info.Mode()&0o111 != 0 && !seen[name] {
+				seen[name] = true
+				external = append(external, name)
+			}
+		}
+	}
+	if len(external) > 0 {
+		sort.Strings(external)
+		fmt.Printf("\nExternal commands\n")
+		for _, name := range external {
+			fmt.Printf("   %s\n", name)
+		}
+	}
+}
+
+func runBuiltin(b builtin, args []string) {
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" {
+			fmt.Printf("usage: example %s [<args>]\n\n    %s.\n", b.name, b.brief)
+			return
+		}
+	}
+	fmt.Printf("%s %v\n", b.name, args)
+}
+
+// runExternal replaces the process with example-<name> from PATH, passing
+// args through untouched.
+func runExternal(name string, args []string) {
+	path, err := exec.LookPath("example-" + name)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "example: '%s' is not an example command. See 'example --help'.\n", name)
+		os.Exit(1)
+	}
+	err = syscall.Exec(path, append([]string{"example-" + name}, args...), os.Environ())
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		fmt.Fprintf(os.Stderr, "example: cannot run example-%s: %v\n", name, err)
+	}
+	os.Exit(126)
+}
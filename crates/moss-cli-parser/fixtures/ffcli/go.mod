@@ -0,0 +1,5 @@
+module example
+
+go 1.21
+
+require github.com/peterbourgon/ff/v3 v3.4.0
@@ -0,0 +1,110 @@
+// Example peterbourgon/ff ffcli CLI for testing help output parsing.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/peterbourgon/ff/v3"
+	"github.com/peterbourgon/ff/v3/ffcli"
+)
+
+// echo returns an Exec printing the flags that were set in fs and the
+// arguments.
+func echo(name string, fs *flag.FlagSet) func(context.Context, []string) error {
+	return func(ctx context.Context, args []string) error {
+		fs.Visit(func(f *flag.Flag) {
+			fmt.Printf("%s=%s\n", f.Name, f.Value)
+		})
+		fmt.Println(name, args)
+		return nil
+	}
+}
+
+// options resolve flags from EXAMPLE_* variables and the file named by
+// -config, in that order after the command line. ffcli says nothing of this
+// in help, so the LongHelp of each command spells it out.
+var options = []ff.Option{
+	ff.WithEnvVarPrefix("EXAMPLE"),
+	ff.WithConfigFileFlag("config"),
+	ff.WithConfigFileParser(ff.PlainParser),
+	ff.WithAllowMissingConfigFile(true),
+}
+
+func main() {
+	rootFlags := flag.NewFlagSet("example", flag.ExitOnError)
+	rootFlags.String("config", "", "config file (optional)")
+	rootFlags.Bool("v", false, "log verbose output")
+	rootFlags.Int("port", 8080, "port to listen on")
+
+	buildFlags := flag.NewFlagSet("example build", flag.ExitOnError)
+	buildFlags.String("config", "", "config file (optional)")
+	buildFlags.Bool("release", false, "build in release mode")
+	buildFlags.String("target", "target", "directory to write artifacts to")
+	buildFlags.Int("jobs", 4, "number of parallel jobs")
+	build := &ffcli.Command{
+		Name:       "build",
+		ShortUsage: "example build [flags] [<package>...]",
+		ShortHelp:  "Build the project.",
+		LongHelp: `Build compiles the named packages, or every package in the project.
+
+Every flag may also be set by an environment variable named EXAMPLE_ and
+the flag name in upper case, with dashes as underscores, or by a line of
+the form "name value" in the file given by -config. The command line wins,
+then the environment, then the file.`,
+		FlagSet: buildFlags,
+		Options: options,
+		Exec:    echo("Building", buildFlags),
+	}
+
+	startFlags := flag.NewFlagSet("example server start", flag.ExitOnError)
+	startFlags.Bool("dev", false, "enable development mode")
+	startFlags.String("listen", ":8080", "address to listen on (env EXAMPLE_LISTEN)")
+	start := &ffcli.Command{
+		Name:       "start",
+		ShortUsage: "example server start [flags]",
+		ShortHelp:  "Start the development server.",
+		FlagSet:    startFlags,
+		Options:    []ff.Option{ff.WithEnvVarPrefix("EXAMPLE")},
+		Exec:       echo("Starting server", startFlags),
+	}
+	stop := &ffcli.Command{
+		Name:       "stop",
+		ShortUsage: "example server stop",
+		ShortHelp:  "Stop the development server.",
+		Exec:       echo("Stopping server", flag.NewFlagSet("example server stop", flag.ExitOnError)),
+	}
+	server := &ffcli.Command{
+		Name:        "server",
+		ShortUsage:  "example server <subcommand>",
+		ShortHelp:   "Manage the development server.",
+		Subcommands: []*ffcli.Command{start, stop},
+		Exec: func(context.Context, []string) error {
+			return flag.ErrHelp
+		},
+	}
+
+	root := &ffcli.Command{
+		Name:        "example",
+		ShortUsage:  "example [flags] <subcommand> [flags] [<arg>...]",
+		ShortHelp:   "An example CLI tool for testing.",
+		LongHelp:    "Flags may also be set by EXAMPLE_* environment variables or a -config file.",
+		FlagSet:     rootFlags,
+		Options:     options,
+		Subcommands: []*ffcli.Command{build, server},
+		Exec: func(context.Context, []string) error {
+			return flag.ErrHelp
+		},
+	}
+
+	if err := root.ParseAndRun(context.Background(), os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,432 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// A helpFeature is something of help output a parser has to cope with,
+// found in a fixture by match.
+type helpFeature struct {
+	name, doc string
+	match     func(h *helpText) bool
+}
+
+// helpText is one fixture as coverage reads it: its lines, and the command
+// of the nearest example.tree.json it is the help of, if any.
+type helpText struct {
+	text    string
+	lines   []string
+	command *treeNode
+}
+
+// treeNode is the part of a command in an example.tree.json that coverage
+// reads: what help leaves out.
+type treeNode struct {
+	Name     string     `json:"name"`
+	Aliases  []string   `json:"aliases"`
+	Hidden   bool       `json:"hidden"`
+	Flags    []treeFlag `json:"flags"`
+	Commands []treeNode `json:"commands"`
+}
+
+type treeFlag struct {
+	Name   string `json:"name"`
+	Hidden bool   `json:"hidden"`
+}
+
+var (
+	// sectionHeaderRE matches section headers, as sectionRE does, with the name
+	// captured.
+	sectionHeaderRE = regexp.MustCompile(`^([A-Z][^:]{0,40}):$|^([A-Z][A-Z ]{2,40})$`)
+	// flagRowRE matches a flag table row, and captures its indent.
+	flagRowRE = regexp.MustCompile(`^(\s+)-{1,2}[\w?]`)
+	// shorthandOnlyRE matches a row of a flag with only a shorthand.
+	shorthandOnlyRE = regexp.MustCompile(`^\s+-[A-Za-z0-9](?: [\w.<>\[\]]+)?(?:\s{2,}|\t)\S`)
+	envRE           = regexp.MustCompile(`\$[A-Z][A-Z0-9_]+|\benv(?:ironment)?(?: var(?:iable)?)?: *\$?[A-Z][A-Z0-9_]+|\[\$[A-Z]`)
+	choicesRE       = regexp.MustCompile(`\{[\w-]+(?:,[\w-]+)+\}|\(one of:|[\[(]possible values:|\b[Oo]ne of\b`)
+	optionalValueRE = regexp.MustCompile(`--[\w-]+(?: \w+)?\[=[^\]]*\]`)
+	typedValueRE    = regexp.MustCompile(`--[\w-]+[ =](?:string|int|uint|bool|float|duration|strings|ints|stringArray|stringToString|ip|bytes)\b`)
+	placeholderRE   = regexp.MustCompile(`--[\w-]+[ =](?:<[\w-]+>|[A-Z][A-Z_]+)\b`)
+	requiredRE      = regexp.MustCompile(`(?i)[\[(]required[\])]|\(REQUIRED\)`)
+	defaultRE       = regexp.MustCompile(`[\[(]default:? `)
+	sliceDefaultRE  = regexp.MustCompile(`[\[(]default:? \[`)
+	stringDefaultRE = regexp.MustCompile(`[\[(]default:? "`)
+	mapDefaultRE    = regexp.MustCompile(`[\[(]default:? (?:map)?\[[\w-]+[:=]`)
+)
+
+// sections returns the names of the section headers in h.
+func (h *helpText) sections() []string {
+	var names []string
+	for _, l := range h.lines {
+		if m := sectionHeaderRE.FindStringSubmatch(strings.TrimRight(l, " ")); m != nil {
+			names = append(names, strings.TrimSpace(m[1]+m[2]))
+		}
+	}
+	return names
+}
+
+// section returns the lines of the first section named one of names, up to
+// the next header.
+func (h *helpText) section(names ...string) []string {
+	for i, l := range h.lines {
+		m := sectionHeaderRE.FindStringSubmatch(strings.TrimRight(l, " "))
+		if m == nil || !contains(names, strings.TrimSpace(m[1]+m[2])) {
+			continue
+		}
+		var body []string
+		for _, l := range h.lines[i+1:] {
+			if sectionHeaderRE.MatchString(strings.TrimRight(l, " ")) {
+				break
+			}
+			body = append(body, l)
+		}
+		return body
+	}
+	return nil
+}
+
+func (h *helpText) any(re *regexp.Regexp) bool { return re.MatchString(h.text) }
+
+func (h *helpText) anyLine(pred func(string) bool) bool {
+	for _, l := range h.lines {
+		if pred(l) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// standardSections are the section names the frameworks print by default;
+// any other naming flags or commands is a group of the CLI's own.
+var standardSections = []string{
+	"Flags", "Global Flags", "Options", "Global Options", "OPTIONS", "GLOBAL OPTIONS",
+	"FLAGS", "GLOBAL FLAGS", "Available Commands", "Commands", "COMMANDS", "Subcommands",
+	"Additional Commands", "Arguments", "Positional Arguments", "positional arguments", "options",
+}
+
+// features are what coverage looks for, in the order it reports them.
+var features = []helpFeature{
+	{"usage-section", "a Usage: section", func(h *helpText) bool {
+		return h.anyLine(func(l string) bool { return strings.HasPrefix(strings.ToLower(l), "usage:") || l == "USAGE" })
+	}},
+	{"multiple-usage-lines", "more than one usage line", func(h *helpText) bool {
+		return countNonBlank(h.section("Usage", "USAGE")) > 1
+	}},
+	{"aliases", "an Aliases: section", func(h *helpText) bool { return contains(h.sections(), "Aliases") }},
+	{"examples", "an Examples: section", func(h *helpText) bool {
+		s := h.sections()
+		return contains(s, "Examples") || contains(s, "EXAMPLES") || contains(s, "Example")
+	}},
+	{"subcommands", "a list of subcommands", func(h *helpText) bool {
+		s := h.sections()
+		return contains(s, "Available Commands") || contains(s, "Commands") || contains(s, "COMMANDS") || contains(s, "Subcommands")
+	}},
+	{"command-groups", "commands listed under headers of the CLI's own", func(h *helpText) bool {
+		for _, s := range h.sections() {
+			if strings.HasSuffix(strings.ToLower(s), "commands") && !contains(standardSections, s) {
+				return true
+			}
+		}
+		return false
+	}},
+	{"help-topics", "an Additional help topics: section", func(h *helpText) bool {
+		return contains(h.sections(), "Additional help topics")
+	}},
+	{"global-flags", "inherited flags in a section of their own", func(h *helpText) bool {
+		for _, s := range h.sections() {
+			if l := strings.ToLower(s); l == "global flags" || l == "global options" {
+				return true
+			}
+		}
+		return false
+	}},
+	{"flag-groups", "flags listed under headers of the CLI's own", func(h *helpText) bool {
+		for _, s := range h.sections() {
+			l := strings.ToLower(s)
+			if (strings.HasSuffix(l, "flags") || strings.HasSuffix(l, "options")) && !contains(standardSections, s) {
+				return true
+			}
+		}
+		return false
+	}},
+	{"custom-sections", "a section no framework prints by default", func(h *helpText) bool {
+		for _, s := range h.sections() {
+			if !contains(standardSections, s) && !contains([]string{"Usage", "USAGE", "Aliases", "Examples", "EXAMPLES", "Example", "Additional help topics", "NAME", "DESCRIPTION", "VERSION"}, s) {
+				return true
+			}
+		}
+		return false
+	}},
+	{"shorthand-only-flags", "a flag with a shorthand and no long name", func(h *helpText) bool {
+		return h.anyLine(shorthandOnlyRE.MatchString)
+	}},
+	{"typed-values", "flag values shown by type, as in --jobs int", func(h *helpText) bool { return h.any(typedValueRE) }},
+	{"value-placeholders", "flag values shown by name, as in --output FILE", func(h *helpText) bool { return h.any(placeholderRE) }},
+	{"optional-values", "a flag whose value may be left out, as in --color[=auto]", func(h *helpText) bool { return h.any(optionalValueRE) }},
+	{"defaults", "a flag default", func(h *helpText) bool { return h.any(defaultRE) }},
+	{"string-defaults", "a quoted string default", func(h *helpText) bool { return h.any(stringDefaultRE) }},
+	{"slice-defaults", "a list default, as in (default [a,b])", func(h *helpText) bool { return h.any(sliceDefaultRE) }},
+	{"map-defaults", "a map default, as in (default [k=v])", func(h *helpText) bool { return h.any(mapDefaultRE) }},
+	{"choices", "a flag's allowed values", func(h *helpText) bool { return h.any(choicesRE) }},
+	{"required", "a flag marked required", func(h *helpText) bool { return h.any(requiredRE) }},
+	{"env-refs", "an environment variable a flag is read from", func(h *helpText) bool { return h.any(envRE) }},
+	{"deprecations", "a deprecated flag or command", func(h *helpText) bool {
+		return strings.Contains(strings.ToLower(h.text), "deprecated")
+	}},
+	{"wrapped-descriptions", "a flag description continued on the next line", wrappedDescription},
+	{"hidden-flags", "a flag the help leaves out, per its tree", func(h *helpText) bool {
+		if h.command == nil {
+			return false
+		}
+		for _, f := range h.command.Flags {
+			if f.Hidden {
+				return true
+			}
+		}
+		return false
+	}},
+	{"hidden-commands", "a subcommand the help leaves out, per its tree", func(h *helpText) bool {
+		if h.command == nil {
+			return false
+		}
+		for _, c := range h.command.Commands {
+			if c.Hidden {
+				return true
+			}
+		}
+		return false
+	}},
+	{"tabs", "tab characters", func(h *helpText) bool { return strings.Contains(h.text, "\t") }},
+	{"trailing-whitespace", "lines ending in spaces", func(h *helpText) bool {
+		return h.anyLine(func(l string) bool { return strings.HasSuffix(l, " ") })
+	}},
+	{"crlf", "CRLF line endings", func(h *helpText) bool { return strings.Contains(h.text, "\r\n") }},
+	{"ansi-escapes", "terminal escape sequences, such as colors", func(h *helpText) bool { return strings.Contains(h.text, "\x1b[") }},
+	{"non-ascii", "text outside ASCII", func(h *helpText) bool {
+		for _, r := range h.text {
+			if r > 0x7f {
+				return true
+			}
+		}
+		return false
+	}},
+	{"long-lines", "lines wider than 120 columns", func(h *helpText) bool {
+		return h.anyLine(func(l string) bool { return len([]rune(l)) > 120 })
+	}},
+}
+
+func countNonBlank(lines []string) int {
+	n := 0
+	for _, l := range lines {
+		if strings.TrimSpace(l) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// wrappedDescription reports whether a flag row is followed by a line
+// indented past the row's own indent that is not another row: the rest of
+// its description.
+func wrappedDescription(h *helpText) bool {
+	for i := 0; i+1 < len(h.lines); i++ {
+		m := flagRowRE.FindStringSubmatch(h.lines[i])
+		next := h.lines[i+1]
+		if m == nil || strings.TrimSpace(next) == "" || flagRowRE.MatchString(next) {
+			continue
+		}
+		if indent := len(next) - len(strings.TrimLeft(next, " \t")); indent > len(m[1])+2 {
+			return true
+		}
+	}
+	return false
+}
+
+// helpExts are the extensions of the fixtures that hold help or other
+// output of a CLI, as opposed to docs, scripts and manifests.
+var helpExts = map[string]bool{".help": true, ".out": true, ".err": true, ".stdout": true, ".stderr": true}
+
+// fixtureCoverage is the features one fixture exercises.
+type fixtureCoverage struct {
+	Path     string   `json:"path"`
+	Features []string `json:"features"`
+}
+
+// coverageReport is what coverage finds over the whole corpus.
+type coverageReport struct {
+	Fixtures []fixtureCoverage `json:"fixtures"`
+	// Counts are the number of fixtures exercising each feature.
+	Counts map[string]int `json:"counts"`
+	// Uncovered are the features no fixture exercises.
+	Uncovered []string `json:"uncovered"`
+}
+
+// coverage reports which of features each help fixture in the manifest
+// exercises, restricted to those under the paths given, and which features
+// no fixture does. With strict, uncovered features are an error.
+func coverage(root string, paths []string, asJSON, verbose, strict bool) error {
+	data, err := os.ReadFile(filepath.Join(root, "manifest.json"))
+	if err != nil {
+		return err
+	}
+	var m struct {
+		Fixtures []struct {
+			Path string   `json:"path"`
+			Argv []string `json:"argv"`
+		} `json:"fixtures"`
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("manifest.json: %w", err)
+	}
+	trees := map[string]*treeNode{}
+	r := coverageReport{Counts: map[string]int{}}
+	for _, f := range m.Fixtures {
+		if !helpExts[path.Ext(f.Path)] || !under(f.Path, paths) {
+			continue
+		}
+		text, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(f.Path)))
+		if err != nil {
+			return err
+		}
+		h := &helpText{text: string(text), lines: strings.Split(strings.TrimSuffix(string(text), "\n"), "\n")}
+		if tree, err := nearestTree(root, f.Path, trees); err != nil {
+			return err
+		} else if tree != nil {
+			h.command = helpCommand(tree, f.Argv)
+		}
+		fc := fixtureCoverage{Path: f.Path, Features: []string{}}
+		for _, feat := range features {
+			if feat.match(h) {
+				fc.Features = append(fc.Features, feat.name)
+				r.Counts[feat.name]++
+			}
+		}
+		r.Fixtures = append(r.Fixtures, fc)
+	}
+	for _, feat := range features {
+		if r.Counts[feat.name] == 0 {
+			r.Uncovered = append(r.Uncovered, feat.name)
+		}
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	} else {
+		printCoverage(r, verbose)
+	}
+	if strict && len(r.Uncovered) > 0 {
+		return fmt.Errorf("%d features without a fixture", len(r.Uncovered))
+	}
+	return nil
+}
+
+// under reports whether the fixture at p is, or is under, one of paths; all
+// are when there are none.
+func under(p string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, d := range paths {
+		d = strings.TrimSuffix(filepath.ToSlash(d), "/")
+		if p == d || strings.HasPrefix(p, d+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+func printCoverage(r coverageReport, verbose bool) {
+	if verbose {
+		for _, f := range r.Fixtures {
+			fmt.Printf("%s: %s\n", f.Path, strings.Join(f.Features, ", "))
+		}
+		fmt.Println()
+	}
+	for _, feat := range features {
+		fmt.Printf("%5d %-22s %s\n", r.Counts[feat.name], feat.name, feat.doc)
+	}
+	fmt.Printf("\n%d fixtures, %d of %d features covered.\n", len(r.Fixtures), len(features)-len(r.Uncovered), len(features))
+	if len(r.Uncovered) > 0 {
+		fmt.Println("No fixture exercises:", strings.Join(r.Uncovered, ", "))
+	}
+}
+
+// nearestTree returns the example.tree.json in the fixture's directory or
+// the closest above it within its framework directory, or nil if there is
+// none, caching each it reads in trees.
+func nearestTree(root, fixture string, trees map[string]*treeNode) (*treeNode, error) {
+	for dir := path.Dir(fixture); ; dir = path.Dir(dir) {
+		file := path.Join(dir, "example.tree.json")
+		t, ok := trees[file]
+		if !ok {
+			data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(file)))
+			switch {
+			case err == nil:
+				t = &treeNode{}
+				if err := json.Unmarshal(data, t); err != nil {
+					return nil, fmt.Errorf("%s: %w", file, err)
+				}
+			case !os.IsNotExist(err):
+				return nil, err
+			}
+			trees[file] = t
+		}
+		if t != nil || !strings.Contains(dir, "/") {
+			return t, nil
+		}
+	}
+}
+
+// helpCommand returns the command of tree that argv asks for the help of,
+// or nil if argv asks for none.
+func helpCommand(tree *treeNode, argv []string) *treeNode {
+	if len(argv) == 0 {
+		return nil
+	}
+	help := false
+	c := tree
+	for i, a := range argv[1:] {
+		switch {
+		case a == "--help" || a == "-h":
+			help = true
+		case a == "help" && i == 0:
+			help = true
+		case !strings.HasPrefix(a, "-"):
+			if sub := c.child(a); sub != nil {
+				c = sub
+			}
+		}
+	}
+	if !help {
+		return nil
+	}
+	return c
+}
+
+func (c *treeNode) child(name string) *treeNode {
+	for i := range c.Commands {
+		if s := &c.Commands[i]; s.Name == name || contains(s.Aliases, name) {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// driftReport is one fixture a fresh run changed, with its differences
+// sorted into categories:
+//
+//	whitespace-only   only spacing or line breaks differ
+//	new-section       a section header appeared
+//	removed-section   a section header disappeared
+//	new-flag          a flag appeared
+//	removed-flag      a flag disappeared
+//	reordered-flags   the same flags are listed in another order
+//	reordered-lines   the same lines appear in another order
+//	content           anything else
+type driftReport struct {
+	change
+	Categories []string `json:"categories,omitempty"`
+	Details    []string `json:"details,omitempty"`
+}
+
+var (
+	// sectionRE matches section headers: unindented lines ending in a colon,
+	// such as cobra's "Flags:", or written in capitals, such as gh's "FLAGS".
+	sectionRE = regexp.MustCompile(`^(?:[A-Z][^:]{0,40}:|[A-Z][A-Z ]{2,40})$`)
+	// flagRE matches the long name at the start of a flag table line.
+	flagRE = regexp.MustCompile(`^\s+(?:-\S, )?(--[\w-]+)`)
+)
+
+// drift regenerates the named sections like verify, then reports how each
+// changed fixture changed, for reviewing cobra and pflag upgrades by kind of
+// change rather than line by line. It fails if anything changed.
+func drift(root string, s *script, names []string, jobs int, asJSON bool) error {
+	progress := io.Writer(os.Stdout)
+	if asJSON {
+		progress = os.Stderr
+	}
+	scratch, changes, err := regenerate(root, s, names, jobs, progress)
+	defer os.RemoveAll(filepath.Dir(scratch))
+	if err != nil {
+		return err
+	}
+	reports := make([]driftReport, 0, len(changes))
+	for _, c := range changes {
+		r := driftReport{change: c}
+		if c.Kind == "changed" {
+			before, err := os.ReadFile(filepath.Join(root, c.Path))
+			if err != nil {
+				return err
+			}
+			after, err := os.ReadFile(filepath.Join(scratch, c.Path))
+			if err != nil {
+				return err
+			}
+			r.Categories, r.Details = classify(string(before), string(after))
+		}
+		reports = append(reports, r)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(reports); err != nil {
+			return err
+		}
+	} else {
+		printDrift(reports)
+	}
+	if len(reports) > 0 {
+		return fmt.Errorf("%d fixtures drifted", len(reports))
+	}
+	return nil
+}
+
+func printDrift(reports []driftReport) {
+	fmt.Println()
+	counts := map[string]int{}
+	for _, r := range reports {
+		if len(r.Categories) == 0 {
+			fmt.Printf("%-7s %s\n", r.Kind, r.Path)
+			counts[r.Kind]++
+			continue
+		}
+		fmt.Printf("%-7s %s: %s\n", r.Kind, r.Path, strings.Join(r.Categories, ", "))
+		for _, d := range r.Details {
+			fmt.Printf("        %s\n", d)
+		}
+		for _, c := range r.Categories {
+			counts[c]++
+		}
+	}
+	if len(reports) == 0 {
+		fmt.Println("No drift.")
+		return
+	}
+	var kinds []string
+	for k := range counts {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	fmt.Println()
+	for _, k := range kinds {
+		fmt.Printf("%5d %s\n", counts[k], k)
+	}
+}
+
+// classify sorts the differences between two versions of a fixture into
+// driftReport categories, with a detail line for each section or flag that
+// came or went.
+func classify(before, after string) (categories, details []string) {
+	if strings.Join(strings.Fields(before), " ") == strings.Join(strings.Fields(after), " ") {
+		return []string{"whitespace-only"}, nil
+	}
+	add := func(category, format string, args ...any) {
+		if len(categories) == 0 || categories[len(categories)-1] != category {
+			categories = append(categories, category)
+		}
+		details = append(details, fmt.Sprintf(format, args...))
+	}
+	oldLines, newLines := lines(before), lines(after)
+
+	oldSections, newSections := matching(oldLines, sectionRE, 0), matching(newLines, sectionRE, 0)
+	for _, h := range missing(newSections, oldSections) {
+		add("new-section", "new section %q", h)
+	}
+	for _, h := range missing(oldSections, newSections) {
+		add("removed-section", "removed section %q", h)
+	}
+
+	oldFlags, newFlags := matching(oldLines, flagRE, 1), matching(newLines, flagRE, 1)
+	for _, f := range missing(newFlags, oldFlags) {
+		add("new-flag", "new flag %s", f)
+	}
+	for _, f := range missing(oldFlags, newFlags) {
+		add("removed-flag", "removed flag %s", f)
+	}
+	if sameSet(oldFlags, newFlags) && strings.Join(oldFlags, " ") != strings.Join(newFlags, " ") {
+		categories = append(categories, "reordered-flags")
+	} else if sameSet(oldLines, newLines) {
+		categories = append(categories, "reordered-lines")
+	}
+	if len(categories) == 0 {
+		categories = []string{"content"}
+	}
+	return categories, details
+}
+
+// lines splits s into lines with trailing whitespace and blank lines
+// dropped.
+func lines(s string) []string {
+	var out []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimRight(line, " \t\r"); line != "" {
+			out = append(out, line)
+		}
+	}
+	return out
+}
+
+// matching returns submatch group of re for each line it matches.
+func matching(lines []string, re *regexp.Regexp, group int) []string {
+	var out []string
+	for _, line := range lines {
+		if m := re.FindStringSubmatch(line); m != nil {
+			out = append(out, m[group])
+		}
+	}
+	return out
+}
+
+// missing returns the elements of a not in b, in a's order.
+func missing(a, b []string) []string {
+	in := map[string]int{}
+	for _, s := range b {
+		in[s]++
+	}
+	var out []string
+	for _, s := range a {
+		if in[s] > 0 {
+			in[s]--
+		} else {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// sameSet reports whether a and b hold the same elements as many times.
+func sameSet(a, b []string) bool {
+	return len(a) == len(b) && len(missing(a, b)) == 0
+}
@@ -0,0 +1,18 @@
+module fixturegen
+
+go 1.21
+
+require (
+	github.com/anthropics/moss/crates/moss-cli-parser/mosshelp v0.0.0
+	github.com/creack/pty v1.1.24
+	golang.org/x/term v0.15.0
+)
+
+require golang.org/x/sys v0.15.0 // indirect
+
+// import runs binaries in mosshelp's sandbox, from this tree, and mosshelp
+// the corpus from it too.
+replace (
+	github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus => ../corpus
+	github.com/anthropics/moss/crates/moss-cli-parser/mosshelp => ../../mosshelp
+)
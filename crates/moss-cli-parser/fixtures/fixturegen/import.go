@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/mosshelp"
+)
+
+// importForms are the ways of asking for help import tries, by the suffix
+// of the fixture each writes: <name><suffix>.help.
+var importForms = []struct {
+	suffix string
+	args   []string
+}{
+	{"", []string{"--help"}},
+	{"-help", []string{"help"}},
+	{"-h", []string{"-h"}},
+}
+
+// neverImport are programs import does not run even when asked to: those
+// that may act on the machine whatever their arguments.
+var neverImport = map[string]bool{
+	"halt": true, "init": true, "kexec": true, "poweroff": true,
+	"reboot": true, "shutdown": true, "telinit": true,
+}
+
+// importRecord is the <out>/<name>/import.json written beside a binary's
+// imported fixtures: what was run, and how.
+type importRecord struct {
+	Binary string `json:"binary"`
+	// Path is where the binary was found, and SHA256 its hash, to tell
+	// which build printed the fixtures.
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+	// Version is the first line the binary prints for --version, if it
+	// exits 0 doing so.
+	Version  string          `json:"version,omitempty"`
+	GOOS     string          `json:"goos"`
+	GOARCH   string          `json:"goarch"`
+	Sandbox  importSandbox   `json:"sandbox"`
+	Captures []importCapture `json:"captures"`
+}
+
+// importSandbox is how each capture was confined; see mosshelp.Sandbox.
+type importSandbox struct {
+	Timeout   string   `json:"timeout"`
+	MaxOutput int      `json:"max_output"`
+	Env       []string `json:"env"`
+	Isolated  bool     `json:"isolated"`
+}
+
+// importCapture is one form of asking for help and what it printed.
+type importCapture struct {
+	Argv []string `json:"argv"`
+	// Fixture is the file written, relative to import.json's directory;
+	// empty when nothing was, in which case Skipped says why.
+	Fixture string `json:"fixture,omitempty"`
+	// Capture is the channel Fixture holds, as in recording: "out", or
+	// "err" for a binary that printed help only there.
+	Capture string `json:"capture,omitempty"`
+	Exit    int    `json:"exit"`
+	// SameAs names the fixture of an earlier form that printed the same.
+	SameAs  string `json:"same_as,omitempty"`
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// importCommand runs fixturegen import: it captures the help of installed
+// binaries, those named or those in PATH allowed, into <out>/<name>/ as
+// fixtures beside an import.json recording how. Synthetic CLIs print only
+// what their frameworks do; real ones are where parsers meet the rest.
+//
+// Each binary runs in a mosshelp.Sandbox, isolated in Linux namespaces
+// unless -isolate=false: asking for help is harmless only for programs
+// that parse their arguments, and whatever is installed may not.
+func importCommand(root string, args []string) error {
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	out := fs.String("o", "real", "output directory, relative to the fixtures directory")
+	s := &mosshelp.Sandbox{Env: []string{"PATH"}}
+	fs.DurationVar(&s.Timeout, "timeout", 5*time.Second, "time limit of each run")
+	fs.IntVar(&s.MaxOutput, "max-output", 1<<20, "bytes of output kept from each stream, past which the run is killed")
+	fs.BoolVar(&s.Isolate, "isolate", true, "run in new Linux namespaces, without network")
+	all := fs.Bool("path", false, "import the binaries in PATH that -allow names")
+	allow := fs.String("allow", "", "with -path, a comma-separated list of the names or patterns, such as 'git*', of the binaries to import")
+	fs.Parse(args)
+	if fs.NArg() == 0 && !*all {
+		return errors.New("usage: fixturegen import [-o DIR] [-timeout D] [-max-output N] [-isolate=false] -path -allow NAMES | <binary>...")
+	}
+	binaries := fs.Args()
+	if *all {
+		if *allow == "" {
+			return errors.New("import: -path runs every binary allowed, so it needs -allow")
+		}
+		found, err := pathBinaries(strings.Split(*allow, ","))
+		if err != nil {
+			return err
+		}
+		binaries = append(binaries, found...)
+	}
+	dir := *out
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(root, dir)
+	}
+	imported := 0
+	for _, b := range binaries {
+		rec, err := importBinary(s, b, dir)
+		if err != nil {
+			fmt.Printf("skip    %s: %v\n", b, err)
+			continue
+		}
+		var fixtures []string
+		for _, c := range rec.Captures {
+			if c.Fixture != "" {
+				fixtures = append(fixtures, c.Fixture)
+			}
+		}
+		fmt.Printf("import  %s: %s\n", rec.Binary, strings.Join(fixtures, " "))
+		imported++
+	}
+	if imported == 0 {
+		return errors.New("import: nothing imported")
+	}
+	return nil
+}
+
+// pathBinaries returns the name of every executable in PATH that one of
+// allow matches, as path.Match does, each once: the first of a name is the
+// one a shell runs.
+func pathBinaries(allow []string) ([]string, error) {
+	for _, pattern := range allow {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("import: -allow %q: %w", pattern, err)
+		}
+	}
+	allowed := func(name string) bool {
+		for _, pattern := range allow {
+			if ok, _ := path.Match(pattern, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+	seen := map[string]bool{}
+	var names []string
+	for _, dir := range filepath.SplitList(os.Getenv("PATH")) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, e := range entries {
+			if seen[e.Name()] || !allowed(e.Name()) {
+				continue
+			}
+			info, err := os.Stat(filepath.Join(dir, e.Name()))
+			if err != nil || info.IsDir() || info.Mode()&0o111 == 0 {
+				continue
+			}
+			seen[e.Name()] = true
+			names = append(names, e.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// importBinary captures each of importForms from binary, a name looked up
+// in PATH or a path, into dir/<name>/, and writes its import.json there.
+// It writes nothing for a binary none of the forms printed anything for.
+func importBinary(s *mosshelp.Sandbox, binary, dir string) (*importRecord, error) {
+	name := filepath.Base(binary)
+	if neverImport[name] {
+		return nil, errors.New("never run")
+	}
+	path, err := exec.LookPath(binary)
+	if err != nil {
+		return nil, err
+	}
+	if path, err = filepath.Abs(path); err != nil {
+		return nil, err
+	}
+	rec := &importRecord{
+		Binary: name, Path: path, GOOS: runtime.GOOS, GOARCH: runtime.GOARCH,
+		Sandbox: importSandbox{s.Timeout.String(), s.MaxOutput, s.Env, s.Isolate},
+	}
+	if rec.SHA256, err = hashFile(path); err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if r, err := s.Exec(ctx, path, []string{name, "--version"}); err == nil && r.Exit == 0 {
+		first, _, _ := strings.Cut(strings.TrimSpace(r.Stdout), "\n")
+		rec.Version = strings.TrimSpace(first)
+	}
+
+	files := map[string][]byte{}
+	printed := map[string]string{}
+	for _, form := range importForms {
+		c := importCapture{Argv: append([]string{name}, form.args...)}
+		r, err := s.Exec(ctx, path, c.Argv)
+		if err != nil {
+			c.Skipped = strings.TrimPrefix(err.Error(), "mosshelp: ")
+			rec.Captures = append(rec.Captures, c)
+			continue
+		}
+		c.Exit = r.Exit
+		text, capture := r.Stdout, "out"
+		if strings.TrimSpace(text) == "" {
+			text, capture = r.Stderr, "err"
+		}
+		switch same, ok := printed[text]; {
+		case strings.TrimSpace(text) == "":
+			c.Skipped = "printed nothing"
+		case ok:
+			c.SameAs = same
+		default:
+			c.Fixture, c.Capture = name+form.suffix+".help", capture
+			files[c.Fixture] = []byte(text)
+			printed[text] = c.Fixture
+		}
+		rec.Captures = append(rec.Captures, c)
+	}
+	if len(files) == 0 {
+		return nil, errors.New("no form printed help")
+	}
+
+	dir = filepath.Join(dir, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	for file, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, file), data, 0o644); err != nil {
+			return nil, err
+		}
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return rec, os.WriteFile(filepath.Join(dir, "import.json"), append(data, '\n'), 0o644)
+}
+
+func hashFile(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,193 @@
+// Command fixturegen regenerates and checks the CLI help fixtures.
+//
+// Usage:
+//
+//	fixturegen list
+//	fixturegen generate [-j N] [-force] [-stats FILE] [section...]
+//	fixturegen verify [-j N] [-diff] [section...]
+//	fixturegen drift [-j N] [-json] [section...]
+//	fixturegen replay [-check] <fixture|recording.json>...
+//	fixturegen record -o <fixture> [-capture out|err|all|tty] <program> [args...]
+//	fixturegen pty [-cols N] [-rows N] <program> [args...]
+//	fixturegen malform [-o DIR] <fixture>...
+//	fixturegen mutate [-o DIR] <fixture>...
+//	fixturegen import [-o DIR] [-isolate=false] -path -allow NAMES | <binary>...
+//	fixturegen coverage [-json] [-v] [-strict] [path...]
+//
+// Sections are those of generate.sh, named by the slug of their
+// "=== Generating <name> fixtures ===" header (e.g. cobra, urfave-cli-v2).
+// generate runs them in place; verify runs them in a scratch copy of the
+// fixtures directory and reports every fixture that would change; drift
+// does the same and sorts the changes by kind (whitespace only, new sections,
+// reordered flags...), for reviewing dependency upgrades.
+//
+// Sections run concurrently, -j at a time, each in its own shell. generate
+// skips sections whose sources and fixtures are unchanged since it last ran
+// them, unless given -force; see cache. With -stats, generate also writes
+// a JSON report of how long each section and fixture took and of the
+// corpus it leaves: each fixture's size and the commands and flags it
+// lists, and the totals by framework; see genStats.
+//
+// replay reproduces single fixtures from the recordings generate.sh keeps
+// of how it captured each, and record writes such a recording for any CLI;
+// see recording. pty runs a program on a pseudo-terminal, for generate.sh's
+// captures of what CLIs print at a terminal rather than into a pipe.
+//
+// malform derives corrupted copies of fixtures (truncated mid-table, tabs
+// and spaces swapped, sections reordered, flag rows duplicated), each beside
+// the diagnostics a parser reading it should report; see diagnostic.
+//
+// mutate derives variants of fixtures laid out differently (sections
+// swapped, indents widened, blank lines added) beside whether a parser
+// should survive each, reading the same command, or reject it; see
+// mutation.
+//
+// import captures the help of real installed binaries, those named or the
+// ones in PATH an allowlist names, as fixtures with a record of each
+// binary's version and how it was run, confined to mosshelp's sandbox and
+// by default isolated in Linux namespaces; see importCommand.
+//
+// coverage reports which features of help output (flag groups, wrapped
+// descriptions, environment variables, deprecations, list defaults...) each
+// fixture exercises, and which no fixture does; see features.
+//
+// fixturegen finds generate.sh in the parent of its working directory, or
+// in the directory given by -dir.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+func usage() {
+	fmt.Fprint(os.Stderr, `usage: fixturegen [-dir DIR] <command> [arguments]
+
+Commands:
+  list                            List the sections of generate.sh and the
+                                  directories each uses
+  generate [-force] [-stats FILE] [section...]
+                                  Regenerate fixtures in place, skipping
+                                  sections unchanged since they last ran,
+                                  and write generation and corpus stats
+  verify [-diff] [section...]     Regenerate in a scratch copy and report changes
+  drift [-json] [section...]      Like verify, with changes sorted by kind
+  replay [-check] <fixture|recording.json>...
+                                  Recapture single fixtures from their recordings
+  record -o <fixture> [-capture out|err|all|tty] [-cols N] <program> [args...]
+                                  Capture any CLI's output with a recording
+  pty [-cols N] [-rows N] <program> [args...]
+                                  Run a program on a pseudo-terminal
+  malform [-o DIR] <fixture>...   Write corrupted variants of fixtures with
+                                  their expected parser diagnostics
+  mutate [-o DIR] <fixture>...    Write re-laid-out variants of fixtures with
+                                  whether parsers should survive or reject them
+  import [-o DIR] [-isolate=false] -path -allow NAMES | <binary>...
+                                  Capture the help of installed binaries,
+                                  sandboxed, as fixtures
+  coverage [-json] [-v] [-strict] [path...]
+                                  Report the help features each fixture
+                                  exercises and those none does
+
+With no sections, generate, verify and drift run all of them, -j N at a
+time (default: the number of CPUs).
+`)
+}
+
+func main() {
+	dir := flag.String("dir", "..", "fixtures directory containing generate.sh")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	root, err := filepath.Abs(*dir)
+	if err == nil {
+		err = run(root, flag.Arg(0), flag.Args()[1:])
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "fixturegen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(root, command string, args []string) error {
+	switch command {
+	case "replay":
+		return replayCommand(root, args)
+	case "record":
+		return recordCommand(args)
+	case "pty":
+		return ptyCommand(args)
+	case "malform":
+		return malformCommand(root, args)
+	case "mutate":
+		return mutateCommand(root, args)
+	case "import":
+		return importCommand(root, args)
+	case "coverage":
+		fs := flag.NewFlagSet(command, flag.ExitOnError)
+		asJSON := fs.Bool("json", false, "print the report as JSON")
+		verbose := fs.Bool("v", false, "list the features of each fixture")
+		strict := fs.Bool("strict", false, "fail if a feature has no fixture")
+		fs.Parse(args)
+		return coverage(root, fs.Args(), *asJSON, *verbose, *strict)
+	}
+	script, err := loadScript(filepath.Join(root, "generate.sh"))
+	if err != nil {
+		return err
+	}
+	switch command {
+	case "list":
+		for _, s := range script.sections {
+			dirs, err := s.dirs(root)
+			if err != nil {
+				return err
+			}
+			if s.needsAll {
+				dirs = []string{"(all)"}
+			}
+			fmt.Printf("%-16s %-16s %s\n", s.slug, s.name, strings.Join(dirs, " "))
+		}
+		return nil
+	}
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	jobs := fs.Int("j", runtime.NumCPU(), "number of sections to run at once")
+	switch command {
+	case "generate":
+		force := fs.Bool("force", false, "run sections even if unchanged")
+		statsFile := fs.String("stats", "", "write a JSON report of generation times and corpus make-up to `file`")
+		fs.Parse(args)
+		c, err := loadCache(filepath.Join(root, cacheFile))
+		if err != nil {
+			return err
+		}
+		if *force {
+			c.Sections = map[string]string{}
+		}
+		opts := runOptions{jobs: *jobs, cache: c}
+		if *statsFile != "" {
+			opts.stats = newStats(*jobs)
+		}
+		if err := script.run(root, fs.Args(), os.Stdout, opts); err != nil || opts.stats == nil {
+			return err
+		}
+		return opts.stats.finish(root, *statsFile)
+	case "verify":
+		diff := fs.Bool("diff", false, "print a unified diff of each changed fixture")
+		fs.Parse(args)
+		return verify(root, script, fs.Args(), *jobs, *diff)
+	case "drift":
+		asJSON := fs.Bool("json", false, "print the report as JSON")
+		fs.Parse(args)
+		return drift(root, script, fs.Args(), *jobs, *asJSON)
+	}
+	usage()
+	os.Exit(2)
+	return nil
+}
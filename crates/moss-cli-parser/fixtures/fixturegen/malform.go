@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// A corruption derives a malformed variant of a help text, returning the
+// variant and the diagnostics a parser reading it should report, or ok
+// false if the text has nothing it applies to (no flag table, no tabs...).
+type corruption struct {
+	name  string
+	apply func(lines []string) (variant string, diags []diagnostic, ok bool)
+}
+
+// diagnostic is one problem a parser should report in a malformed fixture.
+// Line is 1-based, in the variant.
+//
+//	truncated-table        the text ends partway through a flag table row
+//	misaligned-columns     a row's columns are separated by tabs where the
+//	                       rest use spaces, or by a single space
+//	sections-out-of-order  a section appears where the framework never puts it
+//	duplicate-flag         a flag is listed twice in the same table
+type diagnostic struct {
+	Kind    string `json:"kind"`
+	Line    int    `json:"line"`
+	Section string `json:"section,omitempty"`
+	Flag    string `json:"flag,omitempty"`
+	Message string `json:"message"`
+}
+
+// expectation is the <variant>.diagnostics.json written beside each
+// malformed fixture.
+type expectation struct {
+	Source      string       `json:"source"`
+	Corruption  string       `json:"corruption"`
+	Diagnostics []diagnostic `json:"diagnostics"`
+}
+
+// span is a section of a help text: its header line and the lines up to the
+// next header, as indexes into the text's lines.
+type span struct {
+	name       string
+	start, end int
+}
+
+// gapRE matches the run of spaces separating two columns of a table row.
+var gapRE = regexp.MustCompile(`(\S) {2,}(\S)`)
+
+var corruptions = []corruption{
+	{"truncated-table", truncateTable},
+	{"tabs-as-spaces", tabsAsSpaces},
+	{"spaces-as-tabs", spacesAsTabs},
+	{"reordered-sections", reorderSections},
+	{"duplicate-flag-row", duplicateFlagRow},
+}
+
+// malformCommand writes every corruption that applies to each of the named
+// fixtures to <out>/<fixture dir>/<name>-<corruption><ext>, beside its
+// expected diagnostics, for testing that parsers fail loudly rather than
+// return a plausible but wrong spec.
+func malformCommand(root string, args []string) error {
+	fs := flag.NewFlagSet("malform", flag.ExitOnError)
+	out := fs.String("o", "malformed", "output directory, relative to the fixtures directory")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return fmt.Errorf("malform: no fixtures given")
+	}
+	for _, fixture := range fs.Args() {
+		data, err := os.ReadFile(filepath.Join(root, fixture))
+		if err != nil {
+			return err
+		}
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		ext := filepath.Ext(fixture)
+		base := filepath.Join(*out, strings.TrimSuffix(fixture, ext))
+		written := 0
+		for _, c := range corruptions {
+			variant, diags, ok := c.apply(lines)
+			if !ok {
+				continue
+			}
+			path := base + "-" + c.name + ext
+			exp, err := json.MarshalIndent(expectation{Source: fixture, Corruption: c.name, Diagnostics: diags}, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Join(root, filepath.Dir(path)), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(root, path), []byte(variant), 0o644); err != nil {
+				return err
+			}
+			expPath := base + "-" + c.name + ".diagnostics.json"
+			if err := os.WriteFile(filepath.Join(root, expPath), append(exp, '\n'), 0o644); err != nil {
+				return err
+			}
+			fmt.Printf("  %s\n", path)
+			written++
+		}
+		if written == 0 {
+			return fmt.Errorf("malform: no corruption applies to %s", fixture)
+		}
+	}
+	return nil
+}
+
+// spans returns the sections of lines, in order. Lines before the first
+// header belong to none.
+func spans(lines []string) []span {
+	var ss []span
+	for i, line := range lines {
+		if sectionRE.MatchString(line) {
+			if len(ss) > 0 {
+				ss[len(ss)-1].end = i
+			}
+			ss = append(ss, span{name: line, start: i})
+		}
+	}
+	if len(ss) > 0 {
+		ss[len(ss)-1].end = len(lines)
+	}
+	return ss
+}
+
+// flagRows returns the indexes of the flag table rows in s.
+func flagRows(lines []string, s span) []int {
+	var rows []int
+	for i := s.start + 1; i < s.end; i++ {
+		if flagRE.MatchString(lines[i]) {
+			rows = append(rows, i)
+		}
+	}
+	return rows
+}
+
+func indent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " \t"))
+}
+
+func join(lines []string) string {
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// truncateTable cuts the text halfway through the middle row of its first
+// flag table with more than one row, as a help text piped through head or
+// captured from a killed process would be.
+func truncateTable(lines []string) (string, []diagnostic, bool) {
+	for _, s := range spans(lines) {
+		rows := flagRows(lines, s)
+		if len(rows) < 2 {
+			continue
+		}
+		row := rows[len(rows)/2]
+		n := len(lines[row]) / 2
+		for n > 0 && !utf8.RuneStart(lines[row][n]) {
+			n--
+		}
+		cut := lines[row][:n]
+		kept := append(append([]string{}, lines[:row]...), cut)
+		return strings.Join(kept, "\n"), []diagnostic{{
+			Kind:    "truncated-table",
+			Line:    row + 1,
+			Section: s.name,
+			Flag:    flagRE.FindStringSubmatch(lines[row])[1],
+			Message: fmt.Sprintf("text ends partway through a row of %s", s.name),
+		}}, true
+	}
+	return "", nil, false
+}
+
+// tabsAsSpaces replaces each tab with a single space, as copying help text
+// out of some terminals does, losing the alignment the tabs gave.
+func tabsAsSpaces(lines []string) (string, []diagnostic, bool) {
+	return replaceColumns(lines, func(line string) string {
+		return strings.ReplaceAll(line, "\t", " ")
+	}, "tab separating columns replaced by a single space")
+}
+
+// spacesAsTabs replaces the spaces between table columns with a tab, so rows
+// align only at the reader's tab width.
+func spacesAsTabs(lines []string) (string, []diagnostic, bool) {
+	return replaceColumns(lines, func(line string) string {
+		return gapRE.ReplaceAllString(line, "$1\t$2")
+	}, "spaces separating columns replaced by a tab")
+}
+
+// replaceColumns applies replace to every line, reporting each it changed.
+func replaceColumns(lines []string, replace func(string) string, message string) (string, []diagnostic, bool) {
+	var diags []diagnostic
+	variant := make([]string, len(lines))
+	for i, line := range lines {
+		variant[i] = replace(line)
+		if variant[i] != line {
+			diags = append(diags, diagnostic{Kind: "misaligned-columns", Line: i + 1, Message: message})
+		}
+	}
+	return join(variant), diags, len(diags) > 0
+}
+
+// reorderSections swaps the first and last sections, so that, for instance,
+// the usage line comes after the flags.
+func reorderSections(lines []string) (string, []diagnostic, bool) {
+	ss := spans(lines)
+	if len(ss) < 2 {
+		return "", nil, false
+	}
+	first, last := ss[0], ss[len(ss)-1]
+	block := func(s span) []string {
+		b := lines[s.start:s.end]
+		for len(b) > 0 && b[len(b)-1] == "" {
+			b = b[:len(b)-1]
+		}
+		return b
+	}
+	var variant []string
+	variant = append(variant, lines[:first.start]...)
+	moved := len(variant) + 1
+	variant = append(append(variant, block(last)...), "")
+	variant = append(variant, lines[first.end:last.start]...)
+	if n := len(variant); n > 0 && variant[n-1] != "" {
+		variant = append(variant, "")
+	}
+	diags := []diagnostic{{
+		Kind:    "sections-out-of-order",
+		Line:    moved,
+		Section: last.name,
+		Message: fmt.Sprintf("%s appears before %s", last.name, first.name),
+	}, {
+		Kind:    "sections-out-of-order",
+		Line:    len(variant) + 1,
+		Section: first.name,
+		Message: fmt.Sprintf("%s appears after %s", first.name, last.name),
+	}}
+	variant = append(variant, block(first)...)
+	return join(variant), diags, true
+}
+
+// duplicateFlagRow repeats the first flag table row, with its wrapped
+// continuation lines, directly below itself.
+func duplicateFlagRow(lines []string) (string, []diagnostic, bool) {
+	for _, s := range spans(lines) {
+		rows := flagRows(lines, s)
+		if len(rows) == 0 {
+			continue
+		}
+		start, end := rows[0], rows[0]+1
+		for end < s.end && indent(lines[end]) > indent(lines[start]) && !strings.HasPrefix(strings.TrimSpace(lines[end]), "-") {
+			end++
+		}
+		flag := flagRE.FindStringSubmatch(lines[start])[1]
+		var variant []string
+		variant = append(variant, lines[:end]...)
+		variant = append(variant, lines[start:end]...)
+		variant = append(variant, lines[end:]...)
+		return join(variant), []diagnostic{{
+			Kind:    "duplicate-flag",
+			Line:    end + 1,
+			Section: s.name,
+			Flag:    flag,
+			Message: fmt.Sprintf("%s is listed twice in %s", flag, s.name),
+		}}, true
+	}
+	return "", nil, false
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// A mutation perturbs a help text in a controlled way, returning the
+// variant, or ok false if the text has nothing it applies to. Unlike a
+// corruption, a mutation need not make the text wrong: the help of some
+// other framework version or template could read like it.
+type mutation struct {
+	name string
+	// expect is what a parser should do with the variant:
+	//
+	//	survive  parse it into the same command as the original
+	//	reject   fail to parse it strictly, having lost what the original says
+	expect string
+	doc    string
+	apply  func(lines []string) (variant string, ok bool)
+}
+
+// mutationRecord is the <variant>.mutation.json written beside each
+// mutated fixture.
+type mutationRecord struct {
+	Source   string `json:"source"`
+	Mutation string `json:"mutation"`
+	Expect   string `json:"expect"`
+	Doc      string `json:"doc"`
+}
+
+var mutations = []mutation{
+	{"swapped-sections", "survive", "two adjacent sections after Usage: trade places", swapSections},
+	{"indent-4", "survive", "every indented line is indented two columns further", reindent(2)},
+	{"indent-1", "survive", "every indented line is indented one column less", reindent(-1)},
+	{"merged-columns", "survive", "long-only flags start in the shorthand column, their descriptions kept aligned", mergeColumns},
+	{"blank-line-after-header", "survive", "a blank line follows each section header", blankAfterHeaders},
+	{"blank-lines-between-rows", "survive", "a blank line separates the rows of each flag table", blankBetweenRows},
+	{"dedented-continuation", "reject", "a flag description continues on a line indented like the rows rather than the descriptions", dedentContinuation},
+	{"single-space-gap", "reject", "a flag row's description follows its flag after a single space", singleSpaceGap},
+}
+
+// mutateCommand writes every mutation that applies to each of the named
+// fixtures to <out>/<fixture dir>/<name>-<mutation><ext>, beside a record
+// of whether a parser should survive or reject it, for testing how much
+// variation in layout a parser tolerates and that it stops where it should.
+func mutateCommand(root string, args []string) error {
+	fs := flag.NewFlagSet("mutate", flag.ExitOnError)
+	out := fs.String("o", "mutated", "output directory, relative to the fixtures directory")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return fmt.Errorf("mutate: no fixtures given")
+	}
+	for _, fixture := range fs.Args() {
+		data, err := os.ReadFile(filepath.Join(root, fixture))
+		if err != nil {
+			return err
+		}
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		ext := filepath.Ext(fixture)
+		base := filepath.Join(*out, strings.TrimSuffix(fixture, ext))
+		written := 0
+		for _, m := range mutations {
+			variant, ok := m.apply(lines)
+			if !ok || variant == string(data) {
+				continue
+			}
+			path := base + "-" + m.name + ext
+			rec, err := json.MarshalIndent(mutationRecord{fixture, m.name, m.expect, m.doc}, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Join(root, filepath.Dir(path)), 0o755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(filepath.Join(root, path), []byte(variant), 0o644); err != nil {
+				return err
+			}
+			recPath := base + "-" + m.name + ".mutation.json"
+			if err := os.WriteFile(filepath.Join(root, recPath), append(rec, '\n'), 0o644); err != nil {
+				return err
+			}
+			fmt.Printf("  %s\n", path)
+			written++
+		}
+		if written == 0 {
+			return fmt.Errorf("mutate: no mutation applies to %s", fixture)
+		}
+	}
+	return nil
+}
+
+// block returns the lines of s, less the blank lines ending it.
+func block(lines []string, s span) []string {
+	b := lines[s.start:s.end]
+	for len(b) > 0 && b[len(b)-1] == "" {
+		b = b[:len(b)-1]
+	}
+	return b
+}
+
+// swapSections swaps the first two sections after Usage:, or after the
+// first section when there is no Usage:.
+func swapSections(lines []string) (string, bool) {
+	ss := spans(lines)
+	i := 1
+	for j, s := range ss {
+		if strings.EqualFold(strings.TrimSuffix(s.name, ":"), "usage") {
+			i = j + 1
+			break
+		}
+	}
+	if i+1 >= len(ss) {
+		return "", false
+	}
+	a, b := ss[i], ss[i+1]
+	var variant []string
+	variant = append(variant, lines[:a.start]...)
+	variant = append(append(variant, block(lines, b)...), "")
+	variant = append(variant, block(lines, a)...)
+	if b.end < len(lines) {
+		variant = append(variant, "")
+	}
+	variant = append(variant, lines[b.end:]...)
+	return join(variant), true
+}
+
+// reindent moves every indented line of the sections by by columns,
+// keeping at least one column of indent. The text before them and the
+// examples are the CLI author's own, indents and all, and are left alone.
+func reindent(by int) func([]string) (string, bool) {
+	return func(lines []string) (string, bool) {
+		variant := append([]string{}, lines...)
+		for _, s := range spans(lines) {
+			if strings.HasPrefix(strings.ToLower(s.name), "example") {
+				continue
+			}
+			for i := s.start + 1; i < s.end; i++ {
+				line := lines[i]
+				n := indent(line)
+				if n == 0 || strings.TrimSpace(line) == "" || strings.HasPrefix(line, "\t") {
+					continue
+				}
+				if n+by < 1 {
+					return "", false
+				}
+				if by > 0 {
+					variant[i] = strings.Repeat(" ", by) + line
+				} else {
+					variant[i] = line[-by:]
+				}
+			}
+		}
+		return join(variant), true
+	}
+}
+
+// mergeColumns moves the rows of long-only flags left into the shorthand
+// column, as templates that do not reserve one print them, padding their
+// gap so the descriptions stay where they were.
+func mergeColumns(lines []string) (string, bool) {
+	variant := append([]string{}, lines...)
+	changed := false
+	for _, s := range spans(lines) {
+		rows := flagRows(lines, s)
+		short := -1
+		for _, r := range rows {
+			if !strings.HasPrefix(strings.TrimSpace(lines[r]), "--") {
+				short = indent(lines[r])
+				break
+			}
+		}
+		if short < 0 {
+			continue
+		}
+		for _, r := range rows {
+			line := lines[r]
+			shift := indent(line) - short
+			loc := gapRE.FindStringIndex(line)
+			if shift <= 0 || loc == nil || !strings.HasPrefix(strings.TrimSpace(line), "--") {
+				continue
+			}
+			gap := loc[0] + 1
+			variant[r] = line[shift:gap] + strings.Repeat(" ", shift) + line[gap:]
+			changed = true
+		}
+	}
+	return join(variant), changed
+}
+
+// blankAfterHeaders puts a blank line after every section header.
+func blankAfterHeaders(lines []string) (string, bool) {
+	var variant []string
+	for _, line := range lines {
+		variant = append(variant, line)
+		if sectionRE.MatchString(line) {
+			variant = append(variant, "")
+		}
+	}
+	return join(variant), len(variant) > len(lines)
+}
+
+// blankBetweenRows puts a blank line before every flag row but the first of
+// its table.
+func blankBetweenRows(lines []string) (string, bool) {
+	rows := map[int]bool{}
+	for _, s := range spans(lines) {
+		for i, r := range flagRows(lines, s) {
+			rows[r] = i > 0
+		}
+	}
+	var variant []string
+	for i, line := range lines {
+		if rows[i] {
+			variant = append(variant, "")
+		}
+		variant = append(variant, line)
+	}
+	return join(variant), len(variant) > len(lines)
+}
+
+// dedentContinuation wraps the description of the first flag row with one
+// of several words onto a line indented to the rows' own column, where it
+// could as well be a row as the rest of the description.
+func dedentContinuation(lines []string) (string, bool) {
+	for _, s := range spans(lines) {
+		for _, r := range flagRows(lines, s) {
+			line := lines[r]
+			loc := gapRE.FindStringIndex(line)
+			if loc == nil {
+				continue
+			}
+			words := strings.Fields(line[loc[1]-1:])
+			if len(words) < 2 {
+				continue
+			}
+			half := len(words) / 2
+			variant := append([]string{}, lines[:r]...)
+			variant = append(variant, line[:loc[1]-1]+strings.Join(words[:half], " "),
+				strings.Repeat(" ", indent(line))+strings.Join(words[half:], " "))
+			variant = append(variant, lines[r+1:]...)
+			return join(variant), true
+		}
+	}
+	return "", false
+}
+
+// singleSpaceGap narrows the gap between the flag and the description of
+// the first row with a value placeholder to one space, so there is no
+// telling where the placeholder ends.
+func singleSpaceGap(lines []string) (string, bool) {
+	for _, s := range spans(lines) {
+		for _, r := range flagRows(lines, s) {
+			line := lines[r]
+			loc := gapRE.FindStringIndex(line)
+			if loc == nil {
+				continue
+			}
+			if spec := strings.Fields(line[:loc[0]+1]); strings.HasPrefix(spec[len(spec)-1], "-") {
+				continue
+			}
+			variant := append([]string{}, lines...)
+			variant[r] = line[:loc[0]+1] + " " + line[loc[1]-1:]
+			return join(variant), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// ptyRows is the height of the terminals captures run on; nothing the
+// fixtures print depends on it.
+const ptyRows = 24
+
+// ptyCommand runs fixturegen pty, which generate.sh uses for captures on
+// a terminal: it runs a program on a pseudo-terminal, prints what it
+// printed there and exits with its status.
+func ptyCommand(args []string) error {
+	fs := flag.NewFlagSet("pty", flag.ExitOnError)
+	cols := fs.Int("cols", 80, "terminal width")
+	rows := fs.Int("rows", ptyRows, "terminal height")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return errors.New("usage: fixturegen pty [-cols N] [-rows N] <program> [args...]")
+	}
+	cmd := exec.Command(fs.Arg(0), fs.Args()[1:]...)
+	err := runPTY(cmd, *cols, *rows, os.Stdout)
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		os.Exit(exit.ExitCode())
+	}
+	if err != nil {
+		return fmt.Errorf("pty: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+)
+
+// runPTY fails: pseudo-terminals as captures use them are Unix's.
+func runPTY(cmd *exec.Cmd, cols, rows int, w io.Writer) error {
+	return errors.New("pseudo-terminals need Unix")
+}
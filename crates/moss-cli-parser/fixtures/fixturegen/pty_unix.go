@@ -0,0 +1,50 @@
+//go:build unix
+
+package main
+
+import (
+	"errors"
+	"io"
+	"os/exec"
+	"syscall"
+
+	"github.com/creack/pty"
+	"golang.org/x/term"
+)
+
+// runPTY runs cmd with a new pseudo-terminal of cols by rows as its
+// controlling terminal, stdin, stdout and stderr, and copies everything
+// the terminal receives to w. The terminal is raw, so w gets the program's
+// output byte for byte, without the \r a terminal adds before each \n.
+func runPTY(cmd *exec.Cmd, cols, rows int, w io.Writer) error {
+	ptmx, tty, err := pty.Open()
+	if err != nil {
+		return err
+	}
+	defer ptmx.Close()
+	if err := pty.Setsize(ptmx, &pty.Winsize{Cols: uint16(cols), Rows: uint16(rows)}); err != nil {
+		tty.Close()
+		return err
+	}
+	if _, err := term.MakeRaw(int(tty.Fd())); err != nil {
+		tty.Close()
+		return err
+	}
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = tty, tty, tty
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true, Setctty: true}
+	err = cmd.Start()
+	tty.Close()
+	if err != nil {
+		return err
+	}
+	// Reading the terminal fails with EIO once the program and everything
+	// it started have closed it.
+	_, cerr := io.Copy(w, ptmx)
+	if err := cmd.Wait(); err != nil {
+		return err
+	}
+	if cerr != nil && !errors.Is(cerr, syscall.EIO) {
+		return cerr
+	}
+	return nil
+}
@@ -0,0 +1,375 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// recording is everything needed to reproduce one capture: generate.sh
+// writes one per fixture to <dir>/recordings.jsonl, and fixturegen record
+// writes one for any CLI, so a bug report can carry one too.
+//
+// Paths in a recording are relative to its base directory: the fixtures
+// directory for those in recordings.jsonl, otherwise the directory holding
+// the recording's file.
+type recording struct {
+	// Fixture is the file capture wrote.
+	Fixture string `json:"fixture"`
+	// Program is what was run, relative to Cwd if it contains a slash and
+	// looked up in PATH otherwise; Argv[0] is what it was told its name is.
+	Program string   `json:"program"`
+	Argv    []string `json:"argv"`
+	// Env is what the capture set on top of an environment cleared of
+	// everything that shapes output (see shapingEnv). A PATH ending in $PATH
+	// extends the replaying PATH, with ./ entries relative to Cwd.
+	Env map[string]string `json:"env"`
+	Cwd string            `json:"cwd"`
+	// Stdin is "null", so captures read nothing, or "pty" for those run on
+	// a terminal.
+	Stdin string `json:"stdin"`
+	// Terminal is "pipe" when stdout and stderr are pipes, or "pty" when
+	// they are a pseudo-terminal of Cols by Rows.
+	Terminal string `json:"terminal"`
+	Cols     int    `json:"cols,omitempty"`
+	Rows     int    `json:"rows,omitempty"`
+	// Capture is the channel Fixture holds, as capture's mode: "out", "err",
+	// "all" for both merged, or "tty" for everything the terminal received.
+	Capture string `json:"capture"`
+	// Stdout and Stderr are the files holding each channel, which may be
+	// Fixture itself, or empty when the channel printed nothing.
+	Stdout string `json:"stdout"`
+	Stderr string `json:"stderr"`
+	Exit   int    `json:"exit"`
+}
+
+// shapingEnv are the prefixes of environment variables that shape the
+// output of the fixture CLIs. generate.sh unsets them all, so replay does
+// too and record notes those set.
+var shapingEnv = []string{"EXAMPLE_", "COLUMNS", "NO_COLOR", "CLICOLOR", "LANG", "LC_", "PAGER", "LESS"}
+
+func shapesOutput(name string) bool {
+	for _, prefix := range shapingEnv {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// findRecording returns the recording of fixture, a path relative to root,
+// from its directory's recordings.jsonl.
+func findRecording(root, fixture string) (*recording, error) {
+	fixture = filepath.ToSlash(filepath.Clean(fixture))
+	dir, _, _ := strings.Cut(fixture, "/")
+	file := filepath.Join(root, dir, "recordings.jsonl")
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	sc.Buffer(nil, 1<<20)
+	for sc.Scan() {
+		var rec recording
+		if err := json.Unmarshal(sc.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		if rec.Fixture == fixture {
+			return &rec, nil
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("%s: no recording of %s", file, fixture)
+}
+
+// loadRecording reads a recording from arg: a .json file holding one, or
+// a fixture path relative to root. It returns the recording's base
+// directory along with it.
+func loadRecording(root, arg string) (*recording, string, error) {
+	if !strings.HasSuffix(arg, ".json") {
+		rec, err := findRecording(root, arg)
+		return rec, root, err
+	}
+	data, err := os.ReadFile(arg)
+	if err != nil {
+		return nil, "", err
+	}
+	var rec recording
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", arg, err)
+	}
+	base, err := filepath.Abs(filepath.Dir(arg))
+	return &rec, base, err
+}
+
+// command returns the command running rec from base.
+func (rec *recording) command(base string) (*exec.Cmd, error) {
+	if !(rec.Stdin == "null" && rec.Terminal == "pipe" || rec.Stdin == "pty" && rec.Terminal == "pty") {
+		return nil, fmt.Errorf("%s: cannot replay stdin %q on terminal %q", rec.Fixture, rec.Stdin, rec.Terminal)
+	}
+	cwd := filepath.Join(base, rec.Cwd)
+	cmd := &exec.Cmd{Path: filepath.Join(cwd, rec.Program), Args: rec.Argv, Dir: cwd}
+	if !strings.Contains(rec.Program, "/") {
+		path, err := exec.LookPath(rec.Program)
+		if err != nil {
+			return nil, err
+		}
+		cmd.Path = path
+	}
+	if _, err := os.Stat(cmd.Path); err != nil {
+		return nil, fmt.Errorf("%s: %w (run fixturegen generate to build it)", rec.Fixture, err)
+	}
+	for _, kv := range os.Environ() {
+		name, _, _ := strings.Cut(kv, "=")
+		if !shapesOutput(name) && name != "LANGUAGE" {
+			cmd.Env = append(cmd.Env, kv)
+		}
+	}
+	for name, value := range rec.Env {
+		if name == "PATH" {
+			value = expandPath(value, cwd)
+		}
+		cmd.Env = append(cmd.Env, name+"="+value)
+	}
+	return cmd, nil
+}
+
+// expandPath expands a recorded PATH: a trailing $PATH becomes the current
+// PATH, and ./ entries become absolute, as os/exec refuses relative ones.
+func expandPath(value, cwd string) string {
+	list := filepath.SplitList(value)
+	for i, dir := range list {
+		if dir == "$PATH" {
+			list[i] = os.Getenv("PATH")
+		} else if strings.HasPrefix(dir, "./") {
+			list[i] = filepath.Join(cwd, dir)
+		}
+	}
+	return strings.Join(list, string(filepath.ListSeparator))
+}
+
+// captured is what replaying a recording produced.
+type captured struct {
+	// files are the files capture would write, by path relative to the
+	// base directory.
+	files map[string][]byte
+	// stdout and stderr are as in recording.
+	stdout, stderr string
+	exit           int
+}
+
+// replay runs rec again the way capture did.
+func (rec *recording) replay(base string) (*captured, error) {
+	run := func(stdout, stderr *bytes.Buffer) (int, error) {
+		cmd, err := rec.command(base)
+		if err != nil {
+			return 0, err
+		}
+		cmd.Stdout = stdout
+		cmd.Stderr = stdout
+		if stderr != nil {
+			cmd.Stderr = stderr
+		}
+		err = cmd.Run()
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			return exit.ExitCode(), nil
+		}
+		return 0, err
+	}
+	c := &captured{files: map[string][]byte{}}
+	var stdout, stderr bytes.Buffer
+	var err error
+	switch rec.Capture {
+	case "out", "err":
+		c.exit, err = run(&stdout, &stderr)
+	case "all":
+		var merged bytes.Buffer
+		if c.exit, err = run(&merged, nil); err != nil {
+			return nil, err
+		}
+		c.files[rec.Fixture] = merged.Bytes()
+		_, err = run(&stdout, &stderr)
+	case "tty":
+		cmd, err := rec.command(base)
+		if err != nil {
+			return nil, err
+		}
+		var screen bytes.Buffer
+		err = runPTY(cmd, rec.Cols, rec.Rows, &screen)
+		var exit *exec.ExitError
+		if errors.As(err, &exit) {
+			c.exit, err = exit.ExitCode(), nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		c.files[rec.Fixture] = screen.Bytes()
+		if screen.Len() > 0 {
+			c.stdout, c.stderr = rec.Fixture, rec.Fixture
+		}
+		return c, nil
+	default:
+		return nil, fmt.Errorf("%s: unknown capture %q", rec.Fixture, rec.Capture)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Channels not in the fixture are kept beside it when not empty; with
+	// one channel silent, a merged fixture already is the other.
+	switch rec.Capture {
+	case "out":
+		c.files[rec.Fixture] = stdout.Bytes()
+	case "err":
+		c.files[rec.Fixture] = stderr.Bytes()
+	}
+	channel := func(name string, data []byte, own bool) string {
+		switch {
+		case len(data) == 0:
+			return ""
+		case own || rec.Capture == "all" && (stdout.Len() == 0 || stderr.Len() == 0):
+			return rec.Fixture
+		}
+		c.files[rec.Fixture+"."+name] = data
+		return rec.Fixture + "." + name
+	}
+	c.stdout = channel("stdout", stdout.Bytes(), rec.Capture == "out")
+	c.stderr = channel("stderr", stderr.Bytes(), rec.Capture == "err")
+	return c, nil
+}
+
+// replayCommand runs fixturegen replay: it reproduces each recorded
+// fixture alone, writing it in place, or with check, reports those that
+// would change.
+func replayCommand(root string, args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	check := fs.Bool("check", false, "report fixtures that differ instead of rewriting them")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return errors.New("usage: fixturegen replay [-check] <fixture|recording.json>...")
+	}
+	stale := 0
+	for _, arg := range fs.Args() {
+		rec, base, err := loadRecording(root, arg)
+		if err != nil {
+			return err
+		}
+		c, err := rec.replay(base)
+		if err != nil {
+			return err
+		}
+		var problems []string
+		if c.exit != rec.Exit {
+			problems = append(problems, fmt.Sprintf("exit %d, recorded %d", c.exit, rec.Exit))
+		}
+		if c.stdout != rec.Stdout || c.stderr != rec.Stderr {
+			problems = append(problems, "channels moved")
+		}
+		var paths []string
+		for path := range c.files {
+			paths = append(paths, path)
+		}
+		sort.Strings(paths)
+		for _, path := range paths {
+			if old, err := os.ReadFile(filepath.Join(base, path)); err != nil || !bytes.Equal(old, c.files[path]) {
+				problems = append(problems, path+" differs")
+			}
+			if !*check {
+				if err := os.WriteFile(filepath.Join(base, path), c.files[path], 0o644); err != nil {
+					return err
+				}
+			}
+		}
+		for _, path := range []string{rec.Fixture + ".stdout", rec.Fixture + ".stderr"} {
+			if _, ok := c.files[path]; !ok && (path == rec.Stdout || path == rec.Stderr) {
+				problems = append(problems, path+" no longer written")
+				if !*check {
+					os.Remove(filepath.Join(base, path))
+				}
+			}
+		}
+		switch {
+		case len(problems) == 0:
+			fmt.Printf("ok      %s\n", rec.Fixture)
+		case *check:
+			stale++
+			fmt.Printf("differs %s: %s\n", rec.Fixture, strings.Join(problems, "; "))
+		default:
+			fmt.Printf("updated %s: %s\n", rec.Fixture, strings.Join(problems, "; "))
+		}
+	}
+	if stale > 0 {
+		return fmt.Errorf("%d fixtures differ from their recordings", stale)
+	}
+	return nil
+}
+
+// recordCommand runs fixturegen record: it captures a CLI's output as
+// capture in generate.sh does, saves it as the given fixture and writes
+// the recording beside it as <fixture>.json.
+func recordCommand(args []string) error {
+	fs := flag.NewFlagSet("record", flag.ExitOnError)
+	out := fs.String("o", "", "fixture file to write")
+	mode := fs.String("capture", "out", "channel to capture: out, err, all, or tty for a terminal")
+	cols := fs.Int("cols", 80, "terminal width, for -capture tty")
+	fs.Parse(args)
+	if *out == "" || fs.NArg() == 0 {
+		return errors.New("usage: fixturegen record -o <fixture> [-capture out|err|all|tty] [-cols N] <program> [args...]")
+	}
+	base, err := filepath.Abs(filepath.Dir(*out))
+	if err != nil {
+		return err
+	}
+	cwd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(base, cwd)
+	if err != nil {
+		return err
+	}
+	rec := &recording{
+		Fixture:  filepath.Base(*out),
+		Program:  fs.Arg(0),
+		Argv:     append([]string{filepath.Base(fs.Arg(0))}, fs.Args()[1:]...),
+		Env:      map[string]string{},
+		Cwd:      filepath.ToSlash(rel),
+		Stdin:    "null",
+		Terminal: "pipe",
+		Capture:  *mode,
+	}
+	if *mode == "tty" {
+		rec.Stdin, rec.Terminal, rec.Cols, rec.Rows = "pty", "pty", *cols, ptyRows
+	}
+	for _, kv := range os.Environ() {
+		if name, value, _ := strings.Cut(kv, "="); shapesOutput(name) {
+			rec.Env[name] = value
+		}
+	}
+	c, err := rec.replay(base)
+	if err != nil {
+		return err
+	}
+	rec.Exit, rec.Stdout, rec.Stderr = c.exit, c.stdout, c.stderr
+	for path, data := range c.files {
+		if err := os.WriteFile(filepath.Join(base, path), data, 0o644); err != nil {
+			return err
+		}
+	}
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(*out+".json", append(data, '\n'), 0o644)
+}
@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// runOptions control how script.run runs sections.
+type runOptions struct {
+	// jobs is how many sections run at once.
+	jobs int
+	// cache, when set, skips sections with nothing to regenerate and is
+	// updated for those that ran.
+	cache *cache
+	// stats, when set, collects what each section took.
+	stats *genStats
+}
+
+// result is what running one section printed, kept until the sections
+// before it have been printed so output stays in script order.
+type result struct {
+	stdout, stderr bytes.Buffer
+	err            error
+	// canceled is set for sections not started because another failed,
+	// skipped for those the cache found unchanged.
+	canceled, skipped bool
+	elapsed           time.Duration
+	// times are the fixtures the section reported writing.
+	times []fixtureTime
+}
+
+// run runs the named sections in dir, which must hold a copy of the
+// fixtures directory, sending their progress output to w in script order.
+// Sections run opts.jobs at a time, each in its own shell, except those
+// marked needs all, which run once the others are done, one at a time.
+func (s *script) run(dir string, names []string, w io.Writer, opts runOptions) error {
+	secs, err := s.selected(names)
+	if err != nil {
+		return err
+	}
+	var first, last []section
+	for _, sec := range secs {
+		if sec.needsAll {
+			last = append(last, sec)
+		} else {
+			first = append(first, sec)
+		}
+	}
+	err = s.runSections(dir, first, w, opts)
+	if err == nil {
+		sequential := opts
+		sequential.jobs = 1
+		err = s.runSections(dir, last, w, sequential)
+	}
+	if opts.cache != nil {
+		if serr := opts.cache.save(); err == nil {
+			err = serr
+		}
+	}
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("bash", "-c", strings.Join(s.epilogue, "\n"))
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// runSections runs secs concurrently, at most opts.jobs at once. After a
+// failure no more are started, and the first failure is returned once the
+// running ones finish.
+func (s *script) runSections(dir string, secs []section, w io.Writer, opts runOptions) error {
+	jobs := max(opts.jobs, 1)
+	results := make([]*result, len(secs))
+	done := make([]chan struct{}, len(secs))
+	sem := make(chan struct{}, jobs)
+	var failed atomic.Bool
+	for i, sec := range secs {
+		results[i] = &result{}
+		done[i] = make(chan struct{})
+		go func(r *result, sec section, done chan struct{}) {
+			defer close(done)
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if failed.Load() {
+				r.canceled = true
+				return
+			}
+			if r.err = s.runSection(dir, sec, r, opts.cache); r.err != nil {
+				failed.Store(true)
+			}
+		}(results[i], sec, done[i])
+	}
+	var err error
+	for i, r := range results {
+		<-done[i]
+		if r.canceled {
+			continue
+		}
+		w.Write(r.stdout.Bytes())
+		os.Stderr.Write(r.stderr.Bytes())
+		if opts.stats != nil {
+			opts.stats.section(secs[i], r)
+		}
+		if r.err != nil && err == nil {
+			err = fmt.Errorf("section %s: %w", secs[i].slug, r.err)
+		}
+	}
+	return err
+}
+
+// runSection runs sec in dir unless cache says it is up to date, recording
+// its output in r.
+func (s *script) runSection(dir string, sec section, r *result, c *cache) error {
+	if c != nil {
+		key, err := c.key(dir, s, sec)
+		if err != nil {
+			return err
+		}
+		if c.fresh(sec.slug, key) {
+			fmt.Fprintf(&r.stdout, "=== Skipping %s fixtures (unchanged) ===\n", sec.name)
+			r.skipped = true
+			return nil
+		}
+	}
+	path := filepath.Join(dir, ".fixturegen-"+sec.slug+".sh")
+	if err := os.WriteFile(path, []byte(s.source(sec)), 0o755); err != nil {
+		return err
+	}
+	defer os.Remove(path)
+	cmd := exec.Command("bash", path)
+	clock := newClock(&r.stdout)
+	cmd.Stdout = clock
+	cmd.Stderr = &r.stderr
+	err := cmd.Run()
+	r.elapsed, r.times = time.Since(clock.start), clock.times
+	if err != nil {
+		return err
+	}
+	if c != nil {
+		key, err := c.key(dir, s, sec)
+		if err != nil {
+			return err
+		}
+		c.store(sec.slug, key)
+	}
+	return nil
+}
+
+// cache remembers a hash of each section as it was when it last ran: of
+// its script, the Go toolchain, and every file git does not ignore in the
+// fixture directories it uses, sources and fixtures alike. A section whose
+// hash still matches would regenerate exactly what is there, so it can be
+// skipped; editing a source, a dependency pin or a fixture changes the hash.
+type cache struct {
+	path     string
+	mu       sync.Mutex
+	Sections map[string]string `json:"sections"`
+}
+
+// cacheFile is where generate keeps its cache, relative to the fixtures
+// directory.
+const cacheFile = ".fixturegen-cache.json"
+
+// loadCache reads the cache at path, starting an empty one if there is
+// none.
+func loadCache(path string) (*cache, error) {
+	c := &cache{path: path, Sections: map[string]string{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+func (c *cache) save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, append(data, '\n'), 0o644)
+}
+
+func (c *cache) fresh(slug, key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return key != "" && c.Sections[slug] == key
+}
+
+func (c *cache) store(slug, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Sections[slug] = key
+}
+
+var goVersion = sync.OnceValues(func() (string, error) {
+	out, err := exec.Command("go", "env", "GOVERSION").Output()
+	return strings.TrimSpace(string(out)), err
+})
+
+// key hashes sec and the files it uses in dir as they are now.
+func (c *cache) key(dir string, s *script, sec section) (string, error) {
+	version, err := goVersion()
+	if err != nil {
+		return "", fmt.Errorf("go env GOVERSION: %w", err)
+	}
+	h := sha256.New()
+	io.WriteString(h, s.source(sec))
+	io.WriteString(h, version+"\n")
+
+	args := []string{"ls-files", "-z", "--cached", "--others", "--exclude-standard"}
+	if !sec.needsAll {
+		dirs, err := sec.dirs(dir)
+		if err != nil {
+			return "", err
+		}
+		if len(dirs) == 0 {
+			// Nothing to hash means nothing to tell runs apart by.
+			return "", nil
+		}
+		args = append(append(args, "--"), dirs...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git ls-files: %w", err)
+	}
+	paths := strings.Split(strings.TrimSuffix(string(out), "\x00"), "\x00")
+	sort.Strings(paths)
+	for _, path := range paths {
+		full := filepath.Join(dir, path)
+		fmt.Fprintf(h, "%s\x00", path)
+		if link, err := os.Readlink(full); err == nil {
+			fmt.Fprintf(h, "-> %s\x00", link)
+		} else if data, err := os.ReadFile(full); err == nil {
+			fmt.Fprintf(h, "%d\x00", len(data))
+			h.Write(data)
+		} else {
+			io.WriteString(h, "missing\x00")
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
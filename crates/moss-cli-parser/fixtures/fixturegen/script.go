@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// script is generate.sh split into the parts fixturegen recombines to run
+// only some sections.
+type script struct {
+	path string
+	// prelude is everything before the first section: shebang, shell
+	// options and the cd into the fixtures directory.
+	prelude []string
+	// helpers are the shell functions defined anywhere in the script, so a
+	// section still works when the one defining them is skipped.
+	helpers  []string
+	sections []section
+	// epilogue is the closing "Done!" message.
+	epilogue []string
+}
+
+// section is the part of generate.sh from one "=== Generating" header up to
+// the next.
+type section struct {
+	name  string
+	slug  string
+	lines []string
+	// needsAll is set by a "# fixturegen: needs all" line in the section,
+	// for sections reading the fixtures the others write: it runs after
+	// them and after earlier such sections, and its cache entry covers
+	// every file.
+	needsAll bool
+}
+
+var (
+	headerRE = regexp.MustCompile(`^echo "=== Generating (.+) fixtures ==="$`)
+	funcRE   = regexp.MustCompile(`^[a-z_]+\(\) \{$`)
+	slugRE   = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+func loadScript(path string) (*script, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s := &script{path: path}
+	var inFunc bool
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := sc.Text()
+		if m := headerRE.FindStringSubmatch(line); m != nil {
+			slug := strings.Trim(slugRE.ReplaceAllString(strings.ToLower(m[1]), "-"), "-")
+			s.sections = append(s.sections, section{name: m[1], slug: slug})
+		}
+		if strings.HasPrefix(line, `echo "Done!`) {
+			s.epilogue = append(s.epilogue, line)
+			continue
+		}
+		if funcRE.MatchString(line) {
+			inFunc = true
+		}
+		if inFunc {
+			s.helpers = append(s.helpers, line)
+			inFunc = line != "}"
+			continue
+		}
+		if len(s.sections) == 0 {
+			s.prelude = append(s.prelude, line)
+		} else {
+			last := &s.sections[len(s.sections)-1]
+			last.lines = append(last.lines, line)
+			last.needsAll = last.needsAll || strings.TrimSpace(line) == "# fixturegen: needs all"
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(s.sections) == 0 {
+		return nil, fmt.Errorf("%s: no sections found", path)
+	}
+	return s, nil
+}
+
+// selected returns the named sections, or all of them when names is empty,
+// in script order.
+func (s *script) selected(names []string) ([]section, error) {
+	if len(names) == 0 {
+		return s.sections, nil
+	}
+	want := map[string]bool{}
+	for _, name := range names {
+		found := false
+		for _, sec := range s.sections {
+			if sec.slug == name {
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown section %q (see fixturegen list)", name)
+		}
+		want[name] = true
+	}
+	var secs []section
+	for _, sec := range s.sections {
+		if want[sec.slug] {
+			secs = append(secs, sec)
+		}
+	}
+	return secs, nil
+}
+
+// source returns a script running only sec.
+func (s *script) source(sec section) string {
+	var b strings.Builder
+	for _, lines := range [][]string{s.prelude, s.helpers, {""}, sec.lines} {
+		for _, line := range lines {
+			b.WriteString(line)
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+// dirs returns the fixture directories in root that sec uses: those it
+// names as a path, a cd target or a capture helper's directory argument.
+// A section marked needs all uses every file in root.
+func (sec section) dirs(root string) ([]string, error) {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	var code strings.Builder
+	for _, line := range sec.lines {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			code.WriteString(line + "\n")
+		}
+	}
+	var dirs []string
+	for _, e := range entries {
+		if !e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		re := regexp.MustCompile(`(?:^|[\s"'(=])(?:\./)?` + regexp.QuoteMeta(e.Name()) + `(?:/|[\s")]|$)`)
+		if re.MatchString(code.String()) {
+			dirs = append(dirs, e.Name())
+		}
+	}
+	return dirs, nil
+}
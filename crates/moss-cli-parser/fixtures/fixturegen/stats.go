@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// fixtureTime is how long a section took to write one fixture.
+type fixtureTime struct {
+	path    string
+	elapsed time.Duration
+}
+
+// clock passes a section's output through to w, timing the fixtures it
+// reports writing by the "  <path>" line generate.sh prints after each:
+// each from the line before it, or from the start of the section, so the
+// first fixture of a section carries the time of its builds.
+type clock struct {
+	w           io.Writer
+	start, last time.Time
+	partial     []byte
+	times       []fixtureTime
+}
+
+func newClock(w io.Writer) *clock {
+	now := time.Now()
+	return &clock{w: w, start: now, last: now}
+}
+
+func (c *clock) Write(p []byte) (int, error) {
+	now := time.Now()
+	c.partial = append(c.partial, p...)
+	for {
+		i := bytes.IndexByte(c.partial, '\n')
+		if i < 0 {
+			break
+		}
+		line := string(c.partial[:i])
+		c.partial = c.partial[i+1:]
+		if path, ok := strings.CutPrefix(line, "  "); ok && path != "" && !strings.ContainsAny(path, " \t") {
+			c.times = append(c.times, fixtureTime{path, now.Sub(c.last)})
+			c.last = now
+		}
+	}
+	return c.w.Write(p)
+}
+
+// genStats is the report generate -stats writes: what each section took,
+// and the make-up of the corpus as the manifest lists it afterwards.
+type genStats struct {
+	Seconds  float64        `json:"seconds"`
+	Jobs     int            `json:"jobs"`
+	Sections []sectionStats `json:"sections"`
+	Fixtures []fixtureStats `json:"fixtures"`
+	// Frameworks and Total add up Fixtures, by framework and overall.
+	Frameworks map[string]*corpusTotals `json:"frameworks"`
+	Total      corpusTotals             `json:"total"`
+
+	start time.Time
+	// times are the fixtures timed in the sections that ran, by path.
+	times map[string]fixtureStats
+}
+
+type sectionStats struct {
+	Section string  `json:"section"`
+	Seconds float64 `json:"seconds"`
+	// Fixtures is how many fixtures the section reported writing.
+	Fixtures int `json:"fixtures"`
+	// Skipped is set for sections the cache found unchanged, which wrote
+	// nothing and whose fixtures are not timed.
+	Skipped bool `json:"skipped,omitempty"`
+}
+
+type fixtureStats struct {
+	Path      string `json:"path"`
+	Framework string `json:"framework"`
+	// Section and Seconds are left out for fixtures of sections that did
+	// not run.
+	Section  string  `json:"section,omitempty"`
+	Seconds  float64 `json:"seconds,omitempty"`
+	Bytes    int     `json:"bytes"`
+	Lines    int     `json:"lines"`
+	Commands int     `json:"commands"`
+	Flags    int     `json:"flags"`
+}
+
+type corpusTotals struct {
+	Fixtures int `json:"fixtures"`
+	Bytes    int `json:"bytes"`
+	Commands int `json:"commands"`
+	Flags    int `json:"flags"`
+}
+
+func (t *corpusTotals) add(f fixtureStats) {
+	t.Fixtures++
+	t.Bytes += f.Bytes
+	t.Commands += f.Commands
+	t.Flags += f.Flags
+}
+
+func newStats(jobs int) *genStats {
+	return &genStats{Jobs: jobs, start: time.Now(), times: map[string]fixtureStats{}}
+}
+
+// section records what running sec took, from r.
+func (g *genStats) section(sec section, r *result) {
+	g.Sections = append(g.Sections, sectionStats{sec.slug, r.elapsed.Seconds(), len(r.times), r.skipped})
+	for _, t := range r.times {
+		g.times[t.path] = fixtureStats{Section: sec.slug, Seconds: t.elapsed.Seconds()}
+	}
+}
+
+var (
+	// commandSectionRE matches the names of sections listing commands,
+	// the groups CLIs sort theirs into included.
+	commandSectionRE = regexp.MustCompile(`(?i)\b(?:sub)?commands?\b`)
+	// commandRowRE matches a row of a command list.
+	commandRowRE = regexp.MustCompile(`^\s+\w[\w.:-]*(?:, *[\w.:-]+)*(?:\s{2,}|\t|$)`)
+)
+
+// countHelp returns the number of commands and flags the help in text
+// lists: rows of its command sections, and flag rows outside its usage
+// and examples.
+func countHelp(text string) (commands, flags int) {
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	for _, s := range spans(lines) {
+		name := strings.ToLower(s.name)
+		if strings.HasPrefix(name, "usage") || strings.HasPrefix(name, "example") {
+			continue
+		}
+		isCommands := commandSectionRE.MatchString(s.name)
+		for _, line := range lines[s.start+1 : s.end] {
+			switch {
+			case flagRowRE.MatchString(line):
+				flags++
+			case isCommands && commandRowRE.MatchString(line):
+				commands++
+			}
+		}
+	}
+	return commands, flags
+}
+
+// finish fills in g from the manifest in root, as the sections that ran
+// left it, and writes it to path.
+func (g *genStats) finish(root, path string) error {
+	g.Seconds = time.Since(g.start).Seconds()
+	data, err := os.ReadFile(filepath.Join(root, "manifest.json"))
+	if err != nil {
+		return err
+	}
+	var m struct {
+		Fixtures []struct {
+			Path      string `json:"path"`
+			Framework string `json:"framework"`
+		} `json:"fixtures"`
+	}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return fmt.Errorf("manifest.json: %w", err)
+	}
+	g.Fixtures = []fixtureStats{}
+	g.Frameworks = map[string]*corpusTotals{}
+	for _, mf := range m.Fixtures {
+		text, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(mf.Path)))
+		if err != nil {
+			return err
+		}
+		f := g.times[mf.Path]
+		f.Path, f.Framework, f.Bytes = mf.Path, mf.Framework, len(text)
+		f.Lines = bytes.Count(text, []byte("\n"))
+		if helpExts[filepath.Ext(mf.Path)] {
+			f.Commands, f.Flags = countHelp(string(text))
+		}
+		g.Fixtures = append(g.Fixtures, f)
+		if g.Frameworks[f.Framework] == nil {
+			g.Frameworks[f.Framework] = &corpusTotals{}
+		}
+		g.Frameworks[f.Framework].add(f)
+		g.Total.add(f)
+	}
+	out, err := json.MarshalIndent(g, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(out, '\n'), 0o644)
+}
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// skipDirs are not copied into the scratch directory: they hold build
+// output and dependencies, not fixtures.
+var skipDirs = map[string]bool{"node_modules": true, "target": true}
+
+// change is a fixture a fresh run added, removed or changed.
+type change struct {
+	Kind string `json:"kind"`
+	Path string `json:"path"`
+}
+
+// verify runs the named sections in a scratch copy of root and reports each
+// fixture the run changed, added or removed, ignoring files git ignores
+// (built binaries and the like). It fails if there were any.
+func verify(root string, s *script, names []string, jobs int, diff bool) error {
+	scratch, changes, err := regenerate(root, s, names, jobs, os.Stdout)
+	defer os.RemoveAll(filepath.Dir(scratch))
+	if err != nil {
+		return err
+	}
+	fmt.Println()
+	for _, c := range changes {
+		fmt.Printf("%-7s %s\n", c.Kind, c.Path)
+		if diff && c.Kind == "changed" {
+			cmd := exec.Command("diff", "-u", "--label", "a/"+c.Path, "--label", "b/"+c.Path,
+				filepath.Join(root, c.Path), filepath.Join(scratch, c.Path))
+			cmd.Stdout = os.Stdout
+			cmd.Run()
+		}
+	}
+	if len(changes) > 0 {
+		return fmt.Errorf("%d fixtures out of date; run fixturegen generate", len(changes))
+	}
+	fmt.Println("All fixtures up to date.")
+	return nil
+}
+
+// regenerate runs the named sections in a scratch copy of root, jobs at a
+// time and sending their output to w, and returns the copy along with the fixtures that
+// differ from root, sorted by path. The copy sits in a directory of its own
+// beside a link to mosshelp, which fixturegen builds against from the
+// fixtures' parent; the caller removes that directory, the copy's parent.
+func regenerate(root string, s *script, names []string, jobs int, w io.Writer) (string, []change, error) {
+	base, err := os.MkdirTemp("", "fixturegen-")
+	if err != nil {
+		return "", nil, err
+	}
+	scratch := filepath.Join(base, "fixtures")
+	mosshelp, err := filepath.Abs(filepath.Join(root, "..", "mosshelp"))
+	if err != nil {
+		return scratch, nil, err
+	}
+	if err := os.Symlink(mosshelp, filepath.Join(base, "mosshelp")); err != nil {
+		return scratch, nil, err
+	}
+	if err := copyTree(root, scratch); err != nil {
+		return scratch, nil, err
+	}
+	if err := s.run(scratch, names, w, runOptions{jobs: jobs}); err != nil {
+		return scratch, nil, err
+	}
+
+	before, err := listTree(root)
+	if err != nil {
+		return scratch, nil, err
+	}
+	after, err := listTree(scratch)
+	if err != nil {
+		return scratch, nil, err
+	}
+	var changes []change
+	for path := range after {
+		if _, ok := before[path]; !ok {
+			changes = append(changes, change{"added", path})
+		} else if !sameFile(filepath.Join(root, path), filepath.Join(scratch, path)) {
+			changes = append(changes, change{"changed", path})
+		}
+	}
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changes = append(changes, change{"removed", path})
+		}
+	}
+	changes, err = dropIgnored(root, changes)
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return scratch, changes, err
+}
+
+// copyTree copies the fixtures directory src into dst, keeping symlinks as
+// symlinks and file modes as they are.
+func copyTree(src, dst string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dst, rel)
+		switch {
+		case d.IsDir() && skipDirs[d.Name()]:
+			return filepath.SkipDir
+		case d.IsDir():
+			return os.MkdirAll(target, 0o755)
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		in, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, in); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
+
+// listTree returns the paths, relative to root, of every file and symlink
+// under it outside skipDirs.
+func listTree(root string) (map[string]bool, error) {
+	paths := map[string]bool{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if skipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		paths[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	return paths, err
+}
+
+// sameFile reports whether a and b have the same contents, or for
+// symlinks, the same target.
+func sameFile(a, b string) bool {
+	if la, err := os.Readlink(a); err == nil {
+		lb, err := os.Readlink(b)
+		return err == nil && la == lb
+	}
+	da, err := os.ReadFile(a)
+	if err != nil {
+		return false
+	}
+	db, err := os.ReadFile(b)
+	return err == nil && bytes.Equal(da, db)
+}
+
+// dropIgnored removes the changes to paths git ignores in root.
+func dropIgnored(root string, changes []change) ([]change, error) {
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	var paths strings.Builder
+	for _, c := range changes {
+		paths.WriteString(c.Path + "\x00")
+	}
+	cmd := exec.Command("git", "check-ignore", "-z", "--stdin")
+	cmd.Dir = root
+	cmd.Stdin = strings.NewReader(paths.String())
+	out, err := cmd.Output()
+	// check-ignore exits 1 when no path is ignored.
+	if exit, ok := err.(*exec.ExitError); err != nil && !(ok && exit.ExitCode() == 1) {
+		return nil, fmt.Errorf("git check-ignore: %w", err)
+	}
+	ignored := map[string]bool{}
+	for _, path := range strings.Split(string(out), "\x00") {
+		ignored[path] = true
+	}
+	var kept []change
+	for _, c := range changes {
+		if !ignored[c.Path] {
+			kept = append(kept, c)
+		}
+	}
+	return kept, nil
+}
@@ -0,0 +1,77 @@
+// Example standard library flag CLI for testing help output parsing.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// listValue is a repeatable string flag.
+type listValue []string
+
+func (l *listValue) String() string { return strings.Join(*l, ",") }
+
+func (l *listValue) Set(s string) error {
+	*l = append(*l, s)
+	return nil
+}
+
+// The flag set is named rather than using flag.CommandLine, whose default
+// usage prints os.Args[0] and so depends on how the binary was invoked.
+var fs = flag.NewFlagSet("example", flag.ExitOnError)
+
+var (
+	verbose  = fs.Bool("v", false, "enable verbose output")
+	config   = fs.String("config", "", "read settings from `file`")
+	port     = fs.Int("port", 8080, "port to listen on")
+	host     = fs.String("host", "localhost", "host to bind")
+	timeout  = fs.Duration("timeout", 30*time.Second, "request timeout")
+	ratio    = fs.Float64("ratio", 0.5, "fraction of requests to sample")
+	workers  = fs.Uint("workers", 0, "number of worker goroutines; 0 means one per CPU")
+	dryRun   = fs.Bool("dry-run", false, "print what would be done\nwithout doing it")
+	includes listValue
+)
+
+func init() {
+	fs.Var(&includes, "I", "add `dir` to the include path (repeatable)")
+	fs.Func("log-level", "minimum `level` to log: debug, info, warn or error", func(s string) error {
+		switch s {
+		case "debug", "info", "warn", "error":
+			return nil
+		}
+		return fmt.Errorf("unknown level %q", s)
+	})
+}
+
+// customUsage replaces the default "Usage of example:" header with a
+// synopsis and description, as most stdlib-flag tools do.
+func customUsage() {
+	out := fs.Output()
+	fmt.Fprintf(out, "usage: example [flags] <input>...\n\n")
+	fmt.Fprintf(out, "Example reads each input and reports what it finds.\n\n")
+	fmt.Fprintf(out, "Flags:\n")
+	fs.PrintDefaults()
+}
+
+func main() {
+	switch os.Getenv("EXAMPLE_VARIANT") {
+	case "":
+	case "custom-usage":
+		fs.Usage = customUsage
+	case "command-line":
+		// Named as flag.CommandLine is, so help names the binary as it was
+		// invoked.
+		fs.Init(os.Args[0], flag.ExitOnError)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown EXAMPLE_VARIANT %q\n", os.Getenv("EXAMPLE_VARIANT"))
+		os.Exit(2)
+	}
+	fs.Parse(os.Args[1:])
+	fs.Visit(func(f *flag.Flag) {
+		fmt.Printf("%s=%s\n", f.Name, f.Value)
+	})
+	fmt.Println("Args:", fs.Args())
+}
@@ -1,11 +1,223 @@
 #!/usr/bin/env bash
 # Regenerate all CLI --help fixtures.
 # Run from within nix-shell for all dependencies.
+#
+# fixturegen/ runs single sections of this script (each begins at an
+# "=== Generating <name> fixtures ===" line), several at once, and verifies
+# the committed fixtures against a fresh run, so keep shell functions at the
+# top level and have each section write only to its own directories. A
+# section reading what the others wrote says "# fixturegen: needs all".
 
 set -euo pipefail
 
 cd "$(dirname "$0")"
 
+# Captures assume no terminal width, color preference, locale or pager
+# unless they set one; see the widths/, *-colored*, *-localized* and *-paged*
+# fixtures below.
+unset COLUMNS NO_COLOR CLICOLOR CLICOLOR_FORCE LANG LANGUAGE $(compgen -e LC_) PAGER $(compgen -e LESS)
+
+# Every fixture program is a module of its own, pinned by its go.mod and
+# go.sum, so updating one's dependencies leaves the others' alone. Builds
+# use exactly what those pin, failing rather than resolving anything anew,
+# and leave out the paths and VCS state that differ between checkouts, so
+# the same sources build the same binaries wherever the script runs. The
+# tree exporter those built on cobra share, cobratree/, is replaced into
+# each from this directory.
+export GOFLAGS="-mod=readonly -trimpath -buildvcs=false"
+
+# Terminal widths captured by the *_capture_widths helpers, besides unset.
+widths="40 80 120 200"
+
+# PATH to compare against, so captures that extend it can be recorded.
+base_path=$PATH
+
+# Variables the platform's home, config, cache and temporary directories
+# come from, as the script started, so captures that pin them can be
+# recorded; see platform_capture.
+platform_vars="HOME TMPDIR XDG_CONFIG_HOME XDG_CACHE_HOME USERPROFILE APPDATA LOCALAPPDATA TMP"
+declare -A base_platform
+for var in $platform_vars; do
+    base_platform[$var]=${!var+set:}${!var-}
+done
+
+# capture <mode> <dir> <fixture> <program> <args...>: run program and save
+# what it prints as <dir>/<fixture>: stdout for mode out, stderr for err,
+# both merged as the terminal would show them for all, or for tty, what
+# a terminal gets when it is stdin, stdout and stderr (see pty_capture).
+# Whatever the fixture
+# leaves out is kept beside it as <fixture>.stdout or <fixture>.stderr when
+# not empty, so which channel each line came from is never lost, and the
+# invocation is noted in <dir>/invocations.tsv. Programs run from the
+# fixtures directory with stdin from /dev/null. A failing program stops the
+# script in mode out only. Prefix with VAR=value to set its environment.
+capture() {
+    local mode=$1 dir=$2 out=$3 status=0 channel
+    shift 3
+    local fixture=$dir/$out stdout=$dir/$out.stdout stderr=$dir/$out.stderr
+    rm -f "$stdout" "$stderr"
+    case $mode in
+    out)
+        "$@" < /dev/null > "$fixture" 2> "$stderr" || status=$?
+        stdout=$fixture
+        ;;
+    err)
+        "$@" < /dev/null > "$stdout" 2> "$fixture" || status=$?
+        stderr=$fixture
+        ;;
+    all)
+        "$@" < /dev/null > "$fixture" 2>&1 || status=$?
+        "$@" < /dev/null > "$stdout" 2> "$stderr" || true
+        ;;
+    tty)
+        "$pty_run" pty -cols "$pty_cols" "$@" > "$fixture" || status=$?
+        stdout=$fixture stderr=$fixture
+        ;;
+    esac
+    for channel in stdout stderr; do
+        if [ ! -s "${!channel}" ]; then
+            [ "${!channel}" = "$fixture" ] || rm -f "${!channel}"
+            printf -v "$channel" ''
+        fi
+    done
+    # With one channel silent, a merged fixture already is the other.
+    if [ "$mode" = all ] && [ -z "$stdout" ] && [ -n "$stderr" ]; then
+        rm -f "$stderr"
+        stderr=$fixture
+    elif [ "$mode" = all ] && [ -n "$stdout" ] && [ -z "$stderr" ]; then
+        rm -f "$stdout"
+        stdout=$fixture
+    fi
+    record "$mode" "$dir" "$out" "$status" "${stdout#"$dir/"}" "${stderr#"$dir/"}" "$@"
+    if [ "$mode" = out ] && [ "$status" -ne 0 ]; then
+        echo "error: $* exited $status" >&2
+        exit "$status"
+    fi
+}
+
+# pty_capture <dir> <fixture> <cols> <program> <args...>: capture mode tty
+# on a pseudo-terminal <cols> wide, for output that differs at a terminal:
+# color, progress bars, wrapping to the terminal's width. fixturegen pty
+# provides the terminal, built once per run of the script.
+pty_capture() {
+    local dir=$1 out=$2
+    pty_cols=$3
+    shift 3
+    if [ -z "${pty_run:-}" ]; then
+        pty_tmp=$(mktemp -d)
+        trap 'rm -rf "$pty_tmp"' EXIT
+        pty_run=$pty_tmp/fixturegen
+        (cd fixturegen && go build -o "$pty_run")
+    fi
+    capture tty "$dir" "$out" "$@"
+}
+
+# invoked_capture <dir> <name> <args...>: capture mode all of <dir>/example
+# run under other names, as a consumer keying commands by program name
+# meets it: through <dir>/invoked/linked-example, a symlink to it, as
+# <dir>/<name>-symlink.help, and as <dir>/invoked/renamed-example, a copy,
+# as <dir>/<name>-renamed.help. <dir>/<name>.program-name.json notes the
+# name each printed: "path" for os.Args[0] as invoked, "name" for its base
+# name, or "compiled" for the name built into the program, whatever it was
+# run as.
+invoked_capture() {
+    local dir=$1 prefix=$2 kind name shows json=
+    shift 2
+    mkdir -p "$dir/invoked"
+    ln -sfn ../example "$dir/invoked/linked-example"
+    cp "$dir/example" "$dir/invoked/renamed-example"
+    for kind in symlink renamed; do
+        name=linked-example
+        [ "$kind" = symlink ] || name=renamed-example
+        capture all "$dir" "$prefix-$kind.help" "./$dir/invoked/$name" "$@"
+        if grep -qF "./$dir/invoked/$name" "$dir/$prefix-$kind.help"; then
+            shows=path
+        elif grep -qw -- "$name" "$dir/$prefix-$kind.help"; then
+            shows=name
+        else
+            shows=compiled
+        fi
+        json+=$(printf ',\n  "%s": {"invoked": "%s", "help": "%s-%s.help", "shows": "%s"}' "$kind" "$name" "$prefix" "$kind" "$shows")
+    done
+    printf '{\n  "compiled": "example"%s\n}\n' "$json" > "$dir/$prefix.program-name.json"
+    echo "  $dir/$prefix.program-name.json"
+}
+
+# record <mode> <dir> <fixture> <exit> <stdout> <stderr> <program> <args...>:
+# note an invocation of capture in <dir>/invocations.tsv, for reading, as a
+# line of <dir>/exit-codes.jsonl, for tools, and as a line of
+# <dir>/recordings.jsonl, from which fixturegen replay reproduces the
+# fixture alone; all three start afresh on the first capture in <dir>. Each
+# gives the environment that shapes output (EXAMPLE_* variables, COLUMNS,
+# the color switches, the locale, the pager, any PATH extension and any
+# platform directory pinned), argv and the exit
+# status; the TSV adds the files holding stdout and stderr, and recordings
+# everything else capture did, as fixturegen/record.go describes.
+declare -A recorded
+record() {
+    local mode=$1 dir=$2 out=$3 status=$4 stdout=$5 stderr=$6 argv= tsv_env= json_env= json_argv= var value arg
+    local terminal='"stdin": "null", "terminal": "pipe"'
+    shift 6
+    if [ "$mode" = tty ]; then
+        terminal="\"stdin\": \"pty\", \"terminal\": \"pty\", \"cols\": $pty_cols, \"rows\": 24"
+    fi
+    if [ $# -gt 1 ]; then
+        printf -v argv ' %q' "${@:2}"
+    fi
+    for var in $(compgen -e EXAMPLE_; compgen -e COLUMNS; compgen -e NO_COLOR; compgen -e CLICOLOR; compgen -e LANG; compgen -e LC_; compgen -e PAGER; compgen -e LESS) PATH $platform_vars; do
+        if [[ " $platform_vars " = *" $var "* ]]; then
+            [ "${!var+set:}${!var-}" != "${base_platform[$var]}" ] || continue
+        fi
+        value=${!var-}
+        if [ "$var" = PATH ]; then
+            [ "$PATH" != "$base_path" ] || continue
+            value=${PATH%"$base_path"}
+            value=${value//"$PWD"/.}\$PATH
+            tsv_env+=" PATH=$value"
+        elif [ "$var" != EXAMPLE_VARIANT ]; then
+            printf -v tsv_env '%s %s=%q' "$tsv_env" "$var" "$value"
+        fi
+        json_env+=", $(json_string "$var"): $(json_string "$value")"
+    done
+    for arg in "$(basename "$1")" "${@:2}"; do
+        json_argv+=", $(json_string "$arg")"
+    done
+    if [ -z "${recorded[$dir]:-}" ]; then
+        printf 'fixture\tvariant\tenv\targv\texit\tstdout\tstderr\n' > "$dir/invocations.tsv"
+        : > "$dir/exit-codes.jsonl"
+        : > "$dir/recordings.jsonl"
+        recorded[$dir]=1
+    fi
+    printf '%s\t%s\t%s\t%s\t%s\t%s\t%s\n' "$out" "${EXAMPLE_VARIANT:-}" "${tsv_env# }" \
+        "$(basename "$1")$argv" "$status" "$stdout" "$stderr" >> "$dir/invocations.tsv"
+    printf '{"fixture": %s, "argv": [%s], "env": {%s}, "exit": %d}\n' "$(json_string "$dir/$out")" \
+        "${json_argv#, }" "${json_env#, }" "$status" >> "$dir/exit-codes.jsonl"
+    printf '{"fixture": %s, "program": %s, "argv": [%s], "env": {%s}, "cwd": ".", %s, "capture": "%s", "stdout": %s, "stderr": %s, "exit": %d}\n' \
+        "$(json_string "$dir/$out")" "$(json_string "$1")" "${json_argv#, }" "${json_env#, }" "$terminal" "$mode" \
+        "$(json_string "${stdout:+$dir/$stdout}")" "$(json_string "${stderr:+$dir/$stderr}")" "$status" >> "$dir/recordings.jsonl"
+    echo "  $dir/$out"
+}
+
+# json_string <s>: print s as a JSON string.
+json_string() {
+    local s=$1
+    s=${s//\\/\\\\}
+    s=${s//\"/\\\"}
+    s=${s//$'\t'/\\t}
+    s=${s//$'\n'/\\n}
+    s=${s//$'\r'/\\r}
+    s=${s//$'\e'/\\u001b}
+    printf '"%s"' "$s"
+}
+
+# clear_generated <dir>: remove what a section generated into dir, keeping
+# the expected_warnings.json files written by hand.
+clear_generated() {
+    [ -d "$1" ] || return 0
+    find "$1" ! -type d ! -name expected_warnings.json -delete
+    find "$1" -mindepth 1 -type d -empty -delete
+}
+
 echo "=== Generating clap fixtures ==="
 (cd clap && cargo build --release 2>/dev/null)
 ./clap/target/release/example --help > clap/example.help
@@ -35,8 +247,883 @@ echo "  yargs/example.help"
 
 echo "=== Generating cobra fixtures ==="
 (cd cobra && go build -o example 2>/dev/null)
-./cobra/example --help > cobra/example.help
-echo "  cobra/example.help"
+
+# cobra_capture <fixture> <args...>: save stdout of the cobra example.
+cobra_capture() {
+    local out=$1
+    shift
+    capture out cobra "$out" ./cobra/example "$@"
+}
+
+# cobra_capture_all <fixture> <args...>: like cobra_capture, but stderr is
+# merged in for invocations that print warnings alongside their output.
+cobra_capture_all() {
+    local out=$1
+    shift
+    capture all cobra "$out" ./cobra/example "$@"
+}
+
+# cobra_capture_error <fixture> <args...>: save stderr of an invocation that
+# is expected to fail.
+cobra_capture_error() {
+    local out=$1
+    shift
+    capture err cobra "$out" ./cobra/example "$@"
+}
+
+# cobra_capture_widths <name> <args...>: cobra_capture at each of $widths
+# and with COLUMNS unset, as cobra/widths/<name>-<cols>.help.
+cobra_capture_widths() {
+    local name=$1 cols
+    shift
+    mkdir -p cobra/widths
+    for cols in $widths; do
+        COLUMNS=$cols cobra_capture "widths/$name-$cols.help" "$@"
+    done
+    cobra_capture "widths/$name-unset.help" "$@"
+}
+
+# Help for each command.
+cobra_capture example.help --help
+invoked_capture cobra example --help
+cobra_capture example-build.help build --help
+cobra_capture example-run.help run --help
+cobra_capture example-debug.help debug --help
+cobra_capture example-reference.help reference --help
+cobra_capture_all example-compile.help compile --help
+cobra_capture example-deploy.help deploy --help
+cobra_capture example-deploy-rollback.help deploy rollback --help
+cobra_capture example-login.help login --help
+cobra_capture example-serve.help serve --help
+cobra_capture example-greet.help greet --help
+cobra_capture example-greet-japanese.help greet こんにちは --help
+cobra_capture example-init.help init --help
+cobra_capture example-search.help search --help
+cobra_capture example-status.help status --help
+cobra_capture example-version.help version --help
+cobra_capture example-config.help config --help
+cobra_capture example-config-get.help config get --help
+cobra_capture example-config-set.help config set --help
+cobra_capture example-config-path.help config path --help
+cobra_capture example-config-check.help config check --help
+cobra_capture example-config-import.help config import --help
+cobra_capture example-convert.help convert --help
+cobra_capture example-proxy.help proxy --help
+cobra_capture example-exec.help exec --help
+cobra_capture example-calc.help calc --help
+cobra_capture example-cluster.help cluster --help
+cobra_capture example-cluster-node.help cluster node --help
+cobra_capture example-cluster-node-list.help cluster node list --help
+cobra_capture example-cluster-node-pool.help cluster node pool --help
+cobra_capture example-cluster-node-pool-create.help cluster node pool create --help
+cobra_capture example-cluster-node-pool-delete.help cluster node pool delete --help
+cobra_capture example-query.help query --help
+cobra_capture example-query-save.help query save --help
+cobra_capture example-mirror.help mirror --help
+cobra_capture example-sync.help sync --help
+
+# The other ways of asking for help.
+cobra_capture example-help.help help
+cobra_capture example-help-build.help help build
+cobra_capture example-help-cluster-node-pool-create.help help cluster node pool create
+cobra_capture example-build-h.help build -h
+cobra_capture_all example-help-unknown.help help bogus
+cobra_capture example-help-environment.help help environment
+cobra_capture example-help-exit-codes.help help exit-codes
+cobra_capture example-help-cluster-contexts.help help cluster contexts
+cobra_capture example-environment.help environment
+
+# Help under each EXAMPLE_VARIANT.
+EXAMPLE_VARIANT=unhidden cobra_capture example-unhidden.help --help
+EXAMPLE_VARIANT=unhidden cobra_capture example-build-unhidden.help build --help
+EXAMPLE_VARIANT=grouped cobra_capture example-grouped.help --help
+EXAMPLE_VARIANT=custom-help cobra_capture example-custom-help.help --help
+EXAMPLE_VARIANT=custom-help cobra_capture example-build-custom-help.help build --help
+EXAMPLE_VARIANT=usage-template cobra_capture example-usage-template.help --help
+EXAMPLE_VARIANT=usage-template cobra_capture example-build-usage-template.help build --help
+EXAMPLE_VARIANT=usage-func cobra_capture example-usage-func.help --help
+EXAMPLE_VARIANT=usage-func cobra_capture example-build-usage-func.help build --help
+EXAMPLE_VARIANT=wrapped cobra_capture example-build-wrapped.help build --help
+EXAMPLE_VARIANT=wrapped cobra_capture example-deploy-wrapped.help deploy --help
+# Tab indents, and tabs, trailing spaces and non-breaking spaces in text.
+EXAMPLE_VARIANT=whitespace cobra_capture example-whitespace.help --help
+EXAMPLE_VARIANT=whitespace cobra_capture example-build-whitespace.help build --help
+EXAMPLE_VARIANT=whitespace cobra_capture example-cluster-whitespace.help cluster --help
+EXAMPLE_VARIANT=traverse cobra_capture example-traverse.help --help
+EXAMPLE_VARIANT=help-renamed cobra_capture example-help-renamed.help --help
+EXAMPLE_VARIANT=help-renamed cobra_capture example-help-renamed-explain-build.help explain build
+EXAMPLE_VARIANT=help-replaced cobra_capture example-help-replaced.help --help
+EXAMPLE_VARIANT=help-replaced cobra_capture example-help-replaced-cluster-node.help help cluster node
+EXAMPLE_VARIANT=no-help cobra_capture example-no-help.help --help
+EXAMPLE_VARIANT=no-help cobra_capture example-no-help-cluster.help cluster --help
+EXAMPLE_VARIANT=no-completion cobra_capture example-no-completion.help --help
+# A hidden completion command still runs, and unhidden lists it again.
+EXAMPLE_VARIANT=hidden-completion cobra_capture example-hidden-completion.help --help
+EXAMPLE_VARIANT=hidden-completion cobra_capture example-hidden-completion-completion.help completion --help
+EXAMPLE_VARIANT=hidden-completion,unhidden cobra_capture example-hidden-completion-unhidden.help --help
+
+# Help with ANSI colors forced on, then with each way of toggling them.
+EXAMPLE_VARIANT=colored CLICOLOR_FORCE=1 cobra_capture example-colored.help --help
+EXAMPLE_VARIANT=colored CLICOLOR_FORCE=1 cobra_capture example-build-colored.help build --help
+EXAMPLE_VARIANT=grouped,colored CLICOLOR_FORCE=1 cobra_capture example-grouped-colored.help --help
+EXAMPLE_VARIANT=colored CLICOLOR_FORCE=1 cobra_capture example-cluster-colored.help cluster --help
+EXAMPLE_VARIANT=colored cobra_capture example-colored-piped.help --help
+EXAMPLE_VARIANT=colored NO_COLOR=1 cobra_capture example-colored-no-color.help --help
+EXAMPLE_VARIANT=colored CLICOLOR=0 cobra_capture example-colored-clicolor-0.help --help
+EXAMPLE_VARIANT=colored NO_COLOR=1 CLICOLOR_FORCE=1 cobra_capture example-colored-no-color-force.help --help
+
+# At a terminal: color without forcing it, wrapping to the terminal's width
+# unless COLUMNS says otherwise, and a progress bar that pipes never see.
+EXAMPLE_VARIANT=colored pty_capture cobra example-colored-tty.help 80 ./cobra/example --help
+EXAMPLE_VARIANT=colored NO_COLOR=1 pty_capture cobra example-colored-tty-no-color.help 80 ./cobra/example --help
+EXAMPLE_VARIANT=wrapped pty_capture cobra example-build-wrapped-tty-60.help 60 ./cobra/example build --help
+EXAMPLE_VARIANT=wrapped COLUMNS=100 pty_capture cobra example-build-wrapped-tty-columns.help 60 ./cobra/example build --help
+pty_capture cobra example-build-tty.out 80 ./cobra/example build
+
+# Help sent through a pager at a terminal. cobra/pager/example-pager, also
+# installed as less for when PAGER is unset, marks what it pages and does
+# not wait for a key; PAGER=cat, an empty PAGER and pipes get no pager.
+EXAMPLE_VARIANT=paged cobra_capture example-paged-pipe.help --help
+EXAMPLE_VARIANT=paged PAGER=./cobra/pager/example-pager pty_capture cobra example-paged-tty-pager.help 80 ./cobra/example --help
+EXAMPLE_VARIANT=paged PATH="$PWD/cobra/pager:$PATH" pty_capture cobra example-paged-tty-unset.help 80 ./cobra/example --help
+EXAMPLE_VARIANT=paged PATH="$PWD/cobra/pager:$PATH" LESS=R pty_capture cobra example-paged-tty-less-set.help 80 ./cobra/example --help
+EXAMPLE_VARIANT=paged PAGER=cat pty_capture cobra example-paged-tty-cat.help 80 ./cobra/example --help
+EXAMPLE_VARIANT=paged PAGER= pty_capture cobra example-paged-tty-empty.help 80 ./cobra/example --help
+EXAMPLE_VARIANT=paged PAGER=./cobra/pager/example-pager pty_capture cobra example-build-paged-tty-pager.help 80 ./cobra/example build --help
+
+# Help as captured on Windows: CRLF line endings and backslash paths.
+EXAMPLE_VARIANT=windows cobra_capture example-windows.help --help
+EXAMPLE_VARIANT=windows cobra_capture example-build-windows.help build --help
+EXAMPLE_VARIANT=windows cobra_capture example-proxy-windows.help proxy --help
+EXAMPLE_VARIANT=windows cobra_capture_error example-windows-unknown-flag.err build --nope
+
+# Output writers pointed elsewhere: help on stderr, errors on stdout with
+# their usage left on stderr, and help rewritten through a buffer. Merged
+# captures record which channel each line came from.
+EXAMPLE_VARIANT=stderr-output cobra_capture_all example-stderr-output.help --help
+EXAMPLE_VARIANT=stderr-output cobra_capture_all example-build-stderr-output.help build --help
+EXAMPLE_VARIANT=stderr-output cobra_capture_all example-stderr-output-unknown-flag.err build --nope
+EXAMPLE_VARIANT=stdout-errors cobra_capture_all example-stdout-errors-unknown-flag.err build --nope
+EXAMPLE_VARIANT=buffered-help cobra_capture example-buffered-help.help --help
+EXAMPLE_VARIANT=buffered-help cobra_capture example-build-buffered-help.help build --help
+EXAMPLE_VARIANT=buffered-help cobra_capture example-help-build-buffered-help.help help build
+
+# Usage on error after logs and progress bars drawn with carriage returns
+# and cursor movement, and the same error without them.
+cobra_capture_error example-sync-progress-unknown-remote.err sync bogus
+cobra_capture_error example-sync-unknown-remote.err sync --quiet bogus
+
+# Help with templates translated for the locale. LC_ALL overrides
+# LC_MESSAGES, which overrides LANG; C and untranslated locales keep
+# English, as does stock cobra whatever the locale.
+for lang in de es fr; do
+    LANG=${lang}_${lang^^}.UTF-8 EXAMPLE_VARIANT=localized cobra_capture "example-localized-$lang.help" --help
+done
+LANG=de_DE.UTF-8 EXAMPLE_VARIANT=localized cobra_capture example-cluster-localized-de.help cluster --help
+LANG=es_ES.UTF-8 EXAMPLE_VARIANT=localized cobra_capture example-deploy-localized-es.help deploy --help
+LANG=fr_FR.UTF-8 EXAMPLE_VARIANT=grouped,localized cobra_capture example-grouped-localized-fr.help --help
+LANG=de_DE.UTF-8 LC_ALL=es_ES.UTF-8 EXAMPLE_VARIANT=localized cobra_capture example-localized-lc-all.help --help
+LANG=de_DE.UTF-8 LC_MESSAGES=fr_FR.UTF-8 EXAMPLE_VARIANT=localized cobra_capture example-localized-lc-messages.help --help
+LANG=C EXAMPLE_VARIANT=localized cobra_capture example-localized-c.help --help
+LANG=ja_JP.UTF-8 EXAMPLE_VARIANT=localized cobra_capture example-localized-untranslated.help --help
+LANG=de_DE.UTF-8 cobra_capture example-lang-de.help --help
+
+# Wrapped help across terminal widths.
+rm -rf cobra/widths
+EXAMPLE_VARIANT=wrapped cobra_capture_widths example-build build --help
+EXAMPLE_VARIANT=wrapped cobra_capture_widths example-deploy deploy --help
+# mirror's descriptions are fitted to wrapping at 80 columns: at it and a
+# column either side, words land on and one past the last column wrapped
+# lines reach.
+for cols in 79 80 81; do
+    EXAMPLE_VARIANT=wrapped COLUMNS=$cols cobra_capture "widths/example-mirror-$cols.help" mirror --help
+done
+
+# Help with commands discovered from a plugins directory.
+EXAMPLE_PLUGINS=cobra/plugins cobra_capture example-plugins.help --help
+EXAMPLE_PLUGINS=cobra/plugins EXAMPLE_VARIANT=grouped cobra_capture example-plugins-grouped.help --help
+
+# Help of a tree that depends on where it runs: experimental features,
+# switched on by EXAMPLE_EXPERIMENTAL or by the settings file, add a command
+# and a flag and change a default. Their tree is the ground truth for the
+# fixtures in cobra/experimental; the variable wins over the file.
+rm -rf cobra/experimental
+mkdir -p cobra/experimental
+EXAMPLE_EXPERIMENTAL=1 ./cobra/example -gen-tree cobra/experimental
+EXAMPLE_EXPERIMENTAL=1 cobra_capture experimental/example.help --help
+EXAMPLE_EXPERIMENTAL=1 cobra_capture experimental/example-build.help build --help
+EXAMPLE_EXPERIMENTAL=1 cobra_capture experimental/example-watch.help watch --help
+EXAMPLE_CONFIG=cobra/settings/experimental.toml cobra_capture experimental/example-build-config.help build --help
+EXAMPLE_EXPERIMENTAL=0 EXAMPLE_CONFIG=cobra/settings/experimental.toml cobra_capture example-build-experimental-off.help build --help
+
+# Successful invocations, showing how arguments were parsed.
+cobra_capture example-version.out --version
+cobra_capture example-version-command.out version
+EXAMPLE_VARIANT=build-info cobra_capture example-version-build-info.out --version
+EXAMPLE_VARIANT=build-info cobra_capture example-version-command-build-info.out version
+cobra_capture example-run-color-bare.out run --color
+cobra_capture example-run-color-equals.out run --color=never
+cobra_capture example-run-color-space.out run --color never
+cobra_capture example-run-terminator.out run -- --not-a-flag -v
+cobra_capture example-run-terminator-mixed.out run app -v -- --debug
+cobra_capture example-run-terminator-only.out run --
+cobra_capture example-serve-values.out serve --timeout 5s --bind 0.0.0.0 \
+    --allow 192.168.0.0/16 --tags a,b --tags c --header 'X-Env: dev' \
+    --labels team=core --ports 8081,8082 -qq --key deadbeef \
+    --ratio 0.25 --max-body 2MB
+cobra_capture example-serve-shadowed-config.out serve --config prod.toml
+cobra_capture example-proxy-unknown-flags.out proxy -w src make --jobs 4 -k --keep-going=yes all
+cobra_capture example-proxy-terminator.out proxy -w src make -- --jobs 4 -k all
+# exec's flags end at its first positional: after it, flags, --help and --
+# all reach the command run.
+cobra_capture example-exec-flags-first.out exec -e FOO=1 ls -la
+cobra_capture example-exec-flags-after-command.out exec ls -e FOO=1 --help
+cobra_capture example-exec-terminator.out exec --dry-run -- ls -la
+cobra_capture example-exec-terminator-after-command.out exec -e A=1 make -- -j4
+# status counts -v: repeated, bundled, after a positional or with a value.
+cobra_capture example-status-verbose.out status -v api
+cobra_capture example-status-verbose-bundled.out status -vv api
+cobra_capture example-status-verbose-bundled-3.out status -vvv api
+cobra_capture example-status-verbose-repeated.out status -v -v -v api
+cobra_capture example-status-verbose-mixed.out status api -vv --verbose
+cobra_capture example-status-verbose-value.out status --verbose=5 api
+# calc takes numbers: a negative one is a flag to pflag, unless it follows
+# -- or is a flag's value.
+cobra_capture example-calc.out calc 5 3
+cobra_capture example-calc-negative-terminator.out calc -- -5 3
+cobra_capture example-calc-negative-after-terminator.out calc -o mul 2 -- -3
+cobra_capture example-calc-negative-flag-value.out calc --scale -2 5 3
+cobra_capture example-calc-negative-flag-value-equals.out calc --scale=-2 5 3
+EXAMPLE_VARIANT=hooks cobra_capture example-hooks-build.out build
+EXAMPLE_VARIANT=hooks cobra_capture example-hooks-cluster-node-list.out cluster node list
+EXAMPLE_VARIANT=traverse-hooks cobra_capture example-traverse-hooks-cluster-node-list.out cluster node list
+EXAMPLE_VARIANT=hooks cobra_capture example-hooks-help-build.out help build
+EXAMPLE_VARIANT=hooks cobra_capture example-hooks-build-help.out build --help
+cobra_capture example-search-flags.out search -in -C 2 --max-count 3 TODO src
+cobra_capture example-convert-value-names.out convert --log x.log -i 4 --strict --include a,b in.json
+cobra_capture_all example-build-deprecated.out build --out dist -j 4
+# Flags whose shorthand alone is deprecated: help leaves the shorthand out,
+# using it warns, and the long form is as it was.
+cobra_capture_all example-build-shorthand-deprecated.out build -j 4
+cobra_capture example-build-jobs.out build --jobs 4
+cobra_capture example-clean.help clean --help
+cobra_capture_all example-clean-shorthand-deprecated.out clean -f
+cobra_capture example-clean-force.out clean --force
+EXAMPLE_VARIANT=traverse cobra_capture example-traverse-build.out -C /tmp build --release
+EXAMPLE_VARIANT=traverse cobra_capture example-traverse-interleaved.out -p 9000 -C /tmp run -v --color app
+EXAMPLE_PORT=9000 EXAMPLE_VERBOSE=true cobra_capture example-env-run.out run app
+EXAMPLE_PORT=9000 cobra_capture example-env-overridden.out run -p 9001 app
+EXAMPLE_SERVE_TIMEOUT=5s EXAMPLE_LOG_LEVEL=warn cobra_capture example-env-serve.out serve
+EXAMPLE_PLUGINS=cobra/plugins cobra_capture example-plugin-lint.out lint --fix -v src
+EXAMPLE_PLUGINS=cobra/plugins cobra_capture example-plugin-lint-help.out lint --help
+
+# Completion candidates from the hidden __complete command. Its closing
+# "Completion ended with directive" note goes to stderr, kept beside each
+# as <fixture>.stderr.
+cobra_capture example-status.complete __complete status ""
+cobra_capture example-status-prefix.complete __complete status a
+cobra_capture example-config-get.complete __complete config get ""
+cobra_capture example-cluster-node-pool-delete.complete __complete cluster node pool delete ""
+cobra_capture example-cluster-node-pool-delete-more.complete __complete cluster node pool delete gpu ""
+cobra_capture example-cluster-node-pool-create-zone.complete __complete cluster node pool create workers --zone ""
+
+# Shell completion scripts.
+mkdir -p cobra/completions
+cobra_capture completions/example.bash completion bash
+cobra_capture completions/example.zsh completion zsh
+cobra_capture completions/example.fish completion fish
+cobra_capture completions/example.ps1 completion powershell
+# The legacy bash script, which lists the tree instead of asking for it.
+./cobra/example -gen-bash-v1 cobra/completions
+echo "  cobra/completions/example-v1.bash"
+
+# Documentation trees from cobra/doc, dated via SOURCE_DATE_EPOCH in
+# deterministic mode so they are stable.
+for format in man markdown rest yaml; do
+    rm -rf "cobra/$format"
+    SOURCE_DATE_EPOCH=1704067200 ./cobra/example --deterministic "-gen-$format" "cobra/$format"
+    echo "  cobra/$format/"
+done
+# The same without the "Auto generated by spf13/cobra" tag, in the formats
+# that date it.
+for format in man markdown; do
+    rm -rf "cobra/no-autogen-tag/$format"
+    EXAMPLE_VARIANT=no-autogen-tag SOURCE_DATE_EPOCH=1704067200 \
+        ./cobra/example --deterministic "-gen-$format" "cobra/no-autogen-tag/$format"
+    echo "  cobra/no-autogen-tag/$format/"
+done
+
+# Command and flag Annotations, as JSON next to each command's help.
+rm -f cobra/*.annotations.json
+./cobra/example -gen-annotations cobra
+echo "  cobra/*.annotations.json"
+
+# The command tree itself, as ground truth for the help fixtures.
+./cobra/example -gen-tree cobra
+echo "  cobra/example.tree.json"
+
+# --help for every command in the tree.
+rm -rf cobra/golden
+./cobra/example -gen-golden cobra/golden
+echo "  cobra/golden/"
+
+# The same commands asked for help every way cobra answers: --help, -h and
+# help <path>, in cobra/forms/long, short and command, linked by command in
+# cobra/forms/example.forms.json.
+rm -rf cobra/forms
+./cobra/example -gen-forms cobra/forms
+echo "  cobra/forms/"
+
+# --help for the same commands reached through their aliases and those of
+# their ancestors, each spelling mapped to the command it resolved to in
+# cobra/aliases/example.aliases.json.
+rm -rf cobra/aliases
+mkdir -p cobra/aliases
+./cobra/example -gen-aliases cobra/aliases
+echo "  cobra/aliases/"
+
+# The same commands' help as JSON, printed by --help-json with the help-json
+# variant on: the structure each golden help was printed from.
+rm -rf cobra/help-json
+mkdir -p cobra/help-json
+./cobra/example -gen-help-json cobra/help-json
+echo "  cobra/help-json/"
+
+# What __complete answers for the same commands with the cursor on an
+# empty word, mid-word, after - and --, and after each flag, candidates and
+# directives recorded in cobra/example.complete.json.
+./cobra/example -gen-complete cobra
+echo "  cobra/example.complete.json"
+
+# The same program built against other cobra releases, each pinned by its
+# own go.mod and go.sum in cobra/versions, with --help for every command per
+# release.
+# cobra/golden is the release cobra/go.mod pins.
+for mod in cobra/versions/cobra-*.mod; do
+    version=$(basename "$mod" .mod)
+    version=${version#cobra-}
+    (cd cobra && go build -modfile="versions/cobra-$version.mod" -o "versions/example-$version" 2>/dev/null)
+    rm -rf "cobra/versions/$version"
+    "./cobra/versions/example-$version" -gen-golden "cobra/versions/$version"
+    echo "  cobra/versions/$version/"
+done
+
+# Failing invocations.
+cobra_capture_error example-unknown-command.err bogus
+cobra_capture_error example-unknown-flag.err build --nope
+cobra_capture_error example-unknown-shorthand.err build -x
+cobra_capture_error example-missing-flag-value.err build --target
+cobra_capture_error example-invalid-flag-value.err --port abc
+cobra_capture_error example-missing-args.err cluster node pool create
+cobra_capture_error example-args-none.err clean extra
+cobra_capture_error example-args-exact.err cluster node pool create a b
+cobra_capture_error example-args-minimum.err search
+cobra_capture_error example-args-maximum.err init a b
+cobra_capture_error example-args-range-below.err cluster node pool delete
+cobra_capture_error example-args-range-above.err cluster node pool delete a b c d
+cobra_capture_error example-args-only-valid.err status api bogus
+cobra_capture_error example-status-verbose-attached.err status -v3 api
+cobra_capture_error example-calc-negative.err calc -5 3
+cobra_capture_error example-calc-negative-second.err calc 3 -5
+cobra_capture_error example-calc-negative-float.err calc -1.5 2
+# Runtime errors from RunE, wrapped on the way up: config check prints usage
+# after them as after usage errors, config import only the error, and exits
+# 3 for a setting it does not know.
+cobra_capture example-config-check.out config check cobra/settings/example.toml
+cobra_capture_error example-config-check-missing.err config check cobra/settings/missing.toml
+cobra_capture_error example-config-check-malformed.err config check cobra/settings/malformed.toml
+cobra_capture_error example-config-check-flag.err config check -c cobra/settings/malformed.toml
+cobra_capture example-config-import.out config import cobra/settings/example.toml
+cobra_capture_error example-config-import-missing.err config import cobra/settings/missing.toml
+cobra_capture_error example-config-import-malformed.err config import cobra/settings/malformed.toml
+cobra_capture_all example-config-import-unknown.err config import cobra/settings/unknown-key.toml
+cobra_capture_error example-config-import-no-args.err config import
+# How a flag's value may be given, and how it may be left out: pflag takes
+# it after = or as the next argument, or attached to a shorthand, where a
+# leading = is dropped.
+cobra_capture example-value-long-equals.out build --port=9000
+cobra_capture example-value-long-space.out build --port 9000
+cobra_capture example-value-short-attached.out build -p9000
+cobra_capture example-value-short-equals.out build -p=9000
+cobra_capture example-value-short-space.out build -p 9000
+cobra_capture_error example-value-long-missing.err build --port
+cobra_capture_error example-value-long-empty.err build --port=
+cobra_capture_error example-value-short-missing.err build -p
+cobra_capture_error example-value-short-empty.err build -p=
+cobra_capture_error example-args-custom-empty.err config set
+cobra_capture_error example-args-custom-invalid.err config set name=demo verbose
+cobra_capture_error example-root-flag-before-subcommand.err -C /tmp build
+EXAMPLE_VARIANT=traverse cobra_capture_error example-traverse-root-flag-after-subcommand.err build -C /tmp
+cobra_capture_error example-serve-shadowed-shorthand.err serve -c prod.toml
+cobra_capture_error example-run-flag-without-terminator.err run app --debug
+cobra_capture_error example-subcommand-version.err build --version
+cobra_capture_error example-suggest-typo.err biuld
+cobra_capture_error example-suggest-for.err start
+# Commands named by prefixes, with prefix matching on and off: a prefix
+# only one command's name or alias starts with resolves to it, and one
+# several share is an unknown command suggesting each of them.
+EXAMPLE_VARIANT=prefix-matching cobra_capture example-prefix-build.help bui --help
+EXAMPLE_VARIANT=prefix-matching cobra_capture example-prefix-build.out bui --release
+EXAMPLE_VARIANT=prefix-matching cobra_capture example-prefix-cluster-node-list.out clus no li
+EXAMPLE_VARIANT=prefix-matching cobra_capture_error example-prefix-ambiguous.err c
+EXAMPLE_VARIANT=prefix-matching cobra_capture_error example-prefix-ambiguous-co.err co
+cobra_capture_error example-prefix-off.err bui --help
+cobra_capture_error example-deploy-missing-all.err deploy
+cobra_capture_error example-deploy-missing-image.err deploy --env prod
+cobra_capture_error example-deploy-rollback-missing-env.err deploy rollback
+cobra_capture_error example-login-one-required.err login
+cobra_capture_error example-login-required-together.err login --password hunter2
+cobra_capture_error example-login-mutually-exclusive.err login -u admin --password hunter2 --token abc
+cobra_capture_error example-serve-bad-log-level.err serve --log-level trace
+EXAMPLE_PORT=x cobra_capture_error example-env-bad-port.err run
+EXAMPLE_LOG_LEVEL=trace cobra_capture_error example-env-bad-log-level.err serve
+cobra_capture_error example-cluster-node-list-bad-output.err cluster node list -o xml
+cobra_capture_error example-silence-none.err build --nope
+EXAMPLE_VARIANT=silence-usage cobra_capture_error example-silence-usage.err build --nope
+EXAMPLE_VARIANT=silence-errors cobra_capture_error example-silence-errors.err build --nope
+EXAMPLE_VARIANT=silence-usage,silence-errors cobra_capture_error example-silence-both.err build --nope
+EXAMPLE_VARIANT=flag-error cobra_capture_error example-flag-error-unknown.err build --nope
+EXAMPLE_VARIANT=flag-error cobra_capture_error example-flag-error-shorthand.err build -x
+EXAMPLE_VARIANT=flag-error cobra_capture_error example-flag-error-missing-value.err build --target
+EXAMPLE_VARIANT=flag-error cobra_capture_error example-flag-error-invalid-value.err cluster node list -o xml
+EXAMPLE_VARIANT=flag-error cobra_capture_error example-flag-error-args.err clean extra
+EXAMPLE_VARIANT=help-renamed cobra_capture_error example-help-renamed-help.err help build
+EXAMPLE_VARIANT=help-replaced cobra_capture_error example-help-replaced-unknown.err help bogus
+EXAMPLE_VARIANT=no-help cobra_capture_error example-no-help-help.err help build
+EXAMPLE_VARIANT=no-help cobra_capture_error example-no-help-completion.err completion bash
+EXAMPLE_VARIANT=no-completion cobra_capture_error example-no-completion-completion.err completion bash
+EXAMPLE_VARIANT=usage-template cobra_capture_error example-build-usage-template.err build --nope
+EXAMPLE_VARIANT=usage-func cobra_capture_error example-build-usage-func.err build --nope
+
+# The remaining Go fixtures are each their own module with one program,
+# built as <dir>/example and captured with these.
+
+# go_capture <dir> <fixture> <args...>: save stdout of <dir>/example.
+go_capture() {
+    local dir=$1 out=$2
+    shift 2
+    capture out "$dir" "$out" "./$dir/example" "$@"
+}
+
+# go_capture_all <dir> <fixture> <args...>: save stdout and stderr of an
+# invocation that may fail, as help printed after a usage error often does.
+go_capture_all() {
+    local dir=$1 out=$2
+    shift 2
+    capture all "$dir" "$out" "./$dir/example" "$@"
+}
+
+# go_capture_widths <dir> <name> <args...>: go_capture_all at each of
+# $widths and with COLUMNS unset, as <dir>/widths/<name>-<cols>.help.
+go_capture_widths() {
+    local dir=$1 name=$2 cols
+    shift 2
+    mkdir -p "$dir/widths"
+    for cols in $widths; do
+        COLUMNS=$cols go_capture_all "$dir" "widths/$name-$cols.help" "$@"
+    done
+    go_capture_all "$dir" "widths/$name-unset.help" "$@"
+}
+
+echo "=== Generating urfave/cli v2 fixtures ==="
+(cd urfave-v2 && go build -o example 2>/dev/null)
+go_capture urfave-v2 example.help --help
+invoked_capture urfave-v2 example --help
+go_capture urfave-v2 example-build.help build --help
+go_capture urfave-v2 example-run.help run --help
+go_capture urfave-v2 example-cluster.help cluster --help
+go_capture urfave-v2 example-cluster-delete.help cluster delete --help
+go_capture urfave-v2 example-help-build.help help build
+go_capture urfave-v2 example-version.out --version
+EXAMPLE_JOBS=3 go_capture urfave-v2 example-build-flags.out -p 9000 build -r --feature a --feature b x
+go_capture_all urfave-v2 example-unknown-flag.err build --nope
+go_capture_all urfave-v2 example-unknown-command.err bogus
+go_capture_all urfave-v2 example-required-flag.err cluster delete prod
+# Shorthands take no attached value: -j6 is an unknown flag.
+go_capture urfave-v2 example-value-long-equals.out build --jobs=6
+go_capture urfave-v2 example-value-long-space.out build --jobs 6
+go_capture_all urfave-v2 example-value-short-attached.err build -j6
+go_capture urfave-v2 example-value-short-equals.out build -j=6
+go_capture urfave-v2 example-value-short-space.out build -j 6
+go_capture_all urfave-v2 example-value-long-missing.err build --jobs
+go_capture_all urfave-v2 example-value-long-empty.err build --jobs=
+go_capture_all urfave-v2 example-value-short-missing.err build -j
+go_capture_all urfave-v2 example-value-short-empty.err build -j=
+
+echo "=== Generating urfave/cli v3 fixtures ==="
+(cd urfave-v3 && go build -o example 2>/dev/null)
+go_capture urfave-v3 example.help --help
+invoked_capture urfave-v3 example --help
+go_capture urfave-v3 example-build.help build --help
+go_capture urfave-v3 example-run.help run --help
+go_capture urfave-v3 example-cluster.help cluster --help
+go_capture urfave-v3 example-cluster-delete.help cluster delete --help
+go_capture urfave-v3 example-help-build.help help build
+go_capture urfave-v3 example-version.out --version
+EXAMPLE_JOBS=3 go_capture urfave-v3 example-build-flags.out -p 9000 build -r --feature a --feature b x
+go_capture_all urfave-v3 example-unknown-flag.err build --nope
+go_capture_all urfave-v3 example-unknown-command.err bogus
+go_capture_all urfave-v3 example-required-flag.err cluster delete prod
+# As in v2, shorthands take no attached value.
+go_capture urfave-v3 example-value-long-equals.out build --jobs=6
+go_capture urfave-v3 example-value-long-space.out build --jobs 6
+go_capture_all urfave-v3 example-value-short-attached.err build -j6
+go_capture urfave-v3 example-value-short-equals.out build -j=6
+go_capture urfave-v3 example-value-short-space.out build -j 6
+go_capture_all urfave-v3 example-value-long-missing.err build --jobs
+go_capture_all urfave-v3 example-value-long-empty.err build --jobs=
+go_capture_all urfave-v3 example-value-short-missing.err build -j
+go_capture_all urfave-v3 example-value-short-empty.err build -j=
+
+echo "=== Generating kong fixtures ==="
+(cd kong && go build -o example 2>/dev/null)
+go_capture kong example.help --help
+invoked_capture kong example --help
+go_capture kong example-build.help build --help
+go_capture kong example-run.help run --help
+go_capture kong example-cluster.help cluster --help
+go_capture kong example-cluster-delete.help cluster delete --help
+for variant in compact tree summary; do
+    EXAMPLE_VARIANT=$variant go_capture kong "example-$variant.help" --help
+done
+EXAMPLE_JOBS=2 go_capture kong example-build-flags.out build -r --profile release a b
+go_capture kong example-run-passthrough.out run -- --x y
+go_capture_all kong example-bad-enum.err build --profile fast
+go_capture_all kong example-missing-flag.err cluster delete prod
+go_capture_all kong example-unknown-command.err bogus
+# kong takes a shorthand's value attached without =, but not after one.
+go_capture kong example-value-long-equals.out build --jobs=6
+go_capture kong example-value-long-space.out build --jobs 6
+go_capture kong example-value-short-attached.out build -j6
+go_capture_all kong example-value-short-equals.err build -j=6
+go_capture kong example-value-short-space.out build -j 6
+go_capture_all kong example-value-long-missing.err build --jobs
+go_capture_all kong example-value-long-empty.err build --jobs=
+go_capture_all kong example-value-short-missing.err build -j
+go_capture_all kong example-value-short-empty.err build -j=
+# kong wraps help to COLUMNS.
+rm -rf kong/widths
+go_capture_widths kong example --help
+go_capture_widths kong example-build build --help
+# Without COLUMNS, kong wraps to the width of the terminal on stdout.
+pty_capture kong widths/example-pty-60.help 60 ./kong/example --help
+
+echo "=== Generating kingpin fixtures ==="
+(cd kingpin && go build -o example 2>/dev/null)
+# kingpin prints help to stderr.
+go_capture_all kingpin example.help --help
+invoked_capture kingpin example --help
+go_capture_all kingpin example-long.help --help-long
+go_capture_all kingpin example.1 --help-man
+go_capture_all kingpin example-build.help build --help
+go_capture_all kingpin example-run.help run --help
+go_capture_all kingpin example-cluster.help cluster --help
+go_capture_all kingpin example-cluster-delete.help cluster delete --help
+go_capture_all kingpin example-help-build.help help build
+go_capture kingpin example-build-flags.out -vvv build -r --tag a --tag b x y
+go_capture kingpin example-run-args.out run app -e A=1 -- -x
+go_capture kingpin example-cluster-default.out cluster
+go_capture_all kingpin example-missing-arg.err run
+go_capture_all kingpin example-bad-enum.err build --mode fast
+go_capture_all kingpin example-unknown-command.err bogus
+# kingpin reads an attached shorthand value as is, = included.
+go_capture kingpin example-value-long-equals.out run app --port=9000
+go_capture kingpin example-value-long-space.out run app --port 9000
+go_capture kingpin example-value-short-attached.out run app -p9000
+go_capture_all kingpin example-value-short-equals.err run app -p=9000
+go_capture kingpin example-value-short-space.out run app -p 9000
+go_capture_all kingpin example-value-long-missing.err run app --port
+go_capture_all kingpin example-value-long-empty.err run app --port=
+go_capture_all kingpin example-value-short-missing.err run app -p
+go_capture_all kingpin example-value-short-empty.err run app -p=
+# kingpin wraps help to COLUMNS.
+rm -rf kingpin/widths
+go_capture_widths kingpin example --help
+go_capture_widths kingpin example-build build --help
+# Without COLUMNS, kingpin wraps to the width of the terminal on stdout.
+pty_capture kingpin widths/example-pty-60.help 60 ./kingpin/example --help
+
+echo "=== Generating flag fixtures ==="
+(cd flag && go build -o example 2>/dev/null)
+# The flag package prints help to stderr.
+go_capture_all flag example.help -h
+invoked_capture flag example -h
+# flag.CommandLine is named after os.Args[0], as the variant names fs.
+EXAMPLE_VARIANT=command-line invoked_capture flag example-command-line -h
+EXAMPLE_VARIANT=custom-usage go_capture_all flag example-custom-usage.help -help
+go_capture flag example-flags.out -v -port 9000 --timeout=5s -I a -I b input -not-a-flag
+go_capture_all flag example-unknown-flag.err -nope
+go_capture_all flag example-invalid-value.err -port x
+go_capture_all flag example-invalid-func-value.err -log-level trace
+# flag takes one dash or two alike, and has no shorthands.
+go_capture flag example-value-equals.out -port=9000
+go_capture flag example-value-space.out -port 9000
+go_capture flag example-value-long-equals.out --port=9000
+go_capture flag example-value-long-space.out --port 9000
+go_capture_all flag example-value-missing.err -port
+go_capture_all flag example-value-empty.err -port=
+
+echo "=== Generating go-flags fixtures ==="
+(cd go-flags && go build -o example 2>/dev/null)
+go_capture go-flags example.help --help
+invoked_capture go-flags example --help
+go_capture go-flags example-build.help build --help
+go_capture go-flags example-clean.help clean --help
+go_capture go-flags example-build-flags.out -vv build -r --tag a --tag b main extra
+go_capture go-flags example-build-ini.out -c go-flags/example.ini build -t out main
+go_capture_all go-flags example-missing-arg.err build
+go_capture_all go-flags example-bad-choice.err --output.format xml build main
+go_capture_all go-flags example-unknown-command.err bogus
+# go-flags, like pflag, takes every form.
+go_capture go-flags example-value-long-equals.out build main --port=9000
+go_capture go-flags example-value-long-space.out build main --port 9000
+go_capture go-flags example-value-short-attached.out build main -p9000
+go_capture go-flags example-value-short-equals.out build main -p=9000
+go_capture go-flags example-value-short-space.out build main -p 9000
+go_capture_all go-flags example-value-long-missing.err build main --port
+go_capture_all go-flags example-value-long-empty.err build main --port=
+go_capture_all go-flags example-value-short-missing.err build main -p
+go_capture_all go-flags example-value-short-empty.err build main -p=
+
+echo "=== Generating mitchellh/cli fixtures ==="
+(cd mitchellh-cli && go build -o example 2>/dev/null)
+go_capture mitchellh-cli example.help --help
+invoked_capture mitchellh-cli example --help
+go_capture mitchellh-cli example-build.help build -help
+go_capture mitchellh-cli example-server-start.help server start -help
+go_capture_all mitchellh-cli example-server.help server
+go_capture_all mitchellh-cli example-state.help state
+go_capture mitchellh-cli example-version.out --version
+go_capture mitchellh-cli example-build-flags.out build -release -parallelism=4 app
+go_capture_all mitchellh-cli example-no-command.err
+go_capture_all mitchellh-cli example-unknown-command.err bogus
+go_capture_all mitchellh-cli example-unknown-flag.err build -nope
+
+echo "=== Generating ffcli fixtures ==="
+(cd ffcli && go build -o example 2>/dev/null)
+# ffcli prints help to stderr, through the flag package.
+go_capture_all ffcli example.help -h
+invoked_capture ffcli example -h
+go_capture_all ffcli example-build.help build -h
+go_capture_all ffcli example-server.help server -h
+go_capture_all ffcli example-server-start.help server start -h
+go_capture_all ffcli example-no-command.err
+EXAMPLE_JOBS=2 go_capture ffcli example-build-env.out build -release app
+go_capture ffcli example-build-config.out build -config ffcli/example.conf -target out app
+go_capture_all ffcli example-unknown-flag.err build -nope
+
+echo "=== Generating docopt fixtures ==="
+(cd docopt && go build -o example 2>/dev/null)
+go_capture docopt example.help --help
+invoked_capture docopt example --help
+go_capture docopt example-version.out --version
+go_capture docopt example-build.out build --release --target dist app lib
+go_capture docopt example-run.out run -vvv prog -- -x --y
+go_capture docopt example-cluster-ls.out cluster ls -o json
+go_capture docopt example-cluster-delete.out cluster delete prod -f --reason=retired
+go_capture docopt example-config-set.out config set key a b
+go_capture_all docopt example-no-command.err
+go_capture_all docopt example-exclusive-flags.err build --release --debug
+go_capture_all docopt example-missing-option.err cluster delete prod
+
+echo "=== Generating multicall fixtures ==="
+(cd multicall && go build -o example 2>/dev/null)
+# Each applet is reached through a committed example-<applet> symlink, and
+# prints its help to stderr.
+go_capture multicall example.help
+go_capture multicall example-list.out --list
+for applet in build clean run status; do
+    capture all multicall "example-$applet.help" "./multicall/example-$applet" -h
+done
+go_capture_all multicall example-help-build.help build -h
+capture out multicall example-build-flags.out ./multicall/example-build -r -t dist app
+capture all multicall example-bogus.err ./multicall/example-bogus
+
+echo "=== Generating external fixtures ==="
+(cd external && go build -o example 2>/dev/null)
+# Unknown commands run example-<command> from PATH, here external/bin.
+go_capture external example.help --help
+go_capture external example-help-build.help help build
+PATH="$PWD/external/bin:$PATH" go_capture external example-help-all.help help -a
+PATH="$PWD/external/bin:$PATH" go_capture external example-help-sync.help help sync
+PATH="$PWD/external/bin:$PATH" go_capture external example-sync.help sync --help
+PATH="$PWD/external/bin:$PATH" go_capture external example-lint.out lint -x src
+go_capture_all external example-sync-not-found.err sync
+
+echo "=== Generating mixed fixtures ==="
+(cd mixed && go build -o example 2>/dev/null)
+# The tools below example tool are flag programs, printing flag's help, to
+# stderr; cobra's help of the commands running them is had from help.
+go_capture mixed example.help --help
+go_capture mixed example-build.help build --help
+go_capture mixed example-tool.help tool --help
+go_capture_all mixed example-tool-vet.help tool vet --help
+go_capture_all mixed example-tool-fmt.help tool fmt --help
+go_capture mixed example-help-tool-vet.help help tool vet
+go_capture mixed example-tool-vet.out tool vet -c 3 ./...
+go_capture_all mixed example-tool-vet-unknown-flag.err tool vet --bogus
+
+echo "=== Generating kubectl fixtures ==="
+(cd kubectl && go build -o example 2>/dev/null)
+go_capture kubectl example.help --help
+for cmd in get logs apply rollout exec run expose taint options; do
+    go_capture kubectl "example-$cmd.help" $cmd --help
+done
+go_capture kubectl example-rollout-status.help rollout status --help
+go_capture kubectl example-options.out options
+go_capture kubectl example-get-flags.out get pods -o wide -l app=web -A
+go_capture_all kubectl example-unknown-flag.err get --nope
+
+echo "=== Generating docker fixtures ==="
+(cd docker && go build -o example 2>/dev/null)
+go_capture docker example.help --help
+for cmd in run ps container image builder; do
+    go_capture docker "example-$cmd.help" $cmd --help
+done
+go_capture docker example-container-run.help container run --help
+go_capture docker example-run-flags.out run -d -p 80:80 -e A=1 --rm img ls -l
+go_capture_all docker example-unknown-flag.err run --nope
+go_capture_all docker example-unknown-command.err bogus
+
+echo "=== Generating gh fixtures ==="
+(cd gh && go build -o example 2>/dev/null)
+go_capture gh example.help --help
+for cmd in pr issue auth alias; do
+    go_capture gh "example-$cmd.help" $cmd --help
+done
+go_capture gh example-pr-list.help pr list --help
+go_capture gh example-pr-checkout.help pr checkout --help
+go_capture gh example-pr-list-flags.out pr list -R cli/cli -L 5 --label bug
+go_capture gh example-alias-co.out co 321
+go_capture_all gh example-unknown-flag.err pr list --nope
+go_capture_all gh example-unknown-command.err nope
+
+echo "=== Generating cobra-flat fixtures ==="
+(cd cobra-flat && go build -o example 2>/dev/null)
+go_capture cobra-flat example.help --help
+go_capture cobra-flat example-h.help -h
+go_capture cobra-flat example-version.out --version
+go_capture cobra-flat example-flags.out -w -i 2 -e 'vendor/*' -e 'gen/*' --color a.go b.go
+# Without subcommands cobra adds no help or completion command, so these
+# are files to format.
+go_capture cobra-flat example-help-arg.out help
+go_capture cobra-flat example-completion-arg.out completion bash
+go_capture_all cobra-flat example-unknown-flag.err --nope
+go_capture_all cobra-flat example-deprecated-flag.out --fast a.go
+
+echo "=== Generating workspace fixtures ==="
+# Two binaries built from one module, sharing the commands and global flags
+# of workspace/lib, each with a tree of its own as <binary>.tree.json.
+(cd workspace && go build -o example ./cmd/example && go build -o examplectl ./cmd/examplectl) 2>/dev/null
+for bin in example examplectl; do
+    ./workspace/$bin -gen-tree workspace
+    echo "  workspace/$bin.tree.json"
+    capture out workspace "$bin.help" "./workspace/$bin" --help
+    for cmd in status config "config get" "config set" version; do
+        capture out workspace "$bin-${cmd// /-}.help" "./workspace/$bin" $cmd --help
+    done
+done
+for cmd in build run; do
+    capture out workspace "example-$cmd.help" ./workspace/example $cmd --help
+done
+for cmd in server "server start" "server stop"; do
+    capture out workspace "examplectl-${cmd// /-}.help" ./workspace/examplectl $cmd --help
+done
+
+echo "=== Generating cobra-platform fixtures ==="
+# platform_env pins, for each GOOS, the variables its home, config, cache
+# and temporary directories come from, so help is the same for every user
+# and machine capturing it.
+declare -A platform_env=(
+    [linux]="HOME=/home/example TMPDIR= XDG_CONFIG_HOME= XDG_CACHE_HOME="
+    [darwin]="HOME=/Users/example TMPDIR=/var/folders/ex/T/"
+    [windows]='USERPROFILE=C:\Users\example APPDATA=C:\Users\example\AppData\Roaming LOCALAPPDATA=C:\Users\example\AppData\Local TMP=C:\Users\example\AppData\Local\Temp'
+)
+
+# platform_capture <goos> <fixture> <args...>: save stdout of the host's
+# build, run with platform_env[<goos>] as the <goos> build, as
+# cobra-platform/<goos>/<fixture>.
+platform_capture() {
+    local goos=$1 out=$2 kv
+    shift 2
+    for kv in ${platform_env[$goos]}; do
+        local -x "$kv"
+    done
+    capture out cobra-platform "$goos/$out" ./cobra-platform/example -as "$goos" "$@"
+}
+
+# Every GOOS is built, so each stays compiling, but only the host's build
+# runs: -as has it compute the defaults each GOOS's build would, so every
+# platform is captured on any host. Its help as itself, from the os
+# package, must match its help -as the host, which keeps -as true to it.
+host=$(go env GOHOSTOS)
+for goos in linux darwin windows; do
+    (cd cobra-platform && GOOS=$goos go build -o /dev/null)
+done
+(cd cobra-platform && go build -o example)
+for goos in linux darwin windows; do
+    rm -rf "cobra-platform/$goos"
+    mkdir -p "cobra-platform/$goos"
+    (
+        for kv in ${platform_env[$goos]}; do
+            export "$kv"
+        done
+        ./cobra-platform/example -as "$goos" -gen-defaults "cobra-platform/$goos"
+        if [ "$goos" = "$host" ] && ! diff -u <(./cobra-platform/example -as "$goos" --help) <(./cobra-platform/example --help) >&2; then
+            echo "cobra-platform: help -as $goos differs from the $goos build's own" >&2
+            exit 1
+        fi
+    )
+    echo "  cobra-platform/$goos/example.defaults.json"
+    platform_capture "$goos" example.help --help
+    platform_capture "$goos" example-flags.out --tmp-dir build --workspace src app
+done
+
+echo "=== Generating spec-built fixtures ==="
+(cd spec && go build -o example 2>/dev/null)
+for spec in spec/specs/*; do
+    name=$(basename "${spec%.*}")
+    go_capture spec "example-$name.help" "$spec" --help
+done
+go_capture spec example-basic-build.help spec/specs/basic.yaml build --help
+go_capture spec example-basic-cache-prune.help spec/specs/basic.yaml cache prune --help
+go_capture spec example-grouped-deploy.help spec/specs/grouped.json deploy --help
+go_capture spec example-templated-fetch.help spec/specs/templated.yaml fetch --help
+
+# Random command trees: the tree cobra builds, as ground truth, plus every
+# command's help.
+rm -rf spec/random
+for seed in $(seq 1 20); do
+    ./spec/example -random "$seed" "spec/random/$seed"
+done
+echo "  spec/random/*/"
+
+# Corrupted copies of fixtures from the sections above, each beside the
+# diagnostics a parser reading it should report. The expected_warnings.json
+# files, written by hand, are kept.
+echo "=== Generating malformed fixtures ==="
+# fixturegen: needs all
+clear_generated malformed
+(cd fixturegen && go run . malform cobra/example.help cobra/example-build.help \
+    clap/example.help commander/example.help kong/example.help \
+    urfave-v2/example.help gh/example.help flag/example.help kubectl/example-get.help)
+
+# Re-laid-out copies of fixtures from the sections above, each beside
+# whether a parser should still read the same command from it, and keeping
+# the expected_warnings.json files as for the malformed ones.
+echo "=== Generating mutated fixtures ==="
+# fixturegen: needs all
+clear_generated mutated
+(cd fixturegen && go run . mutate cobra/example.help cobra/example-build.help \
+    cobra/example-cluster.help clap/example.help kong/example.help \
+    urfave-v2/example.help gh/example.help kubectl/example-get.help)
+
+# Runs last, to pack and describe every fixture regenerated above.
+echo "=== Generating corpus fixtures ==="
+# fixturegen: needs all
+(cd corpus && go generate)
+echo "  manifest.json"
+echo "  corpus/corpus.tar"
 
 echo ""
 echo "Done! All fixtures regenerated."
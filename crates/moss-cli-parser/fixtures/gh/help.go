@@ -0,0 +1,186 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// helpEntry is one section of gh-style help: an uppercase title over a body
+// indented by two spaces, or an untitled body printed as is.
+type helpEntry struct {
+	Title string
+	Body  string
+}
+
+// rootHelpFunc renders help the way gh does, bypassing cobra's templates.
+func rootHelpFunc(command *cobra.Command, args []string) {
+	var entries []helpEntry
+	long := command.Long
+	if long == "" {
+		long = command.Short
+	}
+	if long != "" {
+		entries = append(entries, helpEntry{"", long})
+	}
+	entries = append(entries, helpEntry{"USAGE", command.UseLine()})
+	if len(command.Aliases) > 0 {
+		parent := command.Parent().CommandPath()
+		var aliases []string
+		for _, alias := range command.Aliases {
+			aliases = append(aliases, parent+" "+alias)
+		}
+		entries = append(entries, helpEntry{"ALIASES", strings.Join(aliases, "\n")})
+	}
+	for _, g := range command.Groups() {
+		if body := commandList(command, g.ID); body != "" {
+			entries = append(entries, helpEntry{strings.ToUpper(g.Title), body})
+		}
+	}
+	if body := commandList(command, ""); body != "" {
+		title := "AVAILABLE COMMANDS"
+		if len(command.Groups()) > 0 {
+			title = "ADDITIONAL COMMANDS"
+		}
+		entries = append(entries, helpEntry{title, body})
+	}
+	if usages := command.LocalFlags().FlagUsages(); usages != "" {
+		entries = append(entries, helpEntry{"FLAGS", dedent(usages)})
+	}
+	if usages := command.InheritedFlags().FlagUsages(); usages != "" {
+		entries = append(entries, helpEntry{"INHERITED FLAGS", dedent(usages)})
+	}
+	if args, ok := command.Annotations["help:arguments"]; ok {
+		entries = append(entries, helpEntry{"ARGUMENTS", args})
+	}
+	if command.Example != "" {
+		entries = append(entries, helpEntry{"EXAMPLES", command.Example})
+	}
+	if env, ok := command.Annotations["help:environment"]; ok {
+		entries = append(entries, helpEntry{"ENVIRONMENT VARIABLES", env})
+	}
+	entries = append(entries, helpEntry{"LEARN MORE", heredoc(`
+		Use ` + "`example <command> <subcommand> --help`" + ` for more information about a command.
+		Read the manual at https://example.com/manual`)})
+
+	out := command.OutOrStdout()
+	for _, e := range entries {
+		if e.Title != "" {
+			fmt.Fprintln(out, e.Title)
+			fmt.Fprintln(out, indent(strings.Trim(e.Body, "\r\n"), "  "))
+		} else {
+			fmt.Fprintln(out, e.Body)
+		}
+		fmt.Fprintln(out)
+	}
+}
+
+// rootUsageFunc prints the short usage gh shows after a flag error.
+func rootUsageFunc(w io.Writer, command *cobra.Command) error {
+	fmt.Fprintf(w, "Usage:  %s", command.UseLine())
+	var subcommands []string
+	for _, c := range command.Commands() {
+		if c.IsAvailableCommand() {
+			subcommands = append(subcommands, c.Name())
+		}
+	}
+	if len(subcommands) > 0 {
+		fmt.Fprint(w, "\n\nAvailable commands:\n")
+		for _, name := range subcommands {
+			fmt.Fprintf(w, "  %s\n", name)
+		}
+		return nil
+	}
+	if usages := command.LocalFlags().FlagUsages(); usages != "" {
+		fmt.Fprintln(w, "\n\nFlags:")
+		fmt.Fprint(w, indent(dedent(usages), "  "))
+	}
+	return nil
+}
+
+// commandList lists the available subcommands of c in group id as
+// "name:" padded to a fixed column, followed by the short description.
+func commandList(c *cobra.Command, id string) string {
+	var lines []string
+	for _, sub := range c.Commands() {
+		if sub.IsAvailableCommand() && sub.GroupID == id {
+			lines = append(lines, fmt.Sprintf("%-13s%s", sub.Name()+":", sub.Short))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dedent removes the leading spaces common to every line of s, so flag
+// tables without shorthands are not indented needlessly.
+func dedent(s string) string {
+	lines := strings.Split(s, "\n")
+	min := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, " "))
+		if min < 0 || n < min {
+			min = n
+		}
+	}
+	var buf bytes.Buffer
+	for _, line := range lines {
+		if len(line) >= min && min > 0 {
+			line = line[min:]
+		}
+		fmt.Fprintln(&buf, line)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// heredoc removes the tab indentation common to the lines of s, along with
+// its leading newline.
+func heredoc(s string) string {
+	lines := strings.Split(strings.TrimPrefix(s, "\n"), "\n")
+	min := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, "\t"))
+		if min < 0 || n < min {
+			min = n
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= min {
+			lines[i] = line[min:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indent prefixes every non-empty line of s.
+func indent(s, prefix string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// flagErrorFunc marks flag errors so main prints usage after them.
+func flagErrorFunc(cmd *cobra.Command, err error) error {
+	if err == pflag.ErrHelp {
+		return err
+	}
+	return &flagError{err}
+}
+
+type flagError struct{ err error }
+
+func (e *flagError) Error() string { return e.err.Error() }
+
+func (e *flagError) Unwrap() error { return e.err }
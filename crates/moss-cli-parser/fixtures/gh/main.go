@@ -0,0 +1,236 @@
+// Example CLI replicating GitHub CLI's help conventions, for testing help
+// output parsing: uppercase section headers, "name:" command lists split into
+// core, additional, alias and extension commands, and flag value names such
+// as [HOST/]OWNER/REPO.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "example <command> <subcommand> [flags]",
+	Short: "Example CLI",
+	Long:  "Work seamlessly with Example from the command line.",
+	Example: heredoc(`
+		$ example issue create
+		$ example repo clone example/cli
+		$ example pr checkout 321`),
+	Annotations: map[string]string{
+		"help:environment": "See 'example help environment' for the list of supported environment variables.",
+	},
+	SilenceErrors: true,
+	SilenceUsage:  true,
+	Version:       "1.0.0",
+}
+
+// aliases stands in for the aliases section of the user's config file.
+var aliases = []struct{ name, expansion string }{
+	{"co", "pr checkout"},
+	{"mine", "issue list --assignee @me"},
+}
+
+// extensions stands in for installed example-<name> extensions.
+var extensions = []string{"dash", "notify"}
+
+// echo prints the flags set and the arguments given.
+func echo(cmd *cobra.Command, args []string) {
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		fmt.Printf("%s=%s\n", f.Name, f.Value)
+	})
+	fmt.Println(cmd.CommandPath(), args)
+}
+
+// simple returns a command that only echoes its arguments.
+func simple(use, short string) *cobra.Command {
+	return &cobra.Command{Use: use, Short: short, Run: echo}
+}
+
+// parent returns a command that only groups subcommands.
+func parent(use, short, group string, subs ...*cobra.Command) *cobra.Command {
+	cmd := &cobra.Command{Use: use, Short: short, GroupID: group}
+	cmd.AddCommand(subs...)
+	return cmd
+}
+
+// addRepoFlag adds gh's persistent -R flag, whose value name comes from the
+// backticks in its usage.
+func addRepoFlag(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringP("repo", "R", "", "Select another repository using the `[HOST/]OWNER/REPO` format")
+}
+
+func newPrCommand() *cobra.Command {
+	list := &cobra.Command{
+		Use:     "list",
+		Short:   "List pull requests in a repository",
+		Long:    "List pull requests in a repository.\n\nFor more information about output formatting flags, see `example help formatting`.",
+		Aliases: []string{"ls"},
+		Example: heredoc(`
+			# List PRs authored by you
+			$ example pr list --author "@me"
+
+			# List only PRs with all of the given labels
+			$ example pr list --label bug --label "priority 1"`),
+		Args: cobra.NoArgs,
+		Run:  echo,
+	}
+	f := list.Flags()
+	f.String("app", "", "Filter by example App author")
+	f.StringP("assignee", "a", "", "Filter by assignee")
+	f.StringP("author", "A", "", "Filter by author")
+	f.StringP("base", "B", "", "Filter by base branch")
+	f.BoolP("draft", "d", false, "Filter by draft state")
+	f.StringSliceP("label", "l", nil, "Filter by label")
+	f.IntP("limit", "L", 30, "Maximum number of items to fetch")
+	f.StringP("state", "s", "open", "Filter by state: {open|closed|merged|all}")
+	f.StringSlice("json", nil, "Output JSON with the specified `fields`")
+	f.StringP("jq", "q", "", "Filter JSON output using a jq `expression`")
+	f.BoolP("web", "w", false, "List pull requests in the web browser")
+
+	checkout := &cobra.Command{
+		Use:   "checkout {<number> | <url> | <branch>}",
+		Short: "Check out a pull request in git",
+		Args:  cobra.ExactArgs(1),
+		Run:   echo,
+	}
+	checkout.Flags().StringP("branch", "b", "", "Local branch name to use (default [the name of the head branch])")
+	checkout.Flags().Bool("detach", false, "Checkout PR with a detached HEAD")
+	checkout.Flags().BoolP("force", "f", false, "Reset the existing local branch to the latest state of the pull request")
+
+	pr := parent("pr <command>", "Manage pull requests", "core",
+		list, checkout,
+		simple("create", "Create a pull request"),
+		simple("view [<number> | <url> | <branch>]", "View a pull request"),
+	)
+	pr.Long = "Work with Example pull requests."
+	pr.Annotations = map[string]string{
+		"help:arguments": heredoc(`
+			A pull request can be supplied as argument in any of the following formats:
+			- by number, e.g. "123";
+			- by URL, e.g. "https://example.com/OWNER/REPO/pull/123"; or
+			- by the name of its head branch, e.g. "patch-1" or "OWNER:patch-1".`),
+	}
+	addRepoFlag(pr)
+	return pr
+}
+
+func newIssueCommand() *cobra.Command {
+	issue := parent("issue <command>", "Manage issues", "core")
+	issue.Long = "Work with Example issues."
+	issue.AddGroup(
+		&cobra.Group{ID: "general", Title: "General commands"},
+		&cobra.Group{ID: "targeted", Title: "Targeted commands"},
+	)
+	for _, c := range []struct{ use, short, group string }{
+		{"create", "Create a new issue", "general"},
+		{"list", "List issues in a repository", "general"},
+		{"status", "Show status of relevant issues", "general"},
+		{"close {<number> | <url>}", "Close issue", "targeted"},
+		{"comment {<number> | <url>}", "Add a comment to an issue", "targeted"},
+		{"view {<number> | <url>}", "View an issue", "targeted"},
+	} {
+		sub := simple(c.use, c.short)
+		sub.GroupID = c.group
+		issue.AddCommand(sub)
+	}
+	addRepoFlag(issue)
+	return issue
+}
+
+func init() {
+	browseCmd := simple("browse [<number> | <path> | <commit-SHA>]", "Open repositories, issues, pull requests, and more in the browser")
+	browseCmd.GroupID = "core"
+
+	rootCmd.PersistentFlags().Bool("help", false, "Show help for command")
+	rootCmd.Flags().Bool("version", false, "Show example version")
+	rootCmd.SetHelpFunc(rootHelpFunc)
+	rootCmd.SetUsageFunc(func(c *cobra.Command) error {
+		return rootUsageFunc(c.OutOrStderr(), c)
+	})
+	rootCmd.SetFlagErrorFunc(flagErrorFunc)
+	rootCmd.SetVersionTemplate("example version {{.Version}}\n")
+	rootCmd.SetHelpCommand(&cobra.Command{Hidden: true})
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
+
+	rootCmd.AddGroup(
+		&cobra.Group{ID: "core", Title: "Core commands"},
+		&cobra.Group{ID: "actions", Title: "Example Actions commands"},
+		&cobra.Group{ID: "alias", Title: "Alias commands"},
+		&cobra.Group{ID: "extension", Title: "Extension commands"},
+	)
+	rootCmd.AddCommand(
+		parent("auth <command>", "Authenticate example and git with Example", "core",
+			simple("login", "Log in to an Example account"),
+			simple("logout", "Log out of an Example account"),
+			simple("status", "Display active account and authentication state"),
+		),
+		browseCmd,
+		newIssueCommand(),
+		newPrCommand(),
+		parent("repo <command>", "Manage repositories", "core",
+			simple("clone <repository> [<directory>] [-- <gitflags>...]", "Clone a repository locally"),
+			simple("view [<repository>]", "View a repository"),
+		),
+		parent("run <command>", "View details about workflow runs", "actions",
+			simple("list", "List recent workflow runs"),
+			simple("view [<run-id>]", "View a summary of a workflow run"),
+		),
+		parent("workflow <command>", "View details about workflows", "actions",
+			simple("list", "List workflows"),
+			simple("run [<workflow-id> | <workflow-name>]", "Run a workflow by creating a workflow_dispatch event"),
+		),
+		parent("alias <command>", "Create command shortcuts", "",
+			simple("list", "List your aliases"),
+			simple("set <alias> <expansion>", "Create a shortcut for an example command"),
+		),
+		simple("api <endpoint>", "Make an authenticated Example API request"),
+		parent("extension", "Manage example extensions", "",
+			simple("install <repository>", "Install an example extension from a repository"),
+			simple("list", "List installed extension commands"),
+		),
+	)
+
+	for _, a := range aliases {
+		expansion := a.expansion
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                a.name,
+			Short:              fmt.Sprintf("Alias for %q", expansion),
+			GroupID:            "alias",
+			DisableFlagParsing: true,
+			Run: func(cmd *cobra.Command, args []string) {
+				fmt.Println("example", expansion, strings.Join(args, " "))
+			},
+		})
+	}
+	for _, name := range extensions {
+		rootCmd.AddCommand(&cobra.Command{
+			Use:                name,
+			Short:              "Extension " + name,
+			GroupID:            "extension",
+			DisableFlagParsing: true,
+			Run: func(cmd *cobra.Command, args []string) {
+				fmt.Println("example-"+cmd.Name(), args)
+			},
+		})
+	}
+}
+
+func main() {
+	cmd, err := rootCmd.ExecuteC()
+	if err == nil {
+		return
+	}
+	fmt.Fprintln(os.Stderr, err)
+	var flagErr *flagError
+	if errors.As(err, &flagErr) || strings.HasPrefix(err.Error(), "unknown command ") {
+		fmt.Fprintln(os.Stderr)
+		fmt.Fprintln(os.Stderr, cmd.UsageString())
+	}
+	os.Exit(1)
+}
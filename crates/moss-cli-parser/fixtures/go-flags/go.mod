@@ -0,0 +1,7 @@
+module example
+
+go 1.21
+
+require github.com/jessevdk/go-flags v1.6.1
+
+require golang.org/x/sys v0.21.0 // indirect
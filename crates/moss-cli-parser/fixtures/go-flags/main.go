@@ -0,0 +1,98 @@
+// Example jessevdk/go-flags CLI for testing help output parsing.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jessevdk/go-flags"
+)
+
+// Options are declared in structs; nested structs with a group tag become
+// their own section of help.
+type options struct {
+	Verbose []bool `short:"v" long:"verbose" description:"Enable verbose output (repeat for more)"`
+	Config  string `short:"c" long:"config" description:"Read defaults from an ini file" value-name:"FILE" env:"EXAMPLE_CONFIG"`
+
+	Server struct {
+		Port    int    `short:"p" long:"port" description:"Port number" default:"8080" env:"EXAMPLE_PORT"`
+		Host    string `long:"host" description:"Host to bind" default:"localhost"`
+		Timeout string `long:"timeout" description:"Request timeout" default:"30s" default-mask:"thirty seconds"`
+	} `group:"Server Options"`
+
+	Output struct {
+		Format string `short:"o" long:"format" description:"Output format" choice:"json" choice:"yaml" choice:"table" default:"table"`
+		Color  string `long:"color" description:"When to color output" optional:"yes" optional-value:"always" default:"auto"`
+		Quiet  bool   `short:"q" long:"quiet" description:"Suppress non-error output"`
+	} `group:"Output Options" namespace:"output"`
+
+	Secret string `long:"secret" description:"API secret" hidden:"yes"`
+}
+
+var opts options
+
+var parser = flags.NewNamedParser("example", flags.Default)
+
+type buildCommand struct {
+	Release bool     `short:"r" long:"release" description:"Build in release mode"`
+	Target  string   `short:"t" long:"target" description:"Target directory" default:"target" value-name:"DIR"`
+	Tags    []string `long:"tag" description:"Build tag to enable (repeatable)" value-name:"TAG"`
+
+	Args struct {
+		Package  string   `positional-arg-name:"package" description:"Package to build" required:"yes"`
+		Packages []string `positional-arg-name:"more" description:"More packages to build"`
+	} `positional-args:"yes"`
+}
+
+func (c *buildCommand) Execute(args []string) error {
+	fmt.Printf("Building %s %v (release=%v target=%s tags=%v port=%d format=%s verbose=%d)\n",
+		c.Args.Package, c.Args.Packages, c.Release, c.Target, c.Tags, opts.Server.Port, opts.Output.Format, len(opts.Verbose))
+	return nil
+}
+
+type cleanCommand struct {
+	All bool `short:"a" long:"all" description:"Also remove downloaded dependencies"`
+}
+
+func (c *cleanCommand) Execute(args []string) error {
+	fmt.Printf("Cleaning (all=%v)\n", c.All)
+	return nil
+}
+
+func main() {
+	parser.ShortDescription = "An example CLI tool for testing"
+	parser.LongDescription = "Example builds and cleans projects. Defaults can be read from an ini\nfile given with --config, and are overridden by the command line."
+	parser.AddGroup("Application Options", "", &opts)
+	parser.AddCommand("build", "Build the project", "Build compiles the named packages and writes the artifacts to the target directory.", &buildCommand{})
+	clean, _ := parser.AddCommand("clean", "Clean build artifacts", "", &cleanCommand{})
+	clean.Aliases = []string{"rm"}
+
+	// Parse once for --config, then load the ini file and parse again so the
+	// command line wins over it.
+	ini := flags.NewIniParser(parser)
+	if config := configPath(); config != "" {
+		if err := ini.ParseFile(config); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	if _, err := parser.Parse(); err != nil {
+		var ferr *flags.Error
+		if errors.As(err, &ferr) && ferr.Type == flags.ErrHelp {
+			os.Exit(0)
+		}
+		os.Exit(1)
+	}
+}
+
+// configPath returns the --config given on the command line or in the
+// environment, without otherwise acting on the arguments.
+func configPath() string {
+	var pre struct {
+		Config string `short:"c" long:"config" env:"EXAMPLE_CONFIG"`
+	}
+	p := flags.NewParser(&pre, flags.IgnoreUnknown)
+	p.Parse()
+	return pre.Config
+}
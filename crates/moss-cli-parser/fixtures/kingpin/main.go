@@ -0,0 +1,61 @@
+// Example kingpin CLI for testing help output parsing.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/alecthomas/kingpin/v2"
+)
+
+var (
+	app = kingpin.New("example", "An example CLI tool for testing.")
+
+	verbose = app.Flag("verbose", "Enable verbose output. Repeat for more detail.").Short('v').Counter()
+	config  = app.Flag("config", "Config file path.").Short('c').Envar("EXAMPLE_CONFIG").PlaceHolder("FILE").String()
+	port    = app.Flag("port", "Port number.").Short('p').Default("8080").Envar("EXAMPLE_PORT").Int()
+	trace   = app.Flag("trace", "Trace internal calls.").Hidden().Bool()
+
+	build        = app.Command("build", "Build the project.").Alias("b")
+	buildRelease = build.Flag("release", "Build in release mode.").Short('r').Bool()
+	buildTarget  = build.Flag("target", "Target directory.").Short('t').Default("target").String()
+	buildTags    = build.Flag("tag", "Build tag to enable (repeatable).").PlaceHolder("TAG").Strings()
+	buildMode    = build.Flag("mode", "Build mode.").Default("debug").Enum("debug", "release", "bench")
+	buildPkgs    = build.Arg("packages", "Packages to build.").Strings()
+
+	run     = app.Command("run", "Run the project.")
+	runEnv  = run.Flag("env", "Environment variable for the program, as KEY=VALUE.").Short('e').StringMap()
+	runProg = run.Arg("program", "Program to run.").Required().String()
+	runArgs = run.Arg("args", "Arguments for the program.").Strings()
+
+	clean    = app.Command("clean", "Clean build artifacts.")
+	cleanAll = clean.Flag("all", "Also remove downloaded dependencies.").Bool()
+
+	cluster        = app.Command("cluster", "Manage clusters.")
+	clusterContext = cluster.Flag("context", "Cluster context to use.").Envar("EXAMPLE_CONTEXT").String()
+	clusterList    = cluster.Command("list", "List clusters.").Default()
+	clusterDelete  = cluster.Command("delete", "Delete a cluster.")
+	deleteName     = clusterDelete.Arg("name", "Cluster to delete.").Required().String()
+	deleteReason   = clusterDelete.Flag("reason", "Why the cluster is being deleted.").Required().String()
+
+	debug = app.Command("debug", "Dump internal state.").Hidden()
+)
+
+func main() {
+	app.Version("1.0.0")
+	app.HelpFlag.Short('h')
+	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	case build.FullCommand():
+		fmt.Printf("Building %v (release=%v target=%s tags=%v mode=%s verbose=%d)\n", *buildPkgs, *buildRelease, *buildTarget, *buildTags, *buildMode, *verbose)
+	case run.FullCommand():
+		fmt.Printf("Running %s %v (env=%v port=%d)\n", *runProg, *runArgs, *runEnv, *port)
+	case clean.FullCommand():
+		fmt.Printf("Cleaning (all=%v)\n", *cleanAll)
+	case clusterList.FullCommand():
+		fmt.Printf("Listing clusters in %q\n", *clusterContext)
+	case clusterDelete.FullCommand():
+		fmt.Printf("Deleting %s: %s\n", *deleteName, *deleteReason)
+	case debug.FullCommand():
+		fmt.Printf("Debugging (config=%q trace=%v)\n", *config, *trace)
+	}
+}
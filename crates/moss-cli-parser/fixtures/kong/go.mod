@@ -0,0 +1,5 @@
+module example
+
+go 1.21
+
+require github.com/alecthomas/kong v1.16.1
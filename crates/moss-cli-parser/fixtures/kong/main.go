@@ -0,0 +1,138 @@
+// Example kong CLI for testing help output parsing.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/kong"
+)
+
+// The whole command line is one struct; kong reads commands, flags, help
+// text, groups, enums and environment defaults from its tags.
+var cli struct {
+	Verbose bool   `short:"v" help:"Enable verbose output." env:"EXAMPLE_VERBOSE"`
+	Config  string `short:"c" help:"Config file path." type:"path" placeholder:"FILE" env:"EXAMPLE_CONFIG"`
+	Port    int    `short:"p" help:"Port number." default:"8080" env:"EXAMPLE_PORT"`
+	Trace   bool   `help:"Trace internal calls." hidden:""`
+
+	Version kong.VersionFlag `help:"Print version information and quit."`
+
+	Build   buildCmd   `cmd:"" aliases:"b" help:"Build the project." group:"build"`
+	Run     runCmd     `cmd:"" help:"Run the project." group:"build"`
+	Clean   cleanCmd   `cmd:"" help:"Clean build artifacts." group:"build"`
+	Cluster clusterCmd `cmd:"" help:"Manage clusters." group:"manage"`
+	Status  statusCmd  `cmd:"" help:"Show the status of project components."`
+	Debug   debugCmd   `cmd:"" help:"Dump internal state." hidden:""`
+}
+
+type buildCmd struct {
+	Release bool     `short:"r" help:"Build in release mode."`
+	Target  string   `short:"t" help:"Target directory." type:"path" default:"target"`
+	Jobs    int      `short:"j" help:"Number of parallel jobs." default:"4" env:"EXAMPLE_JOBS"`
+	Profile string   `help:"Build profile, one of ${enum}." enum:"debug,release,bench" default:"debug"`
+	Feature []string `help:"Enable a feature (repeatable)." placeholder:"NAME"`
+
+	Cache  string `help:"Where to keep the build cache (${enum})." enum:"local,remote,none" default:"local" group:"Cache"`
+	Remote string `help:"URL of the remote cache." placeholder:"URL" group:"Cache"`
+
+	Packages []string `arg:"" optional:"" help:"Packages to build."`
+}
+
+func (c *buildCmd) Run() error {
+	fmt.Printf("Building %v (release=%v jobs=%d profile=%s cache=%s)\n", c.Packages, c.Release, c.Jobs, c.Profile, c.Cache)
+	return nil
+}
+
+type runCmd struct {
+	Color   string        `help:"When to color output: ${enum} (default: ${default})." enum:"always,never,auto" default:"auto"`
+	Timeout time.Duration `help:"Stop the program after this long."`
+	Args    []string      `arg:"" optional:"" passthrough:"" help:"Arguments passed to the program."`
+}
+
+func (c *runCmd) Run() error {
+	fmt.Printf("Running %v (color=%s)\n", c.Args, c.Color)
+	return nil
+}
+
+type cleanCmd struct {
+	All bool `short:"a" help:"Also remove downloaded dependencies."`
+}
+
+func (c *cleanCmd) Run() error {
+	fmt.Println("Cleaning...")
+	return nil
+}
+
+type clusterCmd struct {
+	Context string `help:"Cluster context to use." env:"EXAMPLE_CONTEXT"`
+
+	List   clusterListCmd   `cmd:"" aliases:"ls" help:"List clusters."`
+	Delete clusterDeleteCmd `cmd:"" help:"Delete a cluster."`
+}
+
+type clusterListCmd struct {
+	Output string `short:"o" help:"Output format (${enum})." enum:"json,yaml,table" default:"table"`
+}
+
+func (c *clusterListCmd) Run(parent *clusterCmd) error {
+	fmt.Printf("Listing clusters in %q as %s\n", parent.Context, c.Output)
+	return nil
+}
+
+type clusterDeleteCmd struct {
+	Force  bool   `help:"Do not ask for confirmation."`
+	Reason string `help:"Why the cluster is being deleted." required:""`
+	Name   string `arg:"" help:"Cluster to delete."`
+}
+
+func (c *clusterDeleteCmd) Run() error {
+	fmt.Printf("Deleting %s: %s\n", c.Name, c.Reason)
+	return nil
+}
+
+type statusCmd struct {
+	Components []string `arg:"" optional:"" enum:"api,cache,db,worker" help:"Components to show."`
+}
+
+func (c *statusCmd) Run() error {
+	fmt.Println("Status of", strings.Join(c.Components, ", "))
+	return nil
+}
+
+type debugCmd struct{}
+
+func (c *debugCmd) Run() error {
+	fmt.Println("Debugging...")
+	return nil
+}
+
+// helpOptions are picked by EXAMPLE_VARIANT, to capture kong's other layouts.
+var helpOptions = map[string]kong.HelpOptions{
+	"":        {},
+	"compact": {Compact: true},
+	"tree":    {Tree: true, Indenter: kong.TreeIndenter},
+	"summary": {Summary: true, FlagsLast: true},
+}
+
+func main() {
+	options, ok := helpOptions[os.Getenv("EXAMPLE_VARIANT")]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown EXAMPLE_VARIANT %q\n", os.Getenv("EXAMPLE_VARIANT"))
+		os.Exit(2)
+	}
+	ctx := kong.Parse(&cli,
+		kong.Name("example"),
+		kong.Description("An example CLI tool for testing."),
+		kong.Vars{"version": "1.0.0"},
+		kong.ExplicitGroups([]kong.Group{
+			{Key: "build", Title: "Build commands"},
+			{Key: "manage", Title: "Management commands", Description: "Commands that act on remote resources."},
+		}),
+		kong.ConfigureHelp(options),
+		kong.UsageOnError(),
+	)
+	ctx.FatalIfErrorf(ctx.Run())
+}
@@ -0,0 +1,164 @@
+package main
+
+import "github.com/spf13/cobra"
+
+// The commands below carry the full weight of kubectl's help: long
+// descriptions, many examples, and flags of every kind.
+
+var getCmd = &cobra.Command{
+	Use:                   "get [(-o|--output=)json|yaml|name|go-template|go-template-file|template|templatefile|jsonpath|jsonpath-as-json|jsonpath-file|custom-columns|custom-columns-file|wide] (TYPE[.VERSION][.GROUP] [NAME | -l label] | TYPE[.VERSION][.GROUP]/NAME ...) [flags]",
+	DisableFlagsInUseLine: true,
+	Short:                 "Display one or many resources",
+	Long: longDesc(`
+		Display one or many resources.
+
+		Prints a table of the most important information about the specified resources.
+		You can filter the list using a label selector and the --selector flag. If the
+		desired resource type is namespaced you will only see results in the current
+		namespace unless you pass --all-namespaces.
+
+		By specifying the output as 'template' and providing a Go template as the value
+		of the --template flag, you can filter the attributes of the fetched resources.
+
+		Use "example api-resources" for a complete list of supported resources.`),
+	Example: examples(`
+		# List all pods in ps output format
+		example get pods
+
+		# List all pods in ps output format with more information (such as node name)
+		example get pods -o wide
+
+		# List a single replication controller with specified NAME in ps output format
+		example get replicationcontroller web
+
+		# List deployments in JSON output format, in the "v1" version of the "apps" API group
+		example get deployments.v1.apps -o json
+
+		# List a pod identified by type and name specified in "pod.yaml" in JSON output format
+		example get -f pod.yaml -o json
+
+		# Return only the phase value of the specified pod
+		example get -o template pod/web-pod-13je7 --template={{.status.phase}}
+
+		# List all replication controllers and services together in ps output format
+		example get rc,services`),
+	GroupID: "intermediate",
+	Run:     echo,
+}
+
+var rolloutCmd = &cobra.Command{
+	Use:                   "rollout SUBCOMMAND",
+	DisableFlagsInUseLine: true,
+	Short:                 "Manage the rollout of a resource",
+	Long: longDesc(`
+		Manage the rollout of one or many resources.
+
+		Valid resource types include:
+
+		  *  deployments
+		  *  daemonsets
+		  *  statefulsets`),
+	Example: examples(`
+		# Rollback to the previous deployment
+		example rollout undo deployment/abc
+
+		# Check the rollout status of a daemonset
+		example rollout status daemonset/foo`),
+	GroupID: "deploy",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var logsCmd = &cobra.Command{
+	Use:                   "logs [-f] [-p] (POD | TYPE/NAME) [-c CONTAINER]",
+	DisableFlagsInUseLine: true,
+	Short:                 "Print the logs for a container in a pod",
+	Long: longDesc(`
+		Print the logs for a container in a pod or specified resource.
+		If the pod has only one container, the container name is optional.`),
+	Example: examples(`
+		# Return snapshot logs from pod nginx with only one container
+		example logs nginx
+
+		# Begin streaming the logs of the ruby container in pod web-1
+		example logs -f -c ruby web-1
+
+		# Display only the most recent 20 lines of output in pod nginx
+		example logs --tail=20 nginx`),
+	GroupID: "troubleshooting",
+	Run:     echo,
+}
+
+var applyCmd = &cobra.Command{
+	Use:                   "apply (-f FILENAME | -k DIRECTORY)",
+	DisableFlagsInUseLine: true,
+	Short:                 "Apply a configuration to a resource by file name or stdin",
+	Long: longDesc(`
+		Apply a configuration to a resource by file name or stdin.
+		The resource name must be specified. This resource will be created if it doesn't exist yet.
+
+		JSON and YAML formats are accepted.`),
+	Example: examples(`
+		# Apply the configuration in pod.json to a pod
+		example apply -f ./pod.json
+
+		# Apply resources from a directory containing kustomization.yaml
+		example apply -k dir/`),
+	GroupID: "advanced",
+	Run:     echo,
+}
+
+func init() {
+	f := getCmd.Flags()
+	f.BoolP("all-namespaces", "A", false, "If present, list the requested object(s) across all namespaces. Namespace in current context is ignored even if specified with --namespace.")
+	f.Int64("chunk-size", 500, "Return large lists in chunks rather than all at once. Pass 0 to disable. This flag is beta and may change in the future.")
+	f.String("field-selector", "", "Selector (field query) to filter on, supports '=', '==', and '!='.(e.g. --field-selector key1=value1,key2=value2). The server only supports a limited number of field queries per type.")
+	f.StringSliceP("filename", "f", nil, "Filename, directory, or URL to files identifying the resource to get from a server.")
+	f.Bool("ignore-not-found", false, "If the requested object does not exist the command will return exit code 0.")
+	f.StringP("kustomize", "k", "", "Process the kustomization directory. This flag can't be used together with -f or -R.")
+	f.StringSliceP("label-columns", "L", nil, "Accepts a comma separated list of labels that are going to be presented as columns. Names are case-sensitive. You can also use multiple flag options like -L label1 -L label2...")
+	f.Bool("no-headers", false, "When using the default or custom-column output format, don't print headers (default print headers).")
+	f.StringP("output", "o", "", "Output format. One of: (json, yaml, name, go-template, go-template-file, template, templatefile, jsonpath, jsonpath-as-json, jsonpath-file, custom-columns, custom-columns-file, wide). See custom columns [https://example.com/docs/reference/example/#custom-columns], golang template [http://golang.org/pkg/text/template/#pkg-overview] and jsonpath template [https://example.com/docs/reference/example/jsonpath/].")
+	f.BoolP("recursive", "R", false, "Process the directory used in -f, --filename recursively. Useful when you want to manage related manifests organized within the same directory.")
+	f.StringP("selector", "l", "", "Selector (label query) to filter on, supports '=', '==', '!=', 'in', 'notin'.(e.g. -l key1=value1,key2=value2,key3 in (value3)). Matching objects must satisfy all of the specified label constraints.")
+	f.Bool("show-kind", false, "If present, list the resource type for the requested object(s).")
+	f.Bool("show-labels", false, "When printing, show all labels as the last column (default hide labels column)")
+	f.String("sort-by", "", "If non-empty, sort list types using this field specification.  The field specification is expressed as a JSONPath expression (e.g. '{.metadata.name}').")
+	f.BoolP("watch", "w", false, "After listing/getting the requested object, watch for changes.")
+	f.Bool("watch-only", false, "Watch for changes to the requested object(s), without listing/getting first.")
+
+	f = logsCmd.Flags()
+	f.Bool("all-containers", false, "Get all containers' logs in the pod(s).")
+	f.StringP("container", "c", "", "Print the logs of this container")
+	f.BoolP("follow", "f", false, "Specify if the logs should be streamed.")
+	f.BoolP("previous", "p", false, "If true, print the logs for the previous instance of the container in a pod if it exists.")
+	f.String("since", "0s", "Only return logs newer than a relative duration like 5s, 2m, or 3h. Defaults to all logs. Only one of since-time / since may be used.")
+	f.Int64("tail", -1, "Lines of recent log file to display. Defaults to -1 with no selector, showing all log lines otherwise 10, if a selector is provided.")
+	f.Bool("timestamps", false, "Include timestamps on each line in the log output")
+
+	f = applyCmd.Flags()
+	f.Bool("all", false, "Select all resources in the namespace of the specified resource types.")
+	f.String("dry-run", "none", `Must be "none", "server", or "client". If client strategy, only print the object that would be sent, without sending it. If server strategy, submit server-side request without persisting the resource.`)
+	f.StringSliceP("filename", "f", nil, "The files that contain the configurations to apply.")
+	f.Bool("force", false, "If true, immediately remove resources from API and bypass graceful deletion. Note that immediate deletion of some resources may result in inconsistency or data loss and requires confirmation.")
+	f.StringP("kustomize", "k", "", "Process a kustomization directory. This flag can't be used together with -f or -R.")
+	f.Bool("prune", false, "Automatically delete resource objects, that do not appear in the configs and are created by either apply or create --save-config. Should be used with either -l or --all.")
+	f.Bool("server-side", false, "If true, apply runs in the server instead of the client.")
+	f.Duration("timeout", 0, "The length of time to wait before giving up on a delete, zero means determine a timeout from the size of the object")
+	f.Bool("validate", true, "Use a schema to validate the input before sending it")
+	f.MarkDeprecated("validate", "use --validate=strict instead")
+
+	for _, sub := range []struct{ use, short string }{
+		{"history (TYPE NAME | TYPE/NAME) [flags]", "View rollout history"},
+		{"pause RESOURCE", "Mark the provided resource as paused"},
+		{"restart RESOURCE", "Restart a resource"},
+		{"resume RESOURCE", "Resume a paused resource"},
+		{"status (TYPE NAME | TYPE/NAME) [flags]", "Show the status of the rollout"},
+		{"undo (TYPE NAME | TYPE/NAME) [flags]", "Undo a previous rollout"},
+	} {
+		cmd := simple(sub.use, sub.short, "")
+		cmd.Flags().Int64("revision", 0, "See the details, including podTemplate of the revision specified")
+		rolloutCmd.AddCommand(cmd)
+	}
+}
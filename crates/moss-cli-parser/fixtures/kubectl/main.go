@@ -0,0 +1,171 @@
+// Example CLI replicating kubectl's help conventions, for testing help output
+// parsing: titled command groups, long indented descriptions and examples,
+// options rendered one block per flag, and positional grammar for resource
+// types in usage lines.
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var rootCmd = &cobra.Command{
+	Use:   "example",
+	Short: "example controls the example cluster manager",
+	Long: longDesc(`
+		example controls the example cluster manager.
+
+		Find more information at: https://example.com/docs/reference/example/`),
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+// longDesc dedents s and indents each line by one space, as kubectl's
+// templates.LongDesc renders Long text.
+func longDesc(s string) string {
+	lines := strings.Split(strings.Trim(dedent(s), "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = " " + line
+		}
+	}
+	return strings.TrimPrefix(strings.Join(lines, "\n"), " ")
+}
+
+// examples dedents s and indents each line by two spaces, as kubectl's
+// templates.Examples does.
+func examples(s string) string {
+	lines := strings.Split(strings.Trim(dedent(s), "\n"), "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = "  " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// dedent removes the tab indentation common to the lines of s.
+func dedent(s string) string {
+	lines := strings.Split(s, "\n")
+	indent := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		n := len(line) - len(strings.TrimLeft(line, "\t"))
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// simple returns a command that only echoes its arguments.
+func simple(use, short, group string) *cobra.Command {
+	return &cobra.Command{
+		Use:                   use,
+		Short:                 short,
+		GroupID:               group,
+		DisableFlagsInUseLine: true,
+		Run:                   echo,
+	}
+}
+
+// echo prints the flags that were set and the arguments.
+func echo(cmd *cobra.Command, args []string) {
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		fmt.Printf("%s=%s\n", f.Name, f.Value)
+	})
+	fmt.Println(cmd.CommandPath(), args)
+}
+
+var optionsCmd = &cobra.Command{
+	Use:   "options",
+	Short: "Print the list of flags inherited by all commands",
+	Long:  "Print the list of flags inherited by all commands",
+	Example: examples(`
+		# Print flags inherited by all commands
+		example options`),
+	DisableFlagsInUseLine: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("The following options can be passed to any command:\n\n%s", flagsUsages(cmd.Root().PersistentFlags()))
+	},
+}
+
+func init() {
+	f := rootCmd.PersistentFlags()
+	f.String("as", "", "Username to impersonate for the operation. User could be a regular user or a service account in a namespace.")
+	f.String("cache-dir", "$HOME/.example/cache", "Default cache directory")
+	f.String("cluster", "", "The name of the example cluster to use")
+	f.String("context", "", "The name of the config context to use")
+	f.Bool("insecure-skip-tls-verify", false, "If true, the server's certificate will not be checked for validity. This will make your HTTPS connections insecure")
+	f.String("kubeconfig", "", "Path to the config file to use for CLI requests.")
+	f.Bool("match-server-version", false, "Require server version to match client version")
+	f.StringP("namespace", "n", "", "If present, the namespace scope for this CLI request")
+	f.String("request-timeout", "0", "The length of time to wait before giving up on a single server request. Non-zero values should contain a corresponding time unit (e.g. 1s, 2m, 3h). A value of zero means don't timeout requests.")
+	f.StringP("server", "s", "", "The address and port of the API server")
+	f.String("token", "", "Bearer token for authentication to the API server")
+	f.String("user", "", "The name of the config user to use")
+	f.IntP("v", "v", 0, "number for the log level verbosity")
+	f.Bool("warnings-as-errors", false, "Treat warnings received from the server as errors and exit with a non-zero exit code")
+
+	rootCmd.AddGroup(
+		&cobra.Group{ID: "basic", Title: "Basic Commands (Beginner):"},
+		&cobra.Group{ID: "intermediate", Title: "Basic Commands (Intermediate):"},
+		&cobra.Group{ID: "deploy", Title: "Deploy Commands:"},
+		&cobra.Group{ID: "cluster", Title: "Cluster Management Commands:"},
+		&cobra.Group{ID: "troubleshooting", Title: "Troubleshooting and Debugging Commands:"},
+		&cobra.Group{ID: "advanced", Title: "Advanced Commands:"},
+		&cobra.Group{ID: "settings", Title: "Settings Commands:"},
+	)
+	rootCmd.SetHelpCommandGroupID("")
+	rootCmd.SetCompletionCommandGroupID("settings")
+	rootCmd.AddCommand(
+		simple("create -f FILENAME", "Create a resource from a file or from stdin", "basic"),
+		simple("expose (-f FILENAME | TYPE NAME) [--port=port] [--protocol=TCP|UDP|SCTP] [--target-port=number-or-name] [--name=name] [--external-ip=external-ip-of-service] [--type=type]", "Take a replication controller, service, deployment or pod and expose it as a new example service", "basic"),
+		simple("run NAME --image=image [--env=\"key=value\"] [--port=port] [--dry-run=server|client] [--overrides=inline-json] [--command] -- [COMMAND] [args...]", "Run a particular image on the cluster", "basic"),
+		simple("set SUBCOMMAND", "Set specific features on objects", "basic"),
+		simple("explain TYPE [--recursive=FALSE|TRUE] [--api-version=api-version-group] [-o|--output=plaintext|plaintext-openapiv2]", "Get documentation for a resource", "intermediate"),
+		getCmd,
+		simple("edit (RESOURCE/NAME | -f FILENAME)", "Edit a resource on the server", "intermediate"),
+		simple("delete ([-f FILENAME] | [-k DIRECTORY] | TYPE [(NAME | -l label | --all)])", "Delete resources by file names, stdin, resources and names, or by resources and label selector", "intermediate"),
+		rolloutCmd,
+		simple("scale [--resource-version=version] [--current-replicas=count] --replicas=COUNT (-f FILENAME | TYPE NAME)", "Set a new size for a deployment, replica set, or replication controller", "deploy"),
+		simple("autoscale (-f FILENAME | TYPE NAME | TYPE/NAME) [--min=MINPODS] --max=MAXPODS [--cpu-percent=CPU]", "Auto-scale a deployment, replica set, stateful set, or replication controller", "deploy"),
+		simple("cordon NODE", "Mark node as unschedulable", "cluster"),
+		simple("uncordon NODE", "Mark node as schedulable", "cluster"),
+		simple("drain NODE", "Drain node in preparation for maintenance", "cluster"),
+		simple("taint NODE NAME KEY_1=VAL_1:TAINT_EFFECT_1 ... KEY_N=VAL_N:TAINT_EFFECT_N", "Update the taints on one or more nodes", "cluster"),
+		simple("describe (-f FILENAME | TYPE [NAME_PREFIX | -l label] | TYPE/NAME)", "Show details of a specific resource or group of resources", "troubleshooting"),
+		logsCmd,
+		simple("exec (POD | TYPE/NAME) [-c CONTAINER] [flags] -- COMMAND [args...]", "Execute a command in a container", "troubleshooting"),
+		simple("port-forward TYPE/NAME [options] [LOCAL_PORT:]REMOTE_PORT [...[LOCAL_PORT_N:]REMOTE_PORT_N]", "Forward one or more local ports to a pod", "troubleshooting"),
+		simple("diff -f FILENAME", "Diff the live version against a would-be applied version", "advanced"),
+		applyCmd,
+		simple("patch (-f FILENAME | TYPE NAME) [-p PATCH|--patch-file FILE]", "Update fields of a resource", "advanced"),
+		simple("label [--overwrite] (-f FILENAME | TYPE NAME) KEY_1=VAL_1 ... KEY_N=VAL_N [--resource-version=version]", "Update the labels on a resource", "settings"),
+		simple("annotate [--overwrite] (-f FILENAME | TYPE NAME) KEY_1=VAL_1 ... KEY_N=VAL_N [--resource-version=version]", "Update the annotations on a resource", "settings"),
+		simple("api-resources", "Print the supported API resources on the server", ""),
+		simple("version", "Print the client and server version information", ""),
+		optionsCmd,
+	)
+	rootCmd.SetUsageTemplate(usageTemplate)
+}
+
+func main() {
+	// Commands are listed in the order they were added, not by name.
+	cobra.EnableCommandSorting = false
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
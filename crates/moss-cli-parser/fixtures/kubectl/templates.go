@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// kubectl replaces cobra's usage template with its own: examples first, then
+// grouped commands, options as one block per flag, the usage line last, and
+// global flags moved out to the options command.
+const usageTemplate = `{{if .HasExample}}Examples:
+{{.Example}}
+
+{{end}}{{if .HasAvailableSubCommands}}{{cmdGroupsString .}}
+
+{{end}}{{with flagsUsages .LocalNonPersistentFlags}}Options:
+{{.}}{{end}}Usage:
+  {{useLine .}}
+
+{{if .HasAvailableSubCommands}}Use "{{.Root.Name}} <command> --help" for more information about a given command.
+{{end}}Use "{{.Root.Name}} options" for a list of global command-line options (applies to all commands).
+`
+
+// wrapLimit is the width kubectl wraps flag usage to when not on a terminal.
+const wrapLimit = 80
+
+func init() {
+	cobra.AddTemplateFunc("cmdGroupsString", cmdGroupsString)
+	cobra.AddTemplateFunc("flagsUsages", flagsUsages)
+	cobra.AddTemplateFunc("useLine", useLine)
+}
+
+// cmdGroupsString lists subcommands under their group titles, padding names
+// to the longest in the command rather than per group. Ungrouped commands
+// come last, as "Other Commands:" if there were groups.
+func cmdGroupsString(c *cobra.Command) string {
+	width := 0
+	for _, sub := range c.Commands() {
+		if sub.IsAvailableCommand() && len(sub.Name()) > width {
+			width = len(sub.Name())
+		}
+	}
+	var groups []string
+	list := func(title, id string) {
+		var lines []string
+		for _, sub := range c.Commands() {
+			if sub.IsAvailableCommand() && sub.GroupID == id {
+				lines = append(lines, fmt.Sprintf("  %-*s %s", width+3, sub.Name(), sub.Short))
+			}
+		}
+		if len(lines) > 0 {
+			groups = append(groups, title+"\n"+strings.Join(lines, "\n"))
+		}
+	}
+	for _, g := range c.Groups() {
+		list(g.Title, g.ID)
+	}
+	if len(groups) > 0 {
+		list("Other Commands:", "")
+	} else {
+		list("Available Commands:", "")
+	}
+	return strings.Join(groups, "\n\n")
+}
+
+// flagsUsages renders each flag as "--name=default:" followed by its usage
+// on tab-indented lines, with a blank line after each. --help is left out.
+func flagsUsages(fs *pflag.FlagSet) string {
+	var buf bytes.Buffer
+	fs.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden || f.Name == "help" {
+			return
+		}
+		def := f.DefValue
+		if f.Value.Type() == "string" {
+			def = "'" + def + "'"
+		}
+		head := fmt.Sprintf("    --%s=%s:", f.Name, def)
+		if f.Shorthand != "" {
+			head = fmt.Sprintf("    -%s, --%s=%s:", f.Shorthand, f.Name, def)
+		}
+		usage := f.Usage
+		if f.Deprecated != "" {
+			usage += fmt.Sprintf(" (DEPRECATED: %s)", f.Deprecated)
+		}
+		buf.WriteString(head + "\n\t" + strings.ReplaceAll(wrap(usage, wrapLimit-10), "\n", "\n\t") + "\n\n")
+	})
+	return buf.String()
+}
+
+// useLine is cobra's use line with [options] appended when global flags
+// apply.
+func useLine(c *cobra.Command) string {
+	line := c.UseLine()
+	if c.HasAvailableInheritedFlags() || c.HasAvailablePersistentFlags() {
+		line += " [options]"
+	}
+	return line
+}
+
+// wrap greedily wraps s at width, keeping its own line breaks.
+func wrap(s string, width int) string {
+	var out []string
+	for _, para := range strings.Split(s, "\n") {
+		line := ""
+		for _, word := range strings.Fields(para) {
+			if line != "" && len(line)+1+len(word) > width {
+				out = append(out, line)
+				line = word
+			} else if line != "" {
+				line += " " + word
+			} else {
+				line = word
+			}
+		}
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
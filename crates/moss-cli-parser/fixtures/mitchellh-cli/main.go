@@ -0,0 +1,182 @@
+// Example mitchellh/cli CLI for testing help output parsing, in the style of
+// Terraform and Consul.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mitchellh/cli"
+)
+
+// command is a cli.Command whose flags are a stdlib flag set, as in the
+// HashiCorp tools. Its help is written out by hand, which is the norm.
+type command struct {
+	synopsis string
+	help     string
+	flags    func(fs *flag.FlagSet)
+	run      func(fs *flag.FlagSet) int
+}
+
+func (c *command) Synopsis() string { return c.synopsis }
+
+func (c *command) Help() string { return strings.TrimSpace(c.help) }
+
+func (c *command) Run(args []string) int {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	fs.Usage = func() {}
+	if c.flags != nil {
+		c.flags(fs)
+	}
+	if err := fs.Parse(args); err != nil {
+		return cli.RunResultHelp
+	}
+	if c.run == nil {
+		return cli.RunResultHelp
+	}
+	return c.run(fs)
+}
+
+// echo returns a run function printing the flags that were set and the
+// arguments.
+func echo(name string) func(fs *flag.FlagSet) int {
+	return func(fs *flag.FlagSet) int {
+		fs.Visit(func(f *flag.Flag) {
+			fmt.Printf("%s=%s\n", f.Name, f.Value)
+		})
+		fmt.Printf("%s %v\n", name, fs.Args())
+		return 0
+	}
+}
+
+var commands = map[string]cli.CommandFactory{
+	"build": factory(&command{
+		synopsis: "Build the project",
+		help: `
+Usage: example build [options] [PACKAGE...]
+
+  Build compiles the named packages, or every package in the project if
+  none are named, and writes the artifacts to the target directory.
+
+Options:
+
+  -release             Build in release mode.
+
+  -target=<dir>        Directory to write artifacts to. Defaults to
+                       "target".
+
+  -parallelism=<n>     Limit the number of concurrent jobs. Defaults to 10.
+`,
+		flags: func(fs *flag.FlagSet) {
+			fs.Bool("release", false, "")
+			fs.String("target", "target", "")
+			fs.Int("parallelism", 10, "")
+		},
+		run: echo("Building"),
+	}),
+	"server": factory(&command{
+		synopsis: "Manage the development server",
+		help: `
+Usage: example server <subcommand> [options] [args]
+
+  This command groups subcommands for running the development server.
+  Each subcommand documents its own options with -help.
+`,
+	}),
+	"server start": factory(&command{
+		synopsis: "Start the development server",
+		help: `
+Usage: example server start [options]
+
+  Start the development server in the foreground.
+
+Options:
+
+  -port=<port>         Port to listen on. Defaults to 8080, or the value
+                       of EXAMPLE_PORT if set.
+
+  -dev                 Enable development mode: reload on change and
+                       serve unminified assets.
+`,
+		flags: func(fs *flag.FlagSet) {
+			fs.Int("port", 8080, "")
+			fs.Bool("dev", false, "")
+		},
+		run: echo("Starting server"),
+	}),
+	"server stop": factory(&command{
+		synopsis: "Stop a running development server",
+		help: `
+Usage: example server stop [options]
+
+  Stop the development server started with "example server start".
+
+Options:
+
+  -force               Kill the server without waiting for requests to
+                       finish.
+`,
+		flags: func(fs *flag.FlagSet) { fs.Bool("force", false, "") },
+		run:   echo("Stopping server"),
+	}),
+	// There is no "state" command, so the listing shows state with a blank
+	// synopsis and runs nothing.
+	"state list": factory(&command{
+		synopsis: "List resources in the state",
+		help: `
+Usage: example state list [options] [ADDRESS...]
+
+  List resources in the state, optionally only those matching the given
+  addresses.
+`,
+		run: echo("Listing"),
+	}),
+	"validate": factory(&command{
+		synopsis: "Check whether the configuration is valid",
+		help: `
+Usage: example validate [options] [DIR]
+
+  Validate the configuration files in a directory, referring only to the
+  configuration and not accessing any remote services.
+
+Options:
+
+  -json                Produce output in a machine-readable JSON format.
+
+  -no-color            If specified, output won't contain any color.
+`,
+		flags: func(fs *flag.FlagSet) {
+			fs.Bool("json", false, "")
+			fs.Bool("no-color", false, "")
+		},
+		run: echo("Validating"),
+	}),
+	"debug": factory(&command{
+		synopsis: "Dump internal state",
+		help:     "Usage: example debug",
+		run:      echo("Debugging"),
+	}),
+}
+
+func factory(c cli.Command) cli.CommandFactory {
+	return func() (cli.Command, error) { return c, nil }
+}
+
+func main() {
+	c := &cli.CLI{
+		Name:           "example",
+		Version:        "1.0.0",
+		Args:           os.Args[1:],
+		Commands:       commands,
+		HiddenCommands: []string{"debug"},
+		HelpWriter:     os.Stdout,
+		ErrorWriter:    os.Stderr,
+	}
+	status, err := c.Run()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	os.Exit(status)
+}
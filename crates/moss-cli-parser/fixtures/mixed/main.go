@@ -0,0 +1,136 @@
+// Example cobra CLI that runs built-in tools written with the standard
+// flag package, as go runs `go tool vet`: `example tool <name>` passes its
+// arguments, --help included, to the tool, which this binary runs as a
+// process of its own. One binary so prints help in two dialects, cobra's
+// down to `example tool` and flag's below it.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var rootCmd = &cobra.Command{
+	Use:     "example",
+	Short:   "An example CLI with built-in tools",
+	Version: "1.0.0",
+}
+
+var buildCmd = &cobra.Command{
+	Use:   "build [flags] [package...]",
+	Short: "Build packages",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Flags().Visit(func(f *pflag.Flag) {
+			fmt.Printf("%s=%s\n", f.Name, f.Value)
+		})
+		fmt.Println("Building", args)
+	},
+}
+
+var toolCmd = &cobra.Command{
+	Use:   "tool",
+	Short: "Run a built-in tool",
+	Long: `Tool runs one of the tools built into example, passing it the
+remaining arguments. Each tool parses its own flags and prints its own
+help.`,
+}
+
+// toolEnv names the tool a process of this binary runs as.
+const toolEnv = "EXAMPLE_TOOL"
+
+// tools are the built-in tools, each a flag program of its own.
+var tools = map[string]struct {
+	use, short string
+	run        func(args []string)
+}{
+	"vet": {"vet [flags] [package...]", "Report likely mistakes in packages", vet},
+	"fmt": {"fmt [flags] [path...]", "Reformat source files", format},
+}
+
+func init() {
+	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "Enable verbose output")
+	buildCmd.Flags().StringP("output", "o", "", "Write the result to this file")
+	buildCmd.Flags().BoolP("race", "r", false, "Enable data race detection")
+	for name, tool := range tools {
+		name := name
+		toolCmd.AddCommand(&cobra.Command{
+			Use:                tool.use,
+			Short:              tool.short,
+			DisableFlagParsing: true,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return runTool(name, args)
+			},
+		})
+	}
+	rootCmd.AddCommand(buildCmd, toolCmd)
+}
+
+// runTool runs the named tool with args as another process of this binary,
+// with its output and exit status.
+func runTool(name string, args []string) error {
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(self, args...)
+	cmd.Env = append(os.Environ(), toolEnv+"="+name)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	err = cmd.Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		os.Exit(exit.ExitCode())
+	}
+	return err
+}
+
+// vet prints flag's default usage for -h, headed "Usage of vet:".
+func vet(args []string) {
+	fs := flag.NewFlagSet("vet", flag.ExitOnError)
+	all := fs.Bool("all", false, "enable every check, experimental ones included")
+	context := fs.Int("c", -1, "display offending lines with this many lines of context")
+	fs.Bool("json", false, "emit JSON output")
+	fs.String("tags", "", "a comma-separated list of build `tags` to consider satisfied")
+	fs.Parse(args)
+	fmt.Printf("all=%v c=%d\nVetting %v\n", *all, *context, fs.Args())
+}
+
+// format prints a usage line and a description of its own above the flags
+// for -h, as most flag programs do.
+func format(args []string) {
+	fs := flag.NewFlagSet("fmt", flag.ExitOnError)
+	list := fs.Bool("l", false, "list files whose formatting differs")
+	fs.Bool("w", false, "write the result to the source file instead of stdout")
+	fs.String("r", "", "apply the rewrite `rule` to every file\nbefore formatting it")
+	fs.Int("tabwidth", 8, "tab width")
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "usage: example tool fmt [flags] [path...]\n\n")
+		fmt.Fprintf(out, "Fmt reformats the files given, or standard input if there are none.\n\n")
+		fmt.Fprintf(out, "Flags:\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	fmt.Printf("l=%v\nFormatting %v\n", *list, strings.Join(fs.Args(), " "))
+}
+
+func main() {
+	if name := os.Getenv(toolEnv); name != "" {
+		tool, ok := tools[name]
+		if !ok {
+			fmt.Fprintf(os.Stderr, "unknown %s %q\n", toolEnv, name)
+			os.Exit(2)
+		}
+		tool.run(os.Args[1:])
+		return
+	}
+	if err := rootCmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,122 @@
+// Example busybox-style multi-call binary for testing help output parsing.
+// Which applet runs depends on the name it was invoked as: example-build
+// runs build, and so on. Invoked as example, the first argument names the
+// applet instead, and with none it lists them all.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// applet is one of the programs the binary can act as.
+type applet struct {
+	usage string
+	brief string
+	flags func(fs *flag.FlagSet)
+}
+
+var applets = map[string]applet{
+	"build": {
+		usage: "[-r] [-t DIR] [PACKAGE]...",
+		brief: "Build the named packages, or the whole project",
+		flags: func(fs *flag.FlagSet) {
+			fs.Bool("r", false, "Build in release mode")
+			fs.String("t", "target", "Write artifacts to `DIR`")
+		},
+	},
+	"clean": {
+		usage: "[-a]",
+		brief: "Remove build artifacts",
+		flags: func(fs *flag.FlagSet) {
+			fs.Bool("a", false, "Also remove downloaded dependencies")
+		},
+	},
+	"run": {
+		usage: "[-p PORT] PROGRAM [ARG]...",
+		brief: "Run a built program",
+		flags: func(fs *flag.FlagSet) {
+			fs.Int("p", 8080, "Listen on `PORT`")
+		},
+	},
+	"status": {
+		usage: "[COMPONENT]...",
+		brief: "Show the status of project components",
+		flags: func(fs *flag.FlagSet) {},
+	},
+}
+
+func main() {
+	name := filepath.Base(os.Args[0])
+	args := os.Args[1:]
+	if name == "example" {
+		if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+			listApplets()
+			return
+		}
+		if args[0] == "--list" {
+			for _, name := range appletNames() {
+				fmt.Println(name)
+			}
+			return
+		}
+		name, args = "example-"+args[0], args[1:]
+	}
+	a, ok := applets[strings.TrimPrefix(name, "example-")]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "%s: applet not found\n", name)
+		os.Exit(127)
+	}
+	os.Exit(runApplet(name, a, args))
+}
+
+// runApplet parses args for a and prints what it was given.
+func runApplet(name string, a applet, args []string) int {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	a.flags(fs)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s %s\n\n%s\n\n", name, a.usage, a.brief)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err == flag.ErrHelp {
+		return 0
+	} else if err != nil {
+		return 1
+	}
+	fs.Visit(func(f *flag.Flag) {
+		fmt.Printf("%s=%s\n", f.Name, f.Value)
+	})
+	fmt.Println(name, fs.Args())
+	return 0
+}
+
+// listApplets prints the busybox-style banner and applet list.
+func listApplets() {
+	fmt.Print(`example v1.0.0 multi-call binary.
+
+Usage: example [function [arguments]...]
+   or: example --list
+   or: example-function [arguments]...
+
+	example is a multi-call binary that combines several project tools
+	into a single executable. Most people will create a link to example
+	for each function they wish to use and example will act like whatever
+	it was invoked as.
+
+Currently defined functions:
+	`)
+	fmt.Println(strings.Join(appletNames(), ", "))
+}
+
+func appletNames() []string {
+	var names []string
+	for name := range applets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -8,7 +8,8 @@
 #   - Rust/Cargo for clap fixtures
 #   - Python with argparse (stdlib) and click
 #   - Node.js with npm for commander.js and yargs
-#   - Go for cobra
+#   - Go for cobra, urfave/cli (v2 and v3), kong, kingpin, go-flags,
+#     mitchellh/cli, ffcli, docopt and the flag package
 { pkgs ? import <nixpkgs> {} }:
 
 pkgs.mkShell {
@@ -39,7 +40,29 @@ pkgs.mkShell {
     echo "  - Node/commander: (cd commander && npm install && node example.js --help)"
     echo "  - Node/yargs: (cd yargs && npm install && node example.js --help)"
     echo "  - Go/cobra: (cd cobra && go build && ./example --help)"
+    echo "  - Go/urfave-cli v2: (cd urfave-v2 && go build && ./example --help)"
+    echo "  - Go/urfave-cli v3: (cd urfave-v3 && go build && ./example --help)"
+    echo "  - Go/kong: (cd kong && go build && ./example --help)"
+    echo "  - Go/kingpin: (cd kingpin && go build && ./example --help)"
+    echo "  - Go/flag: (cd flag && go build && ./example -h)"
+    echo "  - Go/go-flags: (cd go-flags && go build && ./example --help)"
+    echo "  - Go/mitchellh-cli: (cd mitchellh-cli && go build && ./example --help)"
+    echo "  - Go/ffcli: (cd ffcli && go build && ./example -h)"
+    echo "  - Go/docopt: (cd docopt && go build && ./example --help)"
+    echo "  - Go/multicall: (cd multicall && go build -o example && ./example-build -h)"
+    echo "  - Go/external: (cd external && go build && PATH=bin:\$PATH ./example help -a)"
+    echo "  - Go/kubectl replica: (cd kubectl && go build && ./example --help)"
+    echo "  - Go/docker replica: (cd docker && go build && ./example --help)"
+    echo "  - Go/gh replica: (cd gh && go build && ./example --help)"
+    echo "  - Go/spec-built: (cd spec && go build && ./example specs/basic.yaml --help)"
+    echo "  - Go corpus package: (cd corpus && go generate)  # repack embedded fixtures"
     echo ""
-    echo "Run ./generate.sh to regenerate all fixtures"
+    echo "Run ./generate.sh to regenerate all fixtures, or use fixturegen:"
+    echo "  (cd fixturegen && go run . list)              # list sections"
+    echo "  (cd fixturegen && go run . generate cobra)    # regenerate some"
+    echo "  (cd fixturegen && go run . verify -diff)      # check for stale fixtures"
+    echo "  (cd fixturegen && go run . drift -json)       # classify what changed"
+    echo "  (cd fixturegen && go run . replay -check cobra/example.help)  # recapture one"
+    echo "  (cd fixturegen && go run . malform cobra/example.help)  # corrupt copies"
   '';
 }
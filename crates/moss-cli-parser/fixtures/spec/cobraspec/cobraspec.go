@@ -0,0 +1,244 @@
+// Package cobraspec builds a cobra command tree from a declarative spec, so
+// a new help layout can be captured by writing a YAML or JSON file instead
+// of a fixture program.
+package cobraspec
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Spec describes a whole CLI: its root command plus the settings that apply
+// to the tree as a whole.
+type Spec struct {
+	Command `yaml:",inline"`
+
+	// Version adds cobra's --version flag when set.
+	Version string `yaml:"version,omitempty"`
+	// SortCommands keeps cobra's alphabetical command order; when false,
+	// commands are listed in the order the spec gives them.
+	SortCommands *bool `yaml:"sort_commands,omitempty"`
+	// UsageTemplate and HelpTemplate replace cobra's templates for the tree.
+	UsageTemplate string `yaml:"usage_template,omitempty"`
+	HelpTemplate  string `yaml:"help_template,omitempty"`
+	// NoCompletion drops cobra's completion command.
+	NoCompletion bool `yaml:"no_completion,omitempty"`
+}
+
+// Command describes one command and, recursively, its subcommands.
+type Command struct {
+	Use        string   `yaml:"use,omitempty"`
+	Aliases    []string `yaml:"aliases,omitempty"`
+	Short      string   `yaml:"short,omitempty"`
+	Long       string   `yaml:"long,omitempty"`
+	Example    string   `yaml:"example,omitempty"`
+	Group      string   `yaml:"group,omitempty"`
+	Hidden     bool     `yaml:"hidden,omitempty"`
+	Deprecated string   `yaml:"deprecated,omitempty"`
+	// Args names a positional argument validator: "none", "arbitrary",
+	// "only-valid", "exact N", "min N", "max N" or "range N M".
+	Args      string   `yaml:"args,omitempty"`
+	ValidArgs []string `yaml:"valid_args,omitempty"`
+	// Groups are the command groups this command's subcommands may join.
+	Groups   []Group   `yaml:"groups,omitempty"`
+	Flags    []Flag    `yaml:"flags,omitempty"`
+	Commands []Command `yaml:"commands,omitempty"`
+}
+
+// Group is a titled command group.
+type Group struct {
+	ID    string `yaml:"id,omitempty"`
+	Title string `yaml:"title,omitempty"`
+}
+
+// Flag describes one flag. Type is a pflag type name such as "string",
+// "int", "bool", "duration", "count" or "stringSlice"; Default is parsed as
+// that type.
+type Flag struct {
+	Name       string `yaml:"name,omitempty"`
+	Shorthand  string `yaml:"shorthand,omitempty"`
+	Type       string `yaml:"type,omitempty"`
+	Default    string `yaml:"default,omitempty"`
+	Usage      string `yaml:"usage,omitempty"`
+	Persistent bool   `yaml:"persistent,omitempty"`
+	Required   bool   `yaml:"required,omitempty"`
+	Hidden     bool   `yaml:"hidden,omitempty"`
+	Deprecated string `yaml:"deprecated,omitempty"`
+}
+
+// Load reads a spec from a YAML or JSON file.
+func Load(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec Spec
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &spec, nil
+}
+
+// Build constructs the command tree spec describes. Leaf commands print the
+// flags they were given and their arguments.
+func Build(spec *Spec) (*cobra.Command, error) {
+	cobra.EnableCommandSorting = spec.SortCommands == nil || *spec.SortCommands
+	root, err := build(&spec.Command)
+	if err != nil {
+		return nil, err
+	}
+	root.Version = spec.Version
+	root.CompletionOptions.DisableDefaultCmd = spec.NoCompletion
+	if spec.UsageTemplate != "" {
+		root.SetUsageTemplate(spec.UsageTemplate)
+	}
+	if spec.HelpTemplate != "" {
+		root.SetHelpTemplate(spec.HelpTemplate)
+	}
+	return root, nil
+}
+
+func build(c *Command) (*cobra.Command, error) {
+	if c.Use == "" {
+		return nil, fmt.Errorf("command without use")
+	}
+	cmd := &cobra.Command{
+		Use:        c.Use,
+		Aliases:    c.Aliases,
+		Short:      c.Short,
+		Long:       c.Long,
+		Example:    strings.TrimRight(c.Example, "\n"),
+		GroupID:    c.Group,
+		Hidden:     c.Hidden,
+		Deprecated: c.Deprecated,
+		ValidArgs:  c.ValidArgs,
+	}
+	if c.Args != "" {
+		args, err := parseArgs(c.Args)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", cmd.Name(), err)
+		}
+		cmd.Args = args
+	}
+	for _, g := range c.Groups {
+		cmd.AddGroup(&cobra.Group{ID: g.ID, Title: g.Title})
+	}
+	for i := range c.Flags {
+		if err := addFlag(cmd, &c.Flags[i]); err != nil {
+			return nil, fmt.Errorf("%s: %w", cmd.Name(), err)
+		}
+	}
+	for i := range c.Commands {
+		sub, err := build(&c.Commands[i])
+		if err != nil {
+			return nil, err
+		}
+		cmd.AddCommand(sub)
+	}
+	if len(c.Commands) == 0 {
+		cmd.Run = echo
+	}
+	return cmd, nil
+}
+
+// parseArgs turns an Args spec into a cobra validator.
+func parseArgs(s string) (cobra.PositionalArgs, error) {
+	fields := strings.Fields(s)
+	var n []int
+	for _, field := range fields[1:] {
+		v, err := strconv.Atoi(field)
+		if err != nil {
+			return nil, fmt.Errorf("args %q: %w", s, err)
+		}
+		n = append(n, v)
+	}
+	switch {
+	case fields[0] == "none" && len(n) == 0:
+		return cobra.NoArgs, nil
+	case fields[0] == "arbitrary" && len(n) == 0:
+		return cobra.ArbitraryArgs, nil
+	case fields[0] == "only-valid" && len(n) == 0:
+		return cobra.OnlyValidArgs, nil
+	case fields[0] == "exact" && len(n) == 1:
+		return cobra.ExactArgs(n[0]), nil
+	case fields[0] == "min" && len(n) == 1:
+		return cobra.MinimumNArgs(n[0]), nil
+	case fields[0] == "max" && len(n) == 1:
+		return cobra.MaximumNArgs(n[0]), nil
+	case fields[0] == "range" && len(n) == 2:
+		return cobra.RangeArgs(n[0], n[1]), nil
+	}
+	return nil, fmt.Errorf("unknown args %q", s)
+}
+
+// addFlag defines f on cmd with its type's zero value, then sets the
+// default through the flag's own parser so any type pflag knows works.
+func addFlag(cmd *cobra.Command, f *Flag) error {
+	fs := cmd.Flags()
+	if f.Persistent {
+		fs = cmd.PersistentFlags()
+	}
+	switch f.Type {
+	case "", "string":
+		fs.StringP(f.Name, f.Shorthand, "", f.Usage)
+	case "bool":
+		fs.BoolP(f.Name, f.Shorthand, false, f.Usage)
+	case "int":
+		fs.IntP(f.Name, f.Shorthand, 0, f.Usage)
+	case "int64":
+		fs.Int64P(f.Name, f.Shorthand, 0, f.Usage)
+	case "uint":
+		fs.UintP(f.Name, f.Shorthand, 0, f.Usage)
+	case "float64":
+		fs.Float64P(f.Name, f.Shorthand, 0, f.Usage)
+	case "duration":
+		fs.DurationP(f.Name, f.Shorthand, 0, f.Usage)
+	case "count":
+		fs.CountP(f.Name, f.Shorthand, f.Usage)
+	case "stringSlice":
+		fs.StringSliceP(f.Name, f.Shorthand, nil, f.Usage)
+	case "stringArray":
+		fs.StringArrayP(f.Name, f.Shorthand, nil, f.Usage)
+	case "intSlice":
+		fs.IntSliceP(f.Name, f.Shorthand, nil, f.Usage)
+	case "stringToString":
+		fs.StringToStringP(f.Name, f.Shorthand, nil, f.Usage)
+	case "ip":
+		fs.IPP(f.Name, f.Shorthand, nil, f.Usage)
+	case "bytesHex":
+		fs.BytesHexP(f.Name, f.Shorthand, nil, f.Usage)
+	default:
+		return fmt.Errorf("flag --%s: unknown type %q", f.Name, f.Type)
+	}
+	flag := fs.Lookup(f.Name)
+	if f.Default != "" {
+		if err := flag.Value.Set(f.Default); err != nil {
+			return fmt.Errorf("flag --%s: default: %w", f.Name, err)
+		}
+		flag.DefValue = flag.Value.String()
+	}
+	flag.Hidden = f.Hidden
+	flag.Deprecated = f.Deprecated
+	if f.Required {
+		if f.Persistent {
+			cmd.MarkPersistentFlagRequired(f.Name)
+		} else {
+			cmd.MarkFlagRequired(f.Name)
+		}
+	}
+	return nil
+}
+
+// echo prints the flags set and the arguments given.
+func echo(cmd *cobra.Command, args []string) {
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		fmt.Printf("%s=%s\n", f.Name, f.Value)
+	})
+	fmt.Println(cmd.CommandPath(), args)
+}
@@ -0,0 +1,196 @@
+package cobraspec
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+)
+
+// words are drawn on for random names and descriptions.
+var words = strings.Fields(`
+	account agent archive artifact backup batch binary branch bucket build
+	cache channel check cluster commit config container context daemon
+	deploy digest domain driver entry event export filter format gateway
+	graph group hook image index instance job key label layer ledger
+	limit link log manifest member metric mirror module mount node object
+	operator package patch peer pipeline plugin policy pool port profile
+	project proxy queue quota record region release remote replica report
+	repository resource revision role route rule runner schema secret
+	service session shard snapshot source stack stage state storage stream
+	subnet switch tag target task template tenant token topic trace
+	trigger tunnel upload user value vault version volume webhook worker
+	zone`)
+
+// verbs start random descriptions.
+var verbs = strings.Fields(`Add Apply Check Create Delete Describe Disable
+	Enable Export Fetch Import Inspect List Manage Move Print Prune Pull Push
+	Remove Rename Restore Rotate Set Show Start Stop Sync Update Validate
+	Watch`)
+
+// randomTypes are the flag types Random picks from, with a generator of
+// default values for each.
+var randomTypes = []struct {
+	name string
+	def  func(r *rand.Rand) string
+}{
+	{"string", func(r *rand.Rand) string { return pick(r, words) }},
+	{"bool", func(r *rand.Rand) string { return "" }},
+	{"int", func(r *rand.Rand) string { return fmt.Sprint(r.Intn(1000)) }},
+	{"int64", func(r *rand.Rand) string { return fmt.Sprint(r.Int63n(1 << 40)) }},
+	{"uint", func(r *rand.Rand) string { return fmt.Sprint(r.Intn(64)) }},
+	{"float64", func(r *rand.Rand) string { return fmt.Sprintf("%.2f", r.Float64()*100) }},
+	{"duration", func(r *rand.Rand) string { return fmt.Sprintf("%ds", r.Intn(3600)) }},
+	{"count", func(r *rand.Rand) string { return "" }},
+	{"stringSlice", func(r *rand.Rand) string { return pick(r, words) + "," + pick(r, words) }},
+	{"stringArray", func(r *rand.Rand) string { return pick(r, words) }},
+	{"intSlice", func(r *rand.Rand) string { return fmt.Sprintf("%d,%d", r.Intn(10), r.Intn(10)) }},
+	{"stringToString", func(r *rand.Rand) string { return pick(r, words) + "=" + pick(r, words) }},
+	{"ip", func(r *rand.Rand) string { return fmt.Sprintf("10.0.%d.%d", r.Intn(256), r.Intn(256)) }},
+}
+
+// Random returns a spec for a random command tree determined entirely by
+// seed: names, nesting, flag types and defaults, and description lengths
+// from a word or two up to text long enough to run past any terminal.
+func Random(seed int64) *Spec {
+	r := rand.New(rand.NewSource(seed))
+	g := &randomizer{r: r}
+	spec := &Spec{NoCompletion: r.Intn(2) == 0}
+	spec.Command = g.command("example", 0, reserved())
+	if r.Intn(3) == 0 {
+		spec.Version = fmt.Sprintf("%d.%d.%d", r.Intn(3), r.Intn(20), r.Intn(10))
+	}
+	if r.Intn(3) == 0 {
+		spec.Groups = []Group{
+			{ID: "core", Title: "Core Commands:"},
+			{ID: "extra", Title: "Extra Commands:"},
+		}
+		for i := range spec.Commands {
+			if id := r.Intn(3); id < 2 {
+				spec.Commands[i].Group = spec.Groups[id].ID
+			}
+		}
+	}
+	return spec
+}
+
+type randomizer struct {
+	r *rand.Rand
+}
+
+// names holds the flag names and shorthands taken on the path to a
+// command, which its own flags must not reuse.
+type names map[string]bool
+
+// reserved returns the names cobra itself takes on every command.
+func reserved() names {
+	return names{"help": true, "h": true, "version": true}
+}
+
+func (n names) copy() names {
+	c := names{}
+	for k := range n {
+		c[k] = true
+	}
+	return c
+}
+
+func (g *randomizer) command(name string, depth int, taken names) Command {
+	r := g.r
+	cmd := Command{Short: g.sentence(1, 12)}
+	cmd.Use = name
+	if r.Intn(3) == 0 {
+		cmd.Long = g.paragraph()
+	}
+	if depth > 0 && r.Intn(5) == 0 {
+		cmd.Aliases = []string{name[:1] + name[len(name)-1:]}
+	}
+	taken = taken.copy()
+	for i, n := 0, r.Intn(7); i < n; i++ {
+		cmd.Flags = append(cmd.Flags, g.flag(taken, depth))
+	}
+	if depth < 3 && (depth == 0 || r.Intn(depth+2) == 0) {
+		siblings := map[string]bool{}
+		for i, n := 0, 1+r.Intn(5); i < n; i++ {
+			sub := g.name(siblings)
+			cmd.Commands = append(cmd.Commands, g.command(sub, depth+1, taken))
+		}
+	} else {
+		cmd.Args = pick(r, []string{"", "none", "exact 1", "min 1", "max 2", "range 1 3"})
+		if cmd.Args != "none" && r.Intn(2) == 0 {
+			cmd.Use += " " + strings.ToUpper(pick(r, words))
+			if r.Intn(2) == 0 {
+				cmd.Use += "..."
+			}
+		}
+	}
+	return cmd
+}
+
+// name returns a command name not yet in siblings, now and then with a
+// hyphen in it.
+func (g *randomizer) name(siblings map[string]bool) string {
+	for {
+		name := pick(g.r, words)
+		if g.r.Intn(4) == 0 {
+			name += "-" + pick(g.r, words)
+		}
+		if !siblings[name] {
+			siblings[name] = true
+			return name
+		}
+	}
+}
+
+func (g *randomizer) flag(taken names, depth int) Flag {
+	r := g.r
+	var f Flag
+	for f.Name == "" || taken[f.Name] {
+		f.Name = pick(r, words)
+		if r.Intn(3) == 0 {
+			f.Name += "-" + pick(r, words)
+		}
+	}
+	taken[f.Name] = true
+	if r.Intn(2) == 0 {
+		for _, c := range r.Perm(26) {
+			if s := string(rune('a' + c)); !taken[s] {
+				f.Shorthand = s
+				taken[s] = true
+				break
+			}
+		}
+	}
+	t := randomTypes[r.Intn(len(randomTypes))]
+	f.Type = t.name
+	if r.Intn(2) == 0 {
+		f.Default = t.def(r)
+	}
+	f.Usage = g.sentence(1, 30)
+	f.Persistent = depth < 2 && r.Intn(4) == 0
+	return f
+}
+
+// sentence returns between min and max words, capitalised, starting with a
+// verb.
+func (g *randomizer) sentence(min, max int) string {
+	n := min + g.r.Intn(max-min+1)
+	parts := []string{pick(g.r, verbs)}
+	for i := 1; i < n; i++ {
+		parts = append(parts, pick(g.r, words))
+	}
+	return strings.Join(parts, " ")
+}
+
+// paragraph returns a Long description of one to three lines of text,
+// each a sentence.
+func (g *randomizer) paragraph() string {
+	var lines []string
+	for i, n := 0, 1+g.r.Intn(3); i < n; i++ {
+		lines = append(lines, g.sentence(3, 20)+".")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func pick(r *rand.Rand, from []string) string {
+	return from[r.Intn(len(from))]
+}
@@ -0,0 +1,15 @@
+module example
+
+go 1.21
+
+require (
+	cobratree v0.0.0
+	github.com/spf13/cobra v1.8.0
+	github.com/spf13/pflag v1.0.5
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require github.com/inconshreveable/mousetrap v1.1.0 // indirect
+
+// The tree exporter is shared with the other fixtures built on cobra.
+replace cobratree => ../cobratree
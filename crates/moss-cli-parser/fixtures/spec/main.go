@@ -0,0 +1,100 @@
+// Example CLI built from a declarative spec, for testing help output parsing
+// against layouts described in specs/ rather than written as programs.
+//
+// Usage:
+//
+//	example <spec.yaml|spec.json> [args...]
+//	example -random <seed> <dir>
+//
+// The second form builds a random command tree from seed and writes it, as
+// cobra builds it, to <dir>/example.tree.json as ground truth, next to the
+// --help output of every command in it.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"cobratree"
+	"example/cobraspec"
+)
+
+func main() {
+	if len(os.Args) == 4 && os.Args[1] == "-random" {
+		seed, err := strconv.ParseInt(os.Args[2], 10, 64)
+		if err == nil {
+			err = writeRandom(seed, os.Args[3])
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
+	}
+	if len(os.Args) < 2 {
+		fmt.Fprintln(os.Stderr, "usage: example <spec> [args...]")
+		os.Exit(2)
+	}
+	spec, err := cobraspec.Load(os.Args[1])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	cmd, err := cobraspec.Build(spec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	cmd.SetArgs(os.Args[2:])
+	if err := cmd.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// writeRandom writes the tree of the random spec for seed and the help of
+// each of its commands to dir.
+func writeRandom(seed int64, dir string) error {
+	spec := cobraspec.Random(seed)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	root, err := cobraspec.Build(spec)
+	if err != nil {
+		return err
+	}
+	if err := cobratree.Write(root, filepath.Join(dir, "example.tree.json")); err != nil {
+		return err
+	}
+	var paths [][]string
+	var collect func(c *cobraspec.Command, path []string)
+	collect = func(c *cobraspec.Command, path []string) {
+		path = append(path[:len(path):len(path)], strings.Fields(c.Use)[0])
+		paths = append(paths, path)
+		for i := range c.Commands {
+			collect(&c.Commands[i], path)
+		}
+	}
+	collect(&spec.Command, nil)
+	for _, path := range paths {
+		// Build afresh each time, as cobra keeps parsed flag values.
+		root, err := cobraspec.Build(spec)
+		if err != nil {
+			return err
+		}
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs(append(path[1:], "--help"))
+		if err := root.Execute(); err != nil {
+			return fmt.Errorf("%s --help: %w", strings.Join(path, " "), err)
+		}
+		name := strings.Join(path, "-") + ".help"
+		if err := os.WriteFile(filepath.Join(dir, name), out.Bytes(), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Example urfave/cli v2 CLI for testing help output parsing.
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v2"
+)
+
+var app = &cli.App{
+	Name:    "example",
+	Usage:   "An example CLI tool for testing",
+	Version: "1.0.0",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{Name: "verbose", Aliases: []string{"v"}, Usage: "Enable verbose output", EnvVars: []string{"EXAMPLE_VERBOSE"}},
+		&cli.StringFlag{Name: "config", Aliases: []string{"c"}, Usage: "Config file path", EnvVars: []string{"EXAMPLE_CONFIG"}, TakesFile: true},
+		&cli.IntFlag{Name: "port", Aliases: []string{"p"}, Value: 8080, Usage: "Port number", EnvVars: []string{"EXAMPLE_PORT"}},
+	},
+	Commands: []*cli.Command{
+		{
+			Name:     "build",
+			Aliases:  []string{"b"},
+			Category: "Build",
+			Usage:    "Build the project",
+			Description: `Build compiles every package in the project and writes the artifacts
+to the target directory.`,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{Name: "release", Aliases: []string{"r"}, Usage: "Build in release mode"},
+				&cli.StringFlag{Name: "target", Aliases: []string{"t"}, Usage: "Target `DIR`ectory"},
+				&cli.IntFlag{Name: "jobs", Aliases: []string{"j"}, Value: 4, Usage: "Number of parallel jobs", EnvVars: []string{"EXAMPLE_JOBS"}},
+				&cli.StringSliceFlag{Name: "feature", Usage: "Enable a feature (repeatable)"},
+				&cli.BoolFlag{Name: "dry-run", Usage: "Print the build plan without running it", Hidden: true},
+			},
+			Action: printFlags("Building..."),
+		},
+		{
+			Name:      "run",
+			Aliases:   []string{"r"},
+			Category:  "Build",
+			Usage:     "Run the project",
+			ArgsUsage: "[args...]",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "color", Value: "auto", Usage: "When to color output: always, never or auto"},
+				&cli.DurationFlag{Name: "timeout", Usage: "Stop the program after this long"},
+			},
+			Action: printFlags("Running..."),
+		},
+		{
+			Name:     "clean",
+			Category: "Build",
+			Usage:    "Clean build artifacts",
+			Action:   printFlags("Cleaning..."),
+		},
+		{
+			Name:     "cluster",
+			Aliases:  []string{"cl"},
+			Category: "Management",
+			Usage:    "Manage clusters",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "context", Usage: "Cluster context to use"},
+			},
+			Subcommands: []*cli.Command{
+				{
+					Name:  "list",
+					Usage: "List clusters",
+					Flags: []cli.Flag{
+						&cli.BoolFlag{Name: "wide", Aliases: []string{"w"}, Usage: "Show more columns"},
+					},
+					Action: printFlags("Listing..."),
+				},
+				{
+					Name:      "delete",
+					Usage:     "Delete a cluster",
+					ArgsUsage: "<name>",
+					Flags: []cli.Flag{
+						&cli.BoolFlag{Name: "force", Usage: "Do not ask for confirmation"},
+						&cli.StringFlag{Name: "reason", Usage: "Why the cluster is being deleted", Required: true},
+					},
+					Action: printFlags("Deleting..."),
+				},
+			},
+		},
+		{
+			Name:   "debug",
+			Usage:  "Dump internal state",
+			Hidden: true,
+			Action: printFlags("Debugging..."),
+		},
+		{
+			Name:   "status",
+			Usage:  "Show the status of project components",
+			Action: printFlags("Status..."),
+		},
+	},
+}
+
+// printFlags returns an action that echoes the flags that were set and the
+// arguments, then msg.
+func printFlags(msg string) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		for _, ctx := range c.Lineage() {
+			if ctx.Command == nil {
+				continue
+			}
+			for _, f := range ctx.Command.Flags {
+				name := f.Names()[0]
+				if !ctx.IsSet(name) {
+					continue
+				}
+				var value any = ctx.Value(name)
+				if _, ok := f.(*cli.StringSliceFlag); ok {
+					value = ctx.StringSlice(name)
+				}
+				fmt.Printf("%s=%v\n", name, value)
+			}
+		}
+		fmt.Println("Args:", c.Args().Slice())
+		fmt.Println(msg)
+		return nil
+	}
+}
+
+func main() {
+	// The built-in --version flag claims -v, which --verbose wants.
+	cli.VersionFlag = &cli.BoolFlag{Name: "version", Usage: "print the version"}
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
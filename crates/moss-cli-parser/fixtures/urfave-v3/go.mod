@@ -0,0 +1,5 @@
+module example
+
+go 1.22
+
+require github.com/urfave/cli/v3 v3.13.0
@@ -0,0 +1,124 @@
+// Example urfave/cli v3 CLI for testing help output parsing.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+)
+
+var app = &cli.Command{
+	Name:    "example",
+	Usage:   "An example CLI tool for testing",
+	Version: "1.0.0",
+	Flags: []cli.Flag{
+		&cli.BoolFlag{Name: "verbose", Aliases: []string{"v"}, Usage: "Enable verbose output", Sources: cli.EnvVars("EXAMPLE_VERBOSE")},
+		&cli.StringFlag{Name: "config", Aliases: []string{"c"}, Usage: "Config file path", Sources: cli.EnvVars("EXAMPLE_CONFIG"), TakesFile: true},
+		&cli.IntFlag{Name: "port", Aliases: []string{"p"}, Value: 8080, Usage: "Port number", Sources: cli.EnvVars("EXAMPLE_PORT")},
+	},
+	Commands: []*cli.Command{
+		{
+			Name:     "build",
+			Aliases:  []string{"b"},
+			Category: "Build",
+			Usage:    "Build the project",
+			Description: `Build compiles every package in the project and writes the artifacts
+to the target directory.`,
+			Flags: []cli.Flag{
+				&cli.BoolFlag{Name: "release", Aliases: []string{"r"}, Usage: "Build in release mode"},
+				&cli.StringFlag{Name: "target", Aliases: []string{"t"}, Usage: "Target `DIR`ectory"},
+				&cli.IntFlag{Name: "jobs", Aliases: []string{"j"}, Value: 4, Usage: "Number of parallel jobs", Sources: cli.EnvVars("EXAMPLE_JOBS")},
+				&cli.StringSliceFlag{Name: "feature", Usage: "Enable a feature (repeatable)"},
+				&cli.BoolFlag{Name: "dry-run", Usage: "Print the build plan without running it", Hidden: true},
+			},
+			Action: printFlags("Building..."),
+		},
+		{
+			Name:      "run",
+			Aliases:   []string{"r"},
+			Category:  "Build",
+			Usage:     "Run the project",
+			ArgsUsage: "[args...]",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "color", Value: "auto", Usage: "When to color output: always, never or auto"},
+				&cli.DurationFlag{Name: "timeout", Usage: "Stop the program after this long"},
+			},
+			Action: printFlags("Running..."),
+		},
+		{
+			Name:     "clean",
+			Category: "Build",
+			Usage:    "Clean build artifacts",
+			Action:   printFlags("Cleaning..."),
+		},
+		{
+			Name:     "cluster",
+			Aliases:  []string{"cl"},
+			Category: "Management",
+			Usage:    "Manage clusters",
+			Flags: []cli.Flag{
+				&cli.StringFlag{Name: "context", Usage: "Cluster context to use"},
+			},
+			Commands: []*cli.Command{
+				{
+					Name:  "list",
+					Usage: "List clusters",
+					Flags: []cli.Flag{
+						&cli.BoolFlag{Name: "wide", Aliases: []string{"w"}, Usage: "Show more columns"},
+					},
+					Action: printFlags("Listing..."),
+				},
+				{
+					Name:      "delete",
+					Usage:     "Delete a cluster",
+					ArgsUsage: "<name>",
+					Flags: []cli.Flag{
+						&cli.BoolFlag{Name: "force", Usage: "Do not ask for confirmation"},
+						&cli.StringFlag{Name: "reason", Usage: "Why the cluster is being deleted", Required: true},
+					},
+					Action: printFlags("Deleting..."),
+				},
+			},
+		},
+		{
+			Name:   "debug",
+			Usage:  "Dump internal state",
+			Hidden: true,
+			Action: printFlags("Debugging..."),
+		},
+		{
+			Name:   "status",
+			Usage:  "Show the status of project components",
+			Action: printFlags("Status..."),
+		},
+	},
+}
+
+// printFlags returns an action that echoes the flags that were set and the
+// arguments, then msg.
+func printFlags(msg string) cli.ActionFunc {
+	return func(ctx context.Context, c *cli.Command) error {
+		for _, cmd := range c.Lineage() {
+			for _, f := range cmd.Flags {
+				name := f.Names()[0]
+				if cmd.IsSet(name) {
+					fmt.Printf("%s=%v\n", name, cmd.Value(name))
+				}
+			}
+		}
+		fmt.Println("Args:", c.Args().Slice())
+		fmt.Println(msg)
+		return nil
+	}
+}
+
+func main() {
+	// The built-in --version flag claims -v, which --verbose wants.
+	cli.VersionFlag = &cli.BoolFlag{Name: "version", Usage: "print the version"}
+	if err := app.Run(context.Background(), os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
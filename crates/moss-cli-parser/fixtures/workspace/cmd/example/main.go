@@ -0,0 +1,43 @@
+// Example is the workspace fixture's main binary: the shared commands of
+// lib, and build and run of its own.
+package main
+
+import (
+	"fmt"
+
+	"example/lib"
+
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:     "example",
+	Short:   "Build and run the project",
+	Version: "1.0.0",
+}
+
+var buildCmd = &cobra.Command{
+	Use:   "build [flags] [package...]",
+	Short: "Build the project",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Building", args)
+	},
+}
+
+var runCmd = &cobra.Command{
+	Use:   "run [flags] -- [args...]",
+	Short: "Run the project",
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Running", args)
+	},
+}
+
+func main() {
+	lib.AddGlobalFlags(rootCmd)
+	rootCmd.PersistentFlags().StringP("chdir", "C", "", "Run as if started in this directory")
+	buildCmd.Flags().BoolP("release", "r", false, "Build in release mode")
+	buildCmd.Flags().IntP("jobs", "j", 0, "Number of parallel jobs")
+	runCmd.Flags().Bool("watch", false, "Rebuild and restart on changes")
+	rootCmd.AddCommand(buildCmd, runCmd, lib.NewStatusCmd(), lib.NewConfigCmd(), lib.NewVersionCmd())
+	lib.Main(rootCmd)
+}
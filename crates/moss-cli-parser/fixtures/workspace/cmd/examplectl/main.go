@@ -0,0 +1,53 @@
+// Examplectl is the workspace fixture's control binary: the shared
+// commands of lib, the status command with a flag of its own, and server
+// commands only it has.
+package main
+
+import (
+	"fmt"
+
+	"example/lib"
+
+	"github.com/spf13/cobra"
+)
+
+var rootCmd = &cobra.Command{
+	Use:     "examplectl",
+	Short:   "Control the project's servers",
+	Version: "1.0.0",
+}
+
+var serverCmd = &cobra.Command{
+	Use:   "server",
+	Short: "Start and stop servers",
+}
+
+var startCmd = &cobra.Command{
+	Use:   "start <name>",
+	Short: "Start a server",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Starting", args[0])
+	},
+}
+
+var stopCmd = &cobra.Command{
+	Use:   "stop <name>",
+	Short: "Stop a server",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Println("Stopping", args[0])
+	},
+}
+
+func main() {
+	lib.AddGlobalFlags(rootCmd)
+	rootCmd.PersistentFlags().String("server", "localhost:8080", "Address of the control server")
+	status := lib.NewStatusCmd()
+	status.Flags().BoolP("watch", "w", false, "Keep printing status as it changes")
+	startCmd.Flags().Int("port", 8080, "Port to listen on")
+	stopCmd.Flags().Bool("force", false, "Stop without draining connections")
+	serverCmd.AddCommand(startCmd, stopCmd)
+	rootCmd.AddCommand(serverCmd, status, lib.NewConfigCmd(), lib.NewVersionCmd())
+	lib.Main(rootCmd)
+}
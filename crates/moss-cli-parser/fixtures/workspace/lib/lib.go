@@ -0,0 +1,133 @@
+// Package lib is the command library the example and examplectl binaries
+// of the workspace fixture share, as related tools from one repository
+// share theirs: the global flags, and the status, config and version
+// commands. Each binary adds commands of its own, and examplectl a flag of
+// its own to the shared status, so the two trees overlap without being
+// the same.
+package lib
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"cobratree"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// markFlags and markCommand mark what lib defines as shared, which the
+// tree records.
+func markFlags(fs *pflag.FlagSet) {
+	fs.VisitAll(func(f *pflag.Flag) {
+		fs.SetAnnotation(f.Name, cobratree.Shared, []string{"true"})
+	})
+}
+
+func markCommand(cmd *cobra.Command) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[cobratree.Shared] = "true"
+	markFlags(cmd.Flags())
+	markFlags(cmd.PersistentFlags())
+	return cmd
+}
+
+// AddGlobalFlags adds the persistent flags every command of both binaries
+// takes to root.
+func AddGlobalFlags(root *cobra.Command) {
+	f := root.PersistentFlags()
+	f.StringP("config", "c", "", "Config file path")
+	f.BoolP("verbose", "v", false, "Enable verbose output")
+	f.StringP("output", "o", "text", "Output format: text, json or yaml")
+	markFlags(f)
+}
+
+// printFlags prints the flags set on cmd's command line, so runs show how
+// they were parsed.
+func printFlags(cmd *cobra.Command) {
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s=%s\n", f.Name, f.Value)
+	})
+}
+
+// NewStatusCmd returns the status command.
+func NewStatusCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status [flags] [name...]",
+		Short: "Show the status of services",
+		Run: func(cmd *cobra.Command, args []string) {
+			printFlags(cmd)
+			fmt.Fprintln(cmd.OutOrStdout(), "Status of", args)
+		},
+	}
+	cmd.Flags().BoolP("all", "a", false, "Include stopped services")
+	cmd.Flags().Duration("timeout", 0, "Give up waiting for a service after this long")
+	return markCommand(cmd)
+}
+
+// NewConfigCmd returns the config command and its get and set commands.
+func NewConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Read and change settings",
+	}
+	get := &cobra.Command{
+		Use:   "get <key>",
+		Short: "Print a setting",
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			printFlags(cmd)
+			fmt.Fprintln(cmd.OutOrStdout(), "Getting", args[0])
+		},
+	}
+	get.Flags().Bool("default", false, "Print the default instead of the value set")
+	set := &cobra.Command{
+		Use:   "set <key>=<value>...",
+		Short: "Change one or more settings",
+		Args:  cobra.MinimumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			printFlags(cmd)
+			fmt.Fprintln(cmd.OutOrStdout(), "Setting", args)
+		},
+	}
+	set.Flags().Bool("global", false, "Change the user's settings, not the project's")
+	cmd.AddCommand(markCommand(get), markCommand(set))
+	return markCommand(cmd)
+}
+
+// NewVersionCmd returns the version command, printing the root's version.
+func NewVersionCmd() *cobra.Command {
+	return markCommand(&cobra.Command{
+		Use:   "version",
+		Short: "Print version information",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			fmt.Fprintln(cmd.OutOrStdout(), cmd.Root().Name(), cmd.Root().Version)
+		},
+	})
+}
+
+// WriteTree writes root's whole command tree, with cobra's default help and
+// completion commands and help and version flags added as Execute would, to
+// <name>.tree.json in dir.
+func WriteTree(root *cobra.Command, dir string) error {
+	return cobratree.Write(root, filepath.Join(dir, root.Name()+".tree.json"))
+}
+
+// Main runs root, or with -gen-tree <dir> as its only arguments writes its
+// tree there.
+func Main(root *cobra.Command) {
+	if len(os.Args) == 3 && os.Args[1] == "-gen-tree" {
+		if err := WriteTree(root, os.Args[2]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,132 @@
+package mosshelp
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+// benchCase is a help text to time Parse on.
+type benchCase struct {
+	name string
+	help string
+}
+
+// benchCases returns help texts from tiny to huge: the stdlib flag
+// package's, a cobra subcommand's, kubectl's largest, a cobra command's
+// tens of thousands of lines of description, and a replica of a
+// kubectl-scale root command with thousands of lines.
+func benchCases(tb testing.TB) []benchCase {
+	var cases []benchCase
+	for _, c := range []struct{ name, path string }{
+		{"tiny", "flag/example.help"},
+		{"small", "cobra/example-build.help"},
+		{"large", "kubectl/example-get.help"},
+		{"long", "cobra/example-reference.help"},
+	} {
+		e, ok := corpus.Lookup(c.path)
+		if !ok {
+			tb.Fatalf("%s missing from corpus", c.path)
+		}
+		cases = append(cases, benchCase{c.name, e.Help})
+	}
+	return append(cases, benchCase{"huge", hugeHelp(400, 1600)})
+}
+
+// hugeHelp renders the help of a root command with cmds subcommands in
+// groups of twenty and flags global flags, a few of them with descriptions
+// several lines long.
+func hugeHelp(cmds, flags int) string {
+	c := &Command{Path: "example", Name: "example", Long: "Example manages a fleet of example clusters.",
+		Usage: []string{"example [flags]", "example [command]"}}
+	for i := 0; i < cmds; i++ {
+		c.Commands = append(c.Commands, Command{
+			Name:  fmt.Sprintf("command-%d", i),
+			Short: fmt.Sprintf("Manage the resources of kind %d", i),
+			Group: fmt.Sprintf("Group %d Commands", i/20),
+		})
+	}
+	for i := 0; i < flags; i++ {
+		f := Flag{Name: fmt.Sprintf("option-%d", i), Value: "string", Usage: fmt.Sprintf("Set option %d for every request", i)}
+		if i%10 == 0 {
+			f.Usage += ".\nThe value is read once at startup.\n\nSee the manual for the accepted values."
+			f.Default = "auto"
+		}
+		c.InheritedFlags = append(c.InheritedFlags, f)
+	}
+	return Render(c)
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, c := range benchCases(b) {
+		b.Run(c.name, func(b *testing.B) {
+			b.SetBytes(int64(len(c.help)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := Parse(c.help); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkDiscoverMega times discovering the 300 commands of the mega tree
+// from their help.
+func BenchmarkDiscoverMega(b *testing.B) {
+	d := &Discoverer{Run: megaRunner()}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if root, err := d.Discover(context.Background(), "example"); root == nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseUsage(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseUsage("example proxy [flags] <tool> [-- tool flags...]"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Parse's budget, per line of help. The discovery driver parses hundreds of
+// pages per binary, so parsing must stay linear and cheap: well under the
+// cost of running the binary to get the page. The allocation budget does
+// not depend on the machine, so it is always held to; the latency budget
+// does, so it is only with -timebudget, on a machine that is not busy.
+const (
+	budgetAllocsPerLine = 8
+	budgetNsPerLine     = 5000
+)
+
+var timeBudget = flag.Bool("timebudget", false, "hold Parse to its latency budget too")
+
+func TestParseBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping benchmarks in short mode")
+	}
+	for _, c := range benchCases(t) {
+		lines := strings.Count(c.help, "\n") + 1
+		r := testing.Benchmark(func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				Parse(c.help)
+			}
+		})
+		if perLine := float64(r.AllocsPerOp()) / float64(lines); perLine > budgetAllocsPerLine {
+			t.Errorf("%s: %d lines, %d allocs/op: %.1f per line, budget %d", c.name, lines, r.AllocsPerOp(), perLine, budgetAllocsPerLine)
+		}
+		if perLine := float64(r.NsPerOp()) / float64(lines); *timeBudget && perLine > budgetNsPerLine {
+			t.Errorf("%s: %d lines, %v/op: %.0fns per line, budget %dns", c.name, lines, time.Duration(r.NsPerOp()), perLine, budgetNsPerLine)
+		}
+	}
+}
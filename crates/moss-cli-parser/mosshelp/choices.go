@@ -0,0 +1,108 @@
+package mosshelp
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// choicesRE matches the phrases that introduce the values a flag
+	// accepts, such as "one of: json|yaml" or "must be 'debug' or 'info'".
+	choicesRE = regexp.MustCompile(`(?i)\b(?:` + strings.Join(choicesPhrases, "|") + `)\b:?\s*`)
+	// colonRE matches the colon a description introduces a list with, as in
+	// "Colorize output: auto, always or never".
+	colonRE = regexp.MustCompile(`:\s+`)
+	// choiceRE matches one value of a list: quoted, or a word that may hold
+	// dots, dashes and slashes but does not end in a dot.
+	choiceRE = regexp.MustCompile("^(?:\"([^\"]+)\"|'([^']+)'|`([^`]+)`|([[:alnum:]_](?:[\\w+/-]|\\.\\w)*))")
+	// choiceSepRE matches what separates the values of a list.
+	choiceSepRE = regexp.MustCompile(`^(?:\s*\|\s*|\s*,\s*(?:or\s+)?|\s+or\s+)`)
+	// choiceEndRE matches what may follow a list: the end of the text, or
+	// punctuation or a space before another note.
+	choiceEndRE = regexp.MustCompile(`^(?:$|[.;,)\]]|\s)`)
+)
+
+// Choices returns the values a flag's usage says it accepts, in order, or
+// nil if it lists none. It recognizes lists introduced by a phrase such as
+// "one of" or "must be", and lists of three or more after a colon ending
+// in "or":
+//
+//	Output format, one of: json|yaml|table
+//	Build profile, one of debug,release,bench.
+//	Level to log, must be 'debug', 'info' or 'error'
+//	Output format. One of: (json, yaml, name)
+//	Colorize output: auto, always or never
+func Choices(usage string) []string {
+	// Most usages list no values, and have neither a phrase choicesRE
+	// matches nor a colon, so look for those more cheaply first.
+	if hasChoicesPhrase(usage) {
+		for _, loc := range choicesRE.FindAllStringIndex(usage, -1) {
+			if values, _ := choiceList(usage[loc[1]:]); len(values) >= 2 {
+				return values
+			}
+		}
+	}
+	if !strings.Contains(usage, ":") {
+		return nil
+	}
+	for _, loc := range colonRE.FindAllStringIndex(usage, -1) {
+		if values, prose := choiceList(usage[loc[1]:]); len(values) >= 3 && prose {
+			return values
+		}
+	}
+	return nil
+}
+
+// choicesPhrases are the phrases choicesRE matches.
+var choicesPhrases = []string{"one of", "must be", "valid values are", "allowed values are", "possible values are", "choices are"}
+
+// hasChoicesPhrase reports whether usage holds one of choicesPhrases, in
+// any case, as it must for choicesRE to match.
+func hasChoicesPhrase(usage string) bool {
+	for i := 0; i < len(usage); i++ {
+		switch usage[i] | 0x20 {
+		case 'o', 'm', 'v', 'a', 'p', 'c':
+			for _, phrase := range choicesPhrases {
+				if len(usage)-i >= len(phrase) && strings.EqualFold(usage[i:i+len(phrase)], phrase) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// choiceList reads the list of values text starts with, reporting whether
+// it is written as prose: comma-separated with "or" before the last value.
+func choiceList(text string) (values []string, prose bool) {
+	if inner, ok := strings.CutPrefix(text, "("); ok {
+		if i := strings.IndexByte(inner, ')'); i >= 0 {
+			text = inner[:i]
+		}
+	}
+	var seps []string
+	for {
+		m := choiceRE.FindStringSubmatch(text)
+		if m == nil {
+			return nil, false
+		}
+		values = append(values, m[1]+m[2]+m[3]+m[4])
+		text = text[len(m[0]):]
+		sep := choiceSepRE.FindString(text)
+		if sep == "" || choiceRE.FindString(text[len(sep):]) == "" {
+			break
+		}
+		seps = append(seps, strings.TrimSpace(sep))
+		text = text[len(sep):]
+	}
+	if !choiceEndRE.MatchString(text) {
+		return nil, false
+	}
+	prose = len(seps) > 0 && strings.HasSuffix(seps[len(seps)-1], "or")
+	for _, sep := range seps[:max(len(seps)-1, 0)] {
+		if sep != "," {
+			prose = false
+		}
+	}
+	return values, prose
+}
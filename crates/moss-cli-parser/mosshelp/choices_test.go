@@ -0,0 +1,34 @@
+package mosshelp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChoices(t *testing.T) {
+	tests := []struct {
+		usage string
+		want  []string
+	}{
+		{"Output format, one of: json|yaml|table", []string{"json", "yaml", "table"}},
+		{"Minimum level to log, one of: debug|info|warn|error (env: EXAMPLE_LOG_LEVEL)", []string{"debug", "info", "warn", "error"}},
+		{"Build profile, one of debug,release,bench.", []string{"debug", "release", "bench"}},
+		{"Level, must be 'debug', 'info' or 'error'", []string{"debug", "info", "error"}},
+		{`Must be "none", "server", or "client". If client strategy, only print`, []string{"none", "server", "client"}},
+		{"Output format. One of: (json, yaml, name,\ngo-template, wide). See custom", []string{"json", "yaml", "name", "go-template", "wide"}},
+		{"Colorize output: auto, always or never", []string{"auto", "always", "never"}},
+		{"minimum level to log: debug, info, warn or error", []string{"debug", "info", "warn", "error"}},
+		{"Pin a version, one of v1.2 or v2.0.", []string{"v1.2", "v2.0"}},
+		// Not lists of values.
+		{"Exactly one of the subcommands below", nil},
+		{"A name must be specified", nil},
+		{"Mode: fast or slow", nil},
+		{"Load variables from a file: each line has the form KEY=VALUE", nil},
+		{"Colorize output", nil},
+	}
+	for _, tt := range tests {
+		if got := Choices(tt.usage); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Choices(%q) = %q, want %q", tt.usage, got, tt.want)
+		}
+	}
+}
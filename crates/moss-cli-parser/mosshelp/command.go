@@ -0,0 +1,198 @@
+// Package mosshelp parses the help text cobra commands print into a
+// Command, for Go programs that want to introspect a CLI without linking
+// the Rust moss-cli-parser crate.
+//
+// Parse reads the output of `<command> --help` (or `<command> help`) as
+// cobra's default usage template writes it:
+//
+//	<Long, or Short when Long is empty>
+//
+//	Usage:
+//	  example build [flags]
+//
+//	Aliases:
+//	  build, b, make
+//
+//	Examples:
+//	  example build --release
+//
+//	Available Commands:
+//	  ...
+//
+//	Flags:
+//	  -r, --release   Build in release mode
+//
+//	Global Flags:
+//	  ...
+//
+//	Additional help topics:
+//	  ...
+//
+// Sections it does not recognize are kept in Command.Sections, so text from
+// templates that add their own is not lost. A Parser tells of those, and of
+// flag table lines it had to skip, as warnings with their positions, or in
+// strict mode as an error. What a parse reads only by inference or a guess,
+// such as a flag's type from its default, is marked so in the Confidence of
+// its Command or Flag.
+//
+// Detect tells help printed by cobra from that of the other Go frameworks
+// (urfave/cli, kong, kingpin and the standard flag package), so a caller
+// discovering an arbitrary binary can check it is cobra's before parsing.
+// Discover does the discovering: it runs a binary's --help, and that of
+// every command listed, down to a depth limit, to assemble its whole tree,
+// running each in a Sandbox that bounds its time, output and side effects.
+// Diff compares two such trees, as of two versions of a binary, into the
+// changes a changelog lists, each classed as breaking, additive or only
+// informational.
+//
+// ParseCompletion reads the tree from a completion script instead, which
+// tells what help does not, such as how flag values are completed, and
+// MergeCompletion adds that to a tree parsed from help. ParseMan reads the
+// man page cobra/doc generates for a command into the same Command, and
+// ParseStdFlag the help of a standard flag program, such as a tool a cobra
+// command hands its arguments to, so Discover can follow a tree below it.
+//
+// The fixture corpus (crates/moss-cli-parser/fixtures) is the test suite:
+// every cobra fixture is parsed and checked against the command tree the
+// fixture binary exports.
+package mosshelp
+
+// Command is one command's help, or one subcommand listed in it.
+type Command struct {
+	// Schema is the version of the JSON form, SchemaVersion, on the
+	// command Parse returns; empty on the commands listed in it.
+	Schema string `json:"schema,omitempty"`
+	// Framework is the dialect of help the command's was read as, set when
+	// that is not cobra's, as for a flag program a cobra command runs; see
+	// ParseStdFlag.
+	Framework Framework `json:"framework,omitempty"`
+	// Path is the command's full path, such as "example cluster node".
+	Path string `json:"path"`
+	// Name is the last word of Path.
+	Name string `json:"name"`
+	// Short is the one-line summary a parent lists the command with. It is
+	// set only on listed subcommands and help topics, and on the command a
+	// man page describes, from its NAME: a command's own help shows Long
+	// instead, or Short in its place when there is no Long.
+	Short string `json:"short,omitempty"`
+	// Long is the text above the Usage: section.
+	Long string `json:"long,omitempty"`
+	// Usage holds the lines of the Usage: section, such as
+	// "example cluster [flags]" and "example cluster [command]".
+	Usage []string `json:"usage,omitempty"`
+	// Args are the positional arguments the first usage line names; see
+	// ParseUsage.
+	Args []Arg `json:"args,omitempty"`
+	// Aliases are the command's other names, from the Aliases: section.
+	Aliases []string `json:"aliases,omitempty"`
+	// Examples is the Examples: section as printed, indentation included.
+	Examples string `json:"examples,omitempty"`
+	// Group is the title of the section a listed subcommand appears under,
+	// such as "Management Commands", or empty for "Available Commands".
+	Group string `json:"group,omitempty"`
+	// Commands are the subcommands listed, with Path, Name, Short and
+	// Group set.
+	Commands []Command `json:"commands,omitempty"`
+	// HelpTopics are the commands listed under "Additional help topics:",
+	// which only document something and cannot be run.
+	HelpTopics []Command `json:"help_topics,omitempty"`
+	// Flags are the flags under "Flags:": those defined on the command,
+	// including the persistent ones its subcommands inherit.
+	Flags []Flag `json:"flags,omitempty"`
+	// InheritedFlags are the flags under "Global Flags:", defined as
+	// persistent by an ancestor.
+	InheritedFlags []Flag `json:"inherited_flags,omitempty"`
+	// Sections are the sections not described above, in order.
+	Sections []Section `json:"sections,omitempty"`
+	// Deprecated is the message a deprecated command's help is prefixed
+	// with, as in `Command "compile" is deprecated, use "build" instead`.
+	Deprecated string `json:"deprecated,omitempty"`
+	// ReplacedBy is what Deprecated says to use instead, such as "build".
+	ReplacedBy string `json:"replaced_by,omitempty"`
+	// ArgCompletion is how a shell completes the command's arguments, from
+	// a completion script; see ParseCompletion.
+	ArgCompletion *Completion `json:"arg_completion,omitempty"`
+	// Warnings are the problems a lenient Parser found in the help, in
+	// order; see Parser.
+	Warnings []Warning `json:"warnings,omitempty"`
+	// Confidence is how surely the fields read less than explicitly were
+	// read; see Confidence.
+	Confidence map[string]Confidence `json:"confidence,omitempty"`
+}
+
+// Flag is one row of a flag table.
+type Flag struct {
+	// Name is the long name, without dashes; empty for a flag with only a
+	// shorthand.
+	Name string `json:"name,omitempty"`
+	// Shorthand is the one-letter name, without the dash.
+	Shorthand string `json:"shorthand,omitempty"`
+	// Value is the placeholder printed after the name, such as "string" or
+	// a name taken from backquotes in the usage; empty for booleans.
+	Value string `json:"value,omitempty"`
+	// NoOptDefault is the value the flag takes when given without one,
+	// printed as [=value] after the name.
+	NoOptDefault string `json:"no_opt_default,omitempty"`
+	// Usage is the description, with wrapped lines joined by newlines and
+	// the trailing "(default ...)" removed.
+	Usage string `json:"usage"`
+	// Default is the value from the trailing "(default ...)", unquoted.
+	// pflag omits it for zero values, so empty means the default is zero or
+	// unknown.
+	Default string `json:"default,omitempty"`
+	// DefaultValue is the default as printed and what it means, read as
+	// the type Value names; nil when no default is printed.
+	DefaultValue *TypedValue `json:"default_value,omitempty"`
+	// Env are the environment variables the usage says the flag reads,
+	// such as EXAMPLE_PORT for "(env: EXAMPLE_PORT)"; see EnvVars.
+	Env []string `json:"env,omitempty"`
+	// Choices are the values the usage says the flag accepts, such as json,
+	// yaml and table for "one of: json|yaml|table"; see Choices.
+	Choices []string `json:"choices,omitempty"`
+	// Deprecated is the message from the "(DEPRECATED: ...)" pflag appends
+	// to the usage of a deprecated flag, which it lists only when the flag
+	// is not hidden too.
+	Deprecated string `json:"deprecated,omitempty"`
+	// ReplacedBy is what Deprecated says to use instead, such as
+	// "--target" for "use --target instead".
+	ReplacedBy string `json:"replaced_by,omitempty"`
+	// ShorthandDeprecated is the message pflag warns with when the
+	// shorthand alone is deprecated and used anyway. Help leaves such a
+	// shorthand out, so it is only known from the warning; see
+	// MergeDeprecations.
+	ShorthandDeprecated string `json:"shorthand_deprecated,omitempty"`
+	// Required is set for a flag the command cannot run without, which
+	// help does not show but completion scripts do; see ParseCompletion.
+	Required bool `json:"required,omitempty"`
+	// Completion is how a shell completes the flag's value, from a
+	// completion script.
+	Completion *Completion `json:"completion,omitempty"`
+	// Confidence is how surely the fields read less than explicitly were
+	// read; see Confidence.
+	Confidence map[string]Confidence `json:"confidence,omitempty"`
+}
+
+// Arg is a positional argument named on a usage line.
+type Arg struct {
+	// Name is the argument's name without brackets or ellipsis, such as
+	// "input" for "<input>" or "tool flags" for "[-- tool flags...]".
+	Name string `json:"name"`
+	// Optional is set for arguments in square brackets.
+	Optional bool `json:"optional,omitempty"`
+	// Repeated is set for arguments followed by "...".
+	Repeated bool `json:"repeated,omitempty"`
+	// Choices are the words an argument written as an alternation, such
+	// as "(json|yaml)", may be; Name is then the alternation itself.
+	Choices []string `json:"choices,omitempty"`
+	// Passthrough is set for arguments after the "--" terminator, which
+	// the command passes on without parsing.
+	Passthrough bool `json:"passthrough,omitempty"`
+}
+
+// Section is a section of help text Parse does not interpret.
+type Section struct {
+	// Title is the header without its colon.
+	Title string `json:"title"`
+	// Body is the lines under the header, as printed.
+	Body string `json:"body"`
+}
@@ -0,0 +1,344 @@
+package mosshelp
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Completion is what a shell completes a flag's value or a command's
+// arguments with.
+type Completion struct {
+	Kind CompletionKind `json:"kind"`
+	// Values are the words a ValuesCompletion offers.
+	Values []Candidate `json:"values,omitempty"`
+	// Extensions are the file extensions a FilesCompletion is limited to,
+	// without dots; nil for any file.
+	Extensions []string `json:"extensions,omitempty"`
+	// Dir is the directory whose subdirectories a DirsCompletion offers;
+	// empty for those of the working directory.
+	Dir string `json:"dir,omitempty"`
+	// Function is the shell function a CustomCompletion runs.
+	Function string `json:"function,omitempty"`
+}
+
+// Candidate is one word a completion offers.
+type Candidate struct {
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+}
+
+// CompletionKind is the kind of a Completion.
+type CompletionKind int
+
+const (
+	// NoneCompletion completes nothing.
+	NoneCompletion CompletionKind = iota
+	FilesCompletion
+	DirsCompletion
+	// ValuesCompletion offers a fixed list of words.
+	ValuesCompletion
+	// DynamicCompletion asks the program, by running its hidden __complete
+	// command, whose answer ParseComplete reads.
+	DynamicCompletion
+	// CustomCompletion runs a function of the completion script.
+	CustomCompletion
+)
+
+var completionKinds = [...]string{"none", "files", "dirs", "values", "dynamic", "custom"}
+
+func (k CompletionKind) String() string {
+	if k >= 0 && int(k) < len(completionKinds) {
+		return completionKinds[k]
+	}
+	return fmt.Sprintf("CompletionKind(%d)", int(k))
+}
+
+var (
+	// scriptHeaderRE matches the first lines of the completion scripts cobra
+	// generates, which name the program they complete.
+	scriptHeaderRE = regexp.MustCompile(`(?m)^(?:# (?:bash|zsh|fish|powershell) completion (?:V2 )?for (\S+)|#compdef (\S+))`)
+	// bashFuncRE matches the function a legacy bash script defines for each
+	// command, up to the closing brace.
+	bashFuncRE = regexp.MustCompile(`(?ms)^(_\S+)\(\)\n\{\n(.*?)^\}$`)
+	// bashAppendRE matches a line appending a word to one of the arrays a
+	// legacy bash script fills in per command, such as flags+=("--target=").
+	bashAppendRE = regexp.MustCompile(`^\s*(\w+)\+=\(("(?:[^"\\]|\\.)*"|:)\)$`)
+	// bashAliasRE matches the line mapping a command alias to its name.
+	bashAliasRE = regexp.MustCompile(`^\s*aliashash\[("(?:[^"\\]|\\.)*")\]=("(?:[^"\\]|\\.)*")$`)
+	// bashLastCommandRE matches the line naming a function's command, its
+	// path with words joined by underscores.
+	bashLastCommandRE = regexp.MustCompile(`(?m)^\s*last_command=("(?:[^"\\]|\\.)*")$`)
+)
+
+// ParseCompletion reads a shell completion script cobra generated into the
+// tree of the commands it completes. The legacy bash script, written by
+// GenBashCompletion, lists every command, with its aliases, the flags it
+// takes, which of those are required, and how the values of flags and
+// arguments are completed: files with certain extensions, directories,
+// fixed words, or what the program answers. The flags cobra hides or
+// deprecates are left out, as in help. Neither says what type a flag's
+// value is, so a flag taking one has Value "value".
+//
+// The other scripts, for bash (V2), zsh, fish and PowerShell, ask the
+// program for every completion instead, so all ParseCompletion has from
+// them is a root that completes dynamically.
+//
+// MergeCompletion adds what the tree holds to one parsed from help.
+func ParseCompletion(script string) (*Command, error) {
+	script = strings.ReplaceAll(script, "\r\n", "\n")
+	m := scriptHeaderRE.FindStringSubmatch(script)
+	if m == nil {
+		return nil, errors.New("mosshelp: not a cobra completion script")
+	}
+	name := m[1] + m[2]
+	funcs := map[string]string{}
+	var root string
+	for _, f := range bashFuncRE.FindAllStringSubmatch(script, -1) {
+		lc := bashLastCommandRE.FindStringSubmatch(f[2])
+		if lc == nil {
+			continue
+		}
+		last, err := strconv.Unquote(lc[1])
+		if err != nil {
+			continue
+		}
+		funcs[last] = f[2]
+		if f[1] == "_"+last+"_root_command" {
+			root = last
+		}
+	}
+	if root == "" {
+		if !strings.Contains(script, " __complete") {
+			return nil, fmt.Errorf("mosshelp: completion script for %s lists no commands", name)
+		}
+		return &Command{Schema: SchemaVersion, Path: name, Name: name, ArgCompletion: &Completion{Kind: DynamicCompletion}}, nil
+	}
+	c := bashCommand(funcs, root, name, name, nil)
+	c.Schema = SchemaVersion
+	return c, nil
+}
+
+// bashCommand assembles the command whose legacy bash function is funcs[last],
+// and its subcommands, given the flags its parent takes.
+func bashCommand(funcs map[string]string, last, path, name string, parentFlags map[string]bool) *Command {
+	c := &Command{Path: path, Name: name}
+	var (
+		subcommands []string
+		aliases     = map[string][]string{}
+		flags       []*Flag
+		byKey       = map[string]*Flag{}
+		local       = map[string]bool{}
+		withCompl   string
+		nouns       []Candidate
+	)
+	for _, line := range strings.Split(funcs[last], "\n") {
+		if m := bashAliasRE.FindStringSubmatch(line); m != nil {
+			alias, _ := strconv.Unquote(m[1])
+			cmd, _ := strconv.Unquote(m[2])
+			aliases[cmd] = append(aliases[cmd], alias)
+			continue
+		}
+		if strings.TrimSpace(line) == "has_completion_function=1" {
+			c.ArgCompletion = &Completion{Kind: DynamicCompletion}
+			continue
+		}
+		m := bashAppendRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		word := m[2]
+		if word != ":" {
+			word, _ = strconv.Unquote(word)
+		}
+		long, takesValue := strings.CutSuffix(word, "=")
+		switch m[1] {
+		case "commands":
+			subcommands = append(subcommands, word)
+		case "flags", "two_word_flags":
+			switch {
+			case strings.HasPrefix(long, "--"):
+				if byKey[long] == nil {
+					f := &Flag{Name: long[2:]}
+					if takesValue || m[1] == "two_word_flags" {
+						f.Value = "value"
+					}
+					flags = append(flags, f)
+					byKey[long] = f
+				}
+			case strings.HasPrefix(long, "-") && len(flags) > 0:
+				// A shorthand follows its flag's long name.
+				f := flags[len(flags)-1]
+				f.Shorthand = long[1:]
+				byKey[long] = f
+			}
+		case "flags_with_completion":
+			withCompl = word
+		case "flags_completion":
+			if f := byKey[withCompl]; f != nil && f.Completion == nil {
+				f.Completion = bashCompletion(word)
+			}
+		case "local_nonpersistent_flags":
+			local[long] = true
+		case "must_have_one_flag":
+			if f := byKey[long]; f != nil {
+				f.Required = true
+			}
+		case "must_have_one_noun":
+			nouns = append(nouns, Candidate{Value: word})
+		}
+	}
+	if nouns != nil && c.ArgCompletion == nil {
+		c.ArgCompletion = &Completion{Kind: ValuesCompletion, Values: nouns}
+	}
+
+	// A flag the parent takes too is inherited from it, unless it is one
+	// the command defines for itself alone.
+	takes := map[string]bool{}
+	for _, f := range flags {
+		key := "--" + f.Name
+		takes[key] = true
+		if parentFlags[key] && !local[key] {
+			c.InheritedFlags = append(c.InheritedFlags, *f)
+		} else {
+			c.Flags = append(c.Flags, *f)
+		}
+	}
+	for _, sub := range subcommands {
+		child := bashCommand(funcs, last+"_"+strings.ReplaceAll(sub, ":", "__"), path+" "+sub, sub, takes)
+		child.Aliases = aliases[sub]
+		c.Commands = append(c.Commands, *child)
+	}
+	return c
+}
+
+// bashCompletion reads the command a legacy bash script completes a flag's
+// value with.
+func bashCompletion(handler string) *Completion {
+	fn, arg, _ := strings.Cut(handler, " ")
+	switch {
+	case handler == "_filedir":
+		return &Completion{Kind: FilesCompletion}
+	case handler == "_filedir -d":
+		return &Completion{Kind: DirsCompletion}
+	case strings.HasSuffix(fn, "_handle_filename_extension_flag"):
+		return &Completion{Kind: FilesCompletion, Extensions: strings.Split(arg, "|")}
+	case strings.HasSuffix(fn, "_handle_subdirs_in_dir_flag"):
+		return &Completion{Kind: DirsCompletion, Dir: arg}
+	case strings.HasSuffix(handler, "_handle_go_custom_completion"):
+		return &Completion{Kind: DynamicCompletion}
+	}
+	return &Completion{Kind: CustomCompletion, Function: handler}
+}
+
+// The bits of the directive cobra's __complete command ends its answer with.
+const (
+	directiveError         = 1 << 0
+	directiveNoFileComp    = 1 << 2
+	directiveFilterFileExt = 1 << 3
+	directiveFilterDirs    = 1 << 4
+)
+
+// ParseComplete reads what cobra's hidden __complete command prints, as in
+// `example __complete status ""`: one candidate per line, with any
+// description after a tab, then a ":" and the directive that says what
+// the candidates are.
+//
+//	batch	Spot instances for batch jobs
+//	workers	General purpose pool
+//	:4
+//
+// With no candidates, and no directive saying to complete nothing, shells
+// complete files, so a FilesCompletion is returned.
+func ParseComplete(out string) (*Completion, error) {
+	lines := strings.Split(strings.TrimRight(strings.ReplaceAll(out, "\r\n", "\n"), "\n"), "\n")
+	last := lines[len(lines)-1]
+	directive, err := strconv.Atoi(strings.TrimPrefix(last, ":"))
+	if !strings.HasPrefix(last, ":") || err != nil {
+		return nil, fmt.Errorf("mosshelp: no completion directive in %q", last)
+	}
+	if directive&directiveError != 0 {
+		return nil, errors.New("mosshelp: completion failed")
+	}
+	var values []Candidate
+	for _, line := range lines[:len(lines)-1] {
+		if line == "" || strings.HasPrefix(line, "_activeHelp_ ") {
+			continue
+		}
+		value, desc, _ := strings.Cut(line, "\t")
+		values = append(values, Candidate{Value: value, Description: desc})
+	}
+	switch {
+	case directive&directiveFilterFileExt != 0:
+		c := &Completion{Kind: FilesCompletion}
+		for _, v := range values {
+			c.Extensions = append(c.Extensions, v.Value)
+		}
+		return c, nil
+	case directive&directiveFilterDirs != 0:
+		c := &Completion{Kind: DirsCompletion}
+		if len(values) > 0 {
+			c.Dir = values[0].Value
+		}
+		return c, nil
+	case len(values) > 0:
+		return &Completion{Kind: ValuesCompletion, Values: values}, nil
+	case directive&directiveNoFileComp != 0:
+		return &Completion{Kind: NoneCompletion}, nil
+	}
+	return &Completion{Kind: FilesCompletion}, nil
+}
+
+// MergeCompletion adds to c, parsed from help, what comp, parsed from a
+// completion script of the same program, holds and help does not show:
+// how flags and arguments are completed, which flags are required, and
+// the aliases, flags and subcommands of commands c only lists, or does not
+// list at all. What c already holds is kept, so a shorthand help does not
+// show, as for one deprecated, is not added back.
+func MergeCompletion(c, comp *Command) {
+	if c.ArgCompletion == nil {
+		c.ArgCompletion = comp.ArgCompletion
+	}
+	if c.Aliases == nil {
+		c.Aliases = comp.Aliases
+	}
+	_, flags := commandFlags(c)
+	var added, inherited []Flag
+	for _, list := range []struct {
+		from []Flag
+		to   *[]Flag
+	}{{comp.Flags, &added}, {comp.InheritedFlags, &inherited}} {
+		for _, f := range list.from {
+			e, ok := flags[flagKey(&f)]
+			if !ok {
+				*list.to = append(*list.to, f)
+				continue
+			}
+			if e.flag.Completion == nil {
+				e.flag.Completion = f.Completion
+			}
+			e.flag.Required = e.flag.Required || f.Required
+		}
+	}
+	c.Flags = append(c.Flags, added...)
+	c.InheritedFlags = append(c.InheritedFlags, inherited...)
+	for i := range comp.Commands {
+		sub := &comp.Commands[i]
+		if j := indexOfCommand(c.Commands, sub.Name); j >= 0 {
+			MergeCompletion(&c.Commands[j], sub)
+		} else {
+			c.Commands = append(c.Commands, *sub)
+		}
+	}
+}
+
+func indexOfCommand(cmds []Command, name string) int {
+	for i := range cmds {
+		if cmds[i].Name == name {
+			return i
+		}
+	}
+	return -1
+}
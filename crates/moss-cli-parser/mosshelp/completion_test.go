@@ -0,0 +1,315 @@
+package mosshelp
+
+import (
+	"encoding/json"
+	"io/fs"
+	"maps"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+func readFixture(t *testing.T, path string) string {
+	t.Helper()
+	data, err := fs.ReadFile(corpus.FS(), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func bashV1(t *testing.T) *Command {
+	t.Helper()
+	c, err := ParseCompletion(readFixture(t, "cobra/completions/example-v1.bash"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// lookupCommand returns the command at path in c's tree, or nil.
+func lookupCommand(c *Command, path string) *Command {
+	if c.Path == path {
+		return c
+	}
+	for i := range c.Commands {
+		if strings.HasPrefix(path, c.Commands[i].Path) {
+			if found := lookupCommand(&c.Commands[i], path); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+func allPaths(c *Command) []string {
+	paths := []string{c.Path}
+	for i := range c.Commands {
+		paths = append(paths, allPaths(&c.Commands[i])...)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// TestParseCompletionTree checks the tree read from the legacy bash script
+// against the one the fixture binary exports: the same commands, with the
+// same aliases and flags, but for the help and completion commands and the
+// help and version flags cobra adds only when run.
+func TestParseCompletionTree(t *testing.T) {
+	c := bashV1(t)
+	var want []string
+	for _, path := range treePaths(exampleTree(t)) {
+		if path != "example help" && !strings.HasPrefix(path, "example completion") {
+			want = append(want, path)
+		}
+	}
+	if got := allPaths(c); strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("commands %q, want %q", got, want)
+	}
+
+	var check func(tree *treeCommand, inherited map[string]treeFlag)
+	check = func(tree *treeCommand, inherited map[string]treeFlag) {
+		got := lookupCommand(c, tree.Path)
+		if got == nil {
+			return
+		}
+		if !reflect.DeepEqual(sortedCopy(got.Aliases), sortedCopy(tree.Aliases)) {
+			t.Errorf("%s: aliases %q, want %q", tree.Path, got.Aliases, tree.Aliases)
+		}
+		_, flags := commandFlags(got)
+		inherit, inherited := map[string]treeFlag{}, maps.Clone(inherited)
+		for k, f := range inherited {
+			inherit[k] = f
+		}
+		for _, f := range tree.Flags {
+			delete(inherited, f.Name)
+			if f.Persistent {
+				inherit[f.Name] = f
+			}
+			if f.Hidden || f.Deprecated != "" || f.Name == "help" || f.Name == "version" {
+				continue
+			}
+			e, ok := flags["--"+f.Name]
+			switch {
+			case !ok:
+				t.Errorf("%s: --%s missing", tree.Path, f.Name)
+			case e.inherited:
+				t.Errorf("%s: --%s inherited, want defined here", tree.Path, f.Name)
+			case e.flag.Shorthand != f.Shorthand:
+				t.Errorf("%s: --%s shorthand %q, want %q", tree.Path, f.Name, e.flag.Shorthand, f.Shorthand)
+			case e.flag.Required != f.Required:
+				t.Errorf("%s: --%s required = %v, want %v", tree.Path, f.Name, e.flag.Required, f.Required)
+			case (e.flag.Value != "") != (f.Type != "bool" && f.NoOptDefault == ""):
+				t.Errorf("%s: --%s value %q for a %s flag", tree.Path, f.Name, e.flag.Value, f.Type)
+			}
+		}
+		for name, f := range inherited {
+			if e, ok := flags["--"+name]; !f.Hidden && f.Deprecated == "" && (!ok || !e.inherited) {
+				t.Errorf("%s: --%s not inherited", tree.Path, name)
+			}
+		}
+		for i := range tree.Commands {
+			check(&tree.Commands[i], inherit)
+		}
+	}
+	check(exampleTree(t), nil)
+}
+
+func TestParseCompletionHints(t *testing.T) {
+	c := bashV1(t)
+	flag := func(path, name string) *Completion {
+		cmd := lookupCommand(c, path)
+		if cmd == nil {
+			t.Fatalf("%s missing", path)
+		}
+		_, flags := commandFlags(cmd)
+		e, ok := flags["--"+name]
+		if !ok {
+			t.Fatalf("%s --%s missing", path, name)
+		}
+		return e.flag.Completion
+	}
+	args := func(path string) *Completion {
+		return lookupCommand(c, path).ArgCompletion
+	}
+	tests := []struct {
+		name string
+		got  *Completion
+		want *Completion
+	}{
+		{"build --target", flag("example build", "target"), &Completion{Kind: DirsCompletion}},
+		{"build --env-file", flag("example build", "env-file"), &Completion{Kind: FilesCompletion, Extensions: []string{"env"}}},
+		{"build --cache", flag("example build", "cache"), nil},
+		{"convert --log", flag("example convert", "log"), &Completion{Kind: FilesCompletion}},
+		{"convert --schema", flag("example convert", "schema"), &Completion{Kind: FilesCompletion, Extensions: []string{"json", "yaml"}}},
+		{"pool create --zone", flag("example cluster node pool create", "zone"), &Completion{Kind: DynamicCompletion}},
+		{"status args", args("example status"), &Completion{Kind: ValuesCompletion, Values: []Candidate{{Value: "api"}, {Value: "cache"}, {Value: "db"}, {Value: "worker"}}}},
+		{"config get args", args("example config get"), &Completion{Kind: DynamicCompletion}},
+		{"build args", args("example build"), nil},
+	}
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.got, tt.want) {
+			t.Errorf("%s: completion %+v, want %+v", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+// TestParseCompletionDynamic checks that the scripts that ask the program
+// for completions yield only a root completing dynamically.
+func TestParseCompletionDynamic(t *testing.T) {
+	for _, name := range []string{"example.bash", "example.zsh", "example.fish", "example.ps1"} {
+		c, err := ParseCompletion(readFixture(t, "cobra/completions/"+name))
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		want := &Command{Schema: SchemaVersion, Path: "example", Name: "example", ArgCompletion: &Completion{Kind: DynamicCompletion}}
+		if !reflect.DeepEqual(c, want) {
+			t.Errorf("%s: %+v, want %+v", name, c, want)
+		}
+	}
+	for _, script := range []string{"", "echo hello\n", "#compdef example\n_arguments '-v[verbose]'\n"} {
+		if c, err := ParseCompletion(script); err == nil {
+			t.Errorf("ParseCompletion(%q) = %+v, want an error", script, c)
+		}
+	}
+}
+
+func TestParseComplete(t *testing.T) {
+	tests := []struct {
+		out  string
+		want *Completion
+	}{
+		{readFixture(t, "cobra/example-status.complete"), &Completion{Kind: ValuesCompletion, Values: []Candidate{{Value: "api"}, {Value: "cache"}, {Value: "db"}, {Value: "worker"}}}},
+		{readFixture(t, "cobra/example-cluster-node-pool-delete-more.complete"), &Completion{Kind: ValuesCompletion, Values: []Candidate{
+			{Value: "batch", Description: "Spot instances for batch jobs"},
+			{Value: "workers", Description: "General purpose pool"},
+		}}},
+		{"json\nyaml\n:8\n", &Completion{Kind: FilesCompletion, Extensions: []string{"json", "yaml"}}},
+		{"themes\n:16\n", &Completion{Kind: DirsCompletion, Dir: "themes"}},
+		{":16\n", &Completion{Kind: DirsCompletion}},
+		{":4\n", &Completion{Kind: NoneCompletion}},
+		{":0\n", &Completion{Kind: FilesCompletion}},
+		{"_activeHelp_ Pick a pool\nbatch\n:4\n", &Completion{Kind: ValuesCompletion, Values: []Candidate{{Value: "batch"}}}},
+	}
+	for _, tt := range tests {
+		got, err := ParseComplete(tt.out)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseComplete(%q) = %+v, %v; want %+v", tt.out, got, err, tt.want)
+		}
+	}
+	for _, out := range []string{"", "api\ncache\n", ":1\n", "api\n:x\n"} {
+		if got, err := ParseComplete(out); err == nil {
+			t.Errorf("ParseComplete(%q) = %+v, want an error", out, got)
+		}
+	}
+}
+
+// TestParseCompleteCursors reads what __complete answered at every cursor
+// position cobra/example.complete.json records, and checks that whether
+// "--flag -" completes flags, as it does after a flag taking no value, is
+// what the tree says of the flag's value.
+func TestParseCompleteCursors(t *testing.T) {
+	var cases []struct {
+		Command    string      `json:"command"`
+		Cursor     string      `json:"cursor"`
+		Flag       string      `json:"flag"`
+		Args       []string    `json:"args"`
+		Stdout     string      `json:"stdout"`
+		Candidates []Candidate `json:"candidates"`
+	}
+	if err := json.Unmarshal([]byte(readFixture(t, "cobra/example.complete.json")), &cases); err != nil {
+		t.Fatal(err)
+	}
+	root := exampleTree(t)
+	cursors := map[string]int{}
+	for _, c := range cases {
+		cursors[c.Cursor]++
+		got, err := ParseComplete(c.Stdout)
+		if err != nil {
+			t.Errorf("__complete %q: %v", c.Args, err)
+			continue
+		}
+		if got.Kind == ValuesCompletion && !reflect.DeepEqual(got.Values, c.Candidates) {
+			t.Errorf("__complete %q: Values = %+v, want %+v", c.Args, got.Values, c.Candidates)
+		}
+		if c.Cursor != "flag-dash" {
+			continue
+		}
+		chain := lookup(root, strings.Fields(c.Command)[1:])
+		if chain == nil {
+			t.Errorf("%s: not in the tree", c.Command)
+			continue
+		}
+		var flag *treeFlag
+		for i, f := range chain[len(chain)-1].Flags {
+			if "--"+f.Name == c.Flag {
+				flag = &chain[len(chain)-1].Flags[i]
+			}
+		}
+		if flag == nil {
+			t.Errorf("%s %s: not in the tree", c.Command, c.Flag)
+			continue
+		}
+		flags := got.Kind == ValuesCompletion && strings.HasPrefix(got.Values[0].Value, "-")
+		if takesValue := flag.NoOptDefault == ""; flags == takesValue {
+			t.Errorf("%s %s -: completes flags %v, want %v for a %s flag with no-value default %q",
+				c.Command, c.Flag, flags, !takesValue, flag.Type, flag.NoOptDefault)
+		}
+	}
+	for _, cursor := range []string{"arg", "prefix", "dash", "double-dash", "terminator", "flag-prefix", "flag-value", "flag-equals", "flag-dash"} {
+		if cursors[cursor] == 0 {
+			t.Errorf("no %s case", cursor)
+		}
+	}
+}
+
+func TestMergeCompletion(t *testing.T) {
+	root := exampleRoot(t)
+	MergeCompletion(root, bashV1(t))
+
+	build := &root.Commands[indexOfCommand(root.Commands, "build")]
+	_, flags := commandFlags(build)
+	target := flags["--target"].flag
+	if target.Value != "string" || target.Completion == nil || target.Completion.Kind != DirsCompletion {
+		t.Errorf("build --target = %+v, want help's with the completion's hint", target)
+	}
+	if jobs := flags["--jobs"].flag; jobs.Shorthand != "" {
+		t.Errorf("build --jobs shorthand %q added, want help's deprecated one left out", jobs.Shorthand)
+	}
+	if len(build.Flags) != 6 || len(build.InheritedFlags) != 3 {
+		t.Errorf("build flags %d and %d inherited, want help's 6 and 3", len(build.Flags), len(build.InheritedFlags))
+	}
+
+	// Commands the root only lists gain what the script knows of them.
+	deploy := &root.Commands[indexOfCommand(root.Commands, "deploy")]
+	if deploy.Short != "Deploy the project" || deploy.Usage != nil {
+		t.Errorf("deploy = %+v, want the listing kept", deploy)
+	}
+	_, flags = commandFlags(deploy)
+	if image, ok := flags["--image"]; !ok || !image.flag.Required {
+		t.Errorf("deploy --image = %+v, want it added, required", image.flag)
+	}
+	if got := lookupCommand(root, "example deploy rollback"); got == nil {
+		t.Error("deploy rollback not added")
+	}
+	if got := lookupCommand(root, "example status"); got.ArgCompletion == nil || got.ArgCompletion.Kind != ValuesCompletion {
+		t.Errorf("status args = %+v, want values", got.ArgCompletion)
+	}
+	run := &root.Commands[indexOfCommand(root.Commands, "run")]
+	if !reflect.DeepEqual(run.Aliases, []string{"r"}) {
+		t.Errorf("run aliases %q, want [r]", run.Aliases)
+	}
+	if want := len(exampleRoot(t).Commands); len(root.Commands) != want {
+		t.Errorf("%d commands after merging, want help's %d", len(root.Commands), want)
+	}
+}
+
+func sortedCopy(s []string) []string {
+	s = append([]string(nil), s...)
+	sort.Strings(s)
+	return s
+}
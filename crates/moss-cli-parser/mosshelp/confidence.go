@@ -0,0 +1,48 @@
+package mosshelp
+
+import "fmt"
+
+// Confidence is how surely a field of the model was read from what a CLI
+// printed. Confidences are ordered from most to least sure, so that
+// conf <= Inferred selects what was not guessed.
+//
+// A Command and a Flag record the confidence of each field read less than
+// explicitly in their Confidence, by the field's JSON name, such as
+// "default_value"; a field not in it is Explicit.
+type Confidence int
+
+const (
+	// Explicit fields are stated where the format puts them, such as a
+	// flag's type in its pflag placeholder, or commands under "Available
+	// Commands:".
+	Explicit Confidence = iota
+	// Inferred fields follow from what is stated by a rule that holds for
+	// the format, such as the environment variables a usage names, or
+	// the commands under a header of cobra's only by the shape of their
+	// rows.
+	Inferred
+	// Guessed fields are read from syntax alone, such as the kind of a
+	// default whose type is not pflag's, or a flag's type from its default
+	// on a man page.
+	Guessed
+)
+
+var confidences = [...]string{"explicit", "inferred", "guessed"}
+
+func (c Confidence) String() string {
+	if c >= 0 && int(c) < len(confidences) {
+		return confidences[c]
+	}
+	return fmt.Sprintf("Confidence(%d)", int(c))
+}
+
+// score records conf as the confidence of field in m, unless Explicit.
+func score(m *map[string]Confidence, field string, conf Confidence) {
+	if conf == Explicit {
+		return
+	}
+	if *m == nil {
+		*m = map[string]Confidence{}
+	}
+	(*m)[field] = conf
+}
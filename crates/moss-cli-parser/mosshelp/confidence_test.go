@@ -0,0 +1,111 @@
+package mosshelp
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestConfidence(t *testing.T) {
+	c, err := Parse(`Usage:
+  example [command]
+
+Management Commands:
+  cluster     Manage clusters
+
+Additional Commands:
+  help        Help about any command
+
+Flags:
+      --schema SCHEMA   Validate against SCHEMA (default "schema.json")
+      --timeout ttl     How long to wait (default 30s)
+      --output string   Output format, one of: json, yaml (default "json")
+  -p, --port int        Port number (env: EXAMPLE_PORT) (default 8080)
+  -q, --quiet           Say less (default true)
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]Confidence{"commands": Inferred}; !reflect.DeepEqual(c.Confidence, want) {
+		t.Errorf("command confidence %v, want %v", c.Confidence, want)
+	}
+	tests := []struct {
+		flag string
+		want map[string]Confidence
+	}{
+		{"schema", map[string]Confidence{"default_value": Guessed}},
+		{"timeout", map[string]Confidence{"default_value": Guessed}},
+		{"output", map[string]Confidence{"choices": Inferred}},
+		{"port", map[string]Confidence{"env": Inferred}},
+		{"quiet", nil},
+	}
+	for _, tt := range tests {
+		f := c.Flags[indexOfFlag(c.Flags, tt.flag)]
+		if !reflect.DeepEqual(f.Confidence, tt.want) {
+			t.Errorf("--%s: confidence %v, want %v", tt.flag, f.Confidence, tt.want)
+		}
+	}
+
+	// Without a usage, the text is Long by position.
+	c, _ = Parse("Environment variables read by example.\n")
+	if want := map[string]Confidence{"long": Inferred}; !reflect.DeepEqual(c.Confidence, want) {
+		t.Errorf("topic confidence %v, want %v", c.Confidence, want)
+	}
+	// Without usage lines, the path is the footer's.
+	c, _ = Parse("Usage:\n\nAvailable Commands:\n  build  Build the project\n\nUse \"example [command] --help\" for more information about a command.\n")
+	if c.Path != "example" || c.Confidence["path"] != Inferred {
+		t.Errorf("path %q, confidence %v; want the footer's, inferred", c.Path, c.Confidence)
+	}
+	if c, _ := Parse(readFixture(t, "cobra/golden/example-build.help")); c.Confidence != nil {
+		t.Errorf("cobra's own help: confidence %v, want all explicit", c.Confidence)
+	}
+}
+
+// TestConfidenceMan checks how surely the types a man page does not state
+// are read: from a usage's backquotes, from the quotes and brackets pflag
+// gives only some types, or from the default.
+func TestConfidenceMan(t *testing.T) {
+	c, err := ParseMan(readFixture(t, "cobra/man/example-convert.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		flag  string
+		value string
+		conf  map[string]Confidence
+	}{
+		{"help", "", map[string]Confidence{"value": Inferred}},
+		{"indent", "N", map[string]Confidence{"default_value": Guessed}},
+		{"log", "FILE", nil},
+		{"to", "value", map[string]Confidence{"value": Guessed, "default_value": Guessed, "choices": Inferred}},
+	}
+	for _, tt := range tests {
+		f := c.Flags[indexOfFlag(c.Flags, tt.flag)]
+		if f.Value != tt.value || !reflect.DeepEqual(f.Confidence, tt.conf) {
+			t.Errorf("--%s: value %q, confidence %v; want %q, %v", tt.flag, f.Value, f.Confidence, tt.value, tt.conf)
+		}
+	}
+	port := c.InheritedFlags[indexOfFlag(c.InheritedFlags, "port")]
+	if port.Value != "int" || port.Confidence["value"] != Guessed || port.Confidence["default_value"] != Guessed {
+		t.Errorf("--port: value %q, confidence %v; want a guessed int", port.Value, port.Confidence)
+	}
+}
+
+func TestConfidenceJSON(t *testing.T) {
+	f := Flag{Name: "jobs", Value: "int", Confidence: map[string]Confidence{"value": Guessed, "env": Inferred}}
+	data, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"name":"jobs","value":"int","usage":"","confidence":{"env":"inferred","value":"guessed"}}`
+	if string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+	var back Flag
+	if err := json.Unmarshal(data, &back); err != nil || !reflect.DeepEqual(back, f) {
+		t.Errorf("round trip = %+v, %v; want %+v", back, err, f)
+	}
+	if err := json.Unmarshal([]byte(`{"confidence":{"value":"certain"}}`), &back); err == nil {
+		t.Error("unknown confidence decoded")
+	}
+}
@@ -0,0 +1,244 @@
+package mosshelp
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"testing"
+	"text/tabwriter"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+// A reporter is told of the mismatches the check functions find: a
+// *testing.T, or a conformance table collecting them.
+type reporter interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// A mismatch is one field of one fixture's parse that differs from the
+// ground truth.
+type mismatch struct {
+	fixture, field, got, want string
+}
+
+// conformance collects the mismatches of every fixture checked, for
+// printing as one table.
+type conformance struct {
+	fixture string
+	rows    []mismatch
+}
+
+func (*conformance) Helper() {}
+
+// Errorf records a check's message as a row, split into the field and the
+// values the checks' "Field = got, want want" messages name.
+func (c *conformance) Errorf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	m := mismatch{fixture: c.fixture, field: msg}
+	if i := strings.LastIndex(msg, ", want "); i >= 0 {
+		m.field, m.want = msg[:i], msg[i+len(", want "):]
+	}
+	if field, got, ok := strings.Cut(m.field, " = "); ok {
+		m.field, m.got = field, got
+	}
+	c.rows = append(c.rows, m)
+}
+
+// table returns the rows aligned under a header, by fixture then field.
+func (c *conformance) table() string {
+	sort.SliceStable(c.rows, func(i, j int) bool { return c.rows[i].fixture < c.rows[j].fixture })
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FIXTURE\tFIELD\tGOT\tWANT")
+	for _, m := range c.rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", m.fixture, m.field, m.got, m.want)
+	}
+	w.Flush()
+	return b.String()
+}
+
+// departures are the variants of the cobra fixture whose help departs from
+// its tree, by the EXAMPLE_VARIANT value or the variable selecting them:
+// they change the tree after it is exported, or print help with templates
+// other than cobra's defaults. Fixtures captured with one are not checked,
+// for the reason given; every other fixture with a tree is.
+var departures = map[string]string{
+	"EXAMPLE_PLUGINS":   "adds the plugins found at startup as commands",
+	"custom-help":       "prints help with a template of its own",
+	"help-renamed":      "renames the help command",
+	"help-replaced":     "replaces the help command",
+	"hidden-completion": "hides the completion command",
+	"localized":         "translates the help",
+	"no-completion":     "removes the completion command",
+	"no-help":           "removes the help command",
+	"unhidden":          "unhides the hidden commands and flags",
+	"usage-func":        "prints usage with a function of its own",
+	"usage-template":    "prints usage with a template of its own",
+	"whitespace":        "puts tabs and trailing spaces in descriptions",
+	"windows":           "sets Windows path defaults",
+}
+
+// TestConformance runs the corpus as a conformance suite: every help
+// fixture with a JSON command tree as ground truth must parse back into the
+// command in it the fixture is the help of, field for field. That is the
+// command it was captured from, or for fixtures written out by the fixture
+// binary itself, the one it is named for. It fails on any such fixture it
+// cannot check, but for those of departures and help topics' pages, and
+// reports all mismatches together, as a table.
+func TestConformance(t *testing.T) {
+	trees := map[string]*treeCommand{}
+	c := &conformance{}
+	checked, skipped := 0, map[string]int{}
+	for _, e := range corpus.All() {
+		if path.Ext(e.Path) != ".help" || e.TruthPath == "" {
+			continue
+		}
+		root, ok := trees[e.TruthPath]
+		if !ok {
+			root = &treeCommand{}
+			if err := json.Unmarshal(e.Truth, root); err != nil {
+				t.Fatalf("%s: %v", e.TruthPath, err)
+			}
+			trees[e.TruthPath] = root
+		}
+		c.fixture = e.Path
+		chain, wrapped, skip := fixtureCommand(e, root)
+		switch {
+		case skip != "":
+			skipped[skip]++
+			continue
+		case chain == nil:
+			c.Errorf("names no single command of %s", e.TruthPath)
+			continue
+		}
+		got, err := Parse(e.Help)
+		if err != nil {
+			c.Errorf("Parse = %v, want a command", err)
+			continue
+		}
+		checkCommand(c, got, chain, wrapped)
+		checked++
+	}
+	if len(c.rows) > 0 {
+		t.Errorf("%d mismatches in %d fixtures checked:\n%s", len(c.rows), checked, c.table())
+	}
+	t.Logf("%d fixtures conform to %d trees", checked, len(trees))
+	for why, n := range skipped {
+		t.Logf("%d fixtures not checked: %s", n, why)
+	}
+}
+
+// fixtureCommand returns the commands from root down to the one the help
+// fixture e is the help of, or nil if it names none or is ambiguous, and
+// whether it was
+// captured with flag descriptions wrapped to the terminal. For a fixture
+// that is not checked against root it returns why instead.
+func fixtureCommand(e corpus.Entry, root *treeCommand) (chain []*treeCommand, wrapped bool, skip string) {
+	inv := e.Invocation
+	if inv == nil {
+		chain = stemCommand(root, strings.TrimSuffix(path.Base(e.Path), ".help"))
+		if chain != nil && chain[len(chain)-1].HelpTopic {
+			return nil, false, "the page of a help topic"
+		}
+		return chain, false, ""
+	}
+	prefix := false
+	for k, v := range inv.Env {
+		if why, ok := departures[k]; ok {
+			return nil, false, why
+		}
+		if k != "EXAMPLE_VARIANT" {
+			continue
+		}
+		for _, variant := range strings.Split(v, ",") {
+			if why, ok := departures[variant]; ok {
+				return nil, false, why
+			}
+			wrapped = wrapped || variant == "wrapped"
+			prefix = prefix || variant == "prefix-matching"
+		}
+	}
+	args, help := inv.Argv[1:], false
+	switch {
+	case len(args) > 0 && args[0] == "help":
+		args, help = args[1:], true
+	case len(args) > 0 && (args[len(args)-1] == "--help" || args[len(args)-1] == "-h"):
+		args = args[:len(args)-1]
+	}
+	chain = []*treeCommand{root}
+	for _, word := range args {
+		next := subcommand(chain[len(chain)-1], word, prefix)
+		if next == nil && help {
+			return nil, false, "the usage printed for an unknown help topic, before the root's help and version flags are added"
+		}
+		if next == nil {
+			return nil, wrapped, ""
+		}
+		chain = append(chain, next)
+	}
+	if chain[len(chain)-1].HelpTopic {
+		return nil, false, "the page of a help topic"
+	}
+	return chain, wrapped, ""
+}
+
+// subcommand returns the subcommand of cmd that word names or aliases, or
+// with prefix set, the only one it is a prefix of the name of, as cobra's
+// EnablePrefixMatching finds it. It returns nil if there is none.
+func subcommand(cmd *treeCommand, word string, prefix bool) *treeCommand {
+	var found *treeCommand
+	for i := range cmd.Commands {
+		sub := &cmd.Commands[i]
+		if sub.Name == word || contains(sub.Aliases, word) {
+			return sub
+		}
+		if prefix && strings.HasPrefix(sub.Name, word) {
+			if found != nil {
+				return nil
+			}
+			found = sub
+		}
+	}
+	return found
+}
+
+// stemCommand returns the commands from root down to the one stem names,
+// the name of a fixture written out by the fixture binary for the help of
+// a command, such as "example-cluster-node-list": its path, or a spelling
+// of it with aliases, joined by hyphens. As names may have hyphens in
+// them, it returns nil if stem could name more than one command, as well
+// as if it names none.
+func stemCommand(root *treeCommand, stem string) []*treeCommand {
+	var found [][]*treeCommand
+	var match func(chain []*treeCommand, rest string)
+	match = func(chain []*treeCommand, rest string) {
+		if rest == "" {
+			found = append(found, chain)
+			return
+		}
+		rest, ok := strings.CutPrefix(rest, "-")
+		if !ok {
+			return
+		}
+		cmd := chain[len(chain)-1]
+		for i := range cmd.Commands {
+			sub := &cmd.Commands[i]
+			for _, name := range append([]string{sub.Name}, sub.Aliases...) {
+				if strings.HasPrefix(rest, name) {
+					match(append(chain[:len(chain):len(chain)], sub), rest[len(name):])
+				}
+			}
+		}
+	}
+	if rest, ok := strings.CutPrefix(stem, root.Name); ok {
+		match([]*treeCommand{root}, rest)
+	}
+	if len(found) != 1 {
+		return nil
+	}
+	return found[0]
+}
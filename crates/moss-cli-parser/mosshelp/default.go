@@ -0,0 +1,200 @@
+package mosshelp
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TypedValue is a flag value as pflag prints it, such as a default, with
+// its meaning: the field Kind names holds it, and the others are zero.
+type TypedValue struct {
+	// Raw is the text as printed, such as `"local"`, "30s" or "[a,b]".
+	Raw  string    `json:"raw"`
+	Kind ValueKind `json:"kind"`
+
+	String   string            `json:"string,omitempty"`
+	Bool     bool              `json:"bool,omitempty"`
+	Int      int64             `json:"int,omitempty"`
+	Float    float64           `json:"float,omitempty"`
+	Duration time.Duration     `json:"duration,omitempty"`
+	List     []string          `json:"list,omitempty"`
+	Map      map[string]string `json:"map,omitempty"`
+}
+
+// ValueKind is the kind of a TypedValue.
+type ValueKind int
+
+const (
+	// StringValue is text: a quoted string, or anything not read as
+	// another kind, such as an IP address or a custom type's value.
+	StringValue ValueKind = iota
+	BoolValue
+	IntValue
+	FloatValue
+	// DurationValue is a time.Duration, such as "1m30s".
+	DurationValue
+	// ListValue is a slice or array flag's items, printed as "[a,b]".
+	ListValue
+	// MapValue is a map flag's entries, printed as "[k=v,...]" by pflag's
+	// stringTo* flags or as "map[k:v ...]" by fmt.
+	MapValue
+)
+
+var valueKinds = [...]string{"string", "bool", "int", "float", "duration", "list", "map"}
+
+func (k ValueKind) String() string {
+	if k >= 0 && int(k) < len(valueKinds) {
+		return valueKinds[k]
+	}
+	return fmt.Sprintf("ValueKind(%d)", int(k))
+}
+
+// typeKinds are the kinds of the values of pflag's flag types, by the
+// placeholder pflag prints for them.
+var typeKinds = map[string]ValueKind{
+	"string": StringValue,
+	"bool":   BoolValue,
+	"int":    IntValue, "int8": IntValue, "int16": IntValue, "int32": IntValue, "int64": IntValue,
+	"uint": IntValue, "uint8": IntValue, "uint16": IntValue, "uint32": IntValue, "uint64": IntValue,
+	"count": IntValue,
+	"float": FloatValue, "float32": FloatValue, "float64": FloatValue,
+	"duration": DurationValue,
+	"strings":  ListValue, "stringArray": ListValue, "ints": ListValue, "uints": ListValue,
+	"int32Slice": ListValue, "int64Slice": ListValue, "float32Slice": ListValue, "float64Slice": ListValue,
+	"bools": ListValue, "durations": ListValue, "ipSlice": ListValue,
+	"stringToString": MapValue, "stringToInt": MapValue, "stringToInt64": MapValue,
+}
+
+// ParseValue interprets raw, a value as pflag prints it, for a flag whose
+// placeholder is typ. For a pflag type's placeholder the value is read as
+// that type; for any other, such as a name from backquotes or a custom
+// type's, its kind is guessed from its syntax. Text that does not read as
+// the kind expected is kept as a StringValue.
+func ParseValue(raw, typ string) TypedValue {
+	v, _ := parseValue(raw, typ)
+	return v
+}
+
+// parseValue is ParseValue, returning too how surely the value was read:
+// Explicit as a pflag type's, else Guessed.
+func parseValue(raw, typ string) (TypedValue, Confidence) {
+	kind, ok := typeKinds[typ]
+	conf := Explicit
+	if !ok {
+		kind, conf = guessKind(raw), Guessed
+		// pflag prints no placeholder for a bool.
+		if typ == "" && kind == BoolValue {
+			conf = Explicit
+		}
+	}
+	v := TypedValue{Raw: raw, Kind: kind}
+	var err error
+	switch kind {
+	case StringValue:
+		v.String = raw
+		if s, err := strconv.Unquote(raw); err == nil && strings.HasPrefix(raw, `"`) {
+			v.String = s
+		}
+	case BoolValue:
+		v.Bool, err = strconv.ParseBool(raw)
+	case IntValue:
+		v.Int, err = strconv.ParseInt(raw, 10, 64)
+	case FloatValue:
+		v.Float, err = strconv.ParseFloat(raw, 64)
+	case DurationValue:
+		v.Duration, err = time.ParseDuration(raw)
+	case ListValue:
+		v.List, err = parseList(raw)
+	case MapValue:
+		v.Map, err = parseMap(raw)
+	}
+	if err != nil {
+		return TypedValue{Raw: raw, Kind: StringValue, String: raw}, Guessed
+	}
+	return v, conf
+}
+
+// guessKind guesses the kind of a value of unknown type from its syntax.
+func guessKind(raw string) ValueKind {
+	switch {
+	case strings.HasPrefix(raw, `"`):
+		return StringValue
+	case raw == "true" || raw == "false":
+		return BoolValue
+	case strings.HasPrefix(raw, "map["):
+		return MapValue
+	case strings.HasPrefix(raw, "["):
+		if items, err := parseList(raw); err == nil && len(items) > 0 && allPairs(items) {
+			return MapValue
+		}
+		return ListValue
+	}
+	if _, err := strconv.ParseInt(raw, 10, 64); err == nil {
+		return IntValue
+	}
+	if _, err := strconv.ParseFloat(raw, 64); err == nil {
+		return FloatValue
+	}
+	if _, err := time.ParseDuration(raw); err == nil {
+		return DurationValue
+	}
+	return StringValue
+}
+
+// parseList reads pflag's "[a,b]", whose items are comma-separated values
+// as encoding/csv writes them, quoted if they hold a comma or quote.
+func parseList(raw string) ([]string, error) {
+	inner, open := strings.CutPrefix(raw, "[")
+	inner, closed := strings.CutSuffix(inner, "]")
+	if !open || !closed {
+		return nil, fmt.Errorf("mosshelp: list %q not in brackets", raw)
+	}
+	if inner == "" {
+		return nil, nil
+	}
+	r := csv.NewReader(strings.NewReader(inner))
+	r.LazyQuotes = true
+	return r.Read()
+}
+
+// parseMap reads pflag's "[k=v,...]" or fmt's "map[k:v ...]".
+func parseMap(raw string) (map[string]string, error) {
+	var pairs []string
+	sep := "="
+	if inner, ok := strings.CutPrefix(raw, "map["); ok {
+		inner, ok = strings.CutSuffix(inner, "]")
+		if !ok {
+			return nil, fmt.Errorf("mosshelp: map %q not in brackets", raw)
+		}
+		pairs, sep = strings.Fields(inner), ":"
+	} else {
+		var err error
+		if pairs, err = parseList(raw); err != nil {
+			return nil, err
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]string, len(pairs))
+	for _, p := range pairs {
+		k, v, ok := strings.Cut(p, sep)
+		if !ok {
+			return nil, fmt.Errorf("mosshelp: map entry %q has no %q", p, sep)
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+func allPairs(items []string) bool {
+	for _, item := range items {
+		if !strings.Contains(item, "=") {
+			return false
+		}
+	}
+	return true
+}
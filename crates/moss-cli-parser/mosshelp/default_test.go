@@ -0,0 +1,90 @@
+package mosshelp
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		raw, typ string
+		want     TypedValue
+	}{
+		{`"local"`, "string", TypedValue{Kind: StringValue, String: "local"}},
+		{`"世界"`, "string", TypedValue{Kind: StringValue, String: "世界"}},
+		{"true", "", TypedValue{Kind: BoolValue, Bool: true}},
+		{"8080", "int", TypedValue{Kind: IntValue, Int: 8080}},
+		{"0.5", "float", TypedValue{Kind: FloatValue, Float: 0.5}},
+		{"1m30s", "duration", TypedValue{Kind: DurationValue, Duration: 90 * time.Second}},
+		{"[go,rust]", "strings", TypedValue{Kind: ListValue, List: []string{"go", "rust"}}},
+		{`["a,b",c]`, "stringArray", TypedValue{Kind: ListValue, List: []string{"a,b", "c"}}},
+		{"[80,443]", "ints", TypedValue{Kind: ListValue, List: []string{"80", "443"}}},
+		{"[owner=core,tier=web]", "stringToString", TypedValue{Kind: MapValue, Map: map[string]string{"owner": "core", "tier": "web"}}},
+		{"map[a:1 b:2]", "labels", TypedValue{Kind: MapValue, Map: map[string]string{"a": "1", "b": "2"}}},
+		// Placeholders from backquotes or custom types name no pflag type.
+		{`"target"`, "DIR", TypedValue{Kind: StringValue, String: "target"}},
+		{"30s", "WAIT", TypedValue{Kind: DurationValue, Duration: 30 * time.Second}},
+		{"4", "N", TypedValue{Kind: IntValue, Int: 4}},
+		{"[a=1]", "pairs", TypedValue{Kind: MapValue, Map: map[string]string{"a": "1"}}},
+		{"127.0.0.1", "ip", TypedValue{Kind: StringValue, String: "127.0.0.1"}},
+		{"1MB", "size", TypedValue{Kind: StringValue, String: "1MB"}},
+		// Text that does not read as its type is kept as it is.
+		{"lots", "int", TypedValue{Kind: StringValue, String: "lots"}},
+		{"[a,b", "strings", TypedValue{Kind: StringValue, String: "[a,b"}},
+		{"[a]", "stringToString", TypedValue{Kind: StringValue, String: "[a]"}},
+	}
+	for _, tt := range tests {
+		tt.want.Raw = tt.raw
+		if got := ParseValue(tt.raw, tt.typ); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseValue(%q, %q) = %+v, want %+v", tt.raw, tt.typ, got, tt.want)
+		}
+	}
+}
+
+// treeKinds are the kinds of the defaults of the flag types in
+// cobra/example.tree.json, by type.
+var treeKinds = map[string]ValueKind{
+	"bool": BoolValue, "count": IntValue, "int": IntValue, "float64": FloatValue,
+	"duration": DurationValue, "string": StringValue,
+	"intSlice": ListValue, "stringSlice": ListValue, "stringArray": ListValue,
+	"stringToString": MapValue,
+}
+
+// checkDefault checks a default parsed from help against the flag it was
+// printed for: its kind, if the type is one pflag prints as its placeholder,
+// and that it formats back as pflag formats the default.
+func checkDefault(t reporter, w treeFlag, v TypedValue, renamed bool) {
+	t.Helper()
+	if kind, ok := treeKinds[w.Type]; ok && !renamed && v.Kind != kind {
+		t.Errorf("--%s: DefaultValue.Kind = %s, want %s for a %s", w.Name, v.Kind, kind, w.Type)
+	}
+	var got string
+	switch v.Kind {
+	case StringValue:
+		got = v.String
+	case BoolValue:
+		got = strconv.FormatBool(v.Bool)
+	case IntValue:
+		got = strconv.FormatInt(v.Int, 10)
+	case FloatValue:
+		got = strconv.FormatFloat(v.Float, 'g', -1, 64)
+	case DurationValue:
+		got = v.Duration.String()
+	case ListValue:
+		got = "[" + strings.Join(v.List, ",") + "]"
+	case MapValue:
+		var pairs []string
+		for k, val := range v.Map {
+			pairs = append(pairs, k+"="+val)
+		}
+		sort.Strings(pairs)
+		got = "[" + strings.Join(pairs, ",") + "]"
+	}
+	if got != w.Default {
+		t.Errorf("--%s: DefaultValue %+v formats as %q, want %q", w.Name, v, got, w.Default)
+	}
+}
@@ -0,0 +1,84 @@
+package mosshelp
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// commandDeprecatedRE matches the warning cobra prints above the help
+	// of a deprecated command, with the message the command was deprecated
+	// with.
+	commandDeprecatedRE = regexp.MustCompile(`^Command "[^"]*" is deprecated, (.*)$`)
+	// flagDeprecatedRE and shorthandDeprecatedRE match the warnings pflag
+	// prints when a deprecated flag, or a flag's deprecated shorthand, is
+	// used, with the flag or shorthand and the message.
+	flagDeprecatedRE      = regexp.MustCompile(`^Flag --(\S+) has been deprecated, (.*)$`)
+	shorthandDeprecatedRE = regexp.MustCompile(`^Flag shorthand -(\S) has been deprecated, (.*)$`)
+	// replacementRE matches the usual ways a deprecation message names what
+	// to use instead: "use --target instead", `use "build" instead`,
+	// "replaced by build", "in favor of --target".
+	replacementRE = regexp.MustCompile("(?i)\\b(?:use|replaced by|superseded by|in favou?r of)\\s+(\"[^\"]+\"|'[^']+'|`[^`]+`|[^\\s,;]+)")
+)
+
+// replacement returns the flag or command a deprecation message points to,
+// unquoted, or "" if it names none.
+func replacement(msg string) string {
+	m := replacementRE.FindStringSubmatch(msg)
+	if m == nil {
+		return ""
+	}
+	name := strings.TrimRight(m[1], ".")
+	if len(name) >= 2 && strings.ContainsRune(`"'`+"`", rune(name[0])) && name[len(name)-1] == name[0] {
+		name = name[1 : len(name)-1]
+	}
+	return name
+}
+
+// splitDeprecated splits the "(DEPRECATED: ...)" pflag appends to the usage
+// of a deprecated flag, after any default, from it.
+func splitDeprecated(usage string) (string, string) {
+	if !strings.HasSuffix(usage, ")") {
+		return usage, ""
+	}
+	i := strings.LastIndex(usage, "(DEPRECATED: ")
+	if i < 0 {
+		return usage, ""
+	}
+	return strings.TrimRight(usage[:i], " \n"), usage[i+len("(DEPRECATED: ") : len(usage)-1]
+}
+
+// MergeDeprecations adds to c, parsed from help, the deprecations pflag
+// warned of in output, what a run of the same command printed: to a flag,
+// the message it was deprecated with, which help shows only for flags not
+// hidden, and to a flag whose shorthand alone is deprecated, that shorthand
+// and its message. Help leaves such a shorthand out, so its flag is the one
+// the message points to, as "use --jobs instead" does; a shorthand whose
+// message names no flag of c is dropped.
+func MergeDeprecations(c *Command, output string) {
+	_, flags := commandFlags(c)
+	for _, line := range strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n") {
+		if m := flagDeprecatedRE.FindStringSubmatch(line); m != nil {
+			if e, ok := flags["--"+m[1]]; ok && e.flag.Deprecated == "" {
+				e.flag.Deprecated, e.flag.ReplacedBy = m[2], replacement(m[2])
+			}
+			continue
+		}
+		m := shorthandDeprecatedRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		var f *Flag
+		for _, e := range flags {
+			if e.flag.Shorthand == m[1] {
+				f = e.flag
+			}
+		}
+		if e, ok := flags[replacement(m[2])]; f == nil && ok && e.flag.Shorthand == "" {
+			f = e.flag
+		}
+		if f != nil {
+			f.Shorthand, f.ShorthandDeprecated = m[1], m[2]
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package mosshelp
+
+import (
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+func TestReplacement(t *testing.T) {
+	tests := []struct{ msg, want string }{
+		{"use --target instead", "--target"},
+		{`use "build" instead`, "build"},
+		{"Use `example deploy` instead.", "example deploy"},
+		{"replaced by --output.", "--output"},
+		{"will be removed in v2; use --jobs, which does more", "--jobs"},
+		{"in favor of 'run'", "run"},
+		{"no longer has any effect", ""},
+		{"because it was confusing", ""},
+	}
+	for _, tt := range tests {
+		if got := replacement(tt.msg); got != tt.want {
+			t.Errorf("replacement(%q) = %q, want %q", tt.msg, got, tt.want)
+		}
+	}
+}
+
+// TestParseDeprecated checks the deprecations of the cobra fixtures that
+// show them: the warning above a deprecated command's help, and a deprecated
+// flag listed once unhidden.
+func TestParseDeprecated(t *testing.T) {
+	tests := []struct {
+		path          string
+		flag          string
+		msg, replaced string
+	}{
+		{"cobra/example-compile.help", "", `use "build" instead`, "build"},
+		{"cobra/example-build-unhidden.help", "out", "use --target instead", "--target"},
+	}
+	for _, tt := range tests {
+		e, ok := corpus.Lookup(tt.path)
+		if !ok {
+			t.Fatalf("%s missing from corpus", tt.path)
+		}
+		c, err := Parse(e.Help)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.path, err)
+		}
+		msg, replaced := c.Deprecated, c.ReplacedBy
+		if tt.flag != "" {
+			msg, replaced = "", ""
+			for _, f := range c.Flags {
+				if f.Name == tt.flag {
+					msg, replaced = f.Deprecated, f.ReplacedBy
+					if f.Usage != "Output directory" {
+						t.Errorf("%s: --%s: Usage = %q, want the deprecation removed", tt.path, tt.flag, f.Usage)
+					}
+				}
+			}
+		} else if c.Long != "Compile the project" {
+			t.Errorf("%s: Long = %q, want the warning removed", tt.path, c.Long)
+		}
+		if msg != tt.msg || replaced != tt.replaced {
+			t.Errorf("%s: deprecated %q, replaced by %q; want %q, %q", tt.path, msg, replaced, tt.msg, tt.replaced)
+		}
+	}
+}
+
+// TestMergeDeprecations reads the warnings pflag printed for deprecated
+// flags and shorthands used into the help of their commands, per form: a
+// deprecated shorthand leaves its long form as it was.
+func TestMergeDeprecations(t *testing.T) {
+	tests := []struct {
+		help, run       string
+		flag, shorthand string
+		msg, shortMsg   string
+	}{
+		{"cobra/example-build.help", "cobra/example-build-shorthand-deprecated.out", "jobs", "j", "", "use --jobs instead"},
+		{"cobra/example-clean.help", "cobra/example-clean-shorthand-deprecated.out", "force", "f", "", "use --force instead"},
+		{"cobra/example-build-unhidden.help", "cobra/example-build-deprecated.out", "out", "", "use --target instead", ""},
+		{"cobra/example-build-unhidden.help", "cobra/example-build-deprecated.out", "jobs", "j", "", "use --jobs instead"},
+	}
+	for _, tt := range tests {
+		c := parseFixture(t, tt.help)
+		e, ok := corpus.Lookup(tt.run)
+		if !ok {
+			t.Fatalf("%s missing from corpus", tt.run)
+		}
+		MergeDeprecations(c, e.Stderr)
+		f := c.Flags[indexOfFlag(c.Flags, tt.flag)]
+		if f.Shorthand != tt.shorthand || f.Deprecated != tt.msg || f.ShorthandDeprecated != tt.shortMsg {
+			t.Errorf("%s with %s: --%s shorthand %q deprecated %q, shorthand deprecated %q; want %q, %q, %q",
+				tt.help, tt.run, tt.flag, f.Shorthand, f.Deprecated, f.ShorthandDeprecated, tt.shorthand, tt.msg, tt.shortMsg)
+		}
+	}
+
+	// The shorthand the tree records as deprecated is the one merged.
+	build := parseFixture(t, "cobra/example-build.help")
+	e, _ := corpus.Lookup("cobra/example-build-shorthand-deprecated.out")
+	MergeDeprecations(build, e.Stderr)
+	for _, w := range lookup(exampleTree(t), []string{"build"})[1].Flags {
+		if w.ShorthandDeprecated == "" {
+			continue
+		}
+		f := build.Flags[indexOfFlag(build.Flags, w.Name)]
+		if f.Shorthand != w.Shorthand || f.ShorthandDeprecated != w.ShorthandDeprecated {
+			t.Errorf("--%s: shorthand -%s deprecated %q, tree has -%s %q", w.Name, f.Shorthand, f.ShorthandDeprecated, w.Shorthand, w.ShorthandDeprecated)
+		}
+	}
+
+	// A shorthand whose message names no flag cannot be placed.
+	MergeDeprecations(build, "Flag shorthand -x has been deprecated, it is no longer supported\n")
+	for _, f := range build.Flags {
+		if f.Shorthand == "x" {
+			t.Errorf("-x merged into --%s", f.Name)
+		}
+	}
+}
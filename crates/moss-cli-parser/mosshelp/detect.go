@@ -0,0 +1,127 @@
+package mosshelp
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Framework is a Go CLI framework whose help Detect recognizes, named as
+// its fixture directory is.
+type Framework string
+
+const (
+	Unknown Framework = ""
+	Cobra   Framework = "cobra"
+	Urfave  Framework = "urfave"
+	Kong    Framework = "kong"
+	Kingpin Framework = "kingpin"
+	// StdFlag is the standard library's flag package.
+	StdFlag Framework = "flag"
+)
+
+// minConfidence is the score a framework needs for Detect to name it.
+const minConfidence = 0.5
+
+// A marker is a trait of one framework's help, and how much finding it
+// counts towards the framework or, with a negative weight, against it.
+type marker struct {
+	re     *regexp.Regexp
+	weight float64
+}
+
+// markers are each framework's traits, from the help its default templates
+// print. No one trait decides: most are shared by a second framework, or
+// missing from some of a framework's pages.
+var markers = map[Framework][]marker{
+	Cobra: {
+		{regexp.MustCompile(`(?m)^Usage:$`), 0.4},
+		{regexp.MustCompile(`(?m)^Flags:$`), 0.1},
+		{regexp.MustCompile(`(?m)^  -h, --help {3,}help for \S+$`), 0.3},
+		{regexp.MustCompile(`(?m)^Available Commands:$`), 0.3},
+		{regexp.MustCompile(`(?m)^Global Flags:$`), 0.1},
+		{regexp.MustCompile(`(?m)^Use ".+ \[command\] --help" for more information about a command\.$`), 0.3},
+		{regexp.MustCompile(`(?m)^(?:Options|OPTIONS):$`), -0.3},
+	},
+	Urfave: {
+		{regexp.MustCompile(`(?m)^NAME:$`), 0.3},
+		{regexp.MustCompile(`(?m)^USAGE:$`), 0.3},
+		{regexp.MustCompile(`(?m)^COMMANDS:$`), 0.2},
+		{regexp.MustCompile(`(?m)^(?:GLOBAL )?OPTIONS:$`), 0.2},
+		{regexp.MustCompile(`(?m)^   --help, -h +show help$`), 0.2},
+		{regexp.MustCompile(`\(default: `), 0.1},
+		{regexp.MustCompile(`\[\$\w+\]`), 0.1},
+	},
+	Kong: {
+		{regexp.MustCompile(`(?m)^Usage: \S`), 0.3},
+		{regexp.MustCompile(`(?m)^  -h, --help +Show\s+context-sensitive\s+help\.$`), 0.4},
+		{regexp.MustCompile(`(?m)^Run ".+ --help" for more information on a command\.$`), 0.2},
+		{regexp.MustCompile(`(?m)^  (?:-\S, |    )--[\w-]+=\S`), 0.1},
+		{regexp.MustCompile(`\(\$\w+\)\.$`), 0.1},
+		{regexp.MustCompile(`--\[no-\]`), -0.5},
+		{regexp.MustCompile(`(?m)^(?:Options|OPTIONS):$`), -0.3},
+	},
+	Kingpin: {
+		{regexp.MustCompile(`(?m)^usage: \S`), 0.3},
+		{regexp.MustCompile(`--\[no-\]help`), 0.4},
+		{regexp.MustCompile(`context-sensitive\s+help\s+\(also\s+try\s+--help-long`), 0.3},
+		{regexp.MustCompile(`\[<flags>\]`), 0.2},
+		{regexp.MustCompile(`(?m)^Args:$`), 0.1},
+		{regexp.MustCompile(`(?m)^(?:options|optional arguments|Options):$`), -0.3},
+	},
+	StdFlag: {
+		{regexp.MustCompile(`(?m)^Usage of \S+:$`), 0.5},
+		// flag indents a description by four spaces and a tab.
+		{regexp.MustCompile(`(?m)^    \t\S`), 0.5},
+		{regexp.MustCompile(`(?m)^  -[\w-]+\t\S`), 0.2},
+		{regexp.MustCompile(`(?m)^ +--\w`), -0.4},
+	},
+}
+
+// Detect guesses which framework printed help, so the parser for its
+// dialect can be chosen, and how confident the guess is, from 0 to 1. If no
+// framework scores at least 0.5, it returns Unknown with the best score.
+func Detect(help string) (Framework, float64) {
+	scores := Scores(help)
+	best, score := Unknown, 0.0
+	for _, fw := range frameworks() {
+		if scores[fw] > score {
+			best, score = fw, scores[fw]
+		}
+	}
+	if score < minConfidence {
+		return Unknown, score
+	}
+	return best, score
+}
+
+// Scores returns every framework's score for help, from 0 to 1.
+func Scores(help string) map[Framework]float64 {
+	help = strings.ReplaceAll(ansiRE.ReplaceAllString(help, ""), "\r\n", "\n")
+	scores := make(map[Framework]float64, len(markers))
+	for fw, ms := range markers {
+		score := 0.0
+		for _, m := range ms {
+			if m.re.MatchString(help) {
+				score += m.weight
+			}
+		}
+		scores[fw] = min(max(score, 0), 1)
+	}
+	return scores
+}
+
+// ansiRE matches the escape sequences that color help printed to a
+// terminal.
+var ansiRE = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// frameworks returns the frameworks with markers, sorted, so that ties
+// are broken the same way every time.
+func frameworks() []Framework {
+	fws := make([]Framework, 0, len(markers))
+	for fw := range markers {
+		fws = append(fws, fw)
+	}
+	sort.Slice(fws, func(i, j int) bool { return fws[i] < fws[j] })
+	return fws
+}
@@ -0,0 +1,72 @@
+package mosshelp
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+// detectDirs are the fixture directories of the frameworks Detect knows.
+var detectDirs = map[string]Framework{
+	"cobra":          Cobra,
+	"cobra-flat":     Cobra,
+	"cobra-platform": Cobra,
+	"urfave-v2":      Urfave,
+	"urfave-v3":      Urfave,
+	"kong":           Kong,
+	"kingpin":        Kingpin,
+	"flag":           StdFlag,
+}
+
+// TestDetectCorpus checks that no help fixture of a known framework is
+// taken for another one's, and that each framework's root help, printed by
+// its default template, is recognized.
+func TestDetectCorpus(t *testing.T) {
+	for _, e := range corpus.All() {
+		if path.Ext(e.Path) != ".help" {
+			continue
+		}
+		want, ok := detectDirs[e.Framework]
+		fw, score := Detect(e.Help)
+		switch {
+		case !ok:
+		case fw != Unknown && fw != want:
+			t.Errorf("%s: detected as %s (%.2f), want %s", e.Path, fw, score, want)
+		case fw == Unknown && e.Path == e.Framework+"/example.help":
+			t.Errorf("%s: not detected (best score %.2f), want %s", e.Path, score, want)
+		}
+	}
+}
+
+// TestDetectOtherLanguages checks that help from frameworks in other
+// languages is not taken for a Go framework's.
+func TestDetectOtherLanguages(t *testing.T) {
+	for _, dir := range []string{"argparse", "clap", "click", "commander", "docopt", "yargs"} {
+		for _, e := range corpus.ByFramework(dir) {
+			if path.Ext(e.Path) != ".help" {
+				continue
+			}
+			if fw, score := Detect(e.Help); fw != Unknown {
+				t.Errorf("%s: detected as %s (%.2f)", e.Path, fw, score)
+			}
+		}
+	}
+}
+
+func TestDetectNormalizes(t *testing.T) {
+	e, ok := corpus.Lookup("cobra/example-build.help")
+	if !ok {
+		t.Fatal("cobra/example-build.help missing from corpus")
+	}
+	want, score := Detect(e.Help)
+	for _, help := range []string{
+		strings.ReplaceAll(e.Help, "\n", "\r\n"),
+		strings.ReplaceAll(e.Help, "Usage:", "\x1b[1mUsage:\x1b[0m"),
+	} {
+		if fw, s := Detect(help); fw != want || s != score {
+			t.Errorf("Detect = %s, %.2f, want %s, %.2f", fw, s, want, score)
+		}
+	}
+}
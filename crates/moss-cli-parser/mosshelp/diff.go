@@ -0,0 +1,406 @@
+package mosshelp
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// ChangeKind is what happened to the thing a Change is about.
+type ChangeKind int
+
+const (
+	AddedChange ChangeKind = iota
+	RemovedChange
+	ModifiedChange
+)
+
+var changeKinds = [...]string{"added", "removed", "modified"}
+
+func (k ChangeKind) String() string {
+	if k >= 0 && int(k) < len(changeKinds) {
+		return changeKinds[k]
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Severity is what a Change means for those who use the CLI. Severities are
+// ordered, so that c.Severity >= Additive selects what a changelog lists.
+type Severity int
+
+const (
+	// Informational changes only document differently, such as a reworded
+	// usage or a new deprecation: whatever ran before runs the same.
+	Informational Severity = iota
+	// Additive changes let more command lines run, or run differently only
+	// when asked to, such as a new flag or a new accepted value.
+	Additive
+	// Breaking changes may make a command line that ran before fail or do
+	// something else, such as a removed flag or a changed default.
+	Breaking
+)
+
+var severities = [...]string{"informational", "additive", "breaking"}
+
+func (s Severity) String() string {
+	if s >= 0 && int(s) < len(severities) {
+		return severities[s]
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// Change is one difference Diff found between two versions of a command
+// tree.
+type Change struct {
+	Kind     ChangeKind `json:"kind"`
+	Severity Severity   `json:"severity"`
+	// Command is the path of the command changed, in the new tree unless
+	// the command was removed.
+	Command string `json:"command"`
+	// Flag is the flag changed, as "--name", or "-x" for a flag with only a
+	// shorthand; empty for a change to the command itself.
+	Flag string `json:"flag,omitempty"`
+	// Field is the JSON name of what changed, such as "default" or
+	// "aliases"; empty when a whole command or flag was added or removed.
+	Field string `json:"field,omitempty"`
+	// Old and New are the field's values before and after, a list's items
+	// joined by ", "; empty for one it did not have.
+	Old string `json:"old,omitempty"`
+	New string `json:"new,omitempty"`
+}
+
+// String returns the change as one line of a changelog, such as
+// `example build --jobs: default changed from "4" to "8" (breaking)`.
+func (c Change) String() string {
+	subject := c.Command
+	if c.Flag != "" {
+		subject += " " + c.Flag
+	}
+	var what string
+	switch {
+	case c.Field == "" || c.Field == "help_topic":
+		noun := "command"
+		if c.Flag != "" {
+			noun = "flag"
+		} else if c.Field != "" {
+			noun = "help topic"
+		}
+		what = noun + " " + c.Kind.String()
+	case c.Kind == AddedChange:
+		what = fmt.Sprintf("%s set to %q", c.Field, c.New)
+	case c.Kind == RemovedChange:
+		what = fmt.Sprintf("%s %q removed", c.Field, c.Old)
+	default:
+		what = fmt.Sprintf("%s changed from %q to %q", c.Field, c.Old, c.New)
+	}
+	return fmt.Sprintf("%s: %s (%s)", subject, what, c.Severity)
+}
+
+// Diff returns the changes from the command tree old to new, such as two
+// versions of a binary's as Discover assembles them: the commands and the
+// flags added, removed and modified, with what each means for those using
+// the CLI. Commands are matched by name, or by an alias for one renamed,
+// and flags by long name, or shorthand for those without one.
+//
+// Diff compares what help shows, so both trees should be expanded alike: a
+// command only listed in one has no flags to compare. Cobra does not list
+// deprecated commands, so one newly deprecated shows as removed. A flag one
+// of the commands inherits is compared where it is defined, unless that is
+// above the commands Diff is given; Sections are not compared.
+//
+// Changes come in the order of the trees, a command's own before those to
+// its flags and those to its subcommands, and a removed command or flag
+// before those added.
+func Diff(old, new *Command) []Change {
+	var d differ
+	d.command(old, new, true)
+	return d.changes
+}
+
+// differ collects the changes of one Diff call.
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) add(c Change) {
+	d.changes = append(d.changes, c)
+}
+
+// command compares two versions of a command; top is set for those Diff
+// was given.
+func (d *differ) command(old, new *Command, top bool) {
+	path := new.Path
+	if old.Name != new.Name {
+		d.field(path, "", "name", old.Name, new.Name, Informational)
+	}
+	d.field(path, "", "short", old.Short, new.Short, Informational)
+	d.field(path, "", "long", old.Long, new.Long, Informational)
+	d.field(path, "", "examples", old.Examples, new.Examples, Informational)
+	d.field(path, "", "deprecated", old.Deprecated, new.Deprecated, Informational)
+	d.list(path, "", "aliases", old.Aliases, new.Aliases, new.Name)
+	d.args(path, old.Args, new.Args)
+	d.flags(path, old, new, top)
+
+	match, matched := matchCommands(old.Commands, new.Commands)
+	for i, j := range match {
+		if j < 0 {
+			d.add(Change{Kind: RemovedChange, Severity: Breaking, Command: old.Commands[i].Path})
+			continue
+		}
+		d.command(&old.Commands[i], &new.Commands[j], false)
+	}
+	for j := range new.Commands {
+		if !matched[j] {
+			d.add(Change{Kind: AddedChange, Severity: Additive, Command: new.Commands[j].Path})
+		}
+	}
+
+	match, matched = matchCommands(old.HelpTopics, new.HelpTopics)
+	for i, j := range match {
+		if j < 0 {
+			d.add(Change{Kind: RemovedChange, Severity: Informational, Command: old.HelpTopics[i].Path, Field: "help_topic"})
+		}
+	}
+	for j := range new.HelpTopics {
+		if !matched[j] {
+			d.add(Change{Kind: AddedChange, Severity: Informational, Command: new.HelpTopics[j].Path, Field: "help_topic"})
+		}
+	}
+}
+
+// matchCommands returns, for each command in old, the index of the command
+// in new that it is, or -1 if none is, and which of new were matched. A
+// command is matched by name, or failing that by an alias if it was renamed
+// keeping the old name working; names are matched first, and each command
+// in new matches one in old at most, so that one that takes over another's
+// name is not taken for both.
+func matchCommands(old, new []Command) ([]int, map[int]bool) {
+	match, matched := make([]int, len(old)), map[int]bool{}
+	for i := range match {
+		match[i] = -1
+	}
+	for _, is := range []func(c *Command, name string) bool{
+		func(c *Command, name string) bool { return c.Name == name },
+		func(c *Command, name string) bool { return slices.Contains(c.Aliases, name) },
+	} {
+		for i := range old {
+			for j := range new {
+				if match[i] < 0 && !matched[j] && is(&new[j], old[i].Name) {
+					match[i], matched[j] = j, true
+				}
+			}
+		}
+	}
+	return match, matched
+}
+
+// flagEntry is a flag a command takes, and whether it inherits it.
+type flagEntry struct {
+	flag      *Flag
+	inherited bool
+}
+
+// commandFlags returns the keys of the flags c takes, in order, and the
+// flags by key.
+func commandFlags(c *Command) ([]string, map[string]flagEntry) {
+	var keys []string
+	flags := map[string]flagEntry{}
+	for _, list := range []struct {
+		flags     []Flag
+		inherited bool
+	}{{c.Flags, false}, {c.InheritedFlags, true}} {
+		for i := range list.flags {
+			f := &list.flags[i]
+			key := flagKey(f)
+			if _, ok := flags[key]; ok {
+				continue
+			}
+			keys = append(keys, key)
+			flags[key] = flagEntry{f, list.inherited}
+		}
+	}
+	return keys, flags
+}
+
+// flagKey returns how a Change names f.
+func flagKey(f *Flag) string {
+	if f.Name == "" {
+		return "-" + f.Shorthand
+	}
+	return "--" + f.Name
+}
+
+// flags compares the flags two versions of a command take. Below the top,
+// those inherited in each version that has them are left to the ancestor
+// defining them.
+func (d *differ) flags(path string, old, new *Command, top bool) {
+	oldKeys, oldFlags := commandFlags(old)
+	newKeys, newFlags := commandFlags(new)
+	elsewhere := func(key string) bool {
+		o, inOld := oldFlags[key]
+		n, inNew := newFlags[key]
+		return !top && (!inOld || o.inherited) && (!inNew || n.inherited)
+	}
+	for _, key := range oldKeys {
+		if elsewhere(key) {
+			continue
+		}
+		n, ok := newFlags[key]
+		if !ok {
+			d.add(Change{Kind: RemovedChange, Severity: Breaking, Command: path, Flag: key})
+			continue
+		}
+		d.flag(path, key, oldFlags[key].flag, n.flag)
+	}
+	for _, key := range newKeys {
+		if _, ok := oldFlags[key]; !ok && !elsewhere(key) {
+			d.add(Change{Kind: AddedChange, Severity: Additive, Command: path, Flag: key})
+		}
+	}
+}
+
+// flag compares two versions of a flag.
+func (d *differ) flag(path, key string, old, new *Flag) {
+	d.field(path, key, "shorthand", old.Shorthand, new.Shorthand, severityIf(old.Shorthand != "", Breaking, Additive))
+	if old.Value != new.Value {
+		d.field(path, key, "value", old.Value, new.Value, valueSeverity(old.Value, new.Value))
+	}
+	d.field(path, key, "no_opt_default", old.NoOptDefault, new.NoOptDefault, severityIf(old.NoOptDefault != "", Breaking, Additive))
+	d.field(path, key, "default", old.Default, new.Default, Breaking)
+	d.list(path, key, "choices", old.Choices, new.Choices)
+	d.list(path, key, "env", old.Env, new.Env)
+	d.field(path, key, "usage", old.Usage, new.Usage, Informational)
+	d.field(path, key, "deprecated", old.Deprecated, new.Deprecated, Informational)
+}
+
+// valueSeverity classifies a change of a flag's value placeholder: a flag
+// that stops or starts taking a value, or takes one of another kind, breaks
+// command lines; a renamed placeholder, or one pflag does not name, may not.
+func valueSeverity(old, new string) Severity {
+	if (old == "") != (new == "") {
+		return Breaking
+	}
+	oldKind, oldKnown := typeKinds[old]
+	newKind, newKnown := typeKinds[new]
+	if oldKnown && newKnown && oldKind != newKind {
+		return Breaking
+	}
+	return Informational
+}
+
+// field records a change of a text field, if old and new differ.
+func (d *differ) field(path, flag, field, old, new string, severity Severity) {
+	if old == new {
+		return
+	}
+	kind := ModifiedChange
+	switch {
+	case old == "":
+		kind = AddedChange
+	case new == "":
+		kind = RemovedChange
+	}
+	d.add(Change{Kind: kind, Severity: severity, Command: path, Flag: flag, Field: field, Old: old, New: new})
+}
+
+// list records a change of a list field whose items are what is accepted,
+// if old and new hold different items: dropping any not still accepted
+// otherwise breaks command lines, else more are accepted. A change of order
+// only is none.
+func (d *differ) list(path, flag, field string, old, new []string, still ...string) {
+	removed := !subset(old, append(still, new...))
+	if !removed && subset(new, old) {
+		return
+	}
+	d.field(path, flag, field, strings.Join(old, ", "), strings.Join(new, ", "), severityIf(removed, Breaking, Additive))
+}
+
+// args records a change of a command's positional arguments. Requiring
+// more, accepting fewer, or restricting the choices of one breaks command
+// lines; otherwise, a change of shape lets more run, and one of names only
+// documents them differently.
+func (d *differ) args(path string, old, new []Arg) {
+	oldText, newText := argsText(old), argsText(new)
+	if oldText == newText {
+		return
+	}
+	oldMin, oldMax := argCounts(old)
+	newMin, newMax := argCounts(new)
+	severity := Informational
+	switch {
+	case newMin > oldMin || newMax < oldMax || restricted(old, new):
+		severity = Breaking
+	case newMin != oldMin || newMax != oldMax || !sameShape(old, new):
+		severity = Additive
+	}
+	d.field(path, "", "args", oldText, newText, severity)
+}
+
+func argsText(args []Arg) string {
+	words := make([]string, len(args))
+	for i, a := range args {
+		text, _ := a.MarshalText()
+		words[i] = string(text)
+	}
+	return strings.Join(words, " ")
+}
+
+// argCounts returns the fewest and the most arguments args accept, the
+// most being math.MaxInt if one is repeated.
+func argCounts(args []Arg) (fewest, most int) {
+	for _, a := range args {
+		if !a.Optional {
+			fewest++
+		}
+		if a.Repeated {
+			most = math.MaxInt
+		} else if most < math.MaxInt {
+			most++
+		}
+	}
+	return fewest, most
+}
+
+// restricted reports whether an argument in new accepts fewer words than
+// the one in its place in old.
+func restricted(old, new []Arg) bool {
+	for i := range old[:min(len(old), len(new))] {
+		if new[i].Choices != nil && (old[i].Choices == nil || !subset(old[i].Choices, new[i].Choices)) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameShape reports whether old and new differ only in their arguments'
+// names.
+func sameShape(old, new []Arg) bool {
+	if len(old) != len(new) {
+		return false
+	}
+	for i := range old {
+		o, n := old[i], new[i]
+		if o.Optional != n.Optional || o.Repeated != n.Repeated || o.Passthrough != n.Passthrough || (o.Choices == nil) != (n.Choices == nil) {
+			return false
+		}
+	}
+	return true
+}
+
+// subset reports whether every item of a is in b.
+func subset(a, b []string) bool {
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			return false
+		}
+	}
+	return true
+}
+
+func severityIf(cond bool, yes, no Severity) Severity {
+	if cond {
+		return yes
+	}
+	return no
+}
@@ -0,0 +1,304 @@
+package mosshelp
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+// parseFixture parses the help of a corpus fixture afresh, so that tests
+// can modify it.
+func parseFixture(t *testing.T, path string) *Command {
+	t.Helper()
+	e, ok := corpus.Lookup(path)
+	if !ok {
+		t.Fatalf("%s missing from corpus", path)
+	}
+	c, err := Parse(e.Help)
+	if err != nil {
+		t.Fatalf("%s: %v", path, err)
+	}
+	return c
+}
+
+// TestDiffCorpusVersions diffs the help the fixture binary prints built
+// against each cobra version with that of the default build, which must be
+// the same, and each fixture with itself.
+func TestDiffCorpusVersions(t *testing.T) {
+	versioned := 0
+	for _, e := range corpus.ByFramework("cobra") {
+		c, err := Parse(e.Help)
+		if err != nil {
+			continue
+		}
+		if changes := Diff(c, c); len(changes) > 0 {
+			t.Errorf("%s: diff with itself: %v", e.Path, changes)
+		}
+		_, rest, ok := strings.Cut(e.Path, "/versions/")
+		if !ok {
+			continue
+		}
+		_, base, _ := strings.Cut(rest, "/")
+		// The versioned fixtures are each command's --help; a default one
+		// of the same name may have been captured otherwise.
+		if b, ok := corpus.Lookup("cobra/" + base); !ok || b.Invocation != nil && b.Invocation.Argv[len(b.Invocation.Argv)-1] != "--help" {
+			continue
+		}
+		versioned++
+		if changes := Diff(c, parseFixture(t, "cobra/"+base)); len(changes) > 0 {
+			t.Errorf("%s: differs from cobra/%s: %v", e.Path, base, changes)
+		}
+	}
+	if versioned == 0 {
+		t.Fatal("no versioned cobra fixtures in corpus")
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name   string
+		page   string
+		modify func(c *Command)
+		want   []Change
+	}{{
+		name: "flag removed and added",
+		page: "cobra/example-build.help",
+		modify: func(c *Command) {
+			c.Flags[indexOfFlag(c.Flags, "release")].Name = "optimize"
+		},
+		want: []Change{
+			{Kind: RemovedChange, Severity: Breaking, Command: "example build", Flag: "--release"},
+			{Kind: AddedChange, Severity: Additive, Command: "example build", Flag: "--optimize"},
+		},
+	}, {
+		name: "default changed",
+		page: "cobra/example-build.help",
+		modify: func(c *Command) {
+			c.Flags[indexOfFlag(c.Flags, "cache")].Default = "remote"
+			c.Flags[indexOfFlag(c.Flags, "jobs")].Default = "4"
+		},
+		want: []Change{
+			{Kind: ModifiedChange, Severity: Breaking, Command: "example build", Flag: "--cache", Field: "default", Old: "local", New: "remote"},
+			{Kind: AddedChange, Severity: Breaking, Command: "example build", Flag: "--jobs", Field: "default", New: "4"},
+		},
+	}, {
+		name: "shorthand",
+		page: "cobra/example-build.help",
+		modify: func(c *Command) {
+			c.Flags[indexOfFlag(c.Flags, "release")].Shorthand = ""
+			c.Flags[indexOfFlag(c.Flags, "jobs")].Shorthand = "j"
+		},
+		want: []Change{
+			{Kind: AddedChange, Severity: Additive, Command: "example build", Flag: "--jobs", Field: "shorthand", New: "j"},
+			{Kind: RemovedChange, Severity: Breaking, Command: "example build", Flag: "--release", Field: "shorthand", Old: "r"},
+		},
+	}, {
+		name: "value",
+		page: "cobra/example-build.help",
+		modify: func(c *Command) {
+			c.Flags[indexOfFlag(c.Flags, "jobs")].Value = "int32"
+			c.Flags[indexOfFlag(c.Flags, "release")].Value = "string"
+			c.Flags[indexOfFlag(c.Flags, "target")].Value = "dir"
+		},
+		want: []Change{
+			{Kind: ModifiedChange, Severity: Informational, Command: "example build", Flag: "--jobs", Field: "value", Old: "int", New: "int32"},
+			{Kind: AddedChange, Severity: Breaking, Command: "example build", Flag: "--release", Field: "value", New: "string"},
+			{Kind: ModifiedChange, Severity: Informational, Command: "example build", Flag: "--target", Field: "value", Old: "string", New: "dir"},
+		},
+	}, {
+		name: "choices",
+		page: "cobra/example-convert.help",
+		modify: func(c *Command) {
+			to := &c.Flags[indexOfFlag(c.Flags, "to")]
+			to.Choices = []string{"toml", "json", "yaml", "ini"}
+		},
+		want: []Change{
+			{Kind: ModifiedChange, Severity: Additive, Command: "example convert", Flag: "--to", Field: "choices", Old: "json, yaml, toml", New: "toml, json, yaml, ini"},
+		},
+	}, {
+		name: "choice removed",
+		page: "cobra/example-convert.help",
+		modify: func(c *Command) {
+			to := &c.Flags[indexOfFlag(c.Flags, "to")]
+			to.Choices = []string{"json", "yaml", "ini"}
+		},
+		want: []Change{
+			{Kind: ModifiedChange, Severity: Breaking, Command: "example convert", Flag: "--to", Field: "choices", Old: "json, yaml, toml", New: "json, yaml, ini"},
+		},
+	}, {
+		name: "flag deprecated",
+		page: "cobra/example-build.help",
+		modify: func(c *Command) {
+			c.Flags[indexOfFlag(c.Flags, "target")].Deprecated = "use --out instead"
+		},
+		want: []Change{
+			{Kind: AddedChange, Severity: Informational, Command: "example build", Flag: "--target", Field: "deprecated", New: "use --out instead"},
+		},
+	}, {
+		name: "aliases",
+		page: "cobra/example-build.help",
+		modify: func(c *Command) {
+			c.Aliases = []string{"make", "b"}
+		},
+	}, {
+		name: "alias removed",
+		page: "cobra/example-build.help",
+		modify: func(c *Command) {
+			c.Aliases = []string{"b", "compile"}
+		},
+		want: []Change{
+			{Kind: ModifiedChange, Severity: Breaking, Command: "example build", Field: "aliases", Old: "b, make", New: "b, compile"},
+		},
+	}, {
+		name: "inherited flag on its own",
+		page: "cobra/example-build.help",
+		modify: func(c *Command) {
+			c.InheritedFlags[indexOfFlag(c.InheritedFlags, "port")].Default = "9090"
+		},
+		want: []Change{
+			{Kind: ModifiedChange, Severity: Breaking, Command: "example build", Flag: "--port", Field: "default", Old: "8080", New: "9090"},
+		},
+	}, {
+		name: "args optional arg added",
+		page: "cobra/example-convert.help",
+		modify: func(c *Command) {
+			c.Args = append(c.Args[:1:1], Arg{Name: "format", Optional: true}, c.Args[1])
+		},
+		want: []Change{
+			{Kind: ModifiedChange, Severity: Additive, Command: "example convert", Field: "args", Old: "<input> [output...]", New: "<input> [format] [output...]"},
+		},
+	}, {
+		name: "args required arg added",
+		page: "cobra/example-convert.help",
+		modify: func(c *Command) {
+			c.Args = append(c.Args[:1:1], Arg{Name: "format"}, c.Args[1])
+		},
+		want: []Change{
+			{Kind: ModifiedChange, Severity: Breaking, Command: "example convert", Field: "args", Old: "<input> [output...]", New: "<input> <format> [output...]"},
+		},
+	}, {
+		name: "args no longer repeated",
+		page: "cobra/example-convert.help",
+		modify: func(c *Command) {
+			c.Args[1].Repeated = false
+		},
+		want: []Change{
+			{Kind: ModifiedChange, Severity: Breaking, Command: "example convert", Field: "args", Old: "<input> [output...]", New: "<input> [output]"},
+		},
+	}, {
+		name: "args renamed",
+		page: "cobra/example-convert.help",
+		modify: func(c *Command) {
+			c.Args[0].Name = "file"
+		},
+		want: []Change{
+			{Kind: ModifiedChange, Severity: Informational, Command: "example convert", Field: "args", Old: "<input> [output...]", New: "<file> [output...]"},
+		},
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			old, new := parseFixture(t, tt.page), parseFixture(t, tt.page)
+			tt.modify(new)
+			if got := Diff(old, new); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff =\n%v\nwant\n%v", got, tt.want)
+			}
+		})
+	}
+}
+
+// exampleRoot returns the root page with the build command expanded, as
+// Discover would.
+func exampleRoot(t *testing.T) *Command {
+	root := parseFixture(t, "cobra/example.help")
+	build := &root.Commands[indexOfCommand(root.Commands, "build")]
+	page := parseFixture(t, "cobra/example-build.help")
+	page.Schema, page.Short, page.Group = "", build.Short, build.Group
+	*build = *page
+	return root
+}
+
+func TestDiffTree(t *testing.T) {
+	old, new := exampleRoot(t), exampleRoot(t)
+	// A persistent flag changed on the root shows in each subcommand too.
+	new.Flags[indexOfFlag(new.Flags, "port")].Default = "9090"
+	build := &new.Commands[indexOfCommand(new.Commands, "build")]
+	build.InheritedFlags[indexOfFlag(build.InheritedFlags, "port")].Default = "9090"
+	// Renamed, keeping the old name as an alias.
+	build.Name, build.Path, build.Aliases = "make", "example make", []string{"build", "b"}
+	new.Commands = append(new.Commands[:indexOfCommand(new.Commands, "clean")], new.Commands[indexOfCommand(new.Commands, "clean")+1:]...)
+	new.Commands = append(new.Commands, Command{Path: "example test", Name: "test", Short: "Run the tests"})
+	new.HelpTopics = new.HelpTopics[:1]
+
+	want := []string{
+		`example --port: default changed from "8080" to "9090" (breaking)`,
+		`example make: name changed from "build" to "make" (informational)`,
+		`example make: aliases changed from "b, make" to "build, b" (additive)`,
+		`example clean: command removed (breaking)`,
+		`example test: command added (additive)`,
+		`example exit-codes: help topic removed (informational)`,
+	}
+	var got []string
+	for _, c := range Diff(old, new) {
+		got = append(got, c.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+// TestDiffMatchOnce checks that a command matches one old command at most:
+// one taking another's alias, and one folded into another with its name
+// kept as an alias, are added and removed, not matched twice.
+func TestDiffMatchOnce(t *testing.T) {
+	old := &Command{Path: "example", Name: "example", Commands: []Command{
+		{Path: "example build", Name: "build", Aliases: []string{"b", "make"}},
+		{Path: "example compile", Name: "compile"},
+	}}
+	new := &Command{Path: "example", Name: "example", Commands: []Command{
+		{Path: "example make", Name: "make"},
+		{Path: "example build", Name: "build", Aliases: []string{"b", "compile"}},
+	}}
+	want := []string{
+		`example build: aliases changed from "b, make" to "b, compile" (breaking)`,
+		`example compile: command removed (breaking)`,
+		`example make: command added (additive)`,
+	}
+	var got []string
+	for _, c := range Diff(old, new) {
+		got = append(got, c.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Diff =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestChangeJSON(t *testing.T) {
+	c := Change{Kind: ModifiedChange, Severity: Breaking, Command: "example build", Flag: "--jobs", Field: "default", Old: "4", New: "8"}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const want = `{"kind":"modified","severity":"breaking","command":"example build","flag":"--jobs","field":"default","old":"4","new":"8"}`
+	if string(data) != want {
+		t.Errorf("JSON = %s, want %s", data, want)
+	}
+	var back Change
+	if err := json.Unmarshal(data, &back); err != nil || back != c {
+		t.Errorf("round trip = %+v, %v; want %+v", back, err, c)
+	}
+	if err := json.Unmarshal([]byte(`{"severity":"fatal"}`), &back); err == nil {
+		t.Error("unknown severity decoded")
+	}
+}
+
+func indexOfFlag(flags []Flag, name string) int {
+	for i, f := range flags {
+		if f.Name == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,136 @@
+package mosshelp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DefaultMaxDepth is how many levels of subcommands below the root a
+// Discoverer expands when its MaxDepth is 0.
+const DefaultMaxDepth = 8
+
+// A Discoverer assembles a binary's command tree from its help: it parses
+// the root's, then the help of every subcommand it lists, and so on down.
+type Discoverer struct {
+	// MaxDepth is how many levels of subcommands below the root to expand;
+	// those past it are left as their parent lists them. 0 means
+	// DefaultMaxDepth.
+	MaxDepth int
+	// Run runs a command line and returns what it printed. If nil, the
+	// binary is run by RunHelp, in a Sandbox with the defaults.
+	Run func(ctx context.Context, argv []string) (string, error)
+}
+
+// Discover assembles the command tree of the cobra binary at path with the
+// default Discoverer.
+func Discover(ctx context.Context, path string) (*Command, error) {
+	return (&Discoverer{}).Discover(ctx, path)
+}
+
+// Discover assembles the command tree of the cobra binary at path, running
+// `path <command...> --help` for the root and for each command listed. In
+// the tree it returns, each of a command's Commands and HelpTopics is that
+// command's own parsed help, with the Short and Group it was listed with.
+//
+// A command whose help is a standard flag program's, as when a cobra
+// command hands its arguments to a tool of its own, is parsed with
+// ParseStdFlag, and has no subcommands to expand.
+//
+// Discover fails only if the root's help cannot be had or is not cobra's. A
+// subcommand whose help cannot be had, or turns out to be another
+// command's, as when a binary falls back to its root help, is left as its
+// parent lists it, and reported in the error returned with the tree.
+func (d *Discoverer) Discover(ctx context.Context, path string) (*Command, error) {
+	help, err := d.run(ctx, []string{path, "--help"})
+	if err != nil {
+		return nil, err
+	}
+	if fw, score := Detect(help); fw != Cobra && fw != Unknown {
+		return nil, fmt.Errorf("mosshelp: %s prints %s help (%.2f), not cobra's", path, fw, score)
+	}
+	root, err := Parse(help)
+	if err != nil {
+		return nil, fmt.Errorf("mosshelp: %s: %w", path, err)
+	}
+	w := &walker{d: d, binary: path, seen: map[string]bool{root.Path: true}}
+	w.expand(ctx, root, nil, 0)
+	return root, errors.Join(w.errs...)
+}
+
+// walker is the state of one Discover call.
+type walker struct {
+	d      *Discoverer
+	binary string
+	// seen holds the paths of the commands expanded so far, so that none is
+	// expanded twice however a binary lists it.
+	seen map[string]bool
+	errs []error
+}
+
+// expand replaces the commands c lists with their parsed help, recursing
+// into each. words are c's path below the root and depth its level.
+func (w *walker) expand(ctx context.Context, c *Command, words []string, depth int) {
+	maxDepth := w.d.MaxDepth
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	if depth >= maxDepth {
+		return
+	}
+	for i := range c.Commands {
+		sub := &c.Commands[i]
+		if sub.Path == "" || w.seen[sub.Path] {
+			continue
+		}
+		w.seen[sub.Path] = true
+		subWords := append(append([]string(nil), words...), sub.Name)
+		if parsed := w.page(ctx, sub, subWords, true); parsed != nil {
+			*sub = *parsed
+			w.expand(ctx, sub, subWords, depth+1)
+		}
+	}
+	for i := range c.HelpTopics {
+		topic := &c.HelpTopics[i]
+		if parsed := w.page(ctx, topic, append(append([]string(nil), words...), topic.Name), false); parsed != nil {
+			*topic = *parsed
+		}
+	}
+}
+
+// page gets and parses the help of a listed command, returning nil and
+// recording why if it cannot be had. Unless runnable is false, as for help
+// topics, whose help shows no path, the help must be listed's own.
+func (w *walker) page(ctx context.Context, listed *Command, words []string, runnable bool) *Command {
+	argv := append(append([]string{w.binary}, words...), "--help")
+	help, err := w.d.run(ctx, argv)
+	if err != nil {
+		w.errs = append(w.errs, err)
+		return nil
+	}
+	parse := Parse
+	if fw, _ := Detect(help); fw == StdFlag {
+		parse = ParseStdFlag
+	}
+	parsed, err := parse(help)
+	if err != nil {
+		w.errs = append(w.errs, fmt.Errorf("mosshelp: %s: %w", listed.Path, err))
+		return nil
+	}
+	// flag's default usage names the program by its flag set alone.
+	own := parsed.Path == listed.Path || parsed.Framework == StdFlag && parsed.Path == listed.Name
+	if runnable && !own {
+		w.errs = append(w.errs, fmt.Errorf("mosshelp: %s: printed the help of %q", listed.Path, parsed.Path))
+		return nil
+	}
+	parsed.Schema, parsed.Path, parsed.Name = "", listed.Path, listed.Name
+	parsed.Short, parsed.Group = listed.Short, listed.Group
+	return parsed
+}
+
+func (d *Discoverer) run(ctx context.Context, argv []string) (string, error) {
+	if d.Run != nil {
+		return d.Run(ctx, argv)
+	}
+	return RunHelp(ctx, argv)
+}
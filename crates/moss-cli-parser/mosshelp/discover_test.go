@@ -0,0 +1,565 @@
+package mosshelp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+var errNoFixture = errors.New("no fixture")
+
+// fixtureRunner serves the help the corpus captured from the cobra fixture
+// binary, for the command lines it was captured with plainly, as
+// `example <words> --help`.
+func fixtureRunner(t *testing.T) func(context.Context, []string) (string, error) {
+	return frameworkRunner(t, "cobra")
+}
+
+// frameworkRunner is fixtureRunner for the fixtures of framework.
+func frameworkRunner(t *testing.T, framework string) func(context.Context, []string) (string, error) {
+	pages := map[string]string{}
+	for _, e := range corpus.ByFramework(framework) {
+		inv := e.Invocation
+		if inv == nil || inv.Exit != 0 || len(inv.Env) > 0 || inv.Argv[len(inv.Argv)-1] != "--help" {
+			continue
+		}
+		pages[strings.Join(inv.Argv, " ")] = e.Help
+	}
+	if len(pages) == 0 {
+		t.Fatalf("no %s help fixtures in corpus", framework)
+	}
+	return func(_ context.Context, argv []string) (string, error) {
+		if help, ok := pages[strings.Join(argv, " ")]; ok {
+			return help, nil
+		}
+		return "", fmt.Errorf("%s: %w", strings.Join(argv, " "), errNoFixture)
+	}
+}
+
+// treePaths returns the paths of the commands in tree that cobra lists in
+// help, sorted: those neither hidden nor deprecated, under listed parents.
+// Help topics are left out.
+func treePaths(tree *treeCommand) []string {
+	paths := []string{tree.Path}
+	for i := range tree.Commands {
+		if sub := &tree.Commands[i]; !sub.Hidden && sub.Deprecated == "" && !sub.HelpTopic {
+			paths = append(paths, treePaths(sub)...)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// discovered returns the paths of the commands in c's tree whose help was
+// parsed, rather than only listed, sorted; topics are left out. Flag's
+// default usage prints no usage line, but parsing it sets the Framework.
+func discovered(c *Command) []string {
+	var paths []string
+	if len(c.Usage) > 0 || c.Framework != "" {
+		paths = append(paths, c.Path)
+	}
+	for i := range c.Commands {
+		paths = append(paths, discovered(&c.Commands[i])...)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func exampleTree(t *testing.T) *treeCommand {
+	e, ok := corpus.Lookup("cobra/example.help")
+	if !ok {
+		t.Fatal("cobra/example.help missing from corpus")
+	}
+	var root treeCommand
+	if err := json.Unmarshal(e.Truth, &root); err != nil {
+		t.Fatal(err)
+	}
+	return &root
+}
+
+// TestDiscoverCorpus discovers the fixture binary's tree from the help the
+// corpus holds: every command with a fixture is expanded, and every one
+// without is reported, unless its parent could not be expanded either.
+func TestDiscoverCorpus(t *testing.T) {
+	run := fixtureRunner(t)
+	root, err := (&Discoverer{Run: run}).Discover(context.Background(), "example")
+	if root == nil {
+		t.Fatal(err)
+	}
+	for _, path := range treePaths(exampleTree(t)) {
+		argv := append(strings.Fields(path), "--help")
+		_, missing := run(context.Background(), argv)
+		found := contains(discovered(root), path)
+		parent := path[:max(strings.LastIndexByte(path, ' '), 0)]
+		switch {
+		case missing == nil && !found:
+			t.Errorf("%s: not discovered", path)
+		case missing != nil && found:
+			t.Errorf("%s: discovered without a fixture", path)
+		case missing != nil && contains(discovered(root), parent) && (err == nil || !strings.Contains(err.Error(), missing.Error())):
+			t.Errorf("%s: missing page not reported: %v", path, err)
+		}
+	}
+	build := root.Commands[indexOfCommand(root.Commands, "build")]
+	if build.Short != "Build the project" || len(build.Flags) == 0 || len(build.Aliases) == 0 {
+		t.Errorf("build not expanded from its help: %+v", build)
+	}
+	if build.Schema != "" {
+		t.Errorf("build.Schema = %q, want only the root's set", build.Schema)
+	}
+}
+
+// TestDiscoverBinary discovers the tree of the fixture binary itself, when
+// generate.sh has built it, and checks it against the tree it exports.
+func TestDiscoverBinary(t *testing.T) {
+	binary, err := filepath.Abs("../fixtures/cobra/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(binary); err != nil {
+		t.Skip("fixture binary not built; run fixtures/generate.sh")
+	}
+	root, err := Discover(context.Background(), binary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, want := discovered(root), treePaths(exampleTree(t))
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("discovered %q, want %q", got, want)
+	}
+	for _, topic := range root.HelpTopics {
+		if topic.Long == "" || topic.Short == "" {
+			t.Errorf("help topic %s not expanded: %+v", topic.Path, topic)
+		}
+	}
+}
+
+// TestDiscoverHang discovers the fixture binary's tree, when generate.sh has
+// built it, with the help of build misbehaving in each of the ways
+// EXAMPLE_HANG offers: those that never finish leave build as root lists
+// it and are reported, whether or not they heed a terminate, and the rest
+// make no difference.
+func TestDiscoverHang(t *testing.T) {
+	binary, err := filepath.Abs("../fixtures/cobra/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(binary); err != nil {
+		t.Skip("fixture binary not built; run fixtures/generate.sh")
+	}
+	all := treePaths(exampleTree(t))
+	var withoutBuild []string
+	for _, path := range all {
+		if path != "example build" {
+			withoutBuild = append(withoutBuild, path)
+		}
+	}
+	env := append(DefaultEnv[:len(DefaultEnv):len(DefaultEnv)], "EXAMPLE_HANG", "EXAMPLE_HANG_DELAY")
+	for _, tc := range []struct {
+		mode    string
+		timeout time.Duration
+		want    []string
+		hung    bool
+	}{
+		{"stdin", time.Second, all, false},
+		{"slow", time.Second, all, false},
+		// The process left behind holds build's output open until a
+		// second after build exits, and the timeout has to outlast that.
+		{"orphan", 3 * time.Second, all, false},
+		{"hold", time.Second / 2, withoutBuild, true},
+		{"sleep", time.Second / 2, withoutBuild, true},
+		{"stubborn", time.Second / 2, withoutBuild, true},
+	} {
+		t.Run(tc.mode, func(t *testing.T) {
+			sandbox := &Sandbox{Timeout: tc.timeout, Env: env}
+			t.Setenv("EXAMPLE_HANG", tc.mode+"@build")
+			t.Setenv("EXAMPLE_HANG_DELAY", "1ms")
+			start := time.Now()
+			root, err := (&Discoverer{Run: sandbox.Run}).Discover(context.Background(), binary)
+			if root == nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(start); elapsed > 10*time.Second {
+				t.Errorf("took %v", elapsed)
+			}
+			switch timedOut := err != nil && strings.Contains(err.Error(), "build --help: timed out"); {
+			case tc.hung && !timedOut:
+				t.Errorf("err = %v, want build's timeout reported", err)
+			case !tc.hung && err != nil:
+				t.Error(err)
+			}
+			if got := discovered(root); strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+				t.Errorf("discovered %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// TestDiscoverFallback checks that a binary printing its root help for
+// every command line, as some do for commands they do not know, is not
+// expanded without end.
+func TestDiscoverFallback(t *testing.T) {
+	e, _ := corpus.Lookup("cobra/example.help")
+	runs := 0
+	root, err := (&Discoverer{Run: func(context.Context, []string) (string, error) {
+		runs++
+		return e.Help, nil
+	}}).Discover(context.Background(), "example")
+	if root == nil {
+		t.Fatal(err)
+	}
+	if err == nil || !strings.Contains(err.Error(), `printed the help of "example"`) {
+		t.Errorf("err = %v, want the fallback reported", err)
+	}
+	if want := 1 + len(root.Commands) + len(root.HelpTopics); runs != want {
+		t.Errorf("ran %d times, want %d", runs, want)
+	}
+	if got := discovered(root); len(got) != 1 {
+		t.Errorf("discovered %q, want only the root", got)
+	}
+}
+
+func TestDiscoverMaxDepth(t *testing.T) {
+	root, _ := (&Discoverer{Run: fixtureRunner(t), MaxDepth: 1}).Discover(context.Background(), "example")
+	if root == nil {
+		t.Fatal("Discover failed")
+	}
+	for _, path := range discovered(root) {
+		if depth := strings.Count(path, " "); depth > 1 {
+			t.Errorf("%s: expanded past MaxDepth 1", path)
+		}
+	}
+}
+
+func TestDiscoverNotCobra(t *testing.T) {
+	e, _ := corpus.Lookup("kong/example.help")
+	_, err := (&Discoverer{Run: func(context.Context, []string) (string, error) {
+		return e.Help, nil
+	}}).Discover(context.Background(), "example")
+	if err == nil || !strings.Contains(err.Error(), "kong") {
+		t.Errorf("err = %v, want kong help refused", err)
+	}
+}
+
+// megaFanout is how many subcommands the mega tree gives a command at each
+// level above the leaves: 6 under the root, 7 under each of those and 6
+// under each of theirs, for 300 below the root, as many as kubectl or the
+// AWS CLI have.
+var megaFanout = []int{6, 7, 6}
+
+// megaFlags is how many flags of its own, besides --help, the mega tree
+// gives every command.
+const megaFlags = 40
+
+// megaWords are drawn on for the mega tree's names and descriptions.
+var megaWords = strings.Fields(`account agent archive artifact backup batch
+	binary branch bucket build cache channel check cluster commit config
+	container context daemon deploy digest domain driver entry event export
+	filter format gateway graph group hook image index instance job key
+	label layer ledger limit link log manifest member metric mirror module
+	mount node object operator package patch peer pipeline plugin policy
+	pool port profile project proxy queue quota record region release remote
+	replica report repository resource revision role route rule runner
+	schema secret service session shard snapshot source stack stage state
+	storage stream subnet switch tag target task template tenant token topic
+	trace trigger tunnel upload user value vault version volume webhook
+	worker zone`)
+
+// megaTypes are the value placeholders pflag prints for the flag types the
+// mega tree picks from, with a generator of defaults for each, nil for
+// types whose flags show none.
+var megaTypes = []struct {
+	value string
+	def   func(r *rand.Rand) string
+}{
+	{"string", func(r *rand.Rand) string { return megaWords[r.Intn(len(megaWords))] }},
+	{"", nil},
+	{"int", func(r *rand.Rand) string { return fmt.Sprint(r.Intn(1000)) }},
+	{"uint", func(r *rand.Rand) string { return fmt.Sprint(r.Intn(64)) }},
+	{"float", func(r *rand.Rand) string { return fmt.Sprintf("%.2f", r.Float64()*100) }},
+	{"duration", func(r *rand.Rand) string { return fmt.Sprintf("%dm%ds", r.Intn(60), r.Intn(60)) }},
+	{"count", nil},
+	{"strings", func(r *rand.Rand) string { return "[" + megaWords[r.Intn(len(megaWords))] + "]" }},
+	{"stringToString", func(r *rand.Rand) string { return "[]" }},
+	{"ip", func(r *rand.Rand) string { return fmt.Sprintf("10.0.%d.%d", r.Intn(256), r.Intn(256)) }},
+}
+
+// megaHelp returns the help of every command of a random tree the size of
+// the largest real CLIs', by path: four levels of commands shaped by
+// megaFanout, each with megaFlags flags, printed as cobra prints them. The
+// root has cobra's help command too, whose own help is left out. It is
+// made here rather than captured, as a fixture of its size would be most of
+// the corpus; like one, it is the same on every run.
+func megaHelp() map[string]string {
+	r := rand.New(rand.NewSource(1))
+	word := func() string { return megaWords[r.Intn(len(megaWords))] }
+	sentence := func(max int) string {
+		first := word()
+		words := []string{strings.ToUpper(first[:1]) + first[1:]}
+		for i, n := 1, 1+r.Intn(max); i < n; i++ {
+			words = append(words, word())
+		}
+		return strings.Join(words, " ")
+	}
+	help := map[string]string{}
+	var build func(path string, depth int) Command
+	build = func(path string, depth int) Command {
+		c := Command{Path: path, Name: path[strings.LastIndexByte(path, ' ')+1:], Short: sentence(12)}
+		// Cobra's help starts with the Short of a command without a Long.
+		c.Long = c.Short
+		taken := map[string]bool{"help": true, "h": true}
+		for len(c.Flags) < megaFlags {
+			f := Flag{Name: word()}
+			if r.Intn(3) == 0 {
+				f.Name += "-" + word()
+			}
+			if taken[f.Name] {
+				continue
+			}
+			taken[f.Name] = true
+			if s := string(rune('a' + r.Intn(26))); r.Intn(2) == 0 && !taken[s] {
+				f.Shorthand = s
+				taken[s] = true
+			}
+			t := megaTypes[r.Intn(len(megaTypes))]
+			f.Value = t.value
+			if t.def != nil && r.Intn(2) == 0 {
+				f.Default = t.def(r)
+			}
+			f.Usage = sentence(30)
+			c.Flags = append(c.Flags, f)
+		}
+		c.Flags = append(c.Flags, Flag{Name: "help", Shorthand: "h", Usage: "help for " + c.Name})
+		sort.Slice(c.Flags, func(i, j int) bool { return c.Flags[i].Name < c.Flags[j].Name })
+		c.Usage = []string{path + " [flags]"}
+		if depth < len(megaFanout) {
+			c.Usage = []string{path + " [command]"}
+			siblings := map[string]bool{"help": depth == 0}
+			for len(c.Commands) < megaFanout[depth] {
+				if name := word(); !siblings[name] {
+					siblings[name] = true
+					c.Commands = append(c.Commands, build(path+" "+name, depth+1))
+				}
+			}
+			if depth == 0 {
+				c.Commands = append(c.Commands, Command{Name: "help", Path: path + " help", Short: "Help about any command"})
+			}
+			sort.Slice(c.Commands, func(i, j int) bool { return c.Commands[i].Name < c.Commands[j].Name })
+		}
+		help[path] = Render(&c)
+		return c
+	}
+	build("example", 0)
+	return help
+}
+
+// megaRunner serves the help of the mega tree, for `example <words> --help`.
+func megaRunner() func(context.Context, []string) (string, error) {
+	help := megaHelp()
+	return func(_ context.Context, argv []string) (string, error) {
+		if h, ok := help[strings.Join(argv[:len(argv)-1], " ")]; ok {
+			return h, nil
+		}
+		return "", fmt.Errorf("%s: %w", strings.Join(argv, " "), errNoFixture)
+	}
+}
+
+// TestDiscoverMega discovers the whole of a tree the size of kubectl's,
+// every command with its 40 flags and cobra's --help. Only the help
+// command's own page, which the mega tree leaves out, may be missing.
+func TestDiscoverMega(t *testing.T) {
+	root, err := (&Discoverer{Run: megaRunner()}).Discover(context.Background(), "example")
+	if root == nil {
+		t.Fatal(err)
+	}
+	if err != nil && err.Error() != "example help --help: no fixture" {
+		t.Errorf("err = %v, want only the help command missing", err)
+	}
+	if got := discovered(root); len(got) != 301 {
+		t.Errorf("discovered %d commands, want 301", len(got))
+	}
+	var check func(c *Command)
+	check = func(c *Command) {
+		if len(c.Flags) != 41 {
+			t.Errorf("%s: %d flags, want 41", c.Path, len(c.Flags))
+		}
+		for i := range c.Commands {
+			if c.Commands[i].Name != "help" {
+				check(&c.Commands[i])
+			}
+		}
+	}
+	check(root)
+}
+
+// workspaceCommand is the part of a workspace tree that marks what the
+// binaries' shared command library defines.
+type workspaceCommand struct {
+	Path  string `json:"path"`
+	Flags []struct {
+		Name   string `json:"name"`
+		Shared bool   `json:"shared"`
+	} `json:"flags"`
+	Commands []workspaceCommand `json:"commands"`
+}
+
+// sharedFlags records which flags of each command in tree are shared, by
+// command path below the binary and flag name.
+func (tree *workspaceCommand) sharedFlags(into map[string]map[string]bool) {
+	path := strings.Join(strings.Fields(tree.Path)[1:], " ")
+	into[path] = map[string]bool{}
+	for _, f := range tree.Flags {
+		into[path][f.Name] = f.Shared
+	}
+	for i := range tree.Commands {
+		tree.Commands[i].sharedFlags(into)
+	}
+}
+
+// TestDiscoverWorkspace discovers the trees of the workspace fixture's two
+// binaries, which share a command library, each against its own ground
+// truth, and checks that what they share parses the same in both.
+func TestDiscoverWorkspace(t *testing.T) {
+	run := frameworkRunner(t, "workspace")
+	// parsed is each shared flag as parsed from each binary's help, by
+	// command path below the binary and flag name.
+	parsed := map[string]map[string]Flag{}
+	for _, binary := range []string{"example", "examplectl"} {
+		e, ok := corpus.Lookup("workspace/" + binary + ".help")
+		if !ok {
+			t.Fatalf("workspace/%s.help missing from corpus", binary)
+		}
+		if want := "workspace/" + binary + ".tree.json"; e.TruthPath != want {
+			t.Errorf("%s: truth from %s, want %s", binary, e.TruthPath, want)
+		}
+		var tree treeCommand
+		var marks workspaceCommand
+		if err := json.Unmarshal(e.Truth, &tree); err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(e.Truth, &marks); err != nil {
+			t.Fatal(err)
+		}
+		shared := map[string]map[string]bool{}
+		marks.sharedFlags(shared)
+
+		root, err := (&Discoverer{Run: run}).Discover(context.Background(), binary)
+		if root == nil {
+			t.Fatal(err)
+		}
+		for _, path := range treePaths(&tree) {
+			_, missing := run(context.Background(), append(strings.Fields(path), "--help"))
+			if found := contains(discovered(root), path); (missing == nil) != found {
+				t.Errorf("%s: discovered %v with fixture %v", path, found, missing == nil)
+			}
+		}
+		var visit func(c *Command)
+		visit = func(c *Command) {
+			words := strings.Fields(c.Path)[1:]
+			record := func(f Flag, words []string) {
+				key := strings.Join(words, " ")
+				for !shared[key][f.Name] {
+					if len(words) == 0 {
+						return
+					}
+					words = words[:len(words)-1]
+					key = strings.Join(words, " ")
+				}
+				id := strings.Join(append(strings.Fields(c.Path)[1:], "--"+f.Name), " ")
+				if parsed[id] == nil {
+					parsed[id] = map[string]Flag{}
+				}
+				parsed[id][binary] = f
+			}
+			if len(c.Usage) > 0 {
+				for _, f := range c.Flags {
+					record(f, words)
+				}
+				for _, f := range c.InheritedFlags {
+					record(f, words[:max(len(words)-1, 0)])
+				}
+			}
+			for i := range c.Commands {
+				visit(&c.Commands[i])
+			}
+		}
+		visit(root)
+	}
+	pairs := 0
+	for id, byBinary := range parsed {
+		if len(byBinary) != 2 {
+			continue
+		}
+		pairs++
+		if !reflect.DeepEqual(byBinary["example"], byBinary["examplectl"]) {
+			t.Errorf("%s: example parses %+v, examplectl %+v", id, byBinary["example"], byBinary["examplectl"])
+		}
+	}
+	if pairs == 0 {
+		t.Error("no shared flag parsed from both binaries")
+	}
+	if _, ok := parsed["status --watch"]; ok {
+		t.Error("examplectl's own status --watch marked shared")
+	}
+}
+
+// TestDiscoverMixed discovers the mixed fixture, a cobra tree whose tool
+// subcommands print the help of stdlib flag programs, and checks each page
+// is read in its own dialect.
+func TestDiscoverMixed(t *testing.T) {
+	root, err := (&Discoverer{Run: frameworkRunner(t, "mixed")}).Discover(context.Background(), "example")
+	if root == nil {
+		t.Fatal(err)
+	}
+	want := []string{"example", "example build", "example tool", "example tool fmt", "example tool vet"}
+	if got := discovered(root); !reflect.DeepEqual(got, want) {
+		t.Fatalf("discovered %q, want %q", got, want)
+	}
+	var visit func(c *Command)
+	visit = func(c *Command) {
+		fw := Framework("")
+		if strings.HasPrefix(c.Path, "example tool ") {
+			fw = StdFlag
+		}
+		if c.Framework != fw {
+			t.Errorf("%s: framework %q, want %q", c.Path, c.Framework, fw)
+		}
+		for i := range c.Commands {
+			visit(&c.Commands[i])
+		}
+	}
+	visit(root)
+
+	tools := map[string]*Command{}
+	for i := range root.Commands {
+		if tool := &root.Commands[i]; tool.Name == "tool" {
+			for j := range tool.Commands {
+				tools[tool.Commands[j].Name] = &tool.Commands[j]
+			}
+		}
+	}
+	vet, format := tools["vet"], tools["fmt"]
+	if vet == nil || format == nil {
+		t.Fatalf("tools %v, want vet and fmt", tools)
+	}
+	if vet.Name != "vet" || vet.Short != "Report likely mistakes in packages" || len(vet.Flags) != 4 {
+		t.Errorf("vet: %+v", vet)
+	}
+	if f := vet.Flags[1]; f.Name != "c" || f.Shorthand != "" || f.Default != "-1" {
+		t.Errorf("vet -c: %+v", f)
+	}
+	if len(format.Usage) != 1 || len(format.Args) != 1 || format.Args[0].Name != "path" {
+		t.Errorf("fmt usage %q args %+v", format.Usage, format.Args)
+	}
+}
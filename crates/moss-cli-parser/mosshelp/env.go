@@ -0,0 +1,39 @@
+package mosshelp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// envRE matches a note naming the environment variables a flag reads:
+// "(env: EXAMPLE_PORT)" as viper-style configuration writes it, or "(env
+// EXAMPLE_LISTEN)" as ffcli does; or variables listed shell-style, in
+// brackets as urfave/cli writes them, "[$EXAMPLE_PORT, $PORT]", or in
+// parentheses as kong and kingpin do, "($EXAMPLE_CONFIG)".
+var envRE = regexp.MustCompile(`\(env:?\s+(` + envList + `)\)|\[(` + envRefList + `)\]|\((` + envRefList + `)\)`)
+
+const (
+	envList    = `\$?[A-Za-z_]\w*(?:,\s*\$?[A-Za-z_]\w*)*`
+	envRefList = `\$[A-Za-z_]\w*(?:,\s*\$[A-Za-z_]\w*)*`
+)
+
+// EnvVars returns the environment variables a flag's usage says it reads,
+// in the order named and without the "$", from notes in any of the forms
+// frameworks print them:
+//
+//	Port number [$EXAMPLE_PORT]
+//	Config file path ($EXAMPLE_CONFIG).
+//	Port number (env: EXAMPLE_PORT)
+func EnvVars(usage string) []string {
+	// Every form has "(env" or a "$", and most usages neither.
+	if !strings.Contains(usage, "$") && !strings.Contains(usage, "(env") {
+		return nil
+	}
+	var names []string
+	for _, m := range envRE.FindAllStringSubmatch(usage, -1) {
+		for _, name := range strings.Split(m[1]+m[2]+m[3], ",") {
+			names = append(names, strings.TrimPrefix(strings.TrimSpace(name), "$"))
+		}
+	}
+	return names
+}
@@ -0,0 +1,30 @@
+package mosshelp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEnvVars(t *testing.T) {
+	tests := []struct {
+		usage string
+		want  []string
+	}{
+		{"Port number [$EXAMPLE_PORT]", []string{"EXAMPLE_PORT"}},
+		{"Port number (default: 8080) [$EXAMPLE_PORT, $PORT]", []string{"EXAMPLE_PORT", "PORT"}},
+		{"Config file path ($EXAMPLE_CONFIG).", []string{"EXAMPLE_CONFIG"}},
+		{"Config file path (env: EXAMPLE_CONFIG)", []string{"EXAMPLE_CONFIG"}},
+		{"address to listen on (env EXAMPLE_LISTEN)", []string{"EXAMPLE_LISTEN"}},
+		{"Token (env: $TOKEN, GH_TOKEN)", []string{"TOKEN", "GH_TOKEN"}},
+		{"Where to read from (env:\nEXAMPLE_INPUT)", []string{"EXAMPLE_INPUT"}},
+		{"Cache [$A] (env: B)", []string{"A", "B"}},
+		{"Enable a feature (repeatable)", nil},
+		{"Expands $HOME in the path", nil},
+		{"Pick one [a, b]", nil},
+	}
+	for _, tt := range tests {
+		if got := EnvVars(tt.usage); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("EnvVars(%q) = %q, want %q", tt.usage, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package mosshelp
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FlagTable is a parsed flag table, such as the body of cobra's "Flags:"
+// section:
+//
+//	-c, --config string             Config file path
+//	    --color string[="always"]   Colorize output (default "auto")
+//	    --env-file string           Load build environment variables.
+//	                                Each line has the form KEY=VALUE.
+//
+// Each row is a flag's names and value placeholder, then its description
+// starting at a column shared by the whole table. A description continues
+// on the lines below indented to that column, whether it has line breaks of
+// its own or was wrapped to the terminal.
+type FlagTable struct {
+	// Column is the column the descriptions start at, 0 if no row has a
+	// description.
+	Column int `json:"column"`
+	// Width is the width the descriptions look wrapped to, or 0 if they do
+	// not look wrapped: then every continuation line is a line break of the
+	// description's own.
+	Width int    `json:"width,omitempty"`
+	Flags []Flag `json:"flags"`
+}
+
+// ParseFlagTable parses the lines of a flag table. Lines that are neither a
+// row nor a continuation of one are skipped.
+func ParseFlagTable(lines []string) *FlagTable {
+	return parseFlagTable(lines, 1, nil)
+}
+
+// parseFlagTable parses a flag table whose first line is numbered first,
+// adding the lines it skips to w.
+func parseFlagTable(lines []string, first int, w *warnings) *FlagTable {
+	t := &FlagTable{Column: flagColumn(lines)}
+	t.Width = wrapWidth(lines)
+
+	var usage []string
+	// column is where the current row's description continues: the
+	// table's column, or for a row whose description pflag moved to the
+	// next line for want of room, that line's indent (-1 until seen).
+	column := t.Column
+	// skipped is whether the current row is one that did not parse.
+	skipped := false
+	flush := func() {
+		for len(usage) > 0 && usage[len(usage)-1] == "" {
+			usage = usage[:len(usage)-1]
+		}
+		if len(t.Flags) > 0 {
+			f := &t.Flags[len(t.Flags)-1]
+			var raw string
+			f.Usage, f.Deprecated = splitDeprecated(t.join(usage, column))
+			f.ReplacedBy = replacement(f.Deprecated)
+			if f.Usage, raw = splitDefault(f.Usage); raw != "" {
+				f.setDefault(raw)
+			}
+			f.readUsage()
+		}
+		usage = nil
+	}
+	for i, line := range lines {
+		if spec, desc, ok := t.row(line); ok {
+			if f, ok := parseFlagSpec(spec); ok {
+				if w != nil && slices.ContainsFunc(t.Flags, func(g Flag) bool { return flagKey(&g) == flagKey(&f) }) {
+					w.add(first+i, line, indent(line), "%s is listed twice", flagKey(&f))
+				}
+				flush()
+				t.Flags = append(t.Flags, f)
+				usage, column, skipped = []string{desc}, t.Column, false
+				if desc == "" {
+					usage, column = nil, -1
+				}
+				continue
+			}
+			w.add(first+i, line, indent(line), "malformed flag row %q", spec)
+			skipped = true
+			continue
+		}
+		if skipped && (strings.TrimSpace(line) == "" || indent(line) > 0) {
+			// The skipped row's description goes with it.
+			continue
+		}
+		if len(t.Flags) == 0 {
+			if strings.TrimSpace(line) != "" {
+				w.add(first+i, line, indent(line), "text before the first flag row")
+			}
+			continue
+		}
+		if column < 0 && indent(line) > 0 {
+			column = indent(line)
+		}
+		if strings.TrimSpace(line) == "" {
+			usage = append(usage, "")
+		} else if column >= 0 && indent(line) >= column {
+			usage = append(usage, line[column:])
+		} else {
+			w.add(first+i, line, indent(line), "line neither a flag row nor indented to its description")
+		}
+	}
+	flush()
+	return t
+}
+
+// setDefault sets f's default from raw, as printed. It is read no more
+// surely than f's Value.
+func (f *Flag) setDefault(raw string) {
+	v, conf := parseValue(raw, f.Value)
+	f.Default, f.DefaultValue = unquote(raw), &v
+	score(&f.Confidence, "default_value", max(conf, f.Confidence["value"]))
+}
+
+// readUsage sets what f's usage tells besides: the environment variables
+// the flag reads and the values it accepts, inferred from its prose.
+func (f *Flag) readUsage() {
+	f.Env, f.Choices = EnvVars(f.Usage), Choices(f.Usage)
+	if len(f.Env) > 0 {
+		score(&f.Confidence, "env", Inferred)
+	}
+	if len(f.Choices) > 0 {
+		score(&f.Confidence, "choices", Inferred)
+	}
+}
+
+// row splits a table row into the flag spec and the start of the
+// description, reporting false for a line that starts no row.
+func (t *FlagTable) row(line string) (spec, desc string, ok bool) {
+	trimmed := strings.TrimLeft(line, " ")
+	if !strings.HasPrefix(trimmed, "-") || indent(line) >= t.Column && t.Column > 0 {
+		return "", "", false
+	}
+	if t.Column > 0 && len(line) > t.Column && line[t.Column-1] == ' ' && line[t.Column-2] == ' ' {
+		return strings.TrimSpace(line[:t.Column]), line[t.Column:], true
+	}
+	// A spec too long for the column pushes the description right.
+	if i := strings.Index(trimmed, "  "); i >= 0 {
+		return trimmed[:i], strings.TrimLeft(trimmed[i:], " "), true
+	}
+	return trimmed, "", true
+}
+
+// join joins description lines, with a space where the table looks wrapped
+// and the next word would not have fitted on the line, else a line break.
+// A line ending a sentence before one starting with a capital is taken to be
+// broken by its author even then, as wrapping cannot be told apart there.
+// So is a line starting with a word too long for the width, a URL or path
+// that pflag let run on with the rest of the description up to its next
+// line break.
+func (t *FlagTable) join(lines []string, column int) string {
+	var b strings.Builder
+	for i, line := range lines {
+		if i > 0 {
+			prev := lines[i-1]
+			first, _, _ := strings.Cut(prev, " ")
+			next, _, _ := strings.Cut(line, " ")
+			sentence := strings.HasSuffix(prev, ".") && next != "" && unicode.IsUpper([]rune(next)[0])
+			overflow := column+len(first) > t.Width
+			if t.Width > 0 && next != "" && prev != "" && !sentence && !overflow && column+len(prev)+1+len(next) > t.Width {
+				b.WriteByte(' ')
+			} else {
+				b.WriteByte('\n')
+			}
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// flagColumn returns the column most rows' descriptions start at: the end
+// of the first run of two or more spaces after the flag spec.
+func flagColumn(lines []string) int {
+	counts := map[int]int{}
+	for _, line := range lines {
+		n := indent(line)
+		if n == 0 || n >= len(line) || line[n] != '-' {
+			continue
+		}
+		if i := strings.Index(line[n:], "  "); i >= 0 {
+			start := n + i
+			end := start + len(line[start:]) - len(strings.TrimLeft(line[start:], " "))
+			if end < len(line) {
+				counts[end]++
+			}
+		}
+	}
+	columns := make([]int, 0, len(counts))
+	for c := range counts {
+		columns = append(columns, c)
+	}
+	sort.Slice(columns, func(i, j int) bool {
+		if counts[columns[i]] != counts[columns[j]] {
+			return counts[columns[i]] > counts[columns[j]]
+		}
+		return columns[i] < columns[j]
+	})
+	if len(columns) == 0 {
+		return 0
+	}
+	return columns[0]
+}
+
+// wrapWidth guesses the width a table was wrapped to: its longest
+// continuation line followed by another, if at least two others come close
+// to it, as lines filled up to the terminal's edge do. Line breaks in
+// unwrapped descriptions fall where their authors put them, rarely at the
+// same length twice. The last line of a description is left out: pflag
+// lets it run a few columns past the width rather than wrap a short tail.
+// So is a line whose first word alone runs past every other: a URL or path
+// too long to wrap, which pflag lets run past the width.
+func wrapWidth(lines []string) int {
+	continues := func(line string) bool {
+		text := strings.TrimSpace(line)
+		return indent(line) > 0 && text != "" && text[0] != '-'
+	}
+	var lengths, firsts []int
+	for i, line := range lines {
+		if continues(line) && i+1 < len(lines) && continues(lines[i+1]) {
+			first, _, _ := strings.Cut(strings.TrimSpace(line), " ")
+			lengths = append(lengths, len(strings.TrimRight(line, " ")))
+			firsts = append(firsts, indent(line)+len(first))
+		}
+	}
+	for len(lengths) > 1 {
+		i := slices.Index(lengths, slices.Max(lengths))
+		others := slices.Delete(slices.Clone(lengths), i, i+1)
+		if firsts[i] <= slices.Max(others) {
+			break
+		}
+		lengths, firsts = others, slices.Delete(firsts, i, i+1)
+	}
+	width := 0
+	if len(lengths) > 0 {
+		width = slices.Max(lengths)
+	}
+	near := 0
+	for _, n := range lengths {
+		if n >= width-10 {
+			near++
+		}
+	}
+	if near < 3 {
+		return 0
+	}
+	return width
+}
+
+// parseFlagSpec parses the names and value placeholder of a row:
+//
+//	-c, --config string
+//	--color string[="always"]
+//	--dry-run[=false]
+//	-i
+func parseFlagSpec(spec string) (Flag, bool) {
+	var f Flag
+	rest := spec
+	// A shorthand is one ASCII letter or symbol.
+	if len(rest) >= 2 && rest[0] == '-' && rest[1] != '-' && rest[1] > ' ' && rest[1] < utf8.RuneSelf {
+		f.Shorthand = rest[1:2]
+		rest = rest[2:]
+		if after, ok := strings.CutPrefix(rest, ", "); ok {
+			rest = after
+		} else if rest != "" && rest[0] != ' ' {
+			return Flag{}, false
+		}
+	}
+	if after, ok := strings.CutPrefix(rest, "--"); ok {
+		name, tail, _ := strings.Cut(after, " ")
+		name, f.NoOptDefault = cutNoOptDefault(name)
+		if name == "" {
+			return Flag{}, false
+		}
+		f.Name, rest = name, tail
+	} else if f.Shorthand == "" {
+		return Flag{}, false
+	}
+	rest = strings.TrimSpace(rest)
+	if rest != "" {
+		if strings.Contains(rest, " ") {
+			return Flag{}, false
+		}
+		var noOpt string
+		f.Value, noOpt = cutNoOptDefault(rest)
+		if noOpt != "" {
+			f.NoOptDefault = noOpt
+		}
+	}
+	f.NoOptDefault = unquote(f.NoOptDefault)
+	return f, true
+}
+
+// cutNoOptDefault splits `name[=value]` into name and value.
+func cutNoOptDefault(s string) (string, string) {
+	if i := strings.Index(s, "[="); i >= 0 && strings.HasSuffix(s, "]") {
+		return s[:i], s[i+2 : len(s)-1]
+	}
+	return s, ""
+}
+
+// splitDefault splits the "(default ...)" pflag appends to a usage from it,
+// returning the default as printed.
+func splitDefault(usage string) (string, string) {
+	if !strings.HasSuffix(usage, ")") {
+		return usage, ""
+	}
+	i := strings.LastIndex(usage, "(default ")
+	if i < 0 {
+		return usage, ""
+	}
+	value := usage[i+len("(default ") : len(usage)-1]
+	return strings.TrimRight(usage[:i], " \n"), value
+}
+
+func indent(line string) int {
+	return len(line) - len(strings.TrimLeft(line, " "))
+}
@@ -0,0 +1,131 @@
+package mosshelp
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+func TestParseFlagTable(t *testing.T) {
+	// pflag indents the blank lines of a description too.
+	blank := strings.Repeat(" ", 38)
+	table := ParseFlagTable(strings.Split(strings.Replace(strings.TrimPrefix(`
+      --color string[="always"]       Colorize output: auto, always or never (default "auto")
+  -h, --help                          help for run
+      --profile string[="cpu.prof"]   Write a CPU profile
+  -i                                  Match case-insensitively
+      --env-file string               Load variables from a file.
+                                      Each line has the form KEY=VALUE.
+BLANK
+                                      Variables already set win.
+      --dry-run[=false]               Print the plan only (default true)
+      --a-spec-too-long-for-the-column string  Pushes its description right
+`, "\n"), "BLANK", blank, 1), "\n"))
+	if table.Column != 38 {
+		t.Errorf("Column = %d, want 38", table.Column)
+	}
+	if table.Width != 0 {
+		t.Errorf("Width = %d, want 0", table.Width)
+	}
+	want := []Flag{
+		{Name: "color", Value: "string", NoOptDefault: "always", Usage: "Colorize output: auto, always or never", Default: "auto",
+			DefaultValue: &TypedValue{Raw: `"auto"`, Kind: StringValue, String: "auto"}, Choices: []string{"auto", "always", "never"},
+			Confidence: map[string]Confidence{"choices": Inferred}},
+		{Name: "help", Shorthand: "h", Usage: "help for run"},
+		{Name: "profile", Value: "string", NoOptDefault: "cpu.prof", Usage: "Write a CPU profile"},
+		{Shorthand: "i", Usage: "Match case-insensitively"},
+		{Name: "env-file", Value: "string", Usage: "Load variables from a file.\nEach line has the form KEY=VALUE.\n\nVariables already set win."},
+		{Name: "dry-run", NoOptDefault: "false", Usage: "Print the plan only", Default: "true",
+			DefaultValue: &TypedValue{Raw: "true", Kind: BoolValue, Bool: true}},
+		{Name: "a-spec-too-long-for-the-column", Value: "string", Usage: "Pushes its description right"},
+	}
+	if !reflect.DeepEqual(table.Flags, want) {
+		t.Errorf("Flags =\n%+v\nwant\n%+v", table.Flags, want)
+	}
+}
+
+// TestParseFlagTableWrapped checks that a table wrapped to the terminal,
+// cobra/example-build-wrapped.help's, joins back into the usages it was
+// wrapped from.
+func TestParseFlagTableWrapped(t *testing.T) {
+	e, ok := corpus.Lookup("cobra/example-build-wrapped.help")
+	if !ok {
+		t.Fatal("cobra/example-build-wrapped.help missing from corpus")
+	}
+	_, rest, _ := strings.Cut(e.Help, "\nFlags:\n")
+	body, _, _ := strings.Cut(rest, "\n\n")
+	table := ParseFlagTable(strings.Split(body, "\n"))
+	if table.Width == 0 {
+		t.Fatal("table not detected as wrapped")
+	}
+	want := map[string]string{
+		"cache": "Where to keep the build cache. A local cache lives in the target directory and is never shared between checkouts, " +
+			"while a remote cache is consulted before every compile step and populated afterwards, which makes clean builds on " +
+			"CI machines considerably faster at the cost of network traffic",
+		"env-file": "Load build environment variables from a file.\nEach line has the form KEY=VALUE; blank lines and\n" +
+			"lines starting with # are ignored.\n\nVariables already set in the environment win.",
+		"jobs": "Number of parallel jobs",
+	}
+	for _, f := range table.Flags {
+		if usage, ok := want[f.Name]; ok && f.Usage != usage {
+			t.Errorf("--%s: Usage = %q, want %q", f.Name, f.Usage, usage)
+		}
+	}
+}
+
+// TestParseFlagTableWrapBoundary checks the tables of
+// cobra/widths/example-mirror-*.help, whose descriptions are fitted to
+// wrapping at 80 columns, join back at it and a column either side: where
+// words land on the last column wrapped lines reach or one past, and where
+// paths and URLs too long to wrap run past it.
+func TestParseFlagTableWrapBoundary(t *testing.T) {
+	want := map[string]string{
+		"dest":    "Where the mirrored packages are written to, in a directory created when missing",
+		"include": "Only mirror packages matching these globs, in any order",
+		"exclude": "Leave out packages matching the globs,\nafter --include has matched them",
+		"keep-partial": "Keep partial downloads, so that the next run resumes them; files it resumes are re-verified " +
+			"against their checksums, and those that fail are re-downloaded from scratch",
+		"retries": "Times to retry a failed download",
+		"ca-file": "Bundle of certificate authorities to trust in place of the system's, such as " +
+			"/etc/pki/ca-trust/extracted/pem/tls-ca-bundle.pem\nRead from stdin when -",
+		"upstream-url": "Upstream to mirror from, such as " +
+			"https://mirror.example.com/packages/stable/x86_64/repodata/primary.xml.gz or a mirror list",
+	}
+	for _, cols := range []int{79, 80, 81} {
+		name := fmt.Sprintf("cobra/widths/example-mirror-%d.help", cols)
+		e, ok := corpus.Lookup(name)
+		if !ok {
+			t.Fatalf("%s missing from corpus", name)
+		}
+		_, rest, _ := strings.Cut(e.Help, "\nFlags:\n")
+		body, _, _ := strings.Cut(rest, "\n\n")
+		table := ParseFlagTable(strings.Split(body, "\n"))
+		// Wrapped lines end by cols-6; the overflowing path must not
+		// count.
+		if table.Width == 0 || table.Width > cols-6 {
+			t.Errorf("%s: Width = %d, want at most %d", name, table.Width, cols-6)
+		}
+		for _, f := range table.Flags {
+			usage, ok := want[f.Name]
+			if cols == 79 && f.Name == "exclude" {
+				// "after" would not have fitted at 79 columns, so the
+				// line break reads as wrapping.
+				usage = strings.ReplaceAll(usage, "\n", " ")
+			}
+			if ok && f.Usage != usage {
+				t.Errorf("%s: --%s: Usage = %q, want %q", name, f.Name, f.Usage, usage)
+			}
+		}
+	}
+}
+
+func TestParseFlagSpec(t *testing.T) {
+	for _, spec := range []string{"", "-", "--", "-ab", "--name two words", "help"} {
+		if f, ok := parseFlagSpec(spec); ok {
+			t.Errorf("parseFlagSpec(%q) = %+v, want failure", spec, f)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package mosshelp
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+// The fuzz targets feed the parsers arbitrary bytes, as a CLI that prints
+// something other than help will. They are seeded with every captured
+// fixture, malformed/'s corrupted ones included, and check that the parsers
+// never panic and that what they return holds together.
+
+// seedHelp returns the contents of every captured help fixture.
+func seedHelp() []string {
+	var seeds []string
+	for _, e := range corpus.All() {
+		if ext := path.Ext(e.Path); ext == ".help" || ext == ".err" {
+			seeds = append(seeds, e.Help)
+		}
+	}
+	return seeds
+}
+
+// seedSections returns the bodies of the sections titled title across the
+// help fixtures.
+func seedSections(title string) []string {
+	var seeds []string
+	for _, help := range seedHelp() {
+		blocks, _ := splitSections(strings.Split(help, "\n"))
+		for _, b := range blocks {
+			if b.title == title {
+				seeds = append(seeds, strings.Join(b.lines, "\n"))
+			}
+		}
+	}
+	return seeds
+}
+
+func FuzzSplitSections(f *testing.F) {
+	for _, help := range seedHelp() {
+		f.Add(help)
+	}
+	f.Fuzz(func(t *testing.T, help string) {
+		lines := strings.Split(help, "\n")
+		blocks, _ := splitSections(lines)
+		n := 0
+		for _, b := range blocks {
+			if b.title == "" || strings.Contains(b.title, "\n") {
+				t.Errorf("bad title %q", b.title)
+			}
+			n += 1 + len(b.lines)
+		}
+		if n > len(lines) {
+			t.Errorf("%d lines split into sections holding %d", len(lines), n)
+		}
+		if _, err := Parse(help); err != nil && strings.TrimSpace(help) != "" {
+			t.Errorf("Parse: %v", err)
+		}
+		if c, err := (&Parser{Lenient: true}).Parse(help); err == nil {
+			for _, w := range c.Warnings {
+				if w.Line < 1 || w.Line > len(lines) || w.Column < 1 {
+					t.Errorf("warning %v outside the %d lines", w, len(lines))
+				}
+			}
+		}
+	})
+}
+
+func FuzzParseUsage(f *testing.F) {
+	for _, body := range seedSections("Usage") {
+		for _, line := range strings.Split(body, "\n") {
+			f.Add(strings.TrimSpace(line))
+		}
+	}
+	f.Fuzz(func(t *testing.T, line string) {
+		u, err := ParseUsage(line)
+		if err != nil {
+			return
+		}
+		for _, a := range u.Args {
+			if a.Choices != nil && a.Name != strings.Join(a.Choices, "|") {
+				t.Errorf("argument %q has choices %q", a.Name, a.Choices)
+			}
+		}
+	})
+}
+
+func FuzzParseFlagTable(f *testing.F) {
+	for _, title := range []string{"Flags", "Global Flags"} {
+		for _, body := range seedSections(title) {
+			f.Add(body)
+		}
+	}
+	f.Fuzz(func(t *testing.T, body string) {
+		table := ParseFlagTable(strings.Split(body, "\n"))
+		for _, fl := range table.Flags {
+			if fl.Name == "" && fl.Shorthand == "" {
+				t.Errorf("flag with neither name: %+v", fl)
+			}
+			if strings.ContainsAny(fl.Name+fl.Shorthand, " \n") {
+				t.Errorf("flag names contain whitespace: %+v", fl)
+			}
+		}
+	})
+}
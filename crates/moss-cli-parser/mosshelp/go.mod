@@ -0,0 +1,7 @@
+module github.com/anthropics/moss/crates/moss-cli-parser/mosshelp
+
+go 1.21
+
+require github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus v0.0.0
+
+replace github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus => ../fixtures/corpus
@@ -0,0 +1,272 @@
+package mosshelp
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+var (
+	// manFlagRE matches the line naming a flag in a man page's OPTIONS, as
+	// cobra/doc writes it: `\fB-t\fP, \fB--target\fP=""`, with the default
+	// in brackets for a flag that may be given without a value.
+	manFlagRE = regexp.MustCompile(`^(?:\\fB-(\S)\\fP, )?\\fB--([^\\]+)\\fP(\[)?=(.*)$`)
+	// manCodeRE matches a code span of the Markdown cobra/doc renders a
+	// page from, text in backquotes, which it sets in bold closed by \fR
+	// rather than the \fP it closes other bold text with.
+	manCodeRE = regexp.MustCompile(`\\fB(.*?)\\fR`)
+	// manSeeAlsoRE matches a page SEE ALSO refers to, such as
+	// `\fBexample-build(1)\fP`.
+	manSeeAlsoRE = regexp.MustCompile(`\\fB([^\\(]+)\(\w+\)\\fP`)
+	// roffEscapeRE matches the escapes cobra/doc's pages hold: font changes,
+	// and the few characters roff needs written otherwise.
+	roffEscapeRE = regexp.MustCompile(`\\(?:f[BIRP]|f\(\w\w|\(\w\w|[-&\\e~ ])`)
+)
+
+// roffChars are the characters roffEscapeRE's named escapes stand for.
+var roffChars = map[string]string{
+	`\-`: "-", `\\`: `\`, `\e`: `\`, `\~`: " ", `\ `: " ",
+	`\(aq`: "'", `\(dq`: `"`, `\(oq`: "‘", `\(cq`: "’", `\(em`: "—", `\(en`: "–", `\(bu`: "•",
+}
+
+// unroff returns the text roff prints for a line of a page, without fonts
+// but with code spans back in backquotes.
+func unroff(line string) string {
+	line = manCodeRE.ReplaceAllString(line, "`$1`")
+	return roffEscapeRE.ReplaceAllStringFunc(line, func(esc string) string {
+		return roffChars[esc]
+	})
+}
+
+// A manPara is one paragraph of a man page: its lines as written, and
+// whether it is an example block, printed without filling.
+type manPara struct {
+	lines []string
+	pre   bool
+}
+
+// ParseMan reads a man page, in roff, as cobra/doc's GenManTree writes one
+// for each command, into the same Command that Parse reads from the
+// command's help:
+//
+//	.SH NAME                 Short, after the dashed path
+//	.SH SYNOPSIS             Usage, hence Path, Name and Args
+//	.SH DESCRIPTION          Long
+//	.SH OPTIONS              Flags
+//	.SH OPTIONS INHERITED FROM PARENT COMMANDS
+//	                         InheritedFlags
+//	.SH EXAMPLE              Examples
+//	.SH SEE ALSO             Commands: the pages below this one
+//
+// A page shows less than help in places: no aliases, no help topics, and
+// no Short for the subcommands it refers to. It names no flag's type
+// either, so Value is the name a usage quotes in backquotes as in help,
+// else the type the default reads as, such as "int" for 8080, or "value"
+// for one pflag would have quoted were it a string. Markdown that cobra/doc
+// renders the page from swallows what looks like HTML, such as <key> in a
+// usage line, so that is lost too.
+func ParseMan(page string) (*Command, error) {
+	page = strings.ReplaceAll(page, "\r\n", "\n")
+	if !strings.Contains(page, "\n.TH ") && !strings.HasPrefix(page, ".TH ") {
+		return nil, errors.New("mosshelp: not a man page")
+	}
+	c := &Command{Schema: SchemaVersion}
+	var (
+		title  string
+		paras  []manPara
+		dashed string
+	)
+	flush := func() {
+		switch title {
+		case "":
+		case "NAME":
+			if len(paras) > 0 {
+				name := unroff(strings.Join(paras[0].lines, " "))
+				dashed, c.Short, _ = strings.Cut(name, " - ")
+			}
+		case "SYNOPSIS":
+			for _, p := range paras {
+				c.Usage = append(c.Usage, strings.TrimSpace(unroff(strings.Join(p.lines, " "))))
+			}
+		case "DESCRIPTION":
+			c.Long = manText(paras)
+		case "OPTIONS":
+			c.Flags = manFlags(paras)
+		case "OPTIONS INHERITED FROM PARENT COMMANDS":
+			c.InheritedFlags = manFlags(paras)
+		case "EXAMPLE":
+			var lines []string
+			for _, p := range paras {
+				for _, line := range p.lines {
+					lines = append(lines, unroff(line))
+				}
+			}
+			c.Examples = strings.Join(trimBlank(lines), "\n")
+		case "SEE ALSO":
+			for _, p := range paras {
+				for _, m := range manSeeAlsoRE.FindAllStringSubmatch(strings.Join(p.lines, " "), -1) {
+					if name, ok := strings.CutPrefix(unroff(m[1]), dashed+"-"); ok && dashed != "" {
+						c.Commands = append(c.Commands, Command{Name: name})
+					}
+				}
+			}
+		case "HISTORY":
+		default:
+			c.Sections = append(c.Sections, Section{Title: title, Body: manText(paras)})
+		}
+		paras = nil
+	}
+
+	var para *manPara
+	for _, line := range strings.Split(page, "\n") {
+		macro, args, _ := strings.Cut(line, " ")
+		switch {
+		case para != nil && para.pre && macro != ".EE" && macro != ".fi":
+			para.lines = append(para.lines, line)
+		case macro == ".SH":
+			flush()
+			title, para = unquote(strings.TrimSpace(args)), nil
+		case macro == ".PP" || macro == ".P" || macro == ".LP" || macro == ".IP" || macro == ".TP" || macro == ".sp":
+			paras = append(paras, manPara{})
+			para = &paras[len(paras)-1]
+		case macro == ".EX" || macro == ".nf":
+			paras = append(paras, manPara{pre: true})
+			para = &paras[len(paras)-1]
+		case macro == ".EE" || macro == ".fi":
+			para = nil
+		case strings.HasPrefix(line, ".") || strings.HasPrefix(line, `'\"`):
+			// Other requests only lay the page out.
+		case line == "":
+		default:
+			if para == nil {
+				paras = append(paras, manPara{})
+				para = &paras[len(paras)-1]
+			}
+			para.lines = append(para.lines, line)
+		}
+	}
+	flush()
+	if len(c.Usage) == 0 {
+		return nil, errors.New("mosshelp: man page has no SYNOPSIS")
+	}
+
+	c.setPath("")
+	for i := range c.Commands {
+		c.Commands[i].Path = c.Path + " " + c.Commands[i].Name
+	}
+	return c, nil
+}
+
+// manText returns the text of paras, paragraphs separated by blank lines.
+func manText(paras []manPara) string {
+	texts := make([]string, 0, len(paras))
+	for _, p := range paras {
+		lines := make([]string, len(p.lines))
+		for i, line := range p.lines {
+			lines[i] = unroff(line)
+		}
+		texts = append(texts, strings.Join(lines, "\n"))
+	}
+	return strings.Trim(strings.Join(texts, "\n\n"), "\n")
+}
+
+// manFlags reads the flags an OPTIONS section lists, each a paragraph
+// naming it, its usage indented by a tab below. The paragraphs after one
+// that do not name a flag continue its usage.
+func manFlags(paras []manPara) []Flag {
+	var (
+		flags []Flag
+		raw   string
+		usage []string
+	)
+	flush := func() {
+		if len(flags) == 0 {
+			return
+		}
+		f := &flags[len(flags)-1]
+		f.Usage = unroff(strings.Join(usage, "\n\n"))
+		// pflag takes the first name in backquotes as the value's, and
+		// prints it without them.
+		if name, rest, ok := strings.Cut(f.Usage, "`"); ok {
+			if value, rest, ok := strings.Cut(rest, "`"); ok {
+				f.Value, f.Usage = value, name+value+rest
+				if delete(f.Confidence, "value"); len(f.Confidence) == 0 {
+					f.Confidence = nil
+				}
+			}
+		}
+		if !zeroDefault(raw) {
+			f.setDefault(raw)
+		}
+		f.readUsage()
+		usage = nil
+	}
+	for _, p := range paras {
+		if len(p.lines) == 0 {
+			continue
+		}
+		m := manFlagRE.FindStringSubmatch(p.lines[0])
+		if m == nil {
+			if len(flags) > 0 {
+				usage = append(usage, strings.Join(p.lines, "\n"))
+			}
+			continue
+		}
+		flush()
+		raw = unroff(m[4])
+		if m[3] != "" {
+			raw = strings.TrimSuffix(raw, "]")
+		}
+		f := Flag{Name: unroff(m[2]), Shorthand: m[1]}
+		var conf Confidence
+		f.Value, conf = manValue(raw, m[3] != "")
+		score(&f.Confidence, "value", conf)
+		flags = append(flags, f)
+		lines := append([]string(nil), p.lines[1:]...)
+		if len(lines) > 0 {
+			lines[0] = strings.TrimPrefix(lines[0], "\t")
+		}
+		usage = append(usage, strings.Join(lines, "\n"))
+	}
+	flush()
+	return flags
+}
+
+// manValue guesses the type of a flag from its default as a man page
+// prints it, optional telling whether the flag may be given without a
+// value; the guess is a placeholder pflag prints, or "" for a bool. Only
+// strings are quoted, and bools all but always optional; other types are
+// guessed from the default's syntax.
+func manValue(raw string, optional bool) (string, Confidence) {
+	if strings.HasPrefix(raw, `"`) {
+		return "string", Inferred
+	}
+	switch guessKind(raw) {
+	case BoolValue:
+		if optional {
+			return "", Inferred
+		}
+		return "bool", Guessed
+	case IntValue:
+		return "int", Guessed
+	case FloatValue:
+		return "float", Guessed
+	case DurationValue:
+		return "duration", Guessed
+	case ListValue:
+		return "strings", Guessed
+	case MapValue:
+		return "stringToString", Guessed
+	}
+	return "value", Guessed
+}
+
+// zeroDefault reports whether raw is a default pflag leaves out of help,
+// as the zero value of its type.
+func zeroDefault(raw string) bool {
+	switch raw {
+	case "", `""`, "false", "0", "0s", "[]", "map[]", "<nil>":
+		return true
+	}
+	return false
+}
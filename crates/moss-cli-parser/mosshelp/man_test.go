@@ -0,0 +1,186 @@
+package mosshelp
+
+import (
+	"io/fs"
+	"path"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+// TestParseManCorpus checks each man page the fixture binary generates,
+// with cobra's tag or without, against its help, parsed: the same command,
+// flags and subcommands, but for what man pages leave out.
+func TestParseManCorpus(t *testing.T) {
+	pages, err := fs.Glob(corpus.FS(), "cobra/man/*.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	untagged, err := fs.Glob(corpus.FS(), "cobra/no-autogen-tag/man/*.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) == 0 || len(untagged) != len(pages) {
+		t.Fatalf("%d man pages in corpus, %d without the tag", len(pages), len(untagged))
+	}
+	for _, name := range append(pages, untagged...) {
+		t.Run(strings.TrimPrefix(name, "cobra/"), func(t *testing.T) {
+			got, err := ParseMan(readFixture(t, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := Parse(readFixture(t, "cobra/golden/"+strings.TrimSuffix(path.Base(name), ".1")+".help"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Path != want.Path || got.Name != want.Name {
+				t.Errorf("path %q name %q, want %q %q", got.Path, got.Name, want.Path, want.Name)
+			}
+			// What looks like HTML in help is lost on the way to roff.
+			if !strings.Contains(want.Long, "<") && got.Long != want.Long {
+				t.Errorf("long %q, want %q", got.Long, want.Long)
+			}
+			if !strings.Contains(strings.Join(want.Usage, "\n"), "<") && !reflect.DeepEqual(got.Args, want.Args) {
+				t.Errorf("args %+v, want %+v", got.Args, want.Args)
+			}
+			// cobra/doc fences examples in Markdown to render them, so fences
+			// of their own end its fence early and the rest is mangled.
+			if !strings.Contains(want.Examples, "```") && got.Examples != want.Examples {
+				t.Errorf("examples %q, want %q", got.Examples, want.Examples)
+			}
+			// Pages are generated without running the binary, before cobra
+			// adds --version.
+			want.Flags = slices.DeleteFunc(want.Flags, func(f Flag) bool { return f.Name == "version" })
+			checkManFlags(t, "", got.Flags, want.Flags)
+			checkManFlags(t, "inherited ", got.InheritedFlags, want.InheritedFlags)
+
+			var commands []string
+			for _, c := range want.Commands {
+				if c.Name != "help" && c.Name != "completion" {
+					commands = append(commands, c.Path)
+				}
+			}
+			var gotCommands []string
+			for _, c := range got.Commands {
+				gotCommands = append(gotCommands, c.Path)
+			}
+			if !reflect.DeepEqual(gotCommands, commands) {
+				t.Errorf("commands %q, want %q", gotCommands, commands)
+			}
+		})
+	}
+}
+
+func checkManFlags(t *testing.T, what string, got, want []Flag) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%d %sflags, want %d", len(got), what, len(want))
+		return
+	}
+	for i, f := range got {
+		w := want[i]
+		switch {
+		// Help leaves out the name of a flag to be given by its shorthand
+		// only; a page does not.
+		case f.Name != w.Name && w.Name != "" || f.Shorthand != w.Shorthand:
+			t.Errorf("%sflag %d: -%s --%s, want -%s --%s", what, i, f.Shorthand, f.Name, w.Shorthand, w.Name)
+		case !strings.Contains(w.Usage, "<") && f.Usage != w.Usage:
+			t.Errorf("--%s: usage %q, want %q", f.Name, f.Usage, w.Usage)
+		// An empty map's default is printed, an empty list's not, and on a
+		// page the two read the same.
+		case f.Default != w.Default && w.Default != "[]":
+			t.Errorf("--%s: default %q, want %q", f.Name, f.Default, w.Default)
+		case !reflect.DeepEqual(f.Env, w.Env) || !reflect.DeepEqual(f.Choices, w.Choices):
+			t.Errorf("--%s: env %q choices %q, want %q %q", f.Name, f.Env, f.Choices, w.Env, w.Choices)
+		}
+		// A page names no type, so only those its defaults tell apart are
+		// compared.
+		kind, known := typeKinds[f.Value]
+		if wantKind, ok := typeKinds[w.Value]; known && ok && kind != wantKind && !(kind == IntValue && wantKind == FloatValue) && w.Default != "[]" {
+			t.Errorf("--%s: value %q, want %q", f.Name, f.Value, w.Value)
+		}
+		if (f.Value == "") != (w.Value == "") {
+			t.Errorf("--%s: value %q, want %q", f.Name, f.Value, w.Value)
+		}
+	}
+}
+
+func TestParseMan(t *testing.T) {
+	const page = `.nh
+.TH "TOOL-RUN" "1" "Jan 2024" "Auto generated by spf13/cobra" ""
+
+.SH NAME
+.PP
+tool-run - Run a task
+
+
+.SH SYNOPSIS
+.PP
+\fBtool run [flags] TASK\fP
+
+
+.SH DESCRIPTION
+.PP
+Run runs the task named \fBTASK\fR\&, or \fBall\fP of them.
+
+
+.SH OPTIONS
+.PP
+\fB--tags\fP=[]
+	Tags to select by
+
+.PP
+\fB--wait\fP=5s
+	How long to wait for the \fBlock\fR
+
+.PP
+\fB--color\fP[="auto"]
+	When to color output: auto, always or never
+
+
+.SH EXIT STATUS
+.PP
+Zero on success.
+
+
+.SH SEE ALSO
+.PP
+\fBtool(1)\fP, \fBtool-run-all(1)\fP
+`
+	got, err := ParseMan(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auto := ParseValue(`"auto"`, "string")
+	wait := ParseValue("5s", "lock")
+	want := &Command{
+		Schema: SchemaVersion,
+		Path:   "tool run",
+		Name:   "run",
+		Short:  "Run a task",
+		Long:   "Run runs the task named `TASK`, or all of them.",
+		Usage:  []string{"tool run [flags] TASK"},
+		Args:   []Arg{{Name: "TASK"}},
+		Flags: []Flag{
+			{Name: "tags", Value: "strings", Usage: "Tags to select by", Confidence: map[string]Confidence{"value": Guessed}},
+			{Name: "wait", Value: "lock", Usage: "How long to wait for the lock", Default: "5s", DefaultValue: &wait,
+				Confidence: map[string]Confidence{"default_value": Guessed}},
+			{Name: "color", Value: "string", Usage: "When to color output: auto, always or never", Default: "auto", DefaultValue: &auto, Choices: []string{"auto", "always", "never"},
+				Confidence: map[string]Confidence{"value": Inferred, "default_value": Inferred, "choices": Inferred}},
+		},
+		Commands: []Command{{Path: "tool run all", Name: "all"}},
+		Sections: []Section{{Title: "EXIT STATUS", Body: "Zero on success."}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseMan =\n%+v\nwant\n%+v", got, want)
+	}
+
+	for _, page := range []string{"", "Usage:\n  tool run\n", ".TH TOOL 1\n.SH NAME\ntool - a tool\n"} {
+		if c, err := ParseMan(page); err == nil {
+			t.Errorf("ParseMan(%q) = %+v, want an error", page, c)
+		}
+	}
+}
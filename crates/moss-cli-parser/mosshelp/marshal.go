@@ -0,0 +1,211 @@
+package mosshelp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// SchemaVersion is the version of the JSON form of a Command, recorded in
+// its Schema field. It changes when a field is renamed or removed, or
+// changes meaning; new fields are added without changing it.
+const SchemaVersion = "mosshelp/v1"
+
+// The model types implement encoding.TextMarshaler for a compact, one-line
+// display, and json.Marshaler so that JSON still holds every field rather
+// than the text form.
+type (
+	plainCommand Command
+	plainFlag    Flag
+	plainArg     Arg
+)
+
+// MarshalText returns the command's path and arguments as a usage line, such
+// as "example convert <input> [output...]".
+func (c Command) MarshalText() ([]byte, error) {
+	words := []string{c.Path}
+	for _, a := range c.Args {
+		text, _ := a.MarshalText()
+		words = append(words, string(text))
+	}
+	return []byte(strings.Join(words, " ")), nil
+}
+
+func (c Command) MarshalJSON() ([]byte, error) {
+	return json.Marshal(plainCommand(c))
+}
+
+// UnmarshalJSON decodes a command, failing if its schema is not one this
+// package reads.
+func (c *Command) UnmarshalJSON(data []byte) error {
+	var p plainCommand
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	if p.Schema != "" && p.Schema != SchemaVersion {
+		return fmt.Errorf("mosshelp: unsupported schema %q (want %q)", p.Schema, SchemaVersion)
+	}
+	*c = Command(p)
+	return nil
+}
+
+// MarshalText returns the flag as pflag lists it, with its default, such as
+// "-p, --port int (default 8080)".
+func (f Flag) MarshalText() ([]byte, error) {
+	var b strings.Builder
+	if f.Shorthand != "" {
+		b.WriteString("-" + f.Shorthand)
+		if f.Name != "" {
+			b.WriteString(", ")
+		}
+	}
+	if f.Name != "" {
+		b.WriteString("--" + f.Name)
+	}
+	if f.Value != "" {
+		b.WriteString(" " + f.Value)
+	}
+	if f.NoOptDefault != "" {
+		b.WriteString("[=" + f.NoOptDefault + "]")
+	}
+	if f.Default != "" {
+		b.WriteString(" (default " + f.Default + ")")
+	}
+	return []byte(b.String()), nil
+}
+
+func (f Flag) MarshalJSON() ([]byte, error) {
+	return json.Marshal(plainFlag(f))
+}
+
+// MarshalText returns the argument as a usage line writes it, such as
+// "<input>", "[output...]" or "(json|yaml)".
+func (a Arg) MarshalText() ([]byte, error) {
+	dots := ""
+	if a.Repeated {
+		dots = "..."
+	}
+	name := a.Name
+	if a.Choices != nil {
+		name = strings.Join(a.Choices, "|")
+	}
+	switch {
+	case a.Optional:
+		return []byte("[" + name + dots + "]"), nil
+	case a.Choices != nil:
+		return []byte("(" + name + ")" + dots), nil
+	}
+	return []byte("<" + name + ">" + dots), nil
+}
+
+func (a Arg) MarshalJSON() ([]byte, error) {
+	return json.Marshal(plainArg(a))
+}
+
+// MarshalText returns the kind's name, which JSON uses too.
+func (k NodeKind) MarshalText() ([]byte, error) {
+	if k < 0 || int(k) >= len(nodeKinds) {
+		return nil, fmt.Errorf("mosshelp: unknown node kind %d", int(k))
+	}
+	return []byte(nodeKinds[k]), nil
+}
+
+func (k *NodeKind) UnmarshalText(text []byte) error {
+	for i, name := range nodeKinds {
+		if name == string(text) {
+			*k = NodeKind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("mosshelp: unknown node kind %q", text)
+}
+
+// MarshalText returns the kind's name, which JSON uses too.
+func (k ValueKind) MarshalText() ([]byte, error) {
+	if k < 0 || int(k) >= len(valueKinds) {
+		return nil, fmt.Errorf("mosshelp: unknown value kind %d", int(k))
+	}
+	return []byte(valueKinds[k]), nil
+}
+
+func (k *ValueKind) UnmarshalText(text []byte) error {
+	for i, name := range valueKinds {
+		if name == string(text) {
+			*k = ValueKind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("mosshelp: unknown value kind %q", text)
+}
+
+// MarshalText returns the kind's name, which JSON uses too.
+func (k ChangeKind) MarshalText() ([]byte, error) {
+	if k < 0 || int(k) >= len(changeKinds) {
+		return nil, fmt.Errorf("mosshelp: unknown change kind %d", int(k))
+	}
+	return []byte(changeKinds[k]), nil
+}
+
+func (k *ChangeKind) UnmarshalText(text []byte) error {
+	for i, name := range changeKinds {
+		if name == string(text) {
+			*k = ChangeKind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("mosshelp: unknown change kind %q", text)
+}
+
+// MarshalText returns the severity's name, which JSON uses too.
+func (s Severity) MarshalText() ([]byte, error) {
+	if s < 0 || int(s) >= len(severities) {
+		return nil, fmt.Errorf("mosshelp: unknown severity %d", int(s))
+	}
+	return []byte(severities[s]), nil
+}
+
+func (s *Severity) UnmarshalText(text []byte) error {
+	for i, name := range severities {
+		if name == string(text) {
+			*s = Severity(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("mosshelp: unknown severity %q", text)
+}
+
+// MarshalText returns the kind's name, which JSON uses too.
+func (k CompletionKind) MarshalText() ([]byte, error) {
+	if k < 0 || int(k) >= len(completionKinds) {
+		return nil, fmt.Errorf("mosshelp: unknown completion kind %d", int(k))
+	}
+	return []byte(completionKinds[k]), nil
+}
+
+func (k *CompletionKind) UnmarshalText(text []byte) error {
+	for i, name := range completionKinds {
+		if name == string(text) {
+			*k = CompletionKind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("mosshelp: unknown completion kind %q", text)
+}
+
+// MarshalText returns the confidence's name, which JSON uses too.
+func (c Confidence) MarshalText() ([]byte, error) {
+	if c < 0 || int(c) >= len(confidences) {
+		return nil, fmt.Errorf("mosshelp: unknown confidence %d", int(c))
+	}
+	return []byte(confidences[c]), nil
+}
+
+func (c *Confidence) UnmarshalText(text []byte) error {
+	for i, name := range confidences {
+		if name == string(text) {
+			*c = Confidence(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("mosshelp: unknown confidence %q", text)
+}
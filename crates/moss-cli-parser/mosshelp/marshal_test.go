@@ -0,0 +1,101 @@
+package mosshelp
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// goldenFixtures are the fixtures whose parsed JSON is committed in
+// testdata, so that changes to the serialization format show up in review.
+var goldenFixtures = []string{
+	"cobra/example.help",
+	"cobra/example-build.help",
+	"cobra/example-run.help",
+	"cobra/example-cluster.help",
+}
+
+func TestMarshalJSONGolden(t *testing.T) {
+	for _, fixture := range goldenFixtures {
+		e, ok := corpus.Lookup(fixture)
+		if !ok {
+			t.Fatalf("%s missing from corpus", fixture)
+		}
+		c, err := Parse(e.Help)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, '\n')
+		golden := filepath.Join("testdata", strings.ReplaceAll(strings.TrimSuffix(fixture, ".help"), "/", "-")+".json")
+		if *update {
+			if err := os.WriteFile(golden, got, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(golden)
+		if err != nil {
+			t.Fatalf("%v (run go test -update to create it)", err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s: JSON differs from %s (run go test -update if the change is intended)", fixture, golden)
+		}
+
+		var back Command
+		if err := json.Unmarshal(got, &back); err != nil {
+			t.Fatal(err)
+		}
+		again, err := json.MarshalIndent(&back, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(append(again, '\n'), got) {
+			t.Errorf("%s: JSON does not survive a round trip", fixture)
+		}
+	}
+}
+
+func TestUnmarshalJSONSchema(t *testing.T) {
+	var c Command
+	if err := json.Unmarshal([]byte(`{"schema": "mosshelp/v0", "path": "example", "name": "example"}`), &c); err == nil {
+		t.Error("Unmarshal of an unknown schema succeeded")
+	}
+	if err := json.Unmarshal([]byte(`{"schema": "`+SchemaVersion+`", "path": "example", "name": "example"}`), &c); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestMarshalText(t *testing.T) {
+	tests := []struct {
+		v    interface{ MarshalText() ([]byte, error) }
+		want string
+	}{
+		{Flag{Name: "port", Shorthand: "p", Value: "int", Default: "8080"}, "-p, --port int (default 8080)"},
+		{Flag{Name: "color", Value: "string", NoOptDefault: "always"}, "--color string[=always]"},
+		{Flag{Shorthand: "i"}, "-i"},
+		{Arg{Name: "input"}, "<input>"},
+		{Arg{Name: "output", Optional: true, Repeated: true}, "[output...]"},
+		{Arg{Name: "json|yaml", Choices: []string{"json", "yaml"}}, "(json|yaml)"},
+		{Command{Path: "example convert", Args: []Arg{{Name: "input"}, {Name: "output", Optional: true, Repeated: true}}},
+			"example convert <input> [output...]"},
+		{OptionalNode, "optional"},
+	}
+	for _, tt := range tests {
+		got, err := tt.v.MarshalText()
+		if err != nil || string(got) != tt.want {
+			t.Errorf("MarshalText(%+v) = %q, %v; want %q", tt.v, got, err, tt.want)
+		}
+	}
+}
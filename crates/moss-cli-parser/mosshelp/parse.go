@@ -0,0 +1,331 @@
+package mosshelp
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var (
+	// headerRE matches a section header: an unindented line ending in a
+	// colon, such as "Flags:" or a command group's "Management Commands:".
+	headerRE = regexp.MustCompile(`^(\S[^:]*):$`)
+	// footerRE matches the line cobra ends a command-with-subcommands'
+	// help with, which names its path.
+	footerRE = regexp.MustCompile(`^Use "(.+) \[command\] --help" for more information about a command\.$`)
+	// commandRE matches a row of a command list: the indent, the name,
+	// padded, then the Short.
+	commandRE = regexp.MustCompile(`^( +)(\S+)(?: +(.*))?$`)
+)
+
+// Parse parses the help text a cobra command prints. It fails only on empty
+// text: help without the sections it recognizes, such as that of a help
+// topic, parses into a Command with just Long set. Help printed as
+// structured help, in StructuredHelpFormat, is read as such instead, and
+// fails in any other format.
+func Parse(help string) (*Command, error) {
+	return parse(help, nil)
+}
+
+// parse parses help text as Parse does, adding what it skips to w.
+func parse(help string, w *warnings) (*Command, error) {
+	help = strings.ReplaceAll(help, "\r\n", "\n")
+	if strings.TrimSpace(help) == "" {
+		return nil, errors.New("mosshelp: empty help text")
+	}
+	help, origin := replayTerminal(help)
+	// Warnings are found in the replay, but told at the lines of help.
+	defer w.relocate(origin)
+	if c, ok, err := parseStructured(help); ok {
+		return c, err
+	}
+	lines := strings.Split(strings.TrimRight(help, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = untab(line)
+	}
+	var deprecated string
+	// first is the line number of lines[0].
+	first := 1
+	if m := commandDeprecatedRE.FindStringSubmatch(lines[0]); m != nil {
+		deprecated, lines, first = m[1], lines[1:], 2
+	}
+	usage := len(lines)
+	for i, line := range lines {
+		if line == "Usage:" {
+			usage = i
+			break
+		}
+	}
+	// On error cobra prints only the error above the usage, so what comes
+	// before it, such as a failing command's logs, is not the command's.
+	long := 0
+	for i, line := range lines[:usage] {
+		if strings.HasPrefix(line, "Error: ") {
+			long = i
+		}
+	}
+	c := &Command{Schema: SchemaVersion, Long: strings.Trim(strings.Join(lines[long:usage], "\n"), "\n")}
+	if usage == len(lines) {
+		// Help without a usage, such as a help topic's, is Long only by
+		// where it is.
+		score(&c.Confidence, "long", Inferred)
+	}
+	c.Deprecated, c.ReplacedBy = deprecated, replacement(deprecated)
+
+	blocks, footer := splitSections(lines[usage:])
+	for _, b := range blocks {
+		b.line += first + usage
+		c.section(b, w)
+	}
+
+	c.setPath(footer)
+	for _, cmds := range [][]Command{c.Commands, c.HelpTopics} {
+		for i := range cmds {
+			cmds[i].Path = strings.TrimSpace(c.Path + " " + cmds[i].Name)
+		}
+	}
+	return c, nil
+}
+
+// A block is one section of help text: its title and the lines under it,
+// and the index of its header among the lines split.
+type block struct {
+	title string
+	lines []string
+	line  int
+}
+
+// splitSections splits help text, from its "Usage:" line on, into sections
+// at each header, returning the path the footer names too. Lines before the
+// first header belong to no section and are dropped.
+func splitSections(lines []string) (blocks []block, footer string) {
+	for i, line := range lines {
+		inExamples := len(blocks) > 0 && blocks[len(blocks)-1].title == "Examples"
+		if m := headerRE.FindStringSubmatch(line); m != nil && (!inExamples || endsExamples(lines, i)) {
+			blocks = append(blocks, block{title: m[1], line: i})
+			continue
+		}
+		if m := footerRE.FindStringSubmatch(line); m != nil {
+			footer = m[1]
+			continue
+		}
+		if len(blocks) > 0 {
+			blocks[len(blocks)-1].lines = append(blocks[len(blocks)-1].lines, line)
+		}
+	}
+	return blocks, footer
+}
+
+// section interprets one section of c's help, whose header is on line
+// b.line.
+func (c *Command) section(b block, w *warnings) {
+	title, body := b.title, trimBlank(b.lines)
+	// line is the line number of body[0].
+	line := b.line + 1 + leadingBlank(b.lines)
+	switch title {
+	case "Usage":
+		for _, line := range body {
+			if line = strings.TrimSpace(line); line != "" {
+				c.Usage = append(c.Usage, line)
+			}
+		}
+	case "Aliases":
+		names := strings.Split(strings.TrimSpace(strings.Join(body, " ")), ", ")
+		if len(names) > 1 {
+			c.Aliases = names[1:]
+		}
+	case "Examples":
+		c.Examples = strings.Join(body, "\n")
+	case "Flags":
+		c.Flags = parseFlagTable(body, line, w).Flags
+	case "Global Flags":
+		c.InheritedFlags = parseFlagTable(body, line, w).Flags
+	case "Additional help topics", "Additional help topcis": // misspelled before cobra 1.10
+		for i, text := range body {
+			// Topics are listed by path, indented and padded by at least
+			// one space; unindented text after them is not one.
+			if text != "" && indent(text) == 0 {
+				w.add(line+i, text, 0, "line not a help topic row")
+				continue
+			}
+			fields := strings.Fields(text)
+			if len(fields) < 2 {
+				if len(fields) > 0 {
+					w.add(line+i, text, indent(text), "help topic %q without a summary", fields[0])
+				}
+				continue
+			}
+			words := len(strings.Fields(c.commandPath()))
+			if words >= len(fields) {
+				words = len(fields) - 1
+			}
+			c.HelpTopics = append(c.HelpTopics, Command{
+				Name:  fields[words],
+				Short: strings.Join(fields[words+1:], " "),
+			})
+		}
+	default:
+		cmds, ok := parseCommands(body)
+		if !ok {
+			if len(body) > 0 {
+				w.add(b.line, title, 0, "unrecognized section %q", title)
+			}
+			c.Sections = append(c.Sections, Section{Title: title, Body: strings.Join(body, "\n")})
+			return
+		}
+		group := title
+		if title == "Available Commands" || title == "Additional Commands" {
+			group = ""
+		} else {
+			// Only the rows tell a group's commands from other text.
+			score(&c.Confidence, "commands", Inferred)
+		}
+		for _, cmd := range cmds {
+			cmd.Group = group
+			c.Commands = append(c.Commands, cmd)
+		}
+	}
+}
+
+// endsExamples reports whether the header-like line i, met among examples,
+// is the header of the next section. Cobra prints examples as written, so
+// an unindented line ending in a colon may be theirs: a heredoc line, or a
+// paragraph written against the margin. The next section's header follows
+// a blank line, and the rows under it are indented.
+func endsExamples(lines []string, i int) bool {
+	if i > 0 && strings.TrimSpace(lines[i-1]) != "" {
+		return false
+	}
+	for _, next := range lines[i+1:] {
+		if strings.TrimSpace(next) != "" {
+			return indent(next) > 0
+		}
+	}
+	return false
+}
+
+// commandPath returns c's path as far as the usage lines tell: the words
+// before "[command]", or before the first argument.
+func (c *Command) commandPath() string {
+	for _, line := range c.Usage {
+		if path, ok := strings.CutSuffix(line, " [command]"); ok {
+			return path
+		}
+	}
+	if len(c.Usage) == 0 {
+		return ""
+	}
+	var words []string
+	for _, word := range strings.Fields(c.Usage[0]) {
+		if isArg(word) {
+			break
+		}
+		words = append(words, word)
+	}
+	return strings.Join(words, " ")
+}
+
+// setPath sets c's Path, Name and Args from its usage lines, or from the
+// footer's path when there are none.
+func (c *Command) setPath(footer string) {
+	c.Path = c.commandPath()
+	if c.Path == "" && footer != "" {
+		c.Path = footer
+		score(&c.Confidence, "path", Inferred)
+	}
+	if i := strings.LastIndexByte(c.Path, ' '); i >= 0 {
+		c.Name = c.Path[i+1:]
+	} else {
+		c.Name = c.Path
+	}
+	c.setArgs()
+}
+
+// setArgs sets c's Args from the first of its usage lines that runs it.
+func (c *Command) setArgs() {
+	for _, line := range c.Usage {
+		if strings.HasSuffix(line, " [command]") {
+			continue
+		}
+		if u, err := ParseUsage(line); err == nil {
+			c.Args = u.Args
+		}
+		break
+	}
+}
+
+// isArg reports whether word on a usage line is an argument or flag rather
+// than part of the command path.
+func isArg(word string) bool {
+	if strings.ContainsAny(word[:1], "[<-") || strings.Contains(word, "...") || strings.Contains(word, "=") {
+		return true
+	}
+	upper := false
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return false
+		}
+		upper = upper || unicode.IsUpper(r)
+	}
+	return upper
+}
+
+// parseCommands parses the rows of a command list, reporting false if body
+// is not one. Cobra's templates indent the rows two spaces; others may
+// indent them more or less, but all alike.
+func parseCommands(body []string) ([]Command, bool) {
+	var cmds []Command
+	rowIndent := ""
+	for _, line := range body {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		m := commandRE.FindStringSubmatch(line)
+		if m == nil || (cmds != nil && m[1] != rowIndent) {
+			return nil, false
+		}
+		rowIndent = m[1]
+		cmds = append(cmds, Command{Name: m[2], Short: m[3]})
+	}
+	return cmds, len(cmds) > 0
+}
+
+// unquote returns s without the quotes pflag puts around string values.
+func unquote(s string) string {
+	if u, err := strconv.Unquote(s); err == nil && strings.HasPrefix(s, `"`) {
+		return u
+	}
+	return s
+}
+
+// untab replaces the tabs a line is indented with by the two spaces cobra's
+// templates indent with, for templates that indent with tabs instead. Tabs
+// after the indent are text and are kept. Warnings count columns in the
+// line untabbed.
+func untab(line string) string {
+	n := len(line) - len(strings.TrimLeft(line, "\t"))
+	if n == 0 {
+		return line
+	}
+	return strings.Repeat("  ", n) + line[n:]
+}
+
+// trimBlank returns lines without its leading and trailing blank lines.
+func trimBlank(lines []string) []string {
+	lines = lines[leadingBlank(lines):]
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// leadingBlank returns how many blank lines lines starts with.
+func leadingBlank(lines []string) int {
+	n := 0
+	for n < len(lines) && strings.TrimSpace(lines[n]) == "" {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,785 @@
+package mosshelp
+
+import (
+	"encoding/json"
+	"io/fs"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+// treeCommand is the part of cobra/example.tree.json, the ground truth the
+// cobra fixture binary exports, that help text shows.
+type treeCommand struct {
+	Name       string     `json:"name"`
+	Path       string     `json:"path"`
+	Aliases    []string   `json:"aliases"`
+	Short      string     `json:"short"`
+	Hidden     bool       `json:"hidden"`
+	Deprecated string     `json:"deprecated"`
+	HelpTopic  bool       `json:"help_topic"`
+	Flags      []treeFlag `json:"flags"`
+	// InheritedFlags name the ancestor each inherited flag is declared on.
+	InheritedFlags []struct {
+		Name string `json:"name"`
+		From string `json:"from"`
+	} `json:"inherited_flags"`
+	Commands []treeCommand `json:"commands"`
+}
+
+type treeFlag struct {
+	Name                string `json:"name"`
+	Shorthand           string `json:"shorthand"`
+	Type                string `json:"type"`
+	Default             string `json:"default"`
+	NoOptDefault        string `json:"no_opt_default"`
+	Usage               string `json:"usage"`
+	Persistent          bool   `json:"persistent"`
+	Required            bool   `json:"required"`
+	Hidden              bool   `json:"hidden"`
+	Deprecated          string `json:"deprecated"`
+	ShorthandDeprecated string `json:"shorthand_deprecated"`
+}
+
+func TestParseEmpty(t *testing.T) {
+	if _, err := Parse(" \n\n"); err == nil {
+		t.Error("Parse of blank text succeeded")
+	}
+}
+
+// TestCorpus parses every cobra fixture and checks it against the command
+// tree: the help of each command, captured with the default template and
+// environment, must give back the command's path, aliases, deprecation,
+// subcommands and flags.
+func TestCorpus(t *testing.T) {
+	entry, ok := corpus.Lookup("cobra/example.help")
+	if !ok {
+		t.Fatal("cobra/example.help missing from corpus")
+	}
+	var root treeCommand
+	if err := json.Unmarshal(entry.Truth, &root); err != nil {
+		t.Fatal(err)
+	}
+	checked := 0
+	for _, e := range corpus.ByFramework("cobra") {
+		if path.Ext(e.Path) != ".help" {
+			continue
+		}
+		got, err := Parse(e.Help)
+		if err != nil {
+			t.Errorf("%s: %v", e.Path, err)
+			continue
+		}
+		words, wrapped := helpPath(e)
+		chain := lookup(&root, words)
+		if chain == nil {
+			continue
+		}
+		t.Run(e.Path, func(t *testing.T) {
+			checkCommand(t, got, chain, wrapped)
+		})
+		checked++
+	}
+	if checked < 20 {
+		t.Errorf("checked %d fixtures against the tree, want at least 20", checked)
+	}
+}
+
+// TestParseBuiltinCommands checks that cobra's help and completion commands
+// are read where the variant lists them, and only there.
+func TestParseBuiltinCommands(t *testing.T) {
+	tests := []struct {
+		fixture          string
+		help, completion bool
+	}{
+		{"cobra/example.help", true, true},
+		{"cobra/example-no-help.help", false, false},
+		{"cobra/example-no-completion.help", true, false},
+		{"cobra/example-hidden-completion.help", true, false},
+		{"cobra/example-hidden-completion-unhidden.help", true, true},
+	}
+	for _, tt := range tests {
+		c := parseFixture(t, tt.fixture)
+		listed := map[string]bool{}
+		for _, sub := range c.Commands {
+			listed[sub.Name] = true
+		}
+		if listed["help"] != tt.help || listed["completion"] != tt.completion {
+			t.Errorf("%s: help listed %v, completion %v; want %v, %v",
+				tt.fixture, listed["help"], listed["completion"], tt.help, tt.completion)
+		}
+	}
+}
+
+// TestParseHelpForms checks that every form of asking a command for help,
+// --help, -h and help <path>, parses into the same command, whether or not
+// the forms printed the same.
+func TestParseHelpForms(t *testing.T) {
+	var index []struct {
+		Command string
+		Forms   map[string]string
+	}
+	if err := json.Unmarshal([]byte(readFixture(t, "cobra/forms/example.forms.json")), &index); err != nil {
+		t.Fatal(err)
+	}
+	if len(index) < 20 {
+		t.Fatalf("%d commands in cobra/forms, want at least 20", len(index))
+	}
+	for _, entry := range index {
+		want := parseFixture(t, "cobra/forms/"+entry.Forms["--help"])
+		// Help topics have no usage lines to read a path from.
+		if want.Path != entry.Command && len(want.Usage) > 0 {
+			t.Errorf("%s: --help parsed to path %q", entry.Command, want.Path)
+		}
+		for _, form := range []string{"-h", "help"} {
+			file, ok := entry.Forms[form]
+			if !ok {
+				t.Errorf("%s: no %s form", entry.Command, form)
+				continue
+			}
+			if got := parseFixture(t, "cobra/forms/"+file); !reflect.DeepEqual(got, want) {
+				t.Errorf("%s: %s parsed unlike --help: %v", entry.Command, form, Diff(want, got))
+			}
+		}
+	}
+}
+
+// TestParseAliasHelp checks that --help asked for through aliases parses
+// into the command the aliases resolve to, as if asked for by its name.
+func TestParseAliasHelp(t *testing.T) {
+	var index []struct {
+		Invoked, Command, Help string
+	}
+	if err := json.Unmarshal([]byte(readFixture(t, "cobra/aliases/example.aliases.json")), &index); err != nil {
+		t.Fatal(err)
+	}
+	if len(index) < 10 {
+		t.Fatalf("%d alias spellings in cobra/aliases, want at least 10", len(index))
+	}
+	for _, entry := range index {
+		want := parseFixture(t, "cobra/forms/long/"+strings.ReplaceAll(entry.Command, " ", "-")+".help")
+		if got := parseFixture(t, "cobra/aliases/"+entry.Help); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s parsed unlike %s: %v", entry.Invoked, entry.Command, Diff(want, got))
+		}
+	}
+}
+
+// TestParseShellExamples checks that examples written as shell sessions
+// and Markdown, with unindented lines ending in colons among them, are read
+// whole rather than split into sections at those lines.
+func TestParseShellExamples(t *testing.T) {
+	tests := []struct {
+		fixture string
+		last    string
+		flags   int
+	}{
+		{"cobra/example-query.help", `  example query --status running --quiet && echo "all done" || exit 1`, 5},
+		{"cobra/example-query-save.help", "```", 2},
+	}
+	for _, tt := range tests {
+		c, err := (&Parser{}).Parse(readFixture(t, tt.fixture))
+		if err != nil {
+			t.Errorf("%s: %v", tt.fixture, err)
+			continue
+		}
+		lines := strings.Split(c.Examples, "\n")
+		if got := lines[len(lines)-1]; got != tt.last {
+			t.Errorf("%s: examples end %q, want %q", tt.fixture, got, tt.last)
+		}
+		if len(c.Flags) != tt.flags || len(c.Sections) > 0 {
+			t.Errorf("%s: %d flags and sections %v, want %d flags and no sections", tt.fixture, len(c.Flags), c.Sections, tt.flags)
+		}
+	}
+}
+
+// TestLocalizedLayout checks that cobra help with translated templates
+// keeps the English help's layout line for line: headers where the English
+// has them, and rows indented and split into columns alike. Only then can
+// sections be told apart by their layout rather than their titles.
+func TestLocalizedLayout(t *testing.T) {
+	tests := []struct{ localized, english string }{
+		{"cobra/example-localized-de.help", "cobra/example.help"},
+		{"cobra/example-localized-es.help", "cobra/example.help"},
+		{"cobra/example-localized-fr.help", "cobra/example.help"},
+		{"cobra/example-cluster-localized-de.help", "cobra/example-cluster.help"},
+		{"cobra/example-deploy-localized-es.help", "cobra/example-deploy.help"},
+		{"cobra/example-grouped-localized-fr.help", "cobra/example-grouped.help"},
+	}
+	// layout is what of a line survives translation: whether it is a
+	// header, its indent, and where its second column starts.
+	layout := func(line string) [3]int {
+		header := 0
+		if headerRE.MatchString(line) {
+			header = 1
+		}
+		return [3]int{header, indent(line), strings.Index(strings.TrimLeft(line, " "), "  ")}
+	}
+	for _, tt := range tests {
+		got := strings.Split(readFixture(t, tt.localized), "\n")
+		want := strings.Split(readFixture(t, tt.english), "\n")
+		if len(got) != len(want) {
+			t.Errorf("%s: %d lines, %s has %d", tt.localized, len(got), tt.english, len(want))
+			continue
+		}
+		for i := range got {
+			if layout(got[i]) != layout(want[i]) {
+				t.Errorf("%s:%d: %q laid out unlike %q", tt.localized, i+1, got[i], want[i])
+			}
+		}
+	}
+}
+
+// TestParseWhitespace checks that help indented with tabs, with tabs,
+// trailing spaces and non-breaking spaces in its text, parses as the plain
+// help does but for that text, which is kept as printed.
+func TestParseWhitespace(t *testing.T) {
+	fields := func(s string) string { return strings.Join(strings.Fields(s), " ") }
+	tests := []struct{ spaced, plain string }{
+		{"cobra/example-whitespace.help", "cobra/example.help"},
+		{"cobra/example-build-whitespace.help", "cobra/example-build.help"},
+		{"cobra/example-cluster-whitespace.help", "cobra/example-cluster.help"},
+	}
+	for _, tt := range tests {
+		got, want := parseFixture(t, tt.spaced), parseFixture(t, tt.plain)
+		if !reflect.DeepEqual(got.Usage, want.Usage) || fields(got.Long) != fields(want.Long) {
+			t.Errorf("%s: usage %q, long %q; want %q, %q", tt.spaced, got.Usage, got.Long, want.Usage, want.Long)
+		}
+		if len(got.Commands) != len(want.Commands) {
+			t.Errorf("%s: %d commands, want %d", tt.spaced, len(got.Commands), len(want.Commands))
+		}
+		for i := range got.Commands {
+			if i < len(want.Commands) && got.Commands[i].Name != want.Commands[i].Name {
+				t.Errorf("%s: command %q, want %q", tt.spaced, got.Commands[i].Name, want.Commands[i].Name)
+			}
+		}
+		for _, list := range [][2][]Flag{{got.Flags, want.Flags}, {got.InheritedFlags, want.InheritedFlags}} {
+			if len(list[0]) != len(list[1]) {
+				t.Errorf("%s: %d flags, want %d", tt.spaced, len(list[0]), len(list[1]))
+				continue
+			}
+			for i, f := range list[0] {
+				w := list[1][i]
+				if f.Name != w.Name || f.Value != w.Value || f.Default != w.Default {
+					t.Errorf("%s: --%s %s (default %q), want --%s %s (default %q)", tt.spaced, f.Name, f.Value, f.Default, w.Name, w.Value, w.Default)
+				}
+			}
+		}
+		if len(got.Warnings) != 0 || len(got.Sections) != len(want.Sections) {
+			t.Errorf("%s: warnings %v, sections %v", tt.spaced, got.Warnings, got.Sections)
+		}
+	}
+
+	build := parseFixture(t, "cobra/example-build-whitespace.help")
+	for name, usage := range map[string]string{
+		"target":  "Target\tdirectory",
+		"release": "Build in release mode  ",
+		"jobs":    "Number of parallel jobs",
+	} {
+		if f := build.Flags[indexOfFlag(build.Flags, name)]; f.Usage != usage {
+			t.Errorf("--%s: usage %q, want %q", name, f.Usage, usage)
+		}
+	}
+}
+
+// TestParseFlat checks the help of a root command with no subcommands,
+// which has no command list and no [command] in its usage.
+func TestParseFlat(t *testing.T) {
+	c := parseFixture(t, "cobra-flat/example.help")
+	if c.Path != "example" || len(c.Commands) != 0 || len(c.InheritedFlags) != 0 {
+		t.Errorf("path %q, %d commands, %d inherited flags; want example, none, none",
+			c.Path, len(c.Commands), len(c.InheritedFlags))
+	}
+	if want := []Arg{{Name: "file", Optional: true, Repeated: true}}; !reflect.DeepEqual(c.Args, want) {
+		t.Errorf("args %+v, want %+v", c.Args, want)
+	}
+	if len(c.Flags) != 15 || c.Examples == "" || c.Long == "" {
+		t.Errorf("%d flags, examples %q, long %q; want 15 flags and both", len(c.Flags), c.Examples, c.Long)
+	}
+	exclude := c.Flags[indexOfFlag(c.Flags, "exclude")]
+	if exclude.Shorthand != "e" || exclude.Value != "stringArray" {
+		t.Errorf("--exclude: %+v", exclude)
+	}
+	if len(c.Warnings) != 0 || len(c.Sections) != 0 {
+		t.Errorf("warnings %v, sections %v; want none", c.Warnings, c.Sections)
+	}
+}
+
+// TestParsePlatformDefaults checks the help cobra-platform was captured with
+// for each platform against the defaults recorded computing it: paths from
+// the home and temporary directories, printed as the platform writes them,
+// with the variables they came from pinned and recorded.
+func TestParsePlatformDefaults(t *testing.T) {
+	checked := map[string]bool{}
+	for _, e := range corpus.ByFramework("cobra-platform") {
+		if path.Base(e.Path) != "example.help" {
+			continue
+		}
+		data, err := fs.ReadFile(corpus.FS(), path.Dir(e.Path)+"/example.defaults.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var truth struct {
+			GOOS     string `json:"goos"`
+			Defaults []struct {
+				Flag, Default string
+			} `json:"defaults"`
+		}
+		if err := json.Unmarshal(data, &truth); err != nil {
+			t.Fatal(err)
+		}
+		if truth.GOOS != path.Base(path.Dir(e.Path)) {
+			t.Errorf("%s: defaults recorded on %s", e.Path, truth.GOOS)
+		}
+		if e.Invocation == nil || len(e.Invocation.Env) == 0 {
+			t.Errorf("%s: captured with no platform directory pinned", e.Path)
+		}
+		c := parseFixture(t, e.Path)
+		for _, d := range truth.Defaults {
+			i := indexOfFlag(c.Flags, d.Flag)
+			if i < 0 {
+				t.Errorf("%s: --%s missing", e.Path, d.Flag)
+				continue
+			}
+			if got := c.Flags[i].Default; got != d.Default {
+				t.Errorf("%s: --%s default %q, want %q", e.Path, d.Flag, got, d.Default)
+			}
+		}
+		checked[truth.GOOS] = true
+	}
+	for _, goos := range []string{"linux", "darwin", "windows"} {
+		if !checked[goos] {
+			t.Errorf("no cobra-platform help for %s in corpus", goos)
+		}
+	}
+}
+
+// TestParseExperimental checks the help of the tree experimental features
+// give against that tree, which cobra/experimental holds as ground truth in
+// place of the default one, and that each fixture records the environment
+// that switched the features on.
+func TestParseExperimental(t *testing.T) {
+	checked := 0
+	for _, e := range corpus.ByFramework("cobra") {
+		if !strings.HasPrefix(e.Path, "cobra/experimental/") || path.Ext(e.Path) != ".help" {
+			continue
+		}
+		if e.TruthPath != "cobra/experimental/example.tree.json" {
+			t.Errorf("%s: truth from %q", e.Path, e.TruthPath)
+			continue
+		}
+		env := e.Invocation.Env
+		if env["EXAMPLE_EXPERIMENTAL"] != "1" && env["EXAMPLE_CONFIG"] == "" {
+			t.Errorf("%s: captured with %v, want the features' switch recorded", e.Path, env)
+		}
+		var root treeCommand
+		if err := json.Unmarshal(e.Truth, &root); err != nil {
+			t.Fatal(err)
+		}
+		got, err := Parse(e.Help)
+		if err != nil {
+			t.Errorf("%s: %v", e.Path, err)
+			continue
+		}
+		argv := e.Invocation.Argv
+		t.Run(e.Path, func(t *testing.T) {
+			checkCommand(t, got, lookup(&root, argv[1:len(argv)-1]), false)
+		})
+		checked++
+	}
+	if checked != 4 {
+		t.Errorf("checked %d fixtures, want 4", checked)
+	}
+	build := parseFixture(t, "cobra/experimental/example-build.help")
+	if jobs := build.Flags[indexOfFlag(build.Flags, "jobs")]; jobs.Default != "4" {
+		t.Errorf("--jobs default %q, want 4", jobs.Default)
+	}
+	if indexOfFlag(parseFixture(t, "cobra/example-build-experimental-off.help").Flags, "incremental") >= 0 {
+		t.Error("--incremental listed with EXAMPLE_EXPERIMENTAL=0")
+	}
+}
+
+// TestParseRedirectedOutput checks help and errors printed through output
+// writers a command set itself. Help sent to stderr is the same help; an
+// error sent to stdout leaves its usage on stderr, each channel kept apart;
+// and help rewritten through a buffer still parses as the plain help does,
+// with the line added in place of cobra's footer reported.
+func TestParseRedirectedOutput(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"cobra/example-stderr-output.help", "cobra/example.help"},
+		{"cobra/example-build-stderr-output.help", "cobra/example-build.help"},
+	} {
+		if readFixture(t, pair[0]) != readFixture(t, pair[1]) {
+			t.Errorf("%s differs from %s", pair[0], pair[1])
+		}
+	}
+
+	e, ok := corpus.Lookup("cobra/example-stdout-errors-unknown-flag.err")
+	if !ok {
+		t.Fatal("cobra/example-stdout-errors-unknown-flag.err missing from corpus")
+	}
+	if e.Stdout != "Error: unknown flag: --nope\n" {
+		t.Errorf("stdout %q, want only the error", e.Stdout)
+	}
+	if usage, err := Parse(e.Stderr); err != nil || usage.Path != "example build" || len(usage.Flags) == 0 {
+		t.Errorf("stderr parsed to %+v, %v; want build's usage", usage, err)
+	}
+
+	for _, pair := range [][2]string{
+		{"cobra/example-buffered-help.help", "cobra/example.help"},
+		{"cobra/example-build-buffered-help.help", "cobra/example-build.help"},
+		{"cobra/example-help-build-buffered-help.help", "cobra/example-build.help"},
+	} {
+		got, err := (&Parser{Lenient: true}).Parse(readFixture(t, pair[0]))
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := parseFixture(t, pair[1])
+		got.Warnings, want.Warnings = nil, nil
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s parsed unlike %s: %v", pair[0], pair[1], Diff(want, got))
+		}
+	}
+	c, _ := (&Parser{Lenient: true}).Parse(readFixture(t, "cobra/example-buffered-help.help"))
+	if len(c.Warnings) != 1 || !strings.Contains(c.Warnings[0].Message, "help topic") {
+		t.Errorf("warnings %v, want the documentation line reported", c.Warnings)
+	}
+}
+
+// TestParseProgressOutput checks that the usage sync prints on error after
+// logging and drawing progress bars parses as it does without them.
+func TestParseProgressOutput(t *testing.T) {
+	noisy := readFixture(t, "cobra/example-sync-progress-unknown-remote.err")
+	if !strings.ContainsAny(noisy, "\r\x1b") {
+		t.Fatal("cobra/example-sync-progress-unknown-remote.err holds no progress bars")
+	}
+	got, err := Parse(noisy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := Parse(readFixture(t, "cobra/example-sync-unknown-remote.err"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parsed unlike the quiet error: %v", Diff(want, got))
+	}
+	if !strings.HasPrefix(got.Long, "Error: unknown remote") || got.Path != "example sync" {
+		t.Errorf("Long %q, Path %q; want only the error, of example sync", got.Long, got.Path)
+	}
+}
+
+// TestParseLargeHelp parses the help of a command whose description and
+// examples run to tens of thousands of lines, whole.
+func TestParseLargeHelp(t *testing.T) {
+	help := readFixture(t, "cobra/example-reference.help")
+	if lines := strings.Count(help, "\n"); lines < 10000 {
+		t.Fatalf("cobra/example-reference.help has %d lines, want tens of thousands", lines)
+	}
+	c, err := Parse(help)
+	if err != nil {
+		t.Fatal(err)
+	}
+	long, examples := strings.Split(c.Long, "\n"), strings.Split(c.Examples, "\n")
+	if len(long) != 20041 || !strings.HasPrefix(long[len(long)-1], "  E20000  ") {
+		t.Errorf("Long has %d lines, ending %q; want 20041, ending with E20000", len(long), long[len(long)-1])
+	}
+	if len(examples) != 10000 || examples[len(examples)-1] != "  example reference | grep E10000" {
+		t.Errorf("Examples has %d lines, ending %q; want 10000, ending with E10000", len(examples), examples[len(examples)-1])
+	}
+	if len(c.InheritedFlags) != 3 {
+		t.Errorf("%d inherited flags after the examples, want 3", len(c.InheritedFlags))
+	}
+}
+
+// programNames is an <name>.program-name.json of the corpus: the name each
+// capture of a binary run under another one printed.
+type programNames struct {
+	Compiled string             `json:"compiled"`
+	Symlink  programNameCapture `json:"symlink"`
+	Renamed  programNameCapture `json:"renamed"`
+}
+
+type programNameCapture struct {
+	Invoked string `json:"invoked"`
+	Help    string `json:"help"`
+	Shows   string `json:"shows"`
+}
+
+// TestParseInvokedNames checks what the corpus notes of the program names
+// binaries print when run through a symlink or as a renamed copy against
+// the help captured, and that the cobra binary's help, which names it by
+// its Use whatever it was run as, parses as when run by its own name.
+func TestParseInvokedNames(t *testing.T) {
+	notes, err := fs.Glob(corpus.FS(), "*/*.program-name.json")
+	if err != nil || len(notes) == 0 {
+		t.Fatalf("no program name notes in corpus: %v", err)
+	}
+	for _, note := range notes {
+		var names programNames
+		if err := json.Unmarshal([]byte(readFixture(t, note)), &names); err != nil {
+			t.Fatalf("%s: %v", note, err)
+		}
+		dir := path.Dir(note)
+		for _, c := range []programNameCapture{names.Symlink, names.Renamed} {
+			help := readFixture(t, path.Join(dir, c.Help))
+			named := map[string]bool{
+				"path":     strings.Contains(help, "./"+dir+"/invoked/"+c.Invoked),
+				"name":     strings.Contains(help, c.Invoked),
+				"compiled": !strings.Contains(help, c.Invoked),
+			}
+			if !named[c.Shows] {
+				t.Errorf("%s: %s noted as showing the %s, but does not", note, c.Help, c.Shows)
+			}
+		}
+	}
+
+	plain, err := Parse(readFixture(t, "cobra/example.help"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names programNames
+	if err := json.Unmarshal([]byte(readFixture(t, "cobra/example.program-name.json")), &names); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []programNameCapture{names.Symlink, names.Renamed} {
+		if c.Shows != "compiled" {
+			t.Errorf("cobra/%s shows the %s, want the compiled name", c.Help, c.Shows)
+		}
+		got, err := Parse(readFixture(t, "cobra/"+c.Help))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, plain) {
+			t.Errorf("cobra/%s parsed unlike cobra/example.help: %v", c.Help, Diff(plain, got))
+		}
+	}
+}
+
+// helpPath returns the command words of a fixture captured plainly as
+// `example <words> --help` or `example help <words>`, or nil for any other,
+// and whether it was captured with flag descriptions wrapped to the
+// terminal.
+func helpPath(e corpus.Entry) (words []string, wrapped bool) {
+	inv := e.Invocation
+	if inv == nil || inv.Exit != 0 || len(inv.Argv) < 2 {
+		return nil, false
+	}
+	for k, v := range inv.Env {
+		switch {
+		case k == "EXAMPLE_VARIANT" && v == "wrapped":
+			wrapped = true
+		case k != "COLUMNS":
+			return nil, false
+		}
+	}
+	args := inv.Argv[1:]
+	switch {
+	case args[0] == "help":
+		return args[1:], wrapped
+	case args[len(args)-1] == "--help" || args[len(args)-1] == "-h":
+		return args[:len(args)-1], wrapped
+	}
+	return nil, false
+}
+
+// lookup returns the commands from root down to the one words names, or nil
+// if words is nil or names none.
+func lookup(root *treeCommand, words []string) []*treeCommand {
+	if words == nil {
+		return nil
+	}
+	chain := []*treeCommand{root}
+	for _, word := range words {
+		cmd := chain[len(chain)-1]
+		var next *treeCommand
+		for i := range cmd.Commands {
+			sub := &cmd.Commands[i]
+			if sub.Name == word || contains(sub.Aliases, word) {
+				next = sub
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		chain = append(chain, next)
+	}
+	if cmd := chain[len(chain)-1]; cmd.HelpTopic {
+		return nil
+	}
+	return chain
+}
+
+func checkCommand(t reporter, got *Command, chain []*treeCommand, wrapped bool) {
+	want := chain[len(chain)-1]
+	if got.Path != want.Path {
+		t.Errorf("Path = %q, want %q", got.Path, want.Path)
+	}
+	if got.Name != want.Name {
+		t.Errorf("Name = %q, want %q", got.Name, want.Name)
+	}
+	if got.Deprecated != want.Deprecated {
+		t.Errorf("Deprecated = %q, want %q", got.Deprecated, want.Deprecated)
+	}
+	if strings.Join(got.Aliases, ",") != strings.Join(want.Aliases, ",") {
+		t.Errorf("Aliases = %q, want %q", got.Aliases, want.Aliases)
+	}
+
+	var cmds, topics []string
+	for _, sub := range want.Commands {
+		switch {
+		case sub.Hidden || sub.Deprecated != "":
+		case sub.HelpTopic:
+			topics = append(topics, sub.Name+": "+sub.Short)
+		default:
+			cmds = append(cmds, sub.Name+": "+sub.Short)
+		}
+	}
+	checkList(t, "Commands", listed(got.Commands), cmds)
+	checkList(t, "HelpTopics", listed(got.HelpTopics), topics)
+	for _, sub := range got.Commands {
+		if wantPath := want.Path + " " + sub.Name; sub.Path != wantPath {
+			t.Errorf("subcommand Path = %q, want %q", sub.Path, wantPath)
+		}
+	}
+
+	var flags, inherited []treeFlag
+	for _, f := range want.Flags {
+		if !f.Hidden {
+			flags = append(flags, f)
+		}
+	}
+	for _, from := range want.InheritedFlags {
+		f := declaredFlag(chain[:len(chain)-1], from.Name, from.From)
+		switch {
+		case f == nil:
+			t.Errorf("--%s: inherited from %q, which declares no such persistent flag", from.Name, from.From)
+		case !f.Hidden:
+			inherited = append(inherited, *f)
+		}
+	}
+	checkFlags(t, "Flags", got.Flags, flags, wrapped)
+	checkFlags(t, "InheritedFlags", got.InheritedFlags, inherited, wrapped)
+}
+
+// declaredFlag returns the persistent flag name the command at path on chain
+// declares, or nil if there is none.
+func declaredFlag(chain []*treeCommand, name, path string) *treeFlag {
+	for _, c := range chain {
+		if c.Path != path {
+			continue
+		}
+		for i, f := range c.Flags {
+			if f.Name == name && f.Persistent {
+				return &c.Flags[i]
+			}
+		}
+	}
+	return nil
+}
+
+func listed(cmds []Command) []string {
+	var out []string
+	for _, c := range cmds {
+		out = append(out, c.Name+": "+c.Short)
+	}
+	return out
+}
+
+func checkList(t reporter, what string, got, want []string) {
+	t.Helper()
+	sort.Strings(got)
+	sort.Strings(want)
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("%s = %q, want %q", what, got, want)
+	}
+}
+
+// checkFlags compares a parsed flag table with the flags it lists, as pflag
+// prints them: with the first `quoted` word of the usage as the value
+// placeholder, and defaults only when set. Bool and count flags show no
+// NoOptDefault, as theirs is implied. Wrapped usages are compared with
+// whitespace collapsed, as where one had line breaks of its own cannot
+// always be told from where it was wrapped.
+func checkFlags(t reporter, what string, got []Flag, want []treeFlag, wrapped bool) {
+	t.Helper()
+	// Flags printed with only their shorthand are keyed by it.
+	byKey := map[string]Flag{}
+	var keys []string
+	for _, f := range got {
+		key := f.Name
+		if key == "" {
+			key = "-" + f.Shorthand
+		}
+		byKey[key] = f
+		keys = append(keys, key)
+	}
+	var wantKeys []string
+	for i, w := range want {
+		if _, ok := byKey["-"+w.Shorthand]; ok && w.Shorthand != "" {
+			want[i].Name = "-" + w.Shorthand
+		}
+		wantKeys = append(wantKeys, want[i].Name)
+	}
+	checkList(t, what, keys, wantKeys)
+	for _, w := range want {
+		f, ok := byKey[w.Name]
+		if !ok {
+			continue
+		}
+		shorthand := w.Shorthand
+		if w.ShorthandDeprecated != "" {
+			shorthand = ""
+		}
+		if f.Shorthand != shorthand {
+			t.Errorf("--%s: Shorthand = %q, want %q", w.Name, f.Shorthand, shorthand)
+		}
+		usage, value := w.Usage, ""
+		if i := strings.IndexByte(usage, '`'); i >= 0 {
+			if j := strings.IndexByte(usage[i+1:], '`'); j >= 0 {
+				value = usage[i+1 : i+1+j]
+				usage = usage[:i] + value + usage[i+2+j:]
+			}
+		}
+		gotUsage := f.Usage
+		if wrapped {
+			gotUsage, usage = strings.Join(strings.Fields(gotUsage), " "), strings.Join(strings.Fields(usage), " ")
+		}
+		if gotUsage != usage {
+			t.Errorf("--%s: Usage = %q, want %q", w.Name, f.Usage, usage)
+		}
+		if value != "" && f.Value != value {
+			t.Errorf("--%s: Value = %q, want %q", w.Name, f.Value, value)
+		}
+		if value == "" && w.Type == "bool" && f.Value != "" {
+			t.Errorf("--%s: Value = %q, want none for a bool", w.Name, f.Value)
+		}
+		if f.Default != "" && f.Default != w.Default {
+			t.Errorf("--%s: Default = %q, want %q", w.Name, f.Default, w.Default)
+		}
+		if f.Deprecated != w.Deprecated {
+			t.Errorf("--%s: Deprecated = %q, want %q", w.Name, f.Deprecated, w.Deprecated)
+		}
+		if env := EnvVars(w.Usage); !reflect.DeepEqual(f.Env, env) {
+			t.Errorf("--%s: Env = %q, want %q", w.Name, f.Env, env)
+		}
+		if f.DefaultValue != nil {
+			checkDefault(t, w, *f.DefaultValue, value != "")
+		}
+		if w.Type != "bool" && w.Type != "count" && f.NoOptDefault != w.NoOptDefault {
+			t.Errorf("--%s: NoOptDefault = %q, want %q", w.Name, f.NoOptDefault, w.NoOptDefault)
+		}
+	}
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,157 @@
+package mosshelp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// minPadding is the least width cobra pads command names and paths to in
+// command lists.
+const minPadding = 11
+
+// Render writes c's help the way cobra's default usage template does. The
+// result is canonical rather than a copy of the text c was parsed from:
+// command lists are padded to the longest listed name, flag tables are not
+// wrapped, and Sections come after the flags. Parsing it gives back c.
+func Render(c *Command) string {
+	var b strings.Builder
+	if c.Deprecated != "" {
+		fmt.Fprintf(&b, "Command %q is deprecated, %s\n", c.Name, c.Deprecated)
+	}
+	if c.Long != "" {
+		b.WriteString(strings.TrimRight(c.Long, " \t\n") + "\n\n")
+	}
+	b.WriteString("Usage:")
+	for _, line := range c.Usage {
+		b.WriteString("\n  " + line)
+	}
+	if len(c.Aliases) > 0 {
+		b.WriteString("\n\nAliases:\n  " + strings.Join(append([]string{c.Name}, c.Aliases...), ", "))
+	}
+	if c.Examples != "" {
+		b.WriteString("\n\nExamples:\n" + c.Examples)
+	}
+	renderCommands(&b, c.Commands)
+	if len(c.Flags) > 0 {
+		b.WriteString("\n\nFlags:\n" + RenderFlags(c.Flags))
+	}
+	if len(c.InheritedFlags) > 0 {
+		b.WriteString("\n\nGlobal Flags:\n" + RenderFlags(c.InheritedFlags))
+	}
+	for _, s := range c.Sections {
+		b.WriteString("\n\n" + s.Title + ":\n" + s.Body)
+	}
+	if len(c.HelpTopics) > 0 {
+		b.WriteString("\n\nAdditional help topics:")
+		pad := minPadding
+		for _, t := range c.HelpTopics {
+			pad = max(pad, len(t.Path))
+		}
+		for _, t := range c.HelpTopics {
+			fmt.Fprintf(&b, "\n  %-*s %s", pad, t.Path, t.Short)
+		}
+	}
+	if len(c.Commands) > 0 {
+		fmt.Fprintf(&b, "\n\nUse \"%s [command] --help\" for more information about a command.", c.Path)
+	}
+	b.WriteByte('\n')
+	return b.String()
+}
+
+// renderCommands writes the command lists: "Available Commands:" if no
+// command has a group, else one list per group in the order they first
+// appear, then "Additional Commands:" for the rest.
+func renderCommands(b *strings.Builder, cmds []Command) {
+	if len(cmds) == 0 {
+		return
+	}
+	pad := minPadding
+	var groups []string
+	seen := map[string]bool{}
+	for _, c := range cmds {
+		pad = max(pad, len(c.Name))
+		if c.Group != "" && !seen[c.Group] {
+			seen[c.Group] = true
+			groups = append(groups, c.Group)
+		}
+	}
+	list := func(title, group string) {
+		started := false
+		for _, c := range cmds {
+			if c.Group != group {
+				continue
+			}
+			if !started {
+				b.WriteString("\n\n" + title + ":")
+				started = true
+			}
+			fmt.Fprintf(b, "\n  %-*s %s", pad, c.Name, c.Short)
+		}
+	}
+	if len(groups) == 0 {
+		list("Available Commands", "")
+		return
+	}
+	for _, g := range groups {
+		list(g, g)
+	}
+	list("Additional Commands", "")
+}
+
+// RenderFlags writes a flag table the way pflag's FlagUsages does, without
+// the trailing newline.
+func RenderFlags(flags []Flag) string {
+	specs := make([]string, len(flags))
+	width := 0
+	for i, f := range flags {
+		var spec string
+		switch {
+		case f.Name == "":
+			spec = "  -" + f.Shorthand
+		case f.Shorthand != "":
+			spec = "  -" + f.Shorthand + ", --" + f.Name
+		default:
+			spec = "      --" + f.Name
+		}
+		if f.Value != "" {
+			spec += " " + f.Value
+		}
+		if f.NoOptDefault != "" {
+			spec += "[=" + quoteIfString(f, f.NoOptDefault) + "]"
+		}
+		specs[i] = spec
+		width = max(width, len(spec))
+	}
+	var lines []string
+	for i, f := range flags {
+		usage := f.Usage
+		switch {
+		case f.DefaultValue != nil:
+			usage += " (default " + f.DefaultValue.Raw + ")"
+		case f.Default != "":
+			usage += " (default " + quoteIfString(f, f.Default) + ")"
+		}
+		if f.Deprecated != "" {
+			usage += " (DEPRECATED: " + f.Deprecated + ")"
+		}
+		usage = strings.ReplaceAll(usage, "\n", "\n"+strings.Repeat(" ", width+3))
+		// pflag pads by bytes, not runes as fmt does.
+		row := specs[i] + strings.Repeat(" ", width-len(specs[i])) + "   " + usage
+		if strings.TrimSpace(usage) == "" {
+			// Only the padding is trimmed: spaces the usage ends with
+			// are printed.
+			row = strings.TrimRight(row, " ")
+		}
+		lines = append(lines, row)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// quoteIfString quotes v the way pflag prints the values of string flags.
+func quoteIfString(f Flag, v string) string {
+	if f.Value == "string" {
+		return strconv.Quote(v)
+	}
+	return v
+}
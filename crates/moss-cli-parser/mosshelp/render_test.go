@@ -0,0 +1,68 @@
+package mosshelp
+
+import (
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+// TestRenderFixpoint parses each cobra fixture, renders the result and
+// parses that again: the two parses must agree, and rendering the second
+// must give the same text, or the parser or renderer drops something.
+func TestRenderFixpoint(t *testing.T) {
+	for _, e := range corpus.ByFramework("cobra") {
+		if path.Ext(e.Path) != ".help" {
+			continue
+		}
+		first, err := Parse(e.Help)
+		if err != nil {
+			t.Errorf("%s: %v", e.Path, err)
+			continue
+		}
+		text := Render(first)
+		second, err := Parse(text)
+		if err != nil {
+			t.Errorf("%s: re-parse: %v", e.Path, err)
+			continue
+		}
+		// Help without a usage is Long only by position, which rendering,
+		// giving it a usage, makes explicit.
+		if first.Confidence["long"] == Inferred && second.Confidence == nil {
+			delete(first.Confidence, "long")
+			if len(first.Confidence) == 0 {
+				first.Confidence = nil
+			}
+		}
+		if !reflect.DeepEqual(first, second) {
+			t.Errorf("%s: re-parse differs:\n got %+v\nwant %+v\nrendered:\n%s", e.Path, second, first, text)
+			continue
+		}
+		if again := Render(second); again != text {
+			t.Errorf("%s: rendering is not a fixpoint:\n%s\nthen:\n%s", e.Path, text, again)
+		}
+	}
+}
+
+// TestRenderExact checks that help printed with cobra's default template
+// renders back exactly.
+func TestRenderExact(t *testing.T) {
+	for _, fixture := range []string{
+		"cobra/example-build.help",
+		"cobra/example-run.help",
+		"cobra/example-cluster-node-pool-create.help",
+	} {
+		e, ok := corpus.Lookup(fixture)
+		if !ok {
+			t.Fatalf("%s missing from corpus", fixture)
+		}
+		c, err := Parse(e.Help)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := Render(c); got != e.Help {
+			t.Errorf("%s: Render =\n%s\nwant\n%s", fixture, got, e.Help)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package mosshelp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Defaults of a Sandbox's limits.
+const (
+	DefaultTimeout   = 10 * time.Second
+	DefaultMaxOutput = 1 << 20
+)
+
+// DefaultEnv are the environment variables a Sandbox passes on when its
+// Env is nil.
+var DefaultEnv = []string{"PATH"}
+
+// A Sandbox runs the untrusted binaries Discover points at, so that asking
+// for their help can neither hang the caller nor leave anything behind
+// where it matters. Each run gets:
+//
+//   - a timeout, after which the process and any it started are killed;
+//   - a cap on the output kept, past which the process is killed too;
+//   - no input, so a prompt reads end of file;
+//   - a fresh, empty working directory, also its HOME and TMPDIR, removed
+//     afterwards;
+//   - only the environment variables allowed, with the C locale and colors
+//     off so that help is printed the way Parse reads it.
+//
+// Optionally it runs as another user, or isolated in Linux namespaces.
+type Sandbox struct {
+	// Timeout limits each run; 0 means DefaultTimeout.
+	Timeout time.Duration
+	// MaxOutput caps the bytes kept from each output stream; 0 means
+	// DefaultMaxOutput.
+	MaxOutput int
+	// Env names the variables of this process's environment passed on;
+	// nil means DefaultEnv.
+	Env []string
+	// Credential, if set, is the user and group to run as, which takes the
+	// privilege to switch to; the working directory is made theirs. Unix
+	// only.
+	Credential *Credential
+	// Isolate runs the binary in new Linux user, PID, mount, network, IPC
+	// and UTS namespaces: it sees no network and no other processes, and
+	// is root only within them. This takes unprivileged user namespaces
+	// being enabled. Linux only.
+	Isolate bool
+}
+
+// Credential is a user and group to run as.
+type Credential struct {
+	UID, GID uint32
+}
+
+// ErrOutputLimit is returned for a run that printed more than a Sandbox's
+// MaxOutput.
+var ErrOutputLimit = errors.New("mosshelp: output limit exceeded")
+
+// RunHelp runs argv in a Sandbox with the defaults.
+func RunHelp(ctx context.Context, argv []string) (string, error) {
+	return (&Sandbox{}).Run(ctx, argv)
+}
+
+// Run runs argv and returns its standard output, or its standard error if
+// it printed nothing else, as programs that exit with a usage error do. It
+// fails if the program cannot be started, times out, prints too much, or
+// prints nothing; a non-zero exit is not an error when there is output.
+func (s *Sandbox) Run(ctx context.Context, argv []string) (string, error) {
+	if len(argv) == 0 {
+		return "", errors.New("mosshelp: no command to run")
+	}
+	r, err := s.Exec(ctx, argv[0], argv)
+	if err != nil {
+		return "", err
+	}
+	out := r.Stdout
+	if strings.TrimSpace(out) == "" {
+		out = r.Stderr
+	}
+	if strings.TrimSpace(out) == "" {
+		return "", fmt.Errorf("mosshelp: %s: printed nothing", strings.Join(argv, " "))
+	}
+	return out, nil
+}
+
+// A Result is what a program run in a Sandbox printed, and how it exited.
+type Result struct {
+	Stdout, Stderr string
+	// Exit is the exit status, or -1 for a program killed by a signal.
+	Exit int
+}
+
+// Exec runs the program at path with argv, argv[0] the name it is run by,
+// as a multi-call binary tells what to be from. It fails as Run does, but
+// for printing nothing, and returns both streams and the exit status.
+func (s *Sandbox) Exec(ctx context.Context, path string, argv []string) (*Result, error) {
+	if len(argv) == 0 {
+		return nil, errors.New("mosshelp: no command to run")
+	}
+	name := strings.Join(argv, " ")
+	timeout := s.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dir, err := os.MkdirTemp("", "mosshelp-")
+	if err != nil {
+		return nil, fmt.Errorf("mosshelp: %s: %w", name, err)
+	}
+	defer os.RemoveAll(dir)
+
+	cmd := exec.CommandContext(ctx, path, argv[1:]...)
+	cmd.Args, cmd.Dir, cmd.Env = argv, dir, s.environ(dir)
+	// Do not wait long for output from processes the binary left behind.
+	cmd.WaitDelay = time.Second
+	if err := s.configure(cmd); err != nil {
+		return nil, fmt.Errorf("mosshelp: %s: %w", name, err)
+	}
+	if c := s.Credential; c != nil {
+		// MkdirTemp leaves the directory to this user alone; the binary,
+		// changing into it as another, needs it for its own.
+		if err := os.Chown(dir, int(c.UID), int(c.GID)); err != nil {
+			return nil, fmt.Errorf("mosshelp: %s: %w", name, err)
+		}
+	}
+	limit := s.MaxOutput
+	if limit == 0 {
+		limit = DefaultMaxOutput
+	}
+	stdout := &capped{limit: limit, exceeded: cancel}
+	stderr := &capped{limit: limit, exceeded: cancel}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	err = cmd.Run()
+	reap(cmd)
+	if errors.Is(err, exec.ErrWaitDelay) {
+		// The binary exited, leaving behind a process holding its output open.
+		err = nil
+	}
+	r := &Result{Stdout: stdout.String(), Stderr: stderr.String()}
+	var exit *exec.ExitError
+	switch {
+	case stdout.over || stderr.over:
+		return nil, fmt.Errorf("mosshelp: %s: %w (%d bytes)", name, ErrOutputLimit, limit)
+	case ctx.Err() == context.DeadlineExceeded:
+		return nil, fmt.Errorf("mosshelp: %s: timed out after %v", name, timeout)
+	case errors.As(err, &exit):
+		r.Exit = exit.ExitCode()
+	case err != nil:
+		return nil, fmt.Errorf("mosshelp: %s: %w", name, err)
+	}
+	return r, nil
+}
+
+// environ returns the environment to run in, in dir.
+func (s *Sandbox) environ(dir string) []string {
+	names := s.Env
+	if names == nil {
+		names = DefaultEnv
+	}
+	var env []string
+	for _, name := range names {
+		if v, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+v)
+		}
+	}
+	return append(env, "HOME="+dir, "TMPDIR="+dir, "LC_ALL=C", "NO_COLOR=1", "TERM=dumb")
+}
+
+// capped is a buffer that keeps at most limit bytes, calling exceeded once
+// when more are written. It does not embed its bytes.Buffer, whose ReadFrom
+// io.Copy would use instead of Write.
+type capped struct {
+	buf      bytes.Buffer
+	limit    int
+	over     bool
+	exceeded func()
+}
+
+func (c *capped) Write(p []byte) (int, error) {
+	if room := c.limit - c.buf.Len(); len(p) > room {
+		c.buf.Write(p[:max(room, 0)])
+		if !c.over {
+			c.over = true
+			c.exceeded()
+		}
+		return len(p), nil
+	}
+	return c.buf.Write(p)
+}
+
+func (c *capped) String() string {
+	return c.buf.String()
+}
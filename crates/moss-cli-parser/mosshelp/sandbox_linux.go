@@ -0,0 +1,37 @@
+package mosshelp
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// configure sets up cmd to run in its own process group, killed as a whole
+// on timeout and if this process dies, and as s's Credential or in new
+// namespaces.
+func (s *Sandbox) configure(cmd *exec.Cmd) error {
+	attr := &syscall.SysProcAttr{Setpgid: true, Pdeathsig: syscall.SIGKILL}
+	switch {
+	case s.Isolate && s.Credential != nil:
+		return errors.New("a sandbox cannot both isolate and switch user")
+	case s.Isolate:
+		attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWPID | syscall.CLONE_NEWNS |
+			syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getuid(), Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}}
+		attr.GidMappingsEnableSetgroups = false
+	case s.Credential != nil:
+		attr.Credential = &syscall.Credential{Uid: s.Credential.UID, Gid: s.Credential.GID}
+	}
+	cmd.SysProcAttr = attr
+	cmd.Cancel = func() error { return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) }
+	return nil
+}
+
+// reap kills what is left of cmd's process group once it has exited.
+func reap(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build !unix
+
+package mosshelp
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// configure rejects what s asks for that needs Unix: switching user and
+// namespaces. The timeout kills only the process itself.
+func (s *Sandbox) configure(cmd *exec.Cmd) error {
+	switch {
+	case s.Isolate:
+		return errors.New("isolation needs Linux namespaces")
+	case s.Credential != nil:
+		return errors.New("switching user needs Unix")
+	}
+	return nil
+}
+
+// reap does nothing: without process groups, what cmd started is not
+// known.
+func reap(cmd *exec.Cmd) {}
@@ -0,0 +1,230 @@
+package mosshelp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestHelperProcess is the binary the sandbox tests run: this test binary,
+// run as `<test> -test.run=TestHelperProcess -- <mode>`.
+func TestHelperProcess(t *testing.T) {
+	args := os.Args
+	for len(args) > 0 && args[0] != "--" {
+		args = args[1:]
+	}
+	if len(args) < 2 {
+		return
+	}
+	switch args[1] {
+	case "help":
+		fmt.Print("Usage:\n  helper [flags]\n")
+	case "usage-error":
+		fmt.Fprint(os.Stderr, "Usage:\n  helper [flags]\n")
+		os.Exit(2)
+	case "hang":
+		time.Sleep(time.Hour)
+	case "orphan":
+		// Leave a process behind holding stdout open.
+		cmd := helper("hang")
+		cmd.Stdout = os.Stdout
+		cmd.Start()
+		fmt.Print("Usage:\n  helper [flags]\n")
+	case "flood":
+		for {
+			fmt.Print("Usage: helper [flags]\n")
+		}
+	case "env":
+		fmt.Print(strings.Join(os.Environ(), "\n"))
+	case "write":
+		wd, _ := os.Getwd()
+		os.WriteFile("left-behind", nil, 0o644)
+		os.WriteFile(os.Getenv("HOME")+"/.helperrc", nil, 0o644)
+		fmt.Print(wd)
+	case "prompt":
+		io.ReadAll(os.Stdin)
+		fmt.Print("read to end of input")
+	case "uid":
+		fmt.Print(os.Getuid())
+	case "name":
+		fmt.Fprint(os.Stderr, os.Args[0])
+		os.Exit(3)
+	}
+	os.Exit(0)
+}
+
+func helper(mode string) *exec.Cmd {
+	return exec.Command(os.Args[0], "-test.run=^TestHelperProcess$", "--", mode)
+}
+
+func runHelper(t *testing.T, s *Sandbox, mode string) (string, error) {
+	t.Helper()
+	return s.Run(context.Background(), helper(mode).Args)
+}
+
+func TestSandboxOutput(t *testing.T) {
+	for _, mode := range []string{"help", "usage-error"} {
+		out, err := runHelper(t, &Sandbox{}, mode)
+		if err != nil || out != "Usage:\n  helper [flags]\n" {
+			t.Errorf("%s: Run = %q, %v", mode, out, err)
+		}
+	}
+}
+
+func TestSandboxTimeout(t *testing.T) {
+	start := time.Now()
+	_, err := runHelper(t, &Sandbox{Timeout: 200 * time.Millisecond}, "hang")
+	if err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("err = %v, want a timeout", err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("took %v to time out", d)
+	}
+}
+
+func TestSandboxOrphan(t *testing.T) {
+	start := time.Now()
+	out, err := runHelper(t, &Sandbox{}, "orphan")
+	if err != nil || !strings.HasPrefix(out, "Usage:") {
+		t.Errorf("Run = %q, %v", out, err)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("took %v, waiting on the process left behind", d)
+	}
+}
+
+func TestSandboxOutputLimit(t *testing.T) {
+	_, err := runHelper(t, &Sandbox{MaxOutput: 4096}, "flood")
+	if !errors.Is(err, ErrOutputLimit) {
+		t.Errorf("err = %v, want ErrOutputLimit", err)
+	}
+}
+
+// TestSandboxLargeHelp runs the fixture binary, when generate.sh has built
+// it, for its megabyte and more of reference help: past DefaultMaxOutput
+// the run fails, and with room for it the whole is kept, as captured.
+func TestSandboxLargeHelp(t *testing.T) {
+	binary, err := filepath.Abs("../fixtures/cobra/example")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(binary); err != nil {
+		t.Skip("fixture binary not built; run fixtures/generate.sh")
+	}
+	want := readFixture(t, "cobra/example-reference.help")
+	if len(want) <= DefaultMaxOutput {
+		t.Fatalf("cobra/example-reference.help is %d bytes, want more than DefaultMaxOutput", len(want))
+	}
+	argv := []string{binary, "reference", "--help"}
+	if _, err := (&Sandbox{}).Run(context.Background(), argv); !errors.Is(err, ErrOutputLimit) {
+		t.Errorf("err = %v, want ErrOutputLimit", err)
+	}
+	got, err := (&Sandbox{MaxOutput: 2 * len(want)}).Run(context.Background(), argv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Errorf("printed %d bytes unlike the %d captured", len(got), len(want))
+	}
+}
+
+func TestSandboxEnv(t *testing.T) {
+	t.Setenv("MOSSHELP_SECRET", "hunter2")
+	t.Setenv("MOSSHELP_ALLOWED", "yes")
+	out, err := runHelper(t, &Sandbox{Env: []string{"MOSSHELP_ALLOWED"}}, "env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	env := strings.Split(out, "\n")
+	for _, want := range []string{"MOSSHELP_ALLOWED=yes", "LC_ALL=C", "NO_COLOR=1"} {
+		if !contains(env, want) {
+			t.Errorf("environment %q lacks %s", env, want)
+		}
+	}
+	if strings.Contains(out, "hunter2") {
+		t.Errorf("environment %q holds a variable not allowed", env)
+	}
+}
+
+// TestSandboxDir checks that what a binary writes to its working directory
+// and HOME is removed.
+func TestSandboxDir(t *testing.T) {
+	out, err := runHelper(t, &Sandbox{}, "write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(out); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("working directory %s left behind: %v", out, err)
+	}
+}
+
+func TestSandboxPrompt(t *testing.T) {
+	out, err := runHelper(t, &Sandbox{Timeout: 5 * time.Second}, "prompt")
+	if err != nil || out != "read to end of input" {
+		t.Errorf("Run = %q, %v", out, err)
+	}
+}
+
+func TestSandboxIsolate(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		if _, err := runHelper(t, &Sandbox{Isolate: true}, "uid"); err == nil {
+			t.Error("isolation succeeded off Linux")
+		}
+		return
+	}
+	out, err := runHelper(t, &Sandbox{Isolate: true}, "uid")
+	if err != nil {
+		t.Skipf("user namespaces unavailable: %v", err)
+	}
+	if out != "0" {
+		t.Errorf("uid in the namespace = %s, want 0", out)
+	}
+}
+
+// TestSandboxCredential runs a shell as nobody, which needs root to switch
+// to, and checks it can work in its directory.
+func TestSandboxCredential(t *testing.T) {
+	if runtime.GOOS == "windows" || os.Getuid() != 0 {
+		t.Skip("switching user needs root on Unix")
+	}
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip(err)
+	}
+	s := &Sandbox{Credential: &Credential{UID: 65534, GID: 65534}}
+	out, err := s.Run(context.Background(), []string{sh, "-c", "id -u && touch left-behind && echo wrote"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "65534\nwrote\n" {
+		t.Errorf("Run = %q, want uid 65534 and a file written", out)
+	}
+}
+
+func TestSandboxNoArgv(t *testing.T) {
+	if _, err := (&Sandbox{}).Run(context.Background(), nil); err == nil {
+		t.Error("ran an empty command line")
+	}
+}
+
+// TestSandboxExec checks that Exec runs a program by the name it is given,
+// and returns both streams and the exit status.
+func TestSandboxExec(t *testing.T) {
+	cmd := helper("name")
+	argv := append([]string{"renamed-helper"}, cmd.Args[1:]...)
+	r, err := (&Sandbox{}).Exec(context.Background(), cmd.Path, argv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *r != (Result{Stderr: "renamed-helper", Exit: 3}) {
+		t.Errorf("Exec = %+v, want the name on stderr and exit 3", r)
+	}
+}
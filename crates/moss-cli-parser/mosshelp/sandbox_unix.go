@@ -0,0 +1,31 @@
+//go:build unix && !linux
+
+package mosshelp
+
+import (
+	"errors"
+	"os/exec"
+	"syscall"
+)
+
+// configure sets up cmd to run in its own process group, killed as a whole
+// on timeout, and as s's Credential. Namespaces are Linux's alone.
+func (s *Sandbox) configure(cmd *exec.Cmd) error {
+	if s.Isolate {
+		return errors.New("isolation needs Linux namespaces")
+	}
+	attr := &syscall.SysProcAttr{Setpgid: true}
+	if s.Credential != nil {
+		attr.Credential = &syscall.Credential{Uid: s.Credential.UID, Gid: s.Credential.GID}
+	}
+	cmd.SysProcAttr = attr
+	cmd.Cancel = func() error { return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) }
+	return nil
+}
+
+// reap kills what is left of cmd's process group once it has exited.
+func reap(cmd *exec.Cmd) {
+	if cmd.Process != nil {
+		syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
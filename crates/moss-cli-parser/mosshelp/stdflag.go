@@ -0,0 +1,133 @@
+package mosshelp
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	// stdFlagHeaderRE matches the line flag's default usage starts with.
+	stdFlagHeaderRE = regexp.MustCompile(`^Usage of (\S+):$`)
+	// stdFlagUsageRE matches the usage line a program's own usage starts
+	// with, in the case it is printed in.
+	stdFlagUsageRE = regexp.MustCompile(`^(?i:usage): +(\S.*)$`)
+	// stdFlagRowRE matches the line PrintDefaults starts a flag with: its
+	// name and value placeholder, and after a tab, for a one-letter flag
+	// taking no value, its usage.
+	stdFlagRowRE = regexp.MustCompile(`^  -([^\s=]+)(?: (\S+))?(?:\t(.*))?$`)
+)
+
+// stdFlagUsageIndent is how PrintDefaults indents the usage below a flag.
+const stdFlagUsageIndent = "    \t"
+
+// ParseStdFlag parses the help a program using the standard library's flag
+// package prints: the defaults flag.PrintDefaults lists, under the line
+// flag's default usage heads them with,
+//
+//	Usage of example:
+//	  -port int
+//	    	port to listen on (default 8080)
+//
+// or under a usage line and description of the program's own, such as
+//
+//	usage: example tool fmt [flags] [path...]
+//
+//	Fmt reformats the files given.
+//
+//	Flags:
+//	  -l	list files whose formatting differs
+//
+// The Command's Framework is StdFlag. Its flags are named as flag names
+// them, to be given with one dash or two, and have no shorthand; only a
+// usage line of the program's own sets Usage and a Path of more than the
+// program name. It fails on help that has neither heading.
+func ParseStdFlag(help string) (*Command, error) {
+	text := strings.Trim(strings.ReplaceAll(help, "\r\n", "\n"), "\n")
+	if text == "" {
+		return nil, errors.New("mosshelp: empty help text")
+	}
+	lines := strings.Split(text, "\n")
+	table := len(lines)
+	for i, line := range lines {
+		if stdFlagRowRE.MatchString(line) {
+			table = i
+			break
+		}
+	}
+	c := &Command{Schema: SchemaVersion, Framework: StdFlag}
+	var long []string
+	for _, line := range lines[:table] {
+		if c.Path == "" {
+			if m := stdFlagHeaderRE.FindStringSubmatch(line); m != nil {
+				c.Path = path.Base(m[1])
+				score(&c.Confidence, "path", Inferred)
+				continue
+			}
+			if m := stdFlagUsageRE.FindStringSubmatch(line); m != nil {
+				c.Usage = []string{m[1]}
+				c.Path = c.commandPath()
+				continue
+			}
+		}
+		long = append(long, line)
+	}
+	if c.Path == "" {
+		return nil, errors.New("mosshelp: not help printed by the flag package")
+	}
+	c.Name = c.Path[strings.LastIndexByte(c.Path, ' ')+1:]
+	c.setArgs()
+	// A header such as "Flags:" may introduce the defaults.
+	if n := len(long); n > 0 && table < len(lines) && strings.HasSuffix(long[n-1], ":") && !strings.HasPrefix(long[n-1], " ") {
+		long = long[:n-1]
+	}
+	c.Long = strings.Join(trimBlank(long), "\n")
+
+	var f *Flag
+	var usage []string
+	flush := func() {
+		if f == nil {
+			return
+		}
+		f.Usage = strings.Join(usage, "\n")
+		if i := strings.LastIndex(f.Usage, " (default "); i >= 0 && strings.HasSuffix(f.Usage, ")") {
+			raw := f.Usage[i+len(" (default ") : len(f.Usage)-1]
+			f.Usage = f.Usage[:i]
+			f.setDefault(raw)
+		}
+		f.readUsage()
+		c.Flags = append(c.Flags, *f)
+		f, usage = nil, nil
+	}
+	for _, line := range lines[table:] {
+		if m := stdFlagRowRE.FindStringSubmatch(line); m != nil {
+			flush()
+			f = &Flag{Name: m[1], Value: m[2]}
+			if m[3] != "" {
+				usage = []string{m[3]}
+			}
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, stdFlagUsageIndent); ok && f != nil {
+			usage = append(usage, rest)
+			continue
+		}
+		flush()
+		if strings.TrimSpace(line) != "" {
+			c.Sections = appendSectionLine(c.Sections, line)
+		}
+	}
+	flush()
+	return c, nil
+}
+
+// appendSectionLine adds a line that follows the flag defaults to the last
+// of sections, an untitled one for text below the table.
+func appendSectionLine(sections []Section, line string) []Section {
+	if n := len(sections); n > 0 {
+		sections[n-1].Body += "\n" + line
+		return sections
+	}
+	return append(sections, Section{Body: line})
+}
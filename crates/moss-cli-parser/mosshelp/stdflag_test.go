@@ -0,0 +1,105 @@
+package mosshelp
+
+import (
+	"testing"
+)
+
+// TestParseStdFlag checks the help of flag programs, printed by flag's
+// default usage or under a usage line of their own.
+func TestParseStdFlag(t *testing.T) {
+	tests := []struct {
+		fixture string
+		path    string
+		usage   bool
+		long    string
+		flags   []Flag
+	}{
+		{"flag/example.help", "example", false, "", []Flag{
+			{Name: "I", Value: "dir", Usage: "add dir to the include path (repeatable)"},
+			{Name: "config", Value: "file", Usage: "read settings from file"},
+			{Name: "dry-run", Usage: "print what would be done\nwithout doing it"},
+			{Name: "host", Value: "string", Usage: "host to bind", Default: "localhost"},
+			{Name: "log-level", Value: "level", Usage: "minimum level to log: debug, info, warn or error"},
+			{Name: "port", Value: "int", Usage: "port to listen on", Default: "8080"},
+			{Name: "ratio", Value: "float", Usage: "fraction of requests to sample", Default: "0.5"},
+			{Name: "timeout", Value: "duration", Usage: "request timeout", Default: "30s"},
+			{Name: "v", Usage: "enable verbose output"},
+			{Name: "workers", Value: "uint", Usage: "number of worker goroutines; 0 means one per CPU"},
+		}},
+		{"flag/example-custom-usage.help", "example", true, "Example reads each input and reports what it finds.", nil},
+		{"mixed/example-tool-vet.help", "vet", false, "", []Flag{
+			{Name: "all", Usage: "enable every check, experimental ones included"},
+			{Name: "c", Value: "int", Usage: "display offending lines with this many lines of context", Default: "-1"},
+			{Name: "json", Usage: "emit JSON output"},
+			{Name: "tags", Value: "tags", Usage: "a comma-separated list of build tags to consider satisfied"},
+		}},
+		{"mixed/example-tool-fmt.help", "example tool fmt", true, "Fmt reformats the files given, or standard input if there are none.", []Flag{
+			{Name: "l", Usage: "list files whose formatting differs"},
+			{Name: "r", Value: "rule", Usage: "apply the rewrite rule to every file\nbefore formatting it"},
+			{Name: "tabwidth", Value: "int", Usage: "tab width", Default: "8"},
+			{Name: "w", Usage: "write the result to the source file instead of stdout"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			help := readFixture(t, tt.fixture)
+			if fw, _ := Detect(help); fw != StdFlag {
+				t.Fatalf("detected %q, want %q", fw, StdFlag)
+			}
+			c, err := ParseStdFlag(help)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Framework != StdFlag || c.Schema != SchemaVersion {
+				t.Errorf("framework %q schema %q", c.Framework, c.Schema)
+			}
+			if c.Path != tt.path {
+				t.Errorf("path %q, want %q", c.Path, tt.path)
+			}
+			if inferred := c.Confidence["path"] == Inferred; inferred == tt.usage {
+				t.Errorf("path inferred %v with usage line %v", inferred, tt.usage)
+			}
+			if (len(c.Usage) > 0) != tt.usage {
+				t.Errorf("usage %q", c.Usage)
+			}
+			if c.Long != tt.long {
+				t.Errorf("long %q, want %q", c.Long, tt.long)
+			}
+			if tt.flags == nil {
+				if len(c.Flags) == 0 {
+					t.Error("no flags")
+				}
+				return
+			}
+			if len(c.Flags) != len(tt.flags) {
+				t.Fatalf("%d flags, want %d: %+v", len(c.Flags), len(tt.flags), c.Flags)
+			}
+			for i, want := range tt.flags {
+				got := c.Flags[i]
+				if got.Name != want.Name || got.Shorthand != "" || got.Value != want.Value || got.Usage != want.Usage || got.Default != want.Default {
+					t.Errorf("flag %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+// TestParseStdFlagCustomArgs checks the arguments a usage line of a flag
+// program's own names.
+func TestParseStdFlagCustomArgs(t *testing.T) {
+	c, err := ParseStdFlag(readFixture(t, "mixed/example-tool-fmt.help"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Args) != 1 || c.Args[0].Name != "path" || !c.Args[0].Repeated || !c.Args[0].Optional {
+		t.Errorf("args %+v, want one optional, repeated path", c.Args)
+	}
+}
+
+func TestParseStdFlagNotFlag(t *testing.T) {
+	for _, help := range []string{"", "\n\n", readFixture(t, "cobra/golden/example.help")} {
+		if c, err := ParseStdFlag(help); err == nil {
+			t.Errorf("parsed %.20q as flag help: %+v", help, c)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package mosshelp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// StructuredHelpFormat is the format of the structured help Parse reads in
+// place of text: JSON giving the structure cobra's help template lays out,
+// each field as the program declared it. No CLI prints it yet; the fixture
+// binary prints it for --help-json, to try the format out.
+const StructuredHelpFormat = "cli-help/v1"
+
+// structuredHelp is structured help as printed.
+type structuredHelp struct {
+	Format         string             `json:"format"`
+	Path           string             `json:"path"`
+	Usage          []string           `json:"usage"`
+	Aliases        []string           `json:"aliases"`
+	Short          string             `json:"short"`
+	Long           string             `json:"long"`
+	Examples       string             `json:"examples"`
+	Commands       []structuredSubcmd `json:"commands"`
+	HelpTopics     []structuredSubcmd `json:"help_topics"`
+	Flags          []structuredFlag   `json:"flags"`
+	InheritedFlags []structuredFlag   `json:"inherited_flags"`
+}
+
+type structuredSubcmd struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+	Short   string   `json:"short"`
+	Group   string   `json:"group"`
+}
+
+// structuredFlag is a flag as structured help declares it: its pflag Type,
+// the placeholder Value help prints for it, and its defaults as declared,
+// unquoted, zero ones included.
+type structuredFlag struct {
+	Name         string `json:"name"`
+	Shorthand    string `json:"shorthand"`
+	Type         string `json:"type"`
+	Value        string `json:"value"`
+	Usage        string `json:"usage"`
+	Default      string `json:"default"`
+	NoOptDefault string `json:"no_opt_default"`
+	Deprecated   string `json:"deprecated"`
+}
+
+// parseStructured reads help as structured help, reporting false if it is
+// not a JSON object naming a format. It fails on a format other than
+// StructuredHelpFormat.
+//
+// The Command is the one Parse would read from the same help printed as
+// text, but for what text does not show: the aliases of the subcommands
+// listed, and the path of a help topic.
+func parseStructured(help string) (*Command, bool, error) {
+	if !strings.HasPrefix(strings.TrimSpace(help), "{") {
+		return nil, false, nil
+	}
+	var s structuredHelp
+	if err := json.Unmarshal([]byte(help), &s); err != nil || s.Format == "" {
+		return nil, false, nil
+	}
+	if s.Format != StructuredHelpFormat {
+		return nil, true, fmt.Errorf("mosshelp: unsupported structured help format %q (want %q)", s.Format, StructuredHelpFormat)
+	}
+	c := &Command{Schema: SchemaVersion, Path: s.Path, Aliases: s.Aliases}
+	if len(s.Usage) > 0 {
+		c.Usage = s.Usage
+	}
+	c.Name = s.Path[strings.LastIndexByte(s.Path, ' ')+1:]
+	// Help shows Long, or Short in its place, trimmed as text is.
+	long := s.Long
+	if long == "" {
+		long = s.Short
+	}
+	c.Long = strings.Trim(untabAll(strings.TrimRightFunc(long, unicode.IsSpace)), "\n")
+	if s.Examples != "" {
+		c.Examples = strings.Join(trimBlank(strings.Split(untabAll(s.Examples), "\n")), "\n")
+	}
+	c.setArgs()
+	for _, sub := range s.Commands {
+		c.Commands = append(c.Commands, Command{
+			Path: c.Path + " " + sub.Name, Name: sub.Name, Aliases: sub.Aliases, Short: sub.Short, Group: sub.Group,
+		})
+	}
+	for _, topic := range s.HelpTopics {
+		c.HelpTopics = append(c.HelpTopics, Command{Path: c.Path + " " + topic.Name, Name: topic.Name, Short: topic.Short})
+	}
+	for _, f := range s.Flags {
+		c.Flags = append(c.Flags, f.flag())
+	}
+	for _, f := range s.InheritedFlags {
+		c.InheritedFlags = append(c.InheritedFlags, f.flag())
+	}
+	return c, true, nil
+}
+
+// untabAll untabs each line of text.
+func untabAll(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = untab(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// flag returns s as help prints it.
+func (s structuredFlag) flag() Flag {
+	f := Flag{Name: s.Name, Shorthand: s.Shorthand, Value: s.Value, Usage: s.Usage, Deprecated: s.Deprecated}
+	f.ReplacedBy = replacement(f.Deprecated)
+	// pflag prints [=value] unless it is what a bool or count flag takes
+	// anyway.
+	switch {
+	case s.Type == "bool" && s.NoOptDefault == "true", s.Type == "count" && s.NoOptDefault == "+1":
+	default:
+		f.NoOptDefault = s.NoOptDefault
+	}
+	if !zeroValueOf(s.Type, s.Default) {
+		raw := s.Default
+		if s.Type == "string" {
+			raw = strconv.Quote(raw)
+		}
+		f.setDefault(raw)
+	}
+	f.readUsage()
+	return f
+}
+
+// zeroValueOf reports whether def is the zero value of a flag of pflag type
+// typ, which pflag's help leaves out: zeroDefault's rule, without the
+// guessing knowing the type spares.
+func zeroValueOf(typ, def string) bool {
+	switch typ {
+	case "bool":
+		return def == "false"
+	case "duration":
+		return def == "0" || def == "0s"
+	case "int", "int8", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "count", "float32", "float64":
+		return def == "0"
+	case "string":
+		return def == ""
+	case "ip", "ipMask", "ipNet":
+		return def == "<nil>"
+	case "intSlice", "stringSlice", "stringArray":
+		return def == "[]"
+	}
+	return def == "false" || def == "<nil>" || def == "" || def == "0"
+}
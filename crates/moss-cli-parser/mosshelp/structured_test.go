@@ -0,0 +1,70 @@
+package mosshelp
+
+import (
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+// TestParseStructuredCorpus checks the structured help the fixture binary
+// prints for --help-json against the golden help of the same command,
+// parsed: the same command, but for what text does not show, the aliases
+// of its subcommands, which the tree tells, and a help topic's path.
+func TestParseStructuredCorpus(t *testing.T) {
+	names, err := fs.Glob(corpus.FS(), "cobra/help-json/*.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	golden, err := fs.Glob(corpus.FS(), "cobra/golden/*.help")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) == 0 || len(names) != len(golden) {
+		t.Fatalf("%d structured helps in corpus, %d golden", len(names), len(golden))
+	}
+	root := exampleTree(t)
+	aliased := 0
+	for _, name := range names {
+		t.Run(path.Base(name), func(t *testing.T) {
+			got, err := Parse(readFixture(t, name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := parseFixture(t, "cobra/golden/"+strings.TrimSuffix(path.Base(name), ".json")+".help")
+			for i, sub := range got.Commands {
+				node := lookup(root, strings.Fields(sub.Path)[1:])
+				if len(node) == 0 || !reflect.DeepEqual(sub.Aliases, node[len(node)-1].Aliases) {
+					t.Errorf("%s: aliases %q unlike the tree's", sub.Path, sub.Aliases)
+				}
+				aliased += len(sub.Aliases)
+				got.Commands[i].Aliases = nil
+			}
+			if want.Path == "" {
+				// A help topic's help does not tell its path, and tells its
+				// Long only by where it is.
+				got.Path, got.Name, want.Confidence = "", "", nil
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("parsed unlike its help: %v", Diff(want, got))
+			}
+		})
+	}
+	if aliased == 0 {
+		t.Error("no subcommand aliases in structured help")
+	}
+}
+
+func TestParseStructuredFormat(t *testing.T) {
+	if _, err := Parse(`{"format": "cli-help/v2", "path": "example"}`); err == nil {
+		t.Error("unsupported format parsed")
+	}
+	// JSON naming no format is not structured help.
+	c, err := Parse(`{"path": "example"}`)
+	if err != nil || c.Long != `{"path": "example"}` {
+		t.Errorf("got %+v, %v; want the text as Long", c, err)
+	}
+}
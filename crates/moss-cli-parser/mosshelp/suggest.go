@@ -0,0 +1,49 @@
+package mosshelp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// unknownCommandRE matches the error cobra fails with when the command
+// named by an argument is not found, with the name as given and the path
+// it was looked up under.
+var unknownCommandRE = regexp.MustCompile(`^Error: unknown command "(.*)" for "(.*)"$`)
+
+// UnknownCommand is cobra's error for an argument naming no command: the
+// name as given, a mistyped name or, with prefix matching on, a prefix
+// several commands share.
+type UnknownCommand struct {
+	Name string `json:"name"`
+	// Parent is the path the name was looked up under, such as "example".
+	Parent string `json:"parent"`
+	// Suggestions are the commands listed under "Did you mean this?", in
+	// the order cobra lists them: those close enough to the name by edit
+	// distance or starting with it, and those whose SuggestFor names it.
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// ParseUnknownCommand reads cobra's unknown command error from output, what
+// a failed run printed to stderr, reporting false if it holds none.
+func ParseUnknownCommand(output string) (*UnknownCommand, bool) {
+	lines := strings.Split(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		m := unknownCommandRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		u := &UnknownCommand{Name: m[1], Parent: m[2]}
+		rest := lines[i+1:]
+		rest = rest[leadingBlank(rest):]
+		if len(rest) > 0 && rest[0] == "Did you mean this?" {
+			for _, s := range rest[1:] {
+				if !strings.HasPrefix(s, "\t") || strings.TrimSpace(s) == "" {
+					break
+				}
+				u.Suggestions = append(u.Suggestions, strings.TrimSpace(s))
+			}
+		}
+		return u, true
+	}
+	return nil, false
+}
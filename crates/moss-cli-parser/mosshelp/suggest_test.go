@@ -0,0 +1,41 @@
+package mosshelp
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseUnknownCommand checks the unknown command errors of the cobra
+// fixtures: mistyped names, names a command's SuggestFor lists, and with
+// prefix matching on, prefixes several commands share.
+func TestParseUnknownCommand(t *testing.T) {
+	tests := []struct {
+		path string
+		want UnknownCommand
+	}{
+		{"cobra/example-suggest-typo.err", UnknownCommand{"biuld", "example", []string{"build"}}},
+		{"cobra/example-suggest-for.err", UnknownCommand{"start", "example", []string{"run"}}},
+		{"cobra/example-prefix-off.err", UnknownCommand{"bui", "example", []string{"build", "run"}}},
+		{"cobra/example-prefix-ambiguous.err", UnknownCommand{"c", "example",
+			[]string{"calc", "cluster", "config", "convert", "clean", "completion"}}},
+		{"cobra/example-prefix-ambiguous-co.err", UnknownCommand{"co", "example", []string{"config", "convert", "completion"}}},
+	}
+	for _, tt := range tests {
+		got, ok := ParseUnknownCommand(readFixture(t, tt.path))
+		if !ok || !reflect.DeepEqual(*got, tt.want) {
+			t.Errorf("%s: got %+v, %v; want %+v", tt.path, got, ok, tt.want)
+		}
+	}
+	if u, ok := ParseUnknownCommand(readFixture(t, "cobra/example-build.help")); ok {
+		t.Errorf("help parsed to %+v, want no unknown command", u)
+	}
+}
+
+// TestParsePrefixHelp checks that help asked for by a prefix of a command's
+// name, with prefix matching on, is that command's.
+func TestParsePrefixHelp(t *testing.T) {
+	got := parseFixture(t, "cobra/example-prefix-build.help")
+	if want := parseFixture(t, "cobra/example-build.help"); !reflect.DeepEqual(got, want) {
+		t.Errorf("parsed unlike build's help: %v", Diff(want, got))
+	}
+}
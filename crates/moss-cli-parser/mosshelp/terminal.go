@@ -0,0 +1,132 @@
+package mosshelp
+
+import (
+	"strconv"
+	"strings"
+)
+
+// replayTerminal returns text as a terminal would show it, for output
+// captured from programs that draw on it: carriage returns and backspaces
+// move back over a line to overwrite it, CSI sequences move the cursor and
+// erase, and those that only color or set modes, such as hiding the cursor,
+// are dropped. What a progress bar redrew in place is left as it was last
+// drawn, and what was erased is gone.
+//
+// It also returns the line of text, from 1, that each line it returns was
+// last written by, so positions in the replay can be told in text. Text
+// without control characters is returned as it is, with nil.
+func replayTerminal(text string) (string, []int) {
+	if !strings.ContainsAny(text, "\r\b\x1b") {
+		return text, nil
+	}
+	s := screen{input: 1}
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; c {
+		case '\n':
+			s.row++
+			s.col = 0
+			s.input++
+		case '\r':
+			s.col = 0
+		case '\b':
+			s.col = max(s.col-1, 0)
+		case '\x1b':
+			i = s.escape(text, i)
+		default:
+			s.put(c)
+		}
+	}
+	s.line()
+	var b strings.Builder
+	for i, l := range s.lines {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.Write(l)
+	}
+	return b.String(), s.origin
+}
+
+// screen is what replayTerminal has drawn so far, and where its cursor is.
+// Columns count bytes, which is as far as help text needs.
+type screen struct {
+	lines [][]byte
+	// origin holds the line of the text each of lines was last written
+	// by, and input the line being read.
+	origin   []int
+	row, col int
+	input    int
+}
+
+// line returns the line under the cursor, padded out to the cursor.
+func (s *screen) line() []byte {
+	for len(s.lines) <= s.row {
+		s.lines = append(s.lines, nil)
+		s.origin = append(s.origin, s.input)
+	}
+	for len(s.lines[s.row]) < s.col {
+		s.lines[s.row] = append(s.lines[s.row], ' ')
+	}
+	return s.lines[s.row]
+}
+
+// put writes c at the cursor and moves past it.
+func (s *screen) put(c byte) {
+	if l := s.line(); s.col < len(l) {
+		l[s.col] = c
+	} else {
+		s.lines[s.row] = append(l, c)
+	}
+	s.origin[s.row] = s.input
+	s.col++
+}
+
+// escape applies the escape sequence starting at text[i] and returns the
+// index of its last byte. Sequences other than CSI ones are dropped with
+// the byte after the escape.
+func (s *screen) escape(text string, i int) int {
+	if i+1 >= len(text) || text[i+1] != '[' {
+		return min(i+1, len(text)-1)
+	}
+	j := i + 2
+	for j < len(text) && (text[j] < 0x40 || text[j] > 0x7e) {
+		j++
+	}
+	if j == len(text) {
+		return j - 1
+	}
+	params := text[i+2 : j]
+	n := 1
+	if v, err := strconv.Atoi(params); err == nil && v > 0 {
+		n = v
+	}
+	switch text[j] {
+	case 'A':
+		s.row = max(s.row-n, 0)
+	case 'B':
+		s.row += n
+	case 'C':
+		s.col += n
+	case 'D':
+		s.col = max(s.col-n, 0)
+	case 'G':
+		s.col = n - 1
+	case 'K':
+		l := s.line()
+		switch params {
+		case "", "0":
+			s.lines[s.row] = l[:s.col]
+		case "1":
+			for k := range l[:min(s.col+1, len(l))] {
+				l[k] = ' '
+			}
+			for len(l) <= s.col {
+				l = append(l, ' ')
+			}
+			s.lines[s.row] = l
+		case "2":
+			s.lines[s.row] = l[:0]
+		}
+	}
+	return j
+}
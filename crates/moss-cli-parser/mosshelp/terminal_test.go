@@ -0,0 +1,90 @@
+package mosshelp
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReplayTerminal(t *testing.T) {
+	tests := []struct {
+		text, want string
+		origin     []int
+	}{
+		{"Usage:\n  example\n", "Usage:\n  example\n", nil},
+		{"\x1b[31mError:\x1b[0m failed\n", "Error: failed\n", []int{1, 2}},
+		{"\x1b[?25lUsage:\x1b[?25h\n", "Usage:\n", []int{1, 2}},
+		{"step 1\rstep 2\rdone\n", "done 2\n", []int{1, 2}},
+		{"50%\r\x1b[2K100%\n", "100%\n", []int{1, 2}},
+		{"abc\b\bX\n", "aXc\n", []int{1, 2}},
+		{"abcdef\r\x1b[3C\x1b[K\n", "abc\n", []int{1, 2}},
+		{"one\ntwo\x1b[1A\rONE\x1b[1B\n", "ONE\ntwo\n", []int{2, 2, 3}},
+		{"\x1b[?25l\x1b[31mError:\x1b[0m 50%\r\x1b[Kfailed\x1b[?25h\n", "failed\n", []int{1, 2}},
+		{"abcdef\x1b[3G\x1b[1K\n", "   def\n", []int{1, 2}},
+		{"a\x1b[2B\nb\n", "a\n\n\nb\n", []int{1, 2, 2, 2, 3}},
+	}
+	for _, tt := range tests {
+		got, origin := replayTerminal(tt.text)
+		if got != tt.want || !reflect.DeepEqual(origin, tt.origin) {
+			t.Errorf("replayTerminal(%q) = %q, %v, want %q, %v", tt.text, got, origin, tt.want, tt.origin)
+		}
+	}
+}
+
+// TestParseReplayedWarnings checks that warnings in help drawn over are
+// told at the lines of the help, not of its replay.
+func TestParseReplayedWarnings(t *testing.T) {
+	help := "Usage:\n  example [flags]\x1b[3B\nFlags:\n  garbage here\n"
+	c, err := (&Parser{Lenient: true}).Parse(help)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Warnings) != 1 || c.Warnings[0].Line != 4 || c.Warnings[0].Column != 3 {
+		t.Errorf("warnings %v, want one at 4:3", c.Warnings)
+	}
+	// Help that a replay erases entirely is not empty, and parses.
+	if _, err := Parse("\b "); err != nil {
+		t.Errorf("Parse(%q): %v", "\b ", err)
+	}
+}
+
+// TestParseColored checks that colored help parses as the same help
+// uncolored does, to the same path, commands and flags.
+func TestParseColored(t *testing.T) {
+	for _, tt := range []struct{ colored, plain, path string }{
+		{"cobra/example-colored.help", "cobra/example-colored-no-color.help", "example"},
+		{"cobra/example-colored-tty.help", "cobra/example-colored-tty-no-color.help", "example"},
+		{"cobra/example-build-colored.help", "cobra/example-build.help", "example build"},
+	} {
+		got, want := parseFixture(t, tt.colored), parseFixture(t, tt.plain)
+		if got.Path != tt.path {
+			t.Errorf("%s: path %q, want %q", tt.colored, got.Path, tt.path)
+		}
+		var gotCmds, wantCmds []string
+		for _, c := range got.Commands {
+			gotCmds = append(gotCmds, c.Name)
+		}
+		for _, c := range want.Commands {
+			wantCmds = append(wantCmds, c.Name)
+		}
+		if !reflect.DeepEqual(gotCmds, wantCmds) {
+			t.Errorf("%s: commands %q, want %q", tt.colored, gotCmds, wantCmds)
+		}
+		if !reflect.DeepEqual(got.Flags, want.Flags) || !reflect.DeepEqual(got.InheritedFlags, want.InheritedFlags) {
+			t.Errorf("%s: flags %+v %+v, want %+v %+v", tt.colored, got.Flags, got.InheritedFlags, want.Flags, want.InheritedFlags)
+		}
+		if len(got.Flags) == 0 {
+			t.Errorf("%s: no flags", tt.colored)
+		}
+	}
+}
+
+// BenchmarkReplayTerminal replays a megabyte line drawn over once, which
+// takes time linear in its length.
+func BenchmarkReplayTerminal(b *testing.B) {
+	text := strings.Repeat("x", 1<<20) + "\r\x1b[Kdone\n"
+	b.SetBytes(int64(len(text)))
+	for i := 0; i < b.N; i++ {
+		replayTerminal(text)
+	}
+}
@@ -0,0 +1,312 @@
+package mosshelp
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Usage is a parsed usage line, such as
+// "example convert [flags] <input> [output...]".
+type Usage struct {
+	// Command is the words naming the command: those before the first
+	// that looks like an argument (bracketed, a <placeholder>, a flag,
+	// written in capitals or containing "..." or "=").
+	Command []string `json:"command"`
+	// Syntax is the rest of the line.
+	Syntax []Node `json:"syntax,omitempty"`
+	// Flags is set if the line has cobra's "[flags]" placeholder.
+	Flags bool `json:"flags,omitempty"`
+	// Subcommand is set if the line has cobra's "[command]" placeholder.
+	Subcommand bool `json:"subcommand,omitempty"`
+	// Args are the positional arguments Syntax describes, in order.
+	Args []Arg `json:"args,omitempty"`
+}
+
+// NodeKind is the kind of a Node.
+type NodeKind int
+
+const (
+	// PlaceholderNode is an argument to fill in: "<file>", "FILE", or a
+	// lowercase word such as "dir" in "init [dir]".
+	PlaceholderNode NodeKind = iota
+	// LiteralNode is a word typed as is, such as "json" in "(json|yaml)".
+	LiteralNode
+	// FlagNode is a flag, such as "--force", or the "[flags]" placeholder.
+	FlagNode
+	// TerminatorNode is "--", after which nothing is parsed as a flag.
+	TerminatorNode
+	// OptionalNode is a bracketed sequence, "[...]".
+	OptionalNode
+	// GroupNode is a sequence in parentheses or braces, "(...)".
+	GroupNode
+	// ChoiceNode is an alternation, "a|b": each child is one alternative.
+	ChoiceNode
+)
+
+var nodeKinds = [...]string{"placeholder", "literal", "flag", "terminator", "optional", "group", "choice"}
+
+func (k NodeKind) String() string {
+	if k >= 0 && int(k) < len(nodeKinds) {
+		return nodeKinds[k]
+	}
+	return fmt.Sprintf("NodeKind(%d)", int(k))
+}
+
+// Node is one element of a usage line's syntax.
+type Node struct {
+	Kind NodeKind `json:"kind"`
+	// Name is the word of a placeholder, literal or flag, without angle
+	// brackets or ellipsis.
+	Name string `json:"name,omitempty"`
+	// Repeated is set for elements followed by "...".
+	Repeated bool `json:"repeated,omitempty"`
+	// Children are the sequence an optional or group node encloses, or a
+	// choice node's alternatives, each a single node or a group.
+	Children []Node `json:"children,omitempty"`
+}
+
+// ParseUsage parses a usage line. It fails on unbalanced brackets.
+func ParseUsage(line string) (*Usage, error) {
+	p := &usageParser{toks: tokenizeUsage(line)}
+	u := &Usage{}
+	for p.pos < len(p.toks) && p.toks[p.pos].kind == wordTok && !isArg(p.toks[p.pos].text) {
+		u.Command = append(u.Command, p.toks[p.pos].text)
+		p.pos++
+	}
+	nodes, err := p.sequence(false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.toks) {
+		return nil, fmt.Errorf("mosshelp: unbalanced %q in usage %q", p.toks[p.pos].text, line)
+	}
+	for _, n := range nodes {
+		switch {
+		case isPlaceholder(n, "flags"):
+			u.Flags = true
+			continue
+		case isPlaceholder(n, "command"):
+			u.Subcommand = true
+			continue
+		}
+		u.Syntax = append(u.Syntax, n)
+	}
+	u.Args = usageArgs(u.Syntax, false, false, new(bool), nil)
+	return u, nil
+}
+
+// isPlaceholder reports whether n is "[name]".
+func isPlaceholder(n Node, name string) bool {
+	return n.Kind == OptionalNode && len(n.Children) == 1 && n.Children[0].Kind == PlaceholderNode && n.Children[0].Name == name
+}
+
+// usageArgs flattens nodes into positional arguments. Words of a choice
+// become the choices of one argument, a placeholder right after a flag is
+// the flag's value rather than an argument, and the arguments after "--" are
+// marked as passed through.
+func usageArgs(nodes []Node, optional, repeated bool, passthrough *bool, args []Arg) []Arg {
+	for i, n := range nodes {
+		switch n.Kind {
+		case PlaceholderNode:
+			if i > 0 && nodes[i-1].Kind == FlagNode && !*passthrough {
+				continue
+			}
+			args = append(args, Arg{Name: n.Name, Optional: optional, Repeated: repeated || n.Repeated, Passthrough: *passthrough})
+		case TerminatorNode:
+			*passthrough = true
+		case OptionalNode, GroupNode:
+			args = usageArgs(n.Children, optional || n.Kind == OptionalNode, repeated || n.Repeated, passthrough, args)
+		case ChoiceNode:
+			arg := Arg{Optional: optional, Repeated: repeated || n.Repeated, Passthrough: *passthrough}
+			for _, alt := range n.Children {
+				if alt.Kind != LiteralNode && alt.Kind != PlaceholderNode {
+					// An alternation of sequences, such as (-a | <file>...):
+					// take its arguments as they come.
+					args = usageArgs(n.Children, true, arg.Repeated, passthrough, args)
+					arg.Choices = nil
+					break
+				}
+				arg.Choices = append(arg.Choices, alt.Name)
+			}
+			if arg.Choices != nil {
+				arg.Name = strings.Join(arg.Choices, "|")
+				args = append(args, arg)
+			}
+		}
+	}
+	return args
+}
+
+type usageTokKind int
+
+const (
+	wordTok usageTokKind = iota
+	openTok
+	closeTok
+	pipeTok
+	ellipsisTok
+)
+
+type usageTok struct {
+	kind usageTokKind
+	text string
+}
+
+// tokenizeUsage splits a usage line into words, brackets, pipes and
+// ellipses. Placeholders in angle brackets are words, spaces included, as
+// are runs of them joined to other text, such as "<key>=<value>".
+func tokenizeUsage(line string) []usageTok {
+	var toks []usageTok
+	var word strings.Builder
+	endWord := func() {
+		if word.Len() > 0 {
+			w := word.String()
+			word.Reset()
+			repeated := false
+			for _, dots := range []string{"...", "…"} {
+				if w != dots && strings.HasSuffix(w, dots) {
+					w, repeated = strings.TrimSuffix(w, dots), true
+				}
+			}
+			if w == "..." || w == "…" {
+				toks = append(toks, usageTok{ellipsisTok, w})
+				return
+			}
+			toks = append(toks, usageTok{wordTok, w})
+			if repeated {
+				toks = append(toks, usageTok{ellipsisTok, "..."})
+			}
+		}
+	}
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; c {
+		case ' ', '\t':
+			endWord()
+		case '<':
+			end := strings.IndexByte(line[i:], '>')
+			if end < 0 {
+				word.WriteByte(c)
+				continue
+			}
+			word.WriteString(line[i : i+end+1])
+			i += end
+		case '[', '(', '{':
+			endWord()
+			toks = append(toks, usageTok{openTok, string(c)})
+		case ']', ')', '}':
+			endWord()
+			toks = append(toks, usageTok{closeTok, string(c)})
+		case '|':
+			endWord()
+			toks = append(toks, usageTok{pipeTok, "|"})
+		default:
+			word.WriteByte(c)
+		}
+	}
+	endWord()
+	return toks
+}
+
+type usageParser struct {
+	toks []usageTok
+	pos  int
+}
+
+var closers = map[string]string{"[": "]", "(": ")", "{": "}"}
+
+// sequence parses items up to a closing bracket or the end of the line,
+// with any alternatives among them. In brackets, a run of bare words names
+// one placeholder, as in "[-- tool flags...]".
+func (p *usageParser) sequence(bracketed bool) ([]Node, error) {
+	var alts [][]Node
+	var cur []Node
+	// bare is set while cur ends in a placeholder written as bare words.
+	bare := false
+	for p.pos < len(p.toks) {
+		t := p.toks[p.pos]
+		switch t.kind {
+		case closeTok:
+			return alternation(append(alts, cur)), nil
+		case pipeTok:
+			p.pos++
+			alts = append(alts, cur)
+			cur, bare = nil, false
+			continue
+		case ellipsisTok:
+			p.pos++
+			if len(cur) > 0 {
+				cur[len(cur)-1].Repeated = true
+			}
+			continue
+		case openTok:
+			p.pos++
+			children, err := p.sequence(true)
+			if err != nil {
+				return nil, err
+			}
+			if p.pos >= len(p.toks) || p.toks[p.pos].text != closers[t.text] {
+				return nil, fmt.Errorf("mosshelp: unclosed %q in usage", t.text)
+			}
+			p.pos++
+			kind := GroupNode
+			if t.text == "[" {
+				kind = OptionalNode
+			}
+			cur, bare = append(cur, Node{Kind: kind, Children: children}), false
+			continue
+		}
+		p.pos++
+		n := wordNode(t.text)
+		if last := len(cur) - 1; bracketed && bare && isBare(t.text) && !cur[last].Repeated {
+			cur[last].Name += " " + n.Name
+			continue
+		}
+		cur = append(cur, n)
+		bare = n.Kind == PlaceholderNode && isBare(t.text)
+	}
+	return alternation(append(alts, cur)), nil
+}
+
+// alternation returns the nodes of a sequence with alternatives: the one
+// sequence itself if there is just one, or a choice node over them. In a
+// choice, bare words are literals rather than placeholders.
+func alternation(alts [][]Node) []Node {
+	if len(alts) == 1 {
+		return alts[0]
+	}
+	choice := Node{Kind: ChoiceNode}
+	for _, alt := range alts {
+		switch {
+		case len(alt) == 1 && alt[0].Kind == PlaceholderNode && isBare(alt[0].Name):
+			alt[0].Kind = LiteralNode
+			choice.Children = append(choice.Children, alt[0])
+		case len(alt) == 1:
+			choice.Children = append(choice.Children, alt[0])
+		default:
+			choice.Children = append(choice.Children, Node{Kind: GroupNode, Children: alt})
+		}
+	}
+	return []Node{choice}
+}
+
+// wordNode classifies a word of a usage line.
+func wordNode(w string) Node {
+	switch {
+	case w == "--":
+		return Node{Kind: TerminatorNode, Name: w}
+	case strings.HasPrefix(w, "-"):
+		return Node{Kind: FlagNode, Name: w}
+	}
+	if strings.HasPrefix(w, "<") && strings.HasSuffix(w, ">") && strings.Count(w, "<") == 1 {
+		w = w[1 : len(w)-1]
+	} else {
+		w = strings.NewReplacer("<", "", ">", "").Replace(w)
+	}
+	return Node{Kind: PlaceholderNode, Name: w}
+}
+
+// isBare reports whether w is a plain lowercase word, with no angle
+// brackets or capitals marking it as a placeholder.
+func isBare(w string) bool {
+	return w != "" && !strings.ContainsAny(w, "<>=") && strings.ToLower(w) == w && !strings.HasPrefix(w, "-")
+}
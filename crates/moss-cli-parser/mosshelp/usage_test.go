@@ -0,0 +1,95 @@
+package mosshelp
+
+import (
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+func TestParseUsage(t *testing.T) {
+	tests := []struct {
+		line    string
+		command []string
+		flags   bool
+		args    []Arg
+	}{
+		{"example init [dir]", []string{"example", "init"}, false, []Arg{
+			{Name: "dir", Optional: true},
+		}},
+		{"example convert [flags] <input> [output...]", []string{"example", "convert"}, true, []Arg{
+			{Name: "input"},
+			{Name: "output", Optional: true, Repeated: true},
+		}},
+		{"example run [flags] -- [args...]", []string{"example", "run"}, true, []Arg{
+			{Name: "args", Optional: true, Repeated: true, Passthrough: true},
+		}},
+		{"example proxy [flags] <tool> [-- tool flags...]", []string{"example", "proxy"}, true, []Arg{
+			{Name: "tool"},
+			{Name: "tool flags", Optional: true, Repeated: true, Passthrough: true},
+		}},
+		{"example config set <key>=<value>...", []string{"example", "config", "set"}, false, []Arg{
+			{Name: "key=value", Repeated: true},
+		}},
+		{"example deploy ENV [REGION]", []string{"example", "deploy"}, false, []Arg{
+			{Name: "ENV"},
+			{Name: "REGION", Optional: true},
+		}},
+		{"tool fmt (json|yaml|table) [FILE]...", []string{"tool", "fmt"}, false, []Arg{
+			{Name: "json|yaml|table", Choices: []string{"json", "yaml", "table"}},
+			{Name: "FILE", Optional: true, Repeated: true},
+		}},
+		{"git [-v | --version] [-C <path>] <command> [<args>]", []string{"git"}, false, []Arg{
+			{Name: "command"},
+			{Name: "args", Optional: true},
+		}},
+	}
+	for _, tt := range tests {
+		u, err := ParseUsage(tt.line)
+		if err != nil {
+			t.Errorf("ParseUsage(%q): %v", tt.line, err)
+			continue
+		}
+		if !reflect.DeepEqual(u.Command, tt.command) {
+			t.Errorf("ParseUsage(%q).Command = %q, want %q", tt.line, u.Command, tt.command)
+		}
+		if u.Flags != tt.flags {
+			t.Errorf("ParseUsage(%q).Flags = %v, want %v", tt.line, u.Flags, tt.flags)
+		}
+		if !reflect.DeepEqual(u.Args, tt.args) {
+			t.Errorf("ParseUsage(%q).Args = %+v, want %+v", tt.line, u.Args, tt.args)
+		}
+	}
+}
+
+func TestParseUsageUnbalanced(t *testing.T) {
+	for _, line := range []string{"example [dir", "example dir]", "example (a|b]"} {
+		if _, err := ParseUsage(line); err == nil {
+			t.Errorf("ParseUsage(%q) succeeded", line)
+		}
+	}
+}
+
+// TestParseUsageCorpus parses every usage line of the cobra fixtures.
+func TestParseUsageCorpus(t *testing.T) {
+	for _, e := range corpus.ByFramework("cobra") {
+		if path.Ext(e.Path) != ".help" {
+			continue
+		}
+		c, err := Parse(e.Help)
+		if err != nil {
+			continue
+		}
+		for _, line := range c.Usage {
+			u, err := ParseUsage(line)
+			if err != nil {
+				t.Errorf("%s: %v", e.Path, err)
+				continue
+			}
+			if len(u.Command) == 0 {
+				t.Errorf("%s: no command in usage %q", e.Path, line)
+			}
+		}
+	}
+}
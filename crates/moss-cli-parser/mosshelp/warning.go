@@ -0,0 +1,78 @@
+package mosshelp
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+// A Parser parses help text as Parse does, but tells what in it it could
+// not make sense of, which Parse skips without a word: a section it does
+// not recognize, kept in Sections, or a flag table line that is neither a
+// row nor a continuation of one, dropped.
+type Parser struct {
+	// Lenient makes each such problem a Warning, recorded in the Warnings
+	// of the Command returned with the rest of the help parsed regardless.
+	// Otherwise Parse fails on the first, returning it as a *Warning.
+	Lenient bool
+}
+
+// A Warning is a problem a Parser found in help text, at the position it
+// starts at.
+type Warning struct {
+	// Line is the line number in the help, from 1.
+	Line int `json:"line"`
+	// Column is the column in Line, in characters from 1.
+	Column  int    `json:"column"`
+	Message string `json:"message"`
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("%d:%d: %s", w.Line, w.Column, w.Message)
+}
+
+func (w *Warning) Error() string {
+	return "mosshelp: " + w.String()
+}
+
+// Parse parses the help text a cobra command prints, as the package's
+// Parse does, failing on empty text, or unless p is Lenient, on the first
+// problem found.
+func (p *Parser) Parse(help string) (*Command, error) {
+	var w warnings
+	c, err := parse(help, &w)
+	if err != nil {
+		return nil, err
+	}
+	if !p.Lenient && len(w) > 0 {
+		return nil, &w[0]
+	}
+	c.Warnings = w
+	return c, nil
+}
+
+// warnings collects the problems parsing finds. Parse collects none, into
+// a nil *warnings.
+type warnings []Warning
+
+// add records a problem at the byte offset col of text, the line numbered
+// line.
+func (w *warnings) add(line int, text string, col int, format string, args ...any) {
+	if w == nil {
+		return
+	}
+	*w = append(*w, Warning{Line: line, Column: utf8.RuneCountInString(text[:col]) + 1, Message: fmt.Sprintf(format, args...)})
+}
+
+// relocate moves each problem recorded at a line of a replay of the help,
+// as replayTerminal returned it, to the line of the help origin says it was
+// written by. It leaves them where they are when origin is nil.
+func (w *warnings) relocate(origin []int) {
+	if w == nil || origin == nil {
+		return
+	}
+	for i := range *w {
+		if l := (*w)[i].Line; l >= 1 && l <= len(origin) {
+			(*w)[i].Line = origin[l-1]
+		}
+	}
+}
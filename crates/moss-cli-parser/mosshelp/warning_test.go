@@ -0,0 +1,262 @@
+package mosshelp
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anthropics/moss/crates/moss-cli-parser/fixtures/corpus"
+)
+
+// TestParserGolden checks that help cobra prints with its default template
+// parses strictly, into what Parse returns.
+func TestParserGolden(t *testing.T) {
+	pages, err := fs.Glob(corpus.FS(), "cobra/golden/*.help")
+	if err != nil || len(pages) == 0 {
+		t.Fatalf("no golden help in corpus: %v", err)
+	}
+	for _, name := range pages {
+		help := readFixture(t, name)
+		got, err := (&Parser{}).Parse(help)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if want, _ := Parse(help); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: strict parse differs from Parse", name)
+		}
+	}
+}
+
+// TestParserMalformed checks the warnings for the malformed cobra fixtures
+// against the problems their sidecars record, of the kinds a Parser tells.
+func TestParserMalformed(t *testing.T) {
+	kinds := map[string]bool{"misaligned-columns": true, "duplicate-flag": true}
+	for _, e := range corpus.ByFramework("malformed") {
+		if !strings.HasPrefix(e.Path, "malformed/cobra/") {
+			continue
+		}
+		var sidecar struct {
+			Diagnostics []struct {
+				Kind string
+				Line int
+			}
+		}
+		if err := json.Unmarshal([]byte(readFixture(t, strings.TrimSuffix(e.Path, ".help")+".diagnostics.json")), &sidecar); err != nil {
+			t.Fatalf("%s: %v", e.Path, err)
+		}
+		// A Parser tells of misaligned rows only in flag tables: a command
+		// list with them is an unrecognized section.
+		lines := strings.Split(e.Help, "\n")
+		var want []int
+		for _, d := range sidecar.Diagnostics {
+			if kinds[d.Kind] && strings.HasSuffix(sectionOf(lines, d.Line), "Flags:") {
+				want = append(want, d.Line)
+			}
+		}
+		c, err := (&Parser{Lenient: true}).Parse(e.Help)
+		if err != nil {
+			t.Errorf("%s: %v", e.Path, err)
+			continue
+		}
+		var got []int
+		for _, w := range c.Warnings {
+			if !strings.HasPrefix(w.Message, "unrecognized section") {
+				got = append(got, w.Line)
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: warnings on lines %v, want %v", e.Path, got, want)
+		}
+		if _, err := (&Parser{}).Parse(e.Help); err == nil && len(want) > 0 {
+			t.Errorf("%s: parsed strictly", e.Path)
+		}
+	}
+}
+
+// sectionOf returns the header of the section holding line n of lines.
+func sectionOf(lines []string, n int) string {
+	for i := n - 1; i >= 0; i-- {
+		if headerRE.MatchString(lines[i]) {
+			return lines[i]
+		}
+	}
+	return ""
+}
+
+func TestParserWarnings(t *testing.T) {
+	help := strings.TrimPrefix(`
+Usage:
+  example build [flags]
+
+Flags:
+  see below
+  -r, --release   Build in release mode
+  -j=N            Parallel jobs
+                  default one per CPU
+  -r, --release   Build in release mode
+ stray text
+
+Environment:
+EXAMPLE_HOME is where state is kept.
+
+Additional help topics:
+  exit-codes
+`, "\n")
+	c, err := (&Parser{Lenient: true}).Parse(help)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Warning{
+		{Line: 5, Column: 3, Message: "text before the first flag row"},
+		{Line: 7, Column: 3, Message: `malformed flag row "-j=N"`},
+		{Line: 9, Column: 3, Message: "--release is listed twice"},
+		{Line: 10, Column: 2, Message: "line neither a flag row nor indented to its description"},
+		{Line: 12, Column: 1, Message: `unrecognized section "Environment"`},
+		{Line: 16, Column: 3, Message: `help topic "exit-codes" without a summary`},
+	}
+	if !reflect.DeepEqual(c.Warnings, want) {
+		t.Errorf("warnings =\n%v\nwant\n%v", c.Warnings, want)
+	}
+	// The rest is parsed as by Parse.
+	parsed, _ := Parse(help)
+	c.Warnings = nil
+	if !reflect.DeepEqual(c, parsed) {
+		t.Errorf("lenient parse = %+v, want Parse's %+v", c, parsed)
+	}
+
+	_, err = (&Parser{}).Parse(help)
+	var w *Warning
+	if !errors.As(err, &w) || *w != want[0] {
+		t.Errorf("strict parse error %v, want %v", err, want[0])
+	}
+	if got := err.Error(); got != "mosshelp: 5:3: text before the first flag row" {
+		t.Errorf("error %q", got)
+	}
+	if _, err := (&Parser{Lenient: true}).Parse("\n"); err == nil {
+		t.Error("empty help parsed")
+	}
+
+	// A deprecation line above the help counts.
+	c, _ = (&Parser{Lenient: true}).Parse("Command \"compile\" is deprecated, use \"build\" instead\n" + help)
+	if c.Warnings[0].Line != 6 {
+		t.Errorf("after a deprecation, first warning on line %d, want 6", c.Warnings[0].Line)
+	}
+}
+
+func TestWarningJSON(t *testing.T) {
+	c := &Command{Path: "example", Name: "example", Warnings: []Warning{{Line: 3, Column: 7, Message: "malformed flag row"}}}
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `"warnings":[{"line":3,"column":7,"message":"malformed flag row"}]`; !strings.Contains(string(data), want) {
+		t.Errorf("JSON %s, want it to hold %s", data, want)
+	}
+}
+
+// TestParserMutated checks the re-laid-out cobra fixtures against the
+// outcome their sidecars record: a variant to survive parses into the
+// command its source does, and one to reject fails to parse strictly.
+func TestParserMutated(t *testing.T) {
+	n := 0
+	for _, e := range corpus.ByFramework("mutated") {
+		if !strings.HasPrefix(e.Path, "mutated/cobra/") || !strings.HasSuffix(e.Path, ".help") {
+			continue
+		}
+		var sidecar struct {
+			Source, Mutation, Expect string
+		}
+		if err := json.Unmarshal([]byte(readFixture(t, strings.TrimSuffix(e.Path, ".help")+".mutation.json")), &sidecar); err != nil {
+			t.Fatalf("%s: %v", e.Path, err)
+		}
+		n++
+		switch sidecar.Expect {
+		case "survive":
+			want := parseFixture(t, sidecar.Source)
+			got, err := Parse(e.Help)
+			if err != nil {
+				t.Errorf("%s: %v", e.Path, err)
+				continue
+			}
+			if d := Diff(want, got); len(d) > 0 {
+				t.Errorf("%s: parses differently from %s: %v", e.Path, sidecar.Source, d)
+			}
+		case "reject":
+			if _, err := (&Parser{}).Parse(e.Help); err == nil {
+				t.Errorf("%s: parsed strictly", e.Path)
+			}
+		default:
+			t.Errorf("%s: expect = %q, want survive or reject", e.Path, sidecar.Expect)
+		}
+	}
+	if n == 0 {
+		t.Fatal("no mutated cobra fixtures in corpus")
+	}
+}
+
+// TestParserExpectedWarnings checks each fixture with expected warnings
+// declared for it: a lenient parse reports every warning required, and no
+// other than those allowed.
+func TestParserExpectedWarnings(t *testing.T) {
+	n := 0
+	for _, e := range corpus.All() {
+		if e.Warnings == nil {
+			continue
+		}
+		n++
+		c, err := (&Parser{Lenient: true}).Parse(e.Help)
+		if err != nil {
+			t.Errorf("%s: %v", e.Path, err)
+			continue
+		}
+		matched := make([]bool, len(e.Warnings.Require))
+	next:
+		for _, w := range c.Warnings {
+			for i, want := range e.Warnings.Require {
+				if want.Matches(w.Line, w.Column, w.Message) {
+					matched[i] = true
+					continue next
+				}
+			}
+			for _, want := range e.Warnings.Allow {
+				if want.Matches(w.Line, w.Column, w.Message) {
+					continue next
+				}
+			}
+			t.Errorf("%s: unexpected warning %v", e.Path, &w)
+		}
+		for i, want := range e.Warnings.Require {
+			if !matched[i] {
+				t.Errorf("%s: no warning %d:%d %q", e.Path, want.Line, want.Column, want.Message)
+			}
+		}
+	}
+	if n == 0 {
+		t.Fatal("no fixtures with expected warnings in corpus")
+	}
+}
+
+// TestExpectedWarningsFixtures checks that each expected_warnings.json
+// declares warnings only for fixtures in its directory.
+func TestExpectedWarningsFixtures(t *testing.T) {
+	files, err := fs.Glob(corpus.FS(), "*/*/expected_warnings.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no expected_warnings.json in corpus: %v", err)
+	}
+	for _, name := range files {
+		var byFixture map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(readFixture(t, name)), &byFixture); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for fixture := range byFixture {
+			if e, ok := corpus.Lookup(path.Join(path.Dir(name), fixture)); !ok || e.Warnings == nil {
+				t.Errorf("%s: no fixture %s", name, fixture)
+			}
+		}
+	}
+}
@@ -3,6 +3,7 @@
 use rhizome_moss_cli_parser::{parse_help, parse_help_with_format};
 
 const EXAMPLE_HELP: &str = include_str!("../fixtures/cobra/example.help");
+const UNHIDDEN_HELP: &str = include_str!("../fixtures/cobra/example-unhidden.help");
 
 #[test]
 fn test_detect_cobra_format() {
@@ -21,14 +22,39 @@ fn test_parse_main_help() {
     );
 
     // Check commands (help/completion filtered out)
-    assert_eq!(spec.commands.len(), 3);
+    assert_eq!(spec.commands.len(), 20);
     let cmd_names: Vec<_> = spec.commands.iter().map(|c| c.name.as_str()).collect();
     assert!(cmd_names.contains(&"build"));
+    assert!(cmd_names.contains(&"calc"));
+    assert!(cmd_names.contains(&"cluster"));
+    assert!(cmd_names.contains(&"config"));
+    assert!(cmd_names.contains(&"convert"));
+    assert!(cmd_names.contains(&"deploy"));
+    assert!(cmd_names.contains(&"exec"));
+    assert!(cmd_names.contains(&"greet"));
+    assert!(cmd_names.contains(&"init"));
+    assert!(cmd_names.contains(&"login"));
+    assert!(cmd_names.contains(&"proxy"));
+    assert!(cmd_names.contains(&"search"));
+    assert!(cmd_names.contains(&"serve"));
+    assert!(cmd_names.contains(&"status"));
+    assert!(cmd_names.contains(&"version"));
     assert!(cmd_names.contains(&"run"));
     assert!(cmd_names.contains(&"clean"));
 
+    // Help topics are listed separately and are not commands
+    assert!(!cmd_names.contains(&"environment"));
+    assert!(!cmd_names.contains(&"exit-codes"));
+
     // Check options (help/version filtered out)
-    assert_eq!(spec.options.len(), 3);
+    assert_eq!(spec.options.len(), 4);
+
+    let chdir = spec
+        .options
+        .iter()
+        .find(|o| o.long == Some("--chdir".to_string()));
+    assert!(chdir.is_some());
+    assert_eq!(chdir.unwrap().short, Some("-C".to_string()));
 
     let verbose = spec
         .options
@@ -50,3 +76,23 @@ fn test_parse_main_help() {
     assert!(port.is_some());
     assert_eq!(port.unwrap().default, Some("8080".to_string()));
 }
+
+#[test]
+fn test_hidden_items() {
+    let spec = parse_help_with_format(EXAMPLE_HELP, "cobra").expect("should parse");
+    assert!(!spec.commands.iter().any(|c| c.name == "debug"));
+    assert!(
+        !spec
+            .options
+            .iter()
+            .any(|o| o.long == Some("--trace".to_string()))
+    );
+
+    let spec = parse_help_with_format(UNHIDDEN_HELP, "cobra").expect("should parse");
+    assert!(spec.commands.iter().any(|c| c.name == "debug"));
+    assert!(
+        spec.options
+            .iter()
+            .any(|o| o.long == Some("--trace".to_string()))
+    );
+}